@@ -1,24 +1,43 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 
+	types "github.com/orca-telemetry/core/datalayer"
+	orca "github.com/orca-telemetry/core/internal"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/certs"
 	dlyrs "github.com/orca-telemetry/core/internal/datalayers"
 	envs "github.com/orca-telemetry/core/internal/envs"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/tracing"
+	"github.com/orca-telemetry/core/internal/webhook"
 )
 
 type cliFlags struct {
-	migrate  bool
-	showHelp bool
+	migrate    bool
+	showHelp   bool
+	configFile string
+
+	// command to run in place of the server, and its arguments
+	args []string
 }
 
+// commands run in place of the server
+var commands = []string{"keys", "grants", "migrate", "doctor"}
+
 var logLevels = []string{
 	"DEBUG",
 	"INFO",
@@ -29,6 +48,8 @@ var logLevels = []string{
 // valid datalayers - as they are displayed
 var datalayerSuggestions = []string{
 	"postgresql",
+	"sqlite",
+	"memory",
 }
 
 // templates for filling out connection string
@@ -45,6 +66,10 @@ var connectionTemplates = map[string]connStringTemplate{
 		validationFunc: ParsePostgresURL,
 		exampleConnStr: "postgresql://<user>:<pass>@<localhost>:<port>/<db>?<setting=value>",
 	},
+	"sqlite": {
+		validationFunc: ParseSqliteURL,
+		exampleConnStr: "sqlite://<path to database file>",
+	},
 }
 
 // validation functions
@@ -111,7 +136,14 @@ func parseFlags() cliFlags {
 		false,
 		"Migrate the orca db prior to launching orca. Will need to be run at least once to provision the store before use",
 	)
+	flag.StringVar(
+		&flags.configFile,
+		"config",
+		"",
+		"Path to a YAML or TOML config file. Environment variables override its settings (default: ORCA_CONFIG_FILE)",
+	)
 	flag.Parse()
+	flags.args = flag.Args()
 
 	return flags
 }
@@ -135,10 +167,15 @@ func validateFlags(flags cliFlags) error {
 	if flags.showHelp {
 		return nil
 	}
+	if len(flags.args) > 0 && !slices.Contains(commands, flags.args[0]) {
+		return fmt.Errorf("unknown command: %s. Must be one of: %s", flags.args[0], strings.Join(commands, ", "))
+	}
 	return nil
 }
 
-func validateConfig(config *envs.Config) error {
+// validateDatalayerConfig validates the configuration needed to connect to
+// the datalayer
+func validateDatalayerConfig(config *envs.Config) error {
 	if config.Platform == "" {
 		return fmt.Errorf("platform cannot be determined from connection string")
 	}
@@ -152,8 +189,35 @@ func validateConfig(config *envs.Config) error {
 	if err := ValidateConnStr(config.ConnectionString, config.Platform); err != nil {
 		return fmt.Errorf("invalid connection string: %w", err)
 	}
+	return nil
+}
 
-	if err := ValidatePort(config.Port); err != nil {
+func validateConfig(config *envs.Config) error {
+	return validateConfigPorts(config, ValidatePort)
+}
+
+// validateReloadedConfig validates a reloaded configuration, where the ports
+// the server is listening on are in use by the server itself
+func validateReloadedConfig(current *envs.Config) func(*envs.Config) error {
+	listening := []int{current.Port, current.WebhookPort, current.GatewayPort, current.MetricsPort}
+	return func(config *envs.Config) error {
+		return validateConfigPorts(config, func(port int) error {
+			if slices.Contains(listening, port) {
+				return nil
+			}
+			return ValidatePort(port)
+		})
+	}
+}
+
+// validateConfigPorts validates the configuration, checking that ports are
+// available with validatePort
+func validateConfigPorts(config *envs.Config, validatePort func(int) error) error {
+	if err := validateDatalayerConfig(config); err != nil {
+		return err
+	}
+
+	if err := validatePort(config.Port); err != nil {
 		return fmt.Errorf("invalid port: %w", err)
 	}
 
@@ -161,22 +225,153 @@ func validateConfig(config *envs.Config) error {
 		return fmt.Errorf("invalid log level: %w", err)
 	}
 
+	if !slices.Contains(logging.Formats, config.LogFormat) {
+		return fmt.Errorf("invalid log format: %s. Must be one of: %s", config.LogFormat, strings.Join(logging.Formats, ", "))
+	}
+
+	if config.WebhookPort != 0 {
+		if config.WebhookPort == config.Port {
+			return fmt.Errorf("webhook port cannot be the same as the server port")
+		}
+		if err := validatePort(config.WebhookPort); err != nil {
+			return fmt.Errorf("invalid webhook port: %w", err)
+		}
+		if config.WebhookConfigPath == "" {
+			return fmt.Errorf("ORCA_WEBHOOK_CONFIG environment variable is required when ORCA_WEBHOOK_PORT is set")
+		}
+		if _, err := webhook.LoadConfig(config.WebhookConfigPath); err != nil {
+			return fmt.Errorf("invalid webhook config: %w", err)
+		}
+	}
+
+	if config.GatewayPort != 0 {
+		if config.GatewayPort == config.Port || config.GatewayPort == config.WebhookPort {
+			return fmt.Errorf("gateway port must differ from the server and webhook ports")
+		}
+		if err := validatePort(config.GatewayPort); err != nil {
+			return fmt.Errorf("invalid gateway port: %w", err)
+		}
+	}
+
+	if !slices.Contains(tracing.Exporters, config.TracesExporter) {
+		return fmt.Errorf("invalid traces exporter: %s. Must be one of: otlp, file", config.TracesExporter)
+	}
+
+	if config.MetricsPort != 0 {
+		if slices.Contains([]int{config.Port, config.WebhookPort, config.GatewayPort}, config.MetricsPort) {
+			return fmt.Errorf("metrics port must differ from the server, webhook and gateway ports")
+		}
+		if err := validatePort(config.MetricsPort); err != nil {
+			return fmt.Errorf("invalid metrics port: %w", err)
+		}
+	}
+
+	if _, err := auth.ParseStaticKeys(config.APIKeys); err != nil {
+		return fmt.Errorf("invalid ORCA_API_KEYS: %w", err)
+	}
+
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return fmt.Errorf("ORCA_TLS_CERT_FILE and ORCA_TLS_KEY_FILE must be set together")
+	}
+	if config.TLSClientCAFile != "" && config.TLSCertFile == "" {
+		return fmt.Errorf("ORCA_TLS_CLIENT_CA_FILE requires ORCA_TLS_CERT_FILE and ORCA_TLS_KEY_FILE")
+	}
+	if !slices.Contains(certs.ClientAuthModes, config.TLSClientAuth) {
+		return fmt.Errorf("invalid TLS client auth: %s. Must be one of: %s", config.TLSClientAuth, strings.Join(certs.ClientAuthModes, ", "))
+	}
+	if config.TLSClientAuth == certs.ClientAuthRequire && config.TLSClientCAFile == "" {
+		return fmt.Errorf("ORCA_TLS_CLIENT_AUTH=require needs ORCA_TLS_CLIENT_CA_FILE")
+	}
+	if config.TLSCertFile != "" {
+		if _, err := certs.NewReloader(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile); err != nil {
+			return fmt.Errorf("invalid server TLS: %w", err)
+		}
+	}
+	if _, err := emitLimits(config); err != nil {
+		return err
+	}
+
+	if (config.ProcessorCertFile == "") != (config.ProcessorKeyFile == "") {
+		return fmt.Errorf("ORCA_PROCESSOR_CERT_FILE and ORCA_PROCESSOR_KEY_FILE must be set together")
+	}
+	if config.ProcessorCAFile != "" || config.ProcessorCertFile != "" {
+		if _, err := certs.NewReloader(config.ProcessorCertFile, config.ProcessorKeyFile, config.ProcessorCAFile); err != nil {
+			return fmt.Errorf("invalid processor TLS: %w", err)
+		}
+	}
+
 	return nil
 }
 
 func runCLI(flags cliFlags) {
 	if flags.showHelp {
 		flag.Usage()
+		fmt.Println("\nCommands:")
+		fmt.Println("  keys create <name>     Create an API key, printing it once")
+		fmt.Println("  keys revoke <name>     Revoke an API key")
+		fmt.Println("  grants add <identity> <role> [scope]")
+		fmt.Println("                         Grant a role (admin, processor, emitter or reader) to an identity, e.g. api_key:ci-pipeline.")
+		fmt.Println("                         Processors and readers are scoped to a namespace, and emitters to <namespace>/<window type>,")
+		fmt.Println("                         either of which can be * (default: all)")
+		fmt.Println("  grants remove <identity> <role> [scope]")
+		fmt.Println("                         Take a role away from an identity")
+		fmt.Println("  grants list <identity> List the roles of an identity")
+		fmt.Println("  migrate up             Apply every migration not yet applied, as -migrate does")
+		fmt.Println("  migrate down [n]       Revert the last n applied migrations (default: 1)")
+		fmt.Println("  migrate goto <version> Migrate up or down to a version")
+		fmt.Println("  migrate status         Print the schema version, whether it is dirty, and the version of this release")
+		fmt.Println("  migrate force <version>")
+		fmt.Println("                         Record the schema as at a version and not dirty, once a failed migration has been fixed by hand")
+		fmt.Println("  doctor                 Check the configuration, the database and its schema, and that registered processors are reachable,")
+		fmt.Println("                         printing how to fix each failed check")
 		fmt.Println("\nEnvironment Variables:")
-		fmt.Println("  ORCA_CONNECTION_STRING  Database connection string (required)")
+		fmt.Println("  ORCA_CONFIG_FILE       YAML or TOML config file, named by extension. Settings are the variables below in lower case without")
+		fmt.Println("                         ORCA_, e.g. log_level, and the variables override them. log_level, rate_limits and concurrency_limits")
+		fmt.Println("                         are reloaded on SIGHUP. ORCA_ENV is given as production: true")
+		fmt.Println("  ORCA_CONNECTION_STRING  Database connection string (required), postgresql://..., sqlite://<path to database file>,")
+		fmt.Println("                         or memory://<name> to keep everything in memory until orca exits")
 		fmt.Println("  ORCA_PORT              Server port (default: 4040)")
 		fmt.Println("  ORCA_LOG_LEVEL         Log level (default: INFO)")
-		fmt.Println("  ORCA_ENV               Environment (production/prod for production mode - if in production mode TLS will be used throughout for all gRPC connections)")
+		fmt.Println("  ORCA_LOG_FORMAT        Log format, text or json (default: text)")
+		fmt.Println("  ORCA_WEBHOOK_PORT      Webhook listener port (default: disabled)")
+		fmt.Println("  ORCA_WEBHOOK_CONFIG    Path to the JSON webhook route config (required with ORCA_WEBHOOK_PORT)")
+		fmt.Println("  ORCA_GATEWAY_PORT      HTTP/JSON gateway port (default: disabled)")
+		fmt.Println("  ORCA_METRICS_PORT      Prometheus /metrics port (default: disabled)")
+		fmt.Println("  ORCA_TRACES_EXPORTER   Span exporter, otlp or file (default: disabled). OTLP is configured with the OTEL_EXPORTER_OTLP_* variables")
+		fmt.Println("  ORCA_TRACES_FILE       File the file exporter appends spans to (default: orca-traces.json)")
+		fmt.Println("  ORCA_AUTH_ENABLED      Require an API key or client certificate on OrcaCore calls, true or false (default: false)")
+		fmt.Println("  ORCA_API_KEYS          Static API keys, as comma separated name:key pairs")
+		fmt.Println("  ORCA_TLS_CERT_FILE     Server certificate, serving gRPC over TLS when set. Reloaded when the file changes")
+		fmt.Println("  ORCA_TLS_KEY_FILE      Server private key (required with ORCA_TLS_CERT_FILE)")
+		fmt.Println("  ORCA_TLS_CLIENT_CA_FILE CA that client certificates are verified against")
+		fmt.Println("  ORCA_TLS_CLIENT_AUTH   Client certificates are optional or required, optional or require (default: optional)")
+		fmt.Println("  ORCA_PROCESSOR_CA_FILE CA bundle processor certificates are verified against (default: system roots)")
+		fmt.Println("  ORCA_PROCESSOR_CERT_FILE Client certificate presented to processors, for mTLS")
+		fmt.Println("  ORCA_PROCESSOR_KEY_FILE Client private key (required with ORCA_PROCESSOR_CERT_FILE)")
+		fmt.Println("  ORCA_RATE_LIMITS       Rate limits on emitting windows, as comma separated key:count/unit[:burst], e.g. caller:100/m,window_type:20/s:40.")
+		fmt.Println("                         Keys are origin, caller or window_type, and units s, m or h (default: unlimited)")
+		fmt.Println("  ORCA_CONCURRENCY_LIMITS Limits on the windows processing at once, as comma separated key:count, e.g. origin:5 (default: unlimited)")
+		fmt.Println("  ORCA_ENV               Environment (production/prod for production mode - if in production mode, or ORCA_PROCESSOR_* is set, TLS is used for the connections to processors. Serving over TLS is configured with ORCA_TLS_CERT_FILE)")
 		return
 	}
 
-	// get singleton  configuration
-	config := envs.GetConfig()
+	// load the configuration, failing when the config file cannot be read
+	if flags.configFile != "" {
+		envs.SetConfigFile(flags.configFile)
+	}
+	config, err := envs.ReloadConfig(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(flags.args) > 0 {
+		if err := runCommand(config, flags.args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// validate configuration
 	if err := validateConfig(config); err != nil {
@@ -184,10 +379,14 @@ func runCLI(flags cliFlags) {
 		os.Exit(1)
 	}
 
-	// stdout logger
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: parseLogLevel(config.LogLevel),
-	})
+	// stdout logger, at a level that can be changed on reload
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(parseLogLevel(config.LogLevel))
+	handler, err := logging.NewHandler(os.Stdout, config.LogFormat, logLevel)
+	if err != nil {
+		slog.Error("could not create logger", "error", err)
+		os.Exit(1)
+	}
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -195,7 +394,14 @@ func runCLI(flags cliFlags) {
 		"platform", config.Platform,
 		"port", config.Port,
 		"production", config.IsProduction,
-		"logLevel", config.LogLevel)
+		"logLevel", config.LogLevel,
+		"logFormat", config.LogFormat)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracesExporter, config.TracesFile)
+	if err != nil {
+		slog.Error("could not set up tracing", "error", err)
+		os.Exit(1)
+	}
 
 	// perform migrations if requested
 	slog.Info("premigration")
@@ -207,8 +413,256 @@ func runCLI(flags cliFlags) {
 			os.Exit(1)
 		}
 	}
-	startGRPCServer(config.Platform, config.ConnectionString, config.Port, config.LogLevel)
+	orcaServer, interceptor, httpTLSConfig, shutdown := startGRPCServer(config)
+	var httpServers []*http.Server
+	if config.WebhookPort != 0 {
+		httpServers = append(httpServers, startWebhookServer(orcaServer, config.WebhookPort, config.WebhookConfigPath))
+	}
+	if config.GatewayPort != 0 {
+		httpServers = append(httpServers, startGatewayServer(orcaServer, interceptor, httpTLSConfig, config.GatewayPort))
+	}
+	if config.MetricsPort != 0 {
+		httpServers = append(httpServers, startMetricsServer(config.MetricsPort))
+	}
 
-	// keep main thread alive
-	select {}
+	// keep main thread alive until asked to stop, reloading the
+	// configuration on SIGHUP
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	sig := <-signals
+	for sig == syscall.SIGHUP {
+		config = reloadConfig(config, logLevel, orcaServer)
+		sig = <-signals
+	}
+	slog.Info("received signal", "signal", sig.String())
+	shutdown(httpServers...)
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("could not flush traces", "error", err)
+	}
+}
+
+// reloadConfig applies the reloadable settings of the reloaded
+// configuration, keeping the current configuration when it is invalid
+func reloadConfig(current *envs.Config, logLevel *slog.LevelVar, orcaServer *orca.OrcaCoreServer) *envs.Config {
+	slog.Info("reloading configuration")
+	config, err := envs.ReloadConfig(validateReloadedConfig(current))
+	if err != nil {
+		slog.Error("could not reload configuration, keeping the current configuration", "error", err)
+		return current
+	}
+
+	logLevel.Set(parseLogLevel(config.LogLevel))
+	limits, err := emitLimits(config)
+	if err != nil {
+		slog.Error("invalid emit limits", "error", err)
+	} else {
+		orcaServer.SetEmitLimits(limits)
+	}
+	if changed := current.RestartRequired(config); len(changed) > 0 {
+		slog.Warn("settings changed that only take effect on restart", "settings", changed)
+	}
+	slog.Info("reloaded configuration",
+		"logLevel", config.LogLevel,
+		"rateLimits", config.RateLimits,
+		"concurrencyLimits", config.ConcurrencyLimits)
+	return config
+}
+
+// runCommand runs a command against the datalayer, in place of the server
+func runCommand(config *envs.Config, args []string) error {
+	if args[0] == "doctor" {
+		// reports a datalayer that cannot be reached as a failed check
+		return runDoctorCommand(config)
+	}
+	if err := validateDatalayerConfig(config); err != nil {
+		return err
+	}
+	if args[0] == "migrate" {
+		// migrations run against stores the datalayer cannot yet query
+		return runMigrateCommand(config, args[1:])
+	}
+	ctx := context.Background()
+	client, err := dlyrs.NewDatalayerClient(ctx, dlyrs.Platform(config.Platform), config.ConnectionString)
+	if err != nil {
+		return fmt.Errorf("could not connect to the datalayer: %w", err)
+	}
+
+	switch args[0] {
+	case "keys":
+		return runKeysCommand(ctx, client, args[1:])
+	case "grants":
+		return runGrantsCommand(ctx, client, args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+// runKeysCommand creates or revokes an API key
+func runKeysCommand(ctx context.Context, client types.Datalayer, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: orca keys create|revoke <name>")
+	}
+	action, name := args[0], args[1]
+	switch action {
+	case "create":
+		key, err := auth.GenerateKey()
+		if err != nil {
+			return err
+		}
+		if err := client.CreateAPIKey(ctx, name, auth.HashKey(key)); err != nil {
+			return err
+		}
+		fmt.Printf("Created API key %s. It is only shown once:\n%s\n", name, key)
+	case "revoke":
+		if err := client.RevokeAPIKey(ctx, name); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked API key %s\n", name)
+	default:
+		return errors.New("usage: orca keys create|revoke <name>")
+	}
+	return nil
+}
+
+// runGrantsCommand grants, takes away or lists the roles of an identity
+func runGrantsCommand(ctx context.Context, client types.Datalayer, args []string) error {
+	usage := errors.New("usage: orca grants add|remove <identity> <role> [scope], or orca grants list <identity>")
+	if len(args) == 2 && args[0] == "list" {
+		grants, err := client.ReadGrants(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		for _, grant := range grants {
+			fmt.Printf("%s\t%s\n", grant.Role, grant.Scope)
+		}
+		return nil
+	}
+	if len(args) < 3 || len(args) > 4 {
+		return usage
+	}
+
+	action, identity := args[0], args[1]
+	grant := types.Grant{Role: args[2], Scope: types.ScopeAll}
+	if len(args) == 4 {
+		grant.Scope = args[3]
+	}
+	if err := auth.ValidateGrant(grant); err != nil {
+		return err
+	}
+	switch action {
+	case "add":
+		if err := client.CreateGrant(ctx, identity, grant); err != nil {
+			return err
+		}
+		fmt.Printf("Granted %s the %s role for %s\n", identity, grant.Role, grant.Scope)
+	case "remove":
+		if err := client.DeleteGrant(ctx, identity, grant); err != nil {
+			return err
+		}
+		fmt.Printf("Took the %s role for %s away from %s\n", grant.Role, grant.Scope, identity)
+	default:
+		return usage
+	}
+	return nil
+}
+
+// runMigrateCommand migrates the datalayer up, down or to a version, or
+// prints its schema version
+func runMigrateCommand(config *envs.Config, args []string) error {
+	usage := errors.New("usage: orca migrate up|down [n]|goto <version>|status|force <version>")
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	migrator, err := dlyrs.NewMigrator(config.Platform, config.ConnectionString)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	action := args[0]
+	switch {
+	case action == "up" && len(args) == 1:
+		err = migrator.Up()
+	case action == "down":
+		n := 1
+		if len(args) == 2 {
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("invalid number of migrations: %s", args[1])
+			}
+		}
+		err = migrator.Down(n)
+	case action == "goto" && len(args) == 2:
+		version, parseErr := strconv.ParseUint(args[1], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid version: %s", args[1])
+		}
+		err = migrator.Goto(uint(version))
+	case action == "force" && len(args) == 2:
+		version, parseErr := strconv.Atoi(args[1])
+		if parseErr != nil {
+			return fmt.Errorf("invalid version: %s", args[1])
+		}
+		err = migrator.Force(version)
+	case action == "status" && len(args) == 1:
+	default:
+		return usage
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+	return nil
+}
+
+// runDoctorCommand checks the deployment, printing a report of the checks.
+// Checks that depend on a failed check are skipped
+func runDoctorCommand(config *envs.Config) error {
+	var checks []types.Check
+	report := func(check types.Check) bool {
+		checks = append(checks, check)
+		fmt.Println(renderCheck(check))
+		return check.Err == nil
+	}
+	defer func() {
+		failed := slices.DeleteFunc(checks, func(check types.Check) bool { return check.Err == nil })
+		fmt.Printf("\n%d checks, %d failed\n", len(checks), len(failed))
+	}()
+
+	// ports are not checked to be free, as the server may be running
+	err := validateConfigPorts(config, func(port int) error {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port number %d (must be between 1-65535)", port)
+		}
+		return nil
+	})
+	if !report(types.Check{Name: "configuration", Err: err, Fix: "see orca -help for the settings"}) {
+		return errors.New("doctor found problems")
+	}
+
+	ctx := context.Background()
+	client, err := dlyrs.NewDatalayerClient(ctx, dlyrs.Platform(config.Platform), config.ConnectionString)
+	if err == nil {
+		err = client.Ping(ctx)
+	}
+	if !report(types.Check{
+		Name: "database connectivity",
+		Err:  err,
+		Fix:  "check that the database is running and reachable, and the user and password of ORCA_CONNECTION_STRING",
+	}) {
+		return errors.New("doctor found problems")
+	}
+
+	ok := report(types.Check{Name: "migration version", Err: dlyrs.CheckSchema(ctx, client, config.Platform)})
+	for _, check := range client.Diagnose(ctx) {
+		ok = report(check) && ok
+	}
+	if !ok {
+		return errors.New("doctor found problems")
+	}
+	return nil
 }
@@ -0,0 +1,905 @@
+// Package conformance checks that a datalayer behaves as datalayer.Datalayer
+// is documented to. Every datalayer, including those outside this module,
+// runs the suite from a test of its own, with a factory of empty datalayers
+package conformance
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// Factory returns an empty datalayer, ready to register processors with,
+// for each test of the suite
+type Factory func(t *testing.T) types.Datalayer
+
+// Run runs every test of the suite against datalayers of the factory
+func Run(t *testing.T, newDatalayer Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, dlyr types.Datalayer)
+	}{
+		{"CircularDependencies", testCircularDependencies},
+		{"MetadataFieldCompatibility", testMetadataFieldCompatibility},
+		{"AlgorithmUniquenessAcrossProcessors", testAlgorithmUniquenessAcrossProcessors},
+		{"LookbackResults", testLookbackResults},
+		{"EmptyOriginLookbackResults", testEmptyOriginLookbackResults},
+		{"CountLookbackAggregate", testCountLookbackAggregate},
+		{"RollupReleasesOnce", testRollupReleasesOnce},
+		{"ExposeRoundTrip", testExposeRoundTrip},
+		{"SharedWindowTypeSchedule", testSharedWindowTypeSchedule},
+		{"ScheduleChange", testScheduleChange},
+		{"NamespacedWindowTypes", testNamespacedWindowTypes},
+		{"NamespacelessWindow", testNamespacelessWindow},
+		{"Authorization", testAuthorization},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newDatalayer(t))
+		})
+	}
+}
+
+// minute returns the time the given number of minutes after the epoch
+func minute(m int) time.Time {
+	return time.Unix(int64(m)*60, 0).UTC()
+}
+
+// window returns a window of a minute, starting the given number of minutes
+// after the epoch
+func window(windowType *pb.WindowType, m int, origin string, metadata map[string]any) *pb.Window {
+	metadataPb, err := structpb.NewStruct(metadata)
+	if err != nil {
+		panic(err)
+	}
+	return &pb.Window{
+		TimeFrom:          timestamppb.New(minute(m)),
+		TimeTo:            timestamppb.New(minute(m + 1)),
+		WindowTypeName:    windowType.GetName(),
+		WindowTypeVersion: windowType.GetVersion(),
+		Origin:            origin,
+		Metadata:          metadataPb,
+	}
+}
+
+// exposedProcessor returns the exposed processor of the name, or nil
+func exposedProcessor(state *pb.InternalState, name string) *pb.ProcessorRegistration {
+	for _, proc := range state.GetProcessors() {
+		if proc.GetName() == name {
+			return proc
+		}
+	}
+	return nil
+}
+
+// testCircularDependencies checks that a dependency that closes a cycle is
+// rejected with a CircularDependencyError, leaving the registry unchanged
+func testCircularDependencies(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	windowType := &pb.WindowType{Name: "CycleWindow", Version: "1.0.0"}
+	algoA := &pb.Algorithm{
+		Name:       "AlgoA",
+		Version:    "1.0.0",
+		WindowType: windowType,
+		ResultType: pb.ResultType_VALUE,
+	}
+	algoB := &pb.Algorithm{
+		Name:       "AlgoB",
+		Version:    "1.0.0",
+		WindowType: windowType,
+		ResultType: pb.ResultType_VALUE,
+		Dependencies: []*pb.AlgorithmDependency{{
+			Name:             "AlgoA",
+			Version:          "1.0.0",
+			ProcessorName:    "CycleProcessor",
+			ProcessorRuntime: "go1.24",
+		}},
+	}
+	proc := &pb.ProcessorRegistration{
+		Name:                "CycleProcessor",
+		Runtime:             "go1.24",
+		ConnectionStr:       "127.0.0.1:1",
+		SupportedAlgorithms: []*pb.Algorithm{algoA, algoB},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, proc))
+
+	// an algorithm cannot depend on itself
+	selfDependent := proto.Clone(proc).(*pb.ProcessorRegistration)
+	selfDependent.SupportedAlgorithms[0].Dependencies = []*pb.AlgorithmDependency{{
+		Name:             "AlgoA",
+		Version:          "1.0.0",
+		ProcessorName:    "CycleProcessor",
+		ProcessorRuntime: "go1.24",
+	}}
+	var circularErr *types.CircularDependencyError
+	err := dlyr.RegisterProcessor(ctx, selfDependent)
+	assert.True(t, errors.As(err, &circularErr), "expected a circular dependency error, got %v", err)
+	assert.Equal(t, []string{"AlgoA"}, cycleNames(circularErr))
+
+	// nor on an algorithm that depends on it
+	cyclic := proto.Clone(proc).(*pb.ProcessorRegistration)
+	cyclic.SupportedAlgorithms[0].Dependencies = []*pb.AlgorithmDependency{{
+		Name:             "AlgoB",
+		Version:          "1.0.0",
+		ProcessorName:    "CycleProcessor",
+		ProcessorRuntime: "go1.24",
+	}}
+	err = dlyr.RegisterProcessor(ctx, cyclic)
+	assert.True(t, errors.As(err, &circularErr), "expected a circular dependency error, got %v", err)
+	// the dependency being registered closes the cycle
+	assert.Equal(t, "AlgoB", circularErr.FromAlgoName)
+	assert.Equal(t, "AlgoA", circularErr.ToAlgoName)
+	assert.Equal(t, []string{"AlgoB", "AlgoA"}, cycleNames(circularErr))
+
+	// nor on algorithms that depend on it through others of the same
+	// registration, the error naming all of them
+	chainAlgorithm := func(name, dependsOn string) *pb.Algorithm {
+		return &pb.Algorithm{
+			Name:       name,
+			Version:    "1.0.0",
+			WindowType: windowType,
+			ResultType: pb.ResultType_VALUE,
+			Dependencies: []*pb.AlgorithmDependency{{
+				Name:             dependsOn,
+				Version:          "1.0.0",
+				ProcessorName:    "CycleChainProcessor",
+				ProcessorRuntime: "go1.24",
+			}},
+		}
+	}
+	chain := &pb.ProcessorRegistration{
+		Name:          "CycleChainProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: "127.0.0.1:1",
+		SupportedAlgorithms: []*pb.Algorithm{
+			chainAlgorithm("AlgoX", "AlgoZ"),
+			chainAlgorithm("AlgoY", "AlgoX"),
+			chainAlgorithm("AlgoZ", "AlgoY"),
+		},
+	}
+	err = dlyr.RegisterProcessor(ctx, chain)
+	assert.True(t, errors.As(err, &circularErr), "expected a circular dependency error, got %v", err)
+	assert.ElementsMatch(t, []string{"AlgoX", "AlgoY", "AlgoZ"}, cycleNames(circularErr))
+	for _, algo := range circularErr.Cycle {
+		assert.Equal(t, "CycleChainProcessor", algo.Processor)
+	}
+
+	// a rejected registration changes nothing, so windows still trigger
+	// the algorithms in order
+	status, err := dlyr.EmitWindow(ctx, window(windowType, 0, "cycle", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+}
+
+// cycleNames returns the names of the algorithms of a circular dependency
+func cycleNames(err *types.CircularDependencyError) []string {
+	if err == nil {
+		return nil
+	}
+	names := make([]string, len(err.Cycle))
+	for ii, algo := range err.Cycle {
+		names[ii] = algo.Name
+	}
+	return names
+}
+
+// testMetadataFieldCompatibility checks that a window type keeps the
+// metadata fields it was first registered with, and that windows must carry
+// them
+func testMetadataFieldCompatibility(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	assetID := &pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
+	fleetID := &pb.MetadataField{Name: "fleet_id", Description: "Unique ID of the fleet"}
+	siteID := &pb.MetadataField{Name: "site_id", Description: "Unique ID of the site"}
+
+	register := func(version string, fields ...*pb.MetadataField) error {
+		windowType := &pb.WindowType{Name: "MetadataWindow", Version: version, MetadataFields: fields}
+		return dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+			Name:          "MetadataProcessor",
+			Runtime:       "go1.24",
+			ConnectionStr: "127.0.0.1:1",
+			SupportedAlgorithms: []*pb.Algorithm{{
+				Name:       "MetadataAlgo",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_NONE,
+			}},
+		})
+	}
+
+	assert.NoError(t, register("1.0.0", assetID, fleetID))
+	// the order of the fields does not matter
+	assert.NoError(t, register("1.0.0", fleetID, assetID))
+	// fields cannot be removed, added or swapped without a new version
+	assert.Error(t, register("1.0.0", assetID))
+	assert.Error(t, register("1.0.0", assetID, fleetID, siteID))
+	assert.Error(t, register("1.0.0", assetID, siteID))
+	assert.NoError(t, register("2.0.0", assetID, siteID))
+
+	// scheduled windows are emitted without metadata, so window types with
+	// metadata fields cannot be scheduled
+	err := dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "MetadataProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: "127.0.0.1:1",
+		SupportedAlgorithms: []*pb.Algorithm{{
+			Name:    "MetadataAlgo",
+			Version: "1.0.0",
+			WindowType: &pb.WindowType{
+				Name:           "MetadataWindow",
+				Version:        "2.0.0",
+				MetadataFields: []*pb.MetadataField{assetID, siteID},
+				Schedule: &pb.WindowSchedule{
+					Trigger: &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)},
+					Origin:  "scheduler",
+				},
+			},
+			ResultType: pb.ResultType_NONE,
+		}},
+	})
+	assert.Error(t, err)
+
+	windowType := &pb.WindowType{Name: "MetadataWindow", Version: "1.0.0"}
+	status, err := dlyr.EmitWindow(ctx, window(windowType, 0, "metadata", map[string]any{"asset_id": "a"}))
+	assert.Error(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_TRIGGERING_FAILED, status.GetStatus())
+
+	status, err = dlyr.EmitWindow(ctx, window(windowType, 0, "metadata", map[string]any{"asset_id": "a", "fleet_id": "f"}))
+	assert.NoError(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+
+	// windows of unregistered window types are rejected
+	unknown := &pb.WindowType{Name: "UnknownWindow", Version: "1.0.0"}
+	status, err = dlyr.EmitWindow(ctx, window(unknown, 0, "metadata", nil))
+	assert.Error(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_TRIGGERING_FAILED, status.GetStatus())
+}
+
+// testAlgorithmUniquenessAcrossProcessors checks that processors can serve
+// algorithms of the same name and version, which dependencies tell apart by
+// processor, and that registering a processor again does not duplicate it
+func testAlgorithmUniquenessAcrossProcessors(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	windowType := &pb.WindowType{Name: "SharedWindow", Version: "1.0.0"}
+	procWith := func(name string, algos ...*pb.Algorithm) *pb.ProcessorRegistration {
+		return &pb.ProcessorRegistration{
+			Name:                name,
+			Runtime:             "go1.24",
+			ConnectionStr:       "127.0.0.1:1",
+			SupportedAlgorithms: algos,
+		}
+	}
+	shared := func() *pb.Algorithm {
+		return &pb.Algorithm{
+			Name:       "SharedAlgo",
+			Version:    "1.0.0",
+			WindowType: windowType,
+			ResultType: pb.ResultType_VALUE,
+		}
+	}
+
+	procA := procWith("ProcessorA", shared())
+	procB := procWith("ProcessorB", shared(), &pb.Algorithm{
+		Name:       "DependantAlgo",
+		Version:    "1.0.0",
+		WindowType: windowType,
+		ResultType: pb.ResultType_VALUE,
+		Dependencies: []*pb.AlgorithmDependency{{
+			Name:             "SharedAlgo",
+			Version:          "1.0.0",
+			ProcessorName:    "ProcessorA",
+			ProcessorRuntime: "go1.24",
+		}},
+	})
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, procA))
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, procB))
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, procA))
+
+	// dependencies name the processor of the algorithm they depend on
+	assert.Error(t, dlyr.RegisterProcessor(ctx, procWith("ProcessorC", &pb.Algorithm{
+		Name:       "OrphanAlgo",
+		Version:    "1.0.0",
+		WindowType: windowType,
+		ResultType: pb.ResultType_VALUE,
+		Dependencies: []*pb.AlgorithmDependency{{
+			Name:             "SharedAlgo",
+			Version:          "1.0.0",
+			ProcessorName:    "ProcessorD",
+			ProcessorRuntime: "go1.24",
+		}},
+	})))
+
+	state, err := dlyr.Expose(ctx, &pb.ExposeSettings{})
+	assert.NoError(t, err)
+	assert.Len(t, state.GetProcessors(), 2)
+	for name, algorithms := range map[string][]string{
+		"ProcessorA": {"SharedAlgo"},
+		"ProcessorB": {"SharedAlgo", "DependantAlgo"},
+	} {
+		exposed := exposedProcessor(state, name)
+		if !assert.NotNil(t, exposed, name) {
+			continue
+		}
+		var names []string
+		for _, algo := range exposed.GetSupportedAlgorithms() {
+			names = append(names, algo.GetName())
+		}
+		assert.ElementsMatch(t, algorithms, names, name)
+	}
+}
+
+// testLookbackResults checks that a lookback passes the latest past results
+// of the window's partition, oldest first, after the result for the window
+func testLookbackResults(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	windowType := &pb.WindowType{Name: "LookbackWindow", Version: "1.0.0"}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "LookbackProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: addr,
+		SupportedAlgorithms: []*pb.Algorithm{
+			{
+				Name:       "Reading",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+			},
+			{
+				Name:       "Trend",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+				Dependencies: []*pb.AlgorithmDependency{{
+					Name:              "Reading",
+					Version:           "1.0.0",
+					ProcessorName:     "LookbackProcessor",
+					ProcessorRuntime:  "go1.24",
+					Lookback:          &pb.AlgorithmDependency_LookbackNum{LookbackNum: 2},
+					LookbackPartition: pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN,
+				}},
+			},
+		},
+	}))
+
+	// windows are processed one at a time, so each sees the results of
+	// the last. The window of another origin is outside the partition
+	origins := []string{"north", "north", "south", "north", "north"}
+	for m, origin := range origins {
+		status, err := dlyr.EmitWindow(ctx, window(windowType, m, origin, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+		proc.waitForExecution(t, "Trend", minute(m))
+	}
+
+	execution := proc.waitForExecution(t, "Trend", minute(len(origins)-1))
+	if !assert.Len(t, execution.GetDependencies(), 1) {
+		return
+	}
+	var values []float32
+	for _, row := range execution.GetDependencies()[0].GetResult() {
+		values = append(values, row.GetResult().GetSingleValue())
+		assert.Equal(t, "north", row.GetWindow().GetOrigin())
+		assert.Equal(t, windowType.GetName(), row.GetWindow().GetWindowTypeName())
+	}
+	// the two latest north windows, rather than the earliest at minutes 0
+	// and 1
+	assert.Equal(t, []float32{4, 1, 3}, values)
+}
+
+// testEmptyOriginLookbackResults checks that an empty origin is a partition
+// of its own, rather than one that sees the results of every origin
+func testEmptyOriginLookbackResults(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	windowType := &pb.WindowType{Name: "EmptyOriginWindow", Version: "1.0.0"}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "EmptyOriginProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: addr,
+		SupportedAlgorithms: []*pb.Algorithm{
+			{
+				Name:       "Reading",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+			},
+			{
+				Name:       "Trend",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+				Dependencies: []*pb.AlgorithmDependency{{
+					Name:              "Reading",
+					Version:           "1.0.0",
+					ProcessorName:     "EmptyOriginProcessor",
+					ProcessorRuntime:  "go1.24",
+					Lookback:          &pb.AlgorithmDependency_LookbackNum{LookbackNum: 2},
+					LookbackPartition: pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN,
+				}},
+			},
+		},
+	}))
+
+	origins := []string{"", "north", ""}
+	for m, origin := range origins {
+		status, err := dlyr.EmitWindow(ctx, window(windowType, m, origin, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+		proc.waitForExecution(t, "Trend", minute(m))
+	}
+
+	for m, expected := range map[int][]float32{1: {1}, 2: {2, 0}} {
+		execution := proc.waitForExecution(t, "Trend", minute(m))
+		if !assert.Len(t, execution.GetDependencies(), 1) {
+			continue
+		}
+		var values []float32
+		for _, row := range execution.GetDependencies()[0].GetResult() {
+			values = append(values, row.GetResult().GetSingleValue())
+			assert.Equal(t, origins[m], row.GetWindow().GetOrigin())
+		}
+		assert.ElementsMatch(t, expected, values, "window at minute %d", m)
+	}
+}
+
+// testCountLookbackAggregate checks that the aggregate of a count lookback
+// is over the latest past results, not the earliest
+func testCountLookbackAggregate(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	windowType := &pb.WindowType{Name: "AggregateWindow", Version: "1.0.0"}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "AggregateProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: addr,
+		SupportedAlgorithms: []*pb.Algorithm{
+			{
+				Name:       "Reading",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+			},
+			{
+				Name:       "Total",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_VALUE,
+				Dependencies: []*pb.AlgorithmDependency{{
+					Name:              "Reading",
+					Version:           "1.0.0",
+					ProcessorName:     "AggregateProcessor",
+					ProcessorRuntime:  "go1.24",
+					Lookback:          &pb.AlgorithmDependency_LookbackNum{LookbackNum: 2},
+					LookbackAggregate: &pb.LookbackAggregate{Function: pb.LookbackAggregate_FUNCTION_SUM},
+				}},
+			},
+		},
+	}))
+
+	for m := range 4 {
+		status, err := dlyr.EmitWindow(ctx, window(windowType, m, "north", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+		proc.waitForExecution(t, "Total", minute(m))
+	}
+
+	// the readings of minutes 1 and 2, rather than of minutes 0 and 1
+	execution := proc.waitForExecution(t, "Total", minute(3))
+	if !assert.Len(t, execution.GetDependencies(), 1) {
+		return
+	}
+	buckets := execution.GetDependencies()[0].GetLookbackAggregate()
+	if !assert.Len(t, buckets, 1) {
+		return
+	}
+	assert.Equal(t, uint64(2), buckets[0].GetCount())
+	assert.Equal(t, 3.0, buckets[0].GetValue())
+	assert.True(t, minute(1).Equal(buckets[0].GetTimeFrom().AsTime()), "time_from = %v", buckets[0].GetTimeFrom().AsTime())
+}
+
+// testRollupReleasesOnce checks that an emitted window frees its slots of
+// the concurrency limits once, when it has been processed, and not again
+// when the window rolled up from it has been
+func testRollupReleasesOnce(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	childWindowType := &pb.WindowType{Name: "ChildWindow", Version: "1.0.0"}
+	parentWindowType := &pb.WindowType{
+		Name:    "ParentWindow",
+		Version: "1.0.0",
+		Rollup: &pb.WindowRollup{
+			ChildWindowTypeName:    childWindowType.GetName(),
+			ChildWindowTypeVersion: childWindowType.GetVersion(),
+			Interval:               uint64(2 * time.Minute),
+			ChildCount:             1,
+		},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "RollupProcessor",
+		Runtime:       "go1.24",
+		ConnectionStr: addr,
+		SupportedAlgorithms: []*pb.Algorithm{
+			{Name: "Child", Version: "1.0.0", WindowType: childWindowType, ResultType: pb.ResultType_VALUE},
+			{Name: "Parent", Version: "1.0.0", WindowType: parentWindowType, ResultType: pb.ResultType_VALUE},
+		},
+	}))
+
+	var releases atomic.Int32
+	emitCtx := ratelimit.WithRelease(ctx, func() { releases.Add(1) })
+	status, err := dlyr.EmitWindow(emitCtx, window(childWindowType, 0, "north", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+	proc.waitForExecution(t, "Parent", minute(0))
+
+	assert.Eventually(t, func() bool { return releases.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return releases.Load() > 1 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+// testExposeRoundTrip checks that Expose returns processors as they were
+// registered
+func testExposeRoundTrip(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	minuteWindow := &pb.WindowType{
+		Name:        "ExposeMinute",
+		Version:     "1.0.0",
+		Description: "A minute of readings",
+		MetadataFields: []*pb.MetadataField{
+			{Name: "expose_asset", Description: "Unique ID of the asset"},
+			{Name: "expose_fleet", Description: "Unique ID of the fleet"},
+		},
+	}
+	tickWindow := &pb.WindowType{
+		Name:        "ExposeTick",
+		Version:     "1.0.0",
+		Description: "A scheduled minute",
+		Schedule: &pb.WindowSchedule{
+			Trigger:  &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)},
+			TimeZone: "Europe/London",
+			Origin:   "scheduler",
+			CatchUp:  true,
+		},
+	}
+	hourWindow := &pb.WindowType{
+		Name:        "ExposeHour",
+		Version:     "1.0.0",
+		Description: "An hour of readings",
+		MetadataFields: []*pb.MetadataField{
+			{Name: "expose_asset", Description: "Unique ID of the asset"},
+			{Name: "expose_fleet", Description: "Unique ID of the fleet"},
+		},
+		Rollup: &pb.WindowRollup{
+			ChildWindowTypeName:    "ExposeMinute",
+			ChildWindowTypeVersion: "1.0.0",
+			Interval:               uint64(time.Hour),
+			ChildCount:             60,
+			LateWindows:            pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT,
+		},
+	}
+	registered := &pb.ProcessorRegistration{
+		Name:          "ExposeProcessor",
+		Runtime:       "python3.12",
+		ConnectionStr: "127.0.0.1:1",
+		ProjectName:   "expose",
+		SupportedAlgorithms: []*pb.Algorithm{
+			{
+				Name:        "ExposeValue",
+				Version:     "1.0.0",
+				Description: "A value",
+				WindowType:  minuteWindow,
+				ResultType:  pb.ResultType_VALUE,
+			},
+			{
+				Name:        "ExposeStruct",
+				Version:     "2.1.0",
+				Description: "A struct",
+				WindowType:  hourWindow,
+				ResultType:  pb.ResultType_STRUCT,
+			},
+			{
+				Name:        "ExposeArray",
+				Version:     "1.0.3",
+				Description: "An array",
+				WindowType:  tickWindow,
+				ResultType:  pb.ResultType_ARRAY,
+			},
+		},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, registered))
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+		Name:          "OtherProcessor",
+		Runtime:       "python3.12",
+		ConnectionStr: "127.0.0.1:1",
+		ProjectName:   "other",
+	}))
+
+	state, err := dlyr.Expose(ctx, &pb.ExposeSettings{Namespace: "expose"})
+	assert.NoError(t, err)
+	if !assert.Len(t, state.GetProcessors(), 1) {
+		return
+	}
+	exposed := state.GetProcessors()[0]
+	assert.Equal(t, registered.GetName(), exposed.GetName())
+	assert.Equal(t, registered.GetRuntime(), exposed.GetRuntime())
+	assert.Equal(t, registered.GetProjectName(), exposed.GetProjectName())
+	assert.Len(t, exposed.GetSupportedAlgorithms(), len(registered.GetSupportedAlgorithms()))
+	for _, algo := range registered.GetSupportedAlgorithms() {
+		var exposedAlgo *pb.Algorithm
+		for _, candidate := range exposed.GetSupportedAlgorithms() {
+			if candidate.GetName() == algo.GetName() {
+				exposedAlgo = candidate
+			}
+		}
+		if !assert.NotNil(t, exposedAlgo, algo.GetName()) {
+			continue
+		}
+		assert.Equal(t, algo.GetVersion(), exposedAlgo.GetVersion())
+		assert.Equal(t, algo.GetDescription(), exposedAlgo.GetDescription())
+		assert.Equal(t, algo.GetResultType(), exposedAlgo.GetResultType())
+
+		// metadata fields are a set, in no particular order
+		exposedWindowType := exposedAlgo.GetWindowType()
+		windowType := algo.GetWindowType()
+		assert.Equal(t, windowType.GetName(), exposedWindowType.GetName())
+		assert.Equal(t, windowType.GetVersion(), exposedWindowType.GetVersion())
+		assert.Equal(t, windowType.GetDescription(), exposedWindowType.GetDescription())
+		assert.ElementsMatch(t, fieldNames(windowType), fieldNames(exposedWindowType))
+		assert.True(t, proto.Equal(windowType.GetSchedule(), exposedWindowType.GetSchedule()), algo.GetName())
+		assert.True(t, proto.Equal(windowType.GetRollup(), exposedWindowType.GetRollup()), algo.GetName())
+	}
+
+	// processors of other namespaces are exposed without the namespace
+	state, err = dlyr.Expose(ctx, &pb.ExposeSettings{ExcludeProject: "expose"})
+	assert.NoError(t, err)
+	assert.Nil(t, exposedProcessor(state, registered.GetName()))
+	assert.NotNil(t, exposedProcessor(state, "OtherProcessor"))
+}
+
+// testSharedWindowTypeSchedule checks that the schedule of a window type is
+// kept when a processor sharing it registers without one, and only removed
+// when a registration asks to
+func testSharedWindowTypeSchedule(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	register := func(procName string, windowType *pb.WindowType) {
+		t.Helper()
+		assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+			Name:          procName,
+			Runtime:       "go1.24",
+			ConnectionStr: "127.0.0.1:1",
+			SupportedAlgorithms: []*pb.Algorithm{{
+				Name:       procName + "Algo",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_NONE,
+			}},
+		}))
+	}
+	// exposedSchedule returns the schedule of the window type, as exposed
+	// through the algorithm of the first processor
+	exposedSchedule := func() *pb.WindowSchedule {
+		t.Helper()
+		state, err := dlyr.Expose(ctx, &pb.ExposeSettings{})
+		assert.NoError(t, err)
+		proc := exposedProcessor(state, "ScheduleOwner")
+		if !assert.NotNil(t, proc) || !assert.Len(t, proc.GetSupportedAlgorithms(), 1) {
+			return nil
+		}
+		return proc.GetSupportedAlgorithms()[0].GetWindowType().GetSchedule()
+	}
+
+	windowSchedule := &pb.WindowSchedule{
+		Trigger: &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)},
+		Origin:  "scheduler",
+	}
+	register("ScheduleOwner", &pb.WindowType{Name: "SharedWindow", Version: "1.0.0", Schedule: windowSchedule})
+	register("ScheduleSharer", &pb.WindowType{Name: "SharedWindow", Version: "1.0.0"})
+	assert.True(t, proto.Equal(windowSchedule, exposedSchedule()))
+
+	register("ScheduleSharer", &pb.WindowType{Name: "SharedWindow", Version: "1.0.0", RemoveSchedule: true})
+	assert.Nil(t, exposedSchedule())
+}
+
+// testScheduleChange checks that the windows of a schedule keep going from
+// where they were when a window type is registered again, and start afresh
+// when the times the schedule fires at change
+func testScheduleChange(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	register := func(windowSchedule *pb.WindowSchedule) {
+		t.Helper()
+		assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+			Name:          "ScheduleProcessor",
+			Runtime:       "go1.24",
+			ConnectionStr: addr,
+			SupportedAlgorithms: []*pb.Algorithm{{
+				Name:       "Scheduled",
+				Version:    "1.0.0",
+				WindowType: &pb.WindowType{Name: "ChangingWindow", Version: "1.0.0", Schedule: windowSchedule},
+				ResultType: pb.ResultType_VALUE,
+			}},
+		}))
+	}
+
+	// a yearly schedule has no window due for months, unless at new year
+	yearly := &pb.WindowSchedule{Trigger: &pb.WindowSchedule_Cron{Cron: "0 0 1 1 *"}, Origin: "scheduler"}
+	register(yearly)
+	// the first minute window of the changed schedule starts at the next
+	// minute, so one closes within two minutes
+	register(&pb.WindowSchedule{Trigger: &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)}, Origin: "scheduler"})
+	now := time.Now().UTC().Add(3 * time.Minute)
+	assert.NoError(t, dlyr.EmitScheduledWindows(ctx, now))
+	proc.waitForExecution(t, "Scheduled", now.Truncate(time.Minute).Add(-time.Minute))
+
+	// registering the same schedule again keeps its windows going, so the
+	// window that was emitted is not emitted again
+	register(&pb.WindowSchedule{Trigger: &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)}, Origin: "scheduler"})
+	assert.NoError(t, dlyr.EmitScheduledWindows(ctx, now))
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	assert.Len(t, proc.requests, 1)
+}
+
+// testNamespacedWindowTypes checks that projects register window types of the
+// same name and version with their own metadata fields and schedules
+func testNamespacedWindowTypes(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	register := func(project string, windowType *pb.WindowType) {
+		t.Helper()
+		assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+			Name:          "ProjectProcessor",
+			Runtime:       "go1.24",
+			ConnectionStr: "127.0.0.1:1",
+			ProjectName:   project,
+			SupportedAlgorithms: []*pb.Algorithm{{
+				Name:       "ProjectAlgo",
+				Version:    "1.0.0",
+				WindowType: windowType,
+				ResultType: pb.ResultType_NONE,
+			}},
+		}))
+	}
+	// exposedWindowType returns the window type of the algorithm of the
+	// project, as exposed
+	exposedWindowType := func(project string) *pb.WindowType {
+		t.Helper()
+		state, err := dlyr.Expose(ctx, &pb.ExposeSettings{Namespace: project})
+		assert.NoError(t, err)
+		proc := exposedProcessor(state, "ProjectProcessor")
+		if !assert.NotNil(t, proc) || !assert.Len(t, proc.GetSupportedAlgorithms(), 1) {
+			return nil
+		}
+		return proc.GetSupportedAlgorithms()[0].GetWindowType()
+	}
+
+	windowSchedule := &pb.WindowSchedule{
+		Trigger: &pb.WindowSchedule_Interval{Interval: uint64(time.Minute)},
+		Origin:  "scheduler",
+	}
+	tagged := &pb.WindowType{
+		Name:           "ProjectWindow",
+		Version:        "1.0.0",
+		MetadataFields: []*pb.MetadataField{{Name: "asset_id", Description: "Asset"}},
+	}
+	scheduled := &pb.WindowType{Name: "ProjectWindow", Version: "1.0.0", Schedule: windowSchedule}
+	register("project-a", tagged)
+	register("project-b", scheduled)
+
+	assert.Equal(t, []string{"asset_id: Asset"}, fieldNames(exposedWindowType("project-a")))
+	assert.Nil(t, exposedWindowType("project-a").GetSchedule())
+	assert.Empty(t, fieldNames(exposedWindowType("project-b")))
+	assert.True(t, proto.Equal(windowSchedule, exposedWindowType("project-b").GetSchedule()))
+
+	// windows are checked against the window type of their namespace, and
+	// windows without a namespace against that of every namespace
+	untagged := window(scheduled, 0, "test", nil)
+	untagged.Namespace = "project-b"
+	_, err := dlyr.EmitWindow(ctx, untagged)
+	assert.NoError(t, err)
+	untagged.Namespace = "project-a"
+	_, err = dlyr.EmitWindow(ctx, untagged)
+	assert.Error(t, err)
+	// windows without a namespace are ambiguous when their window type is
+	// in several namespaces, none of them the default
+	untagged.Namespace = ""
+	_, err = dlyr.EmitWindow(ctx, untagged)
+	assert.ErrorIs(t, err, types.AmbiguousNamespace)
+}
+
+// testNamespacelessWindow checks that a window without a namespace only
+// triggers the algorithms of the default namespace, even when other
+// namespaces have a window type of the same name
+func testNamespacelessWindow(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	proc, addr := startProcessor(t)
+	windowType := &pb.WindowType{Name: "SharedWindow", Version: "1.0.0"}
+	register := func(project, algorithm string) {
+		t.Helper()
+		assert.NoError(t, dlyr.RegisterProcessor(ctx, &pb.ProcessorRegistration{
+			Name:          "NamespaceProcessor",
+			Runtime:       "go1.24",
+			ConnectionStr: addr,
+			ProjectName:   project,
+			SupportedAlgorithms: []*pb.Algorithm{
+				{Name: algorithm, Version: "1.0.0", WindowType: windowType, ResultType: pb.ResultType_VALUE},
+			},
+		}))
+	}
+	register("", "DefaultAlgo")
+	register("project-b", "ProjectAlgo")
+
+	status, err := dlyr.EmitWindow(ctx, window(windowType, 0, "north", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, status.GetStatus())
+	proc.waitForExecution(t, "DefaultAlgo", minute(0))
+	assert.Never(t, func() bool { return proc.ran("ProjectAlgo") }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+// fieldNames returns the names and descriptions of the metadata fields of a
+// window type
+func fieldNames(windowType *pb.WindowType) []string {
+	var names []string
+	for _, field := range windowType.GetMetadataFields() {
+		names = append(names, field.GetName()+": "+field.GetDescription())
+	}
+	return names
+}
+
+// testAuthorization checks that the datalayer only lets callers register,
+// emit and expose within the namespaces and window types of their roles
+func testAuthorization(t *testing.T, dlyr types.Datalayer) {
+	ctx := context.Background()
+	windowType := &pb.WindowType{Name: "SharedWindow", Version: "1.0.0"}
+	registration := func(project string) *pb.ProcessorRegistration {
+		return &pb.ProcessorRegistration{
+			Name:          "AuthProcessor",
+			Runtime:       "go1.24",
+			ConnectionStr: "127.0.0.1:1",
+			ProjectName:   project,
+			SupportedAlgorithms: []*pb.Algorithm{
+				{Name: "AuthAlgo", Version: "1.0.0", WindowType: windowType, ResultType: pb.ResultType_NONE},
+			},
+		}
+	}
+	caller := auth.WithIdentity(ctx, auth.Identity{Name: "team-a", Method: auth.MethodAPIKey, Grants: []types.Grant{
+		{Role: auth.RoleProcessor, Scope: "project-a"},
+		{Role: auth.RoleEmitter, Scope: auth.WindowTypeScope("project-a", windowType.GetName())},
+		{Role: auth.RoleReader, Scope: "project-a"},
+	}})
+	denied := func(err error) {
+		t.Helper()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), "error = %v", err)
+	}
+
+	assert.NoError(t, dlyr.RegisterProcessor(caller, registration("project-a")))
+	denied(dlyr.RegisterProcessor(caller, registration("project-b")))
+	assert.NoError(t, dlyr.RegisterProcessor(ctx, registration("project-b")))
+
+	tagged := window(windowType, 0, "north", nil)
+	tagged.Namespace = "project-a"
+	_, err := dlyr.EmitWindow(caller, tagged)
+	assert.NoError(t, err)
+	tagged.Namespace = "project-b"
+	_, err = dlyr.EmitWindow(caller, tagged)
+	denied(err)
+
+	_, err = dlyr.Expose(caller, &pb.ExposeSettings{Namespace: "project-a"})
+	assert.NoError(t, err)
+	_, err = dlyr.Expose(caller, &pb.ExposeSettings{Namespace: "project-b"})
+	denied(err)
+	_, err = dlyr.Expose(caller, &pb.ExposeSettings{})
+	denied(err)
+}
@@ -0,0 +1,109 @@
+package conformance
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// processor is an OrcaProcessor that records the requests it is sent. It
+// returns a value result for every algorithm, of the minutes since the
+// epoch at the start of the window, so results can be told apart
+type processor struct {
+	pb.UnimplementedOrcaProcessorServer
+
+	mu       sync.Mutex
+	requests []*pb.ExecutionRequest
+}
+
+// startProcessor serves a processor on a free port until the test ends,
+// returning it and its address
+func startProcessor(t *testing.T) (*processor, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen for the processor: %v", err)
+	}
+	proc := &processor{}
+	server := grpc.NewServer()
+	pb.RegisterOrcaProcessorServer(server, proc)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return proc, lis.Addr().String()
+}
+
+func (p *processor) ExecuteDagPart(req *pb.ExecutionRequest, stream pb.OrcaProcessor_ExecuteDagPartServer) error {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	p.mu.Unlock()
+
+	for _, execution := range req.GetAlgorithmExecutions() {
+		err := stream.Send(&pb.ExecutionResult{
+			ExecId: req.GetExecId(),
+			AlgorithmResult: &pb.AlgorithmResult{
+				Algorithm: execution.GetAlgorithm(),
+				Result: &pb.Result{
+					Status: pb.ResultStatus_RESULT_STATUS_SUCEEDED,
+					ResultData: &pb.Result_SingleValue{
+						SingleValue: float32(req.GetWindow().GetTimeFrom().GetSeconds() / 60),
+					},
+					Timestamp: time.Now().Unix(),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *processor) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	return &pb.HealthCheckResponse{Status: pb.HealthCheckResponse_STATUS_SERVING}, nil
+}
+
+// waitForExecution waits for the processor to be asked to run an algorithm
+// for the window starting at the given time, returning the execution
+func (p *processor) waitForExecution(t *testing.T, algorithm string, timeFrom time.Time) *pb.ExecuteAlgorithm {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.Lock()
+		for _, req := range p.requests {
+			if !req.GetWindow().GetTimeFrom().AsTime().Equal(timeFrom) {
+				continue
+			}
+			for _, execution := range req.GetAlgorithmExecutions() {
+				if execution.GetAlgorithm().GetName() == algorithm {
+					p.mu.Unlock()
+					return execution
+				}
+			}
+		}
+		p.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("processor was not asked to run %s for the window starting at %v", algorithm, timeFrom)
+	return nil
+}
+
+// ran reports whether the processor has been asked to run an algorithm, for
+// any window
+func (p *processor) ran(algorithm string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, req := range p.requests {
+		for _, execution := range req.GetAlgorithmExecutions() {
+			if execution.GetAlgorithm().GetName() == algorithm {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+// Package datalayer defines the interface of the stores Orca core keeps its
+// processors, windows and results in, along with the values and errors of
+// their documented behaviour. Datalayers outside this module implement it
+// too, and check themselves against it with the conformance suite
+package datalayer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// the interface that all datalayers must implement to be compatible with Orca
+type (
+	Tx interface {
+		Rollback(ctx context.Context)
+		Commit(ctx context.Context) error
+	}
+	Datalayer interface {
+		WithTx(ctx context.Context) (Tx, error)
+
+		// Core level operations
+		RegisterProcessor(ctx context.Context, proc *pb.ProcessorRegistration) error
+		EmitWindow(ctx context.Context, window *pb.Window) (pb.WindowEmitStatus, error)
+		Expose(ctx context.Context, settings *pb.ExposeSettings) (*pb.InternalState, error)
+
+		// Scheduled window emission
+		EmitScheduledWindows(ctx context.Context, now time.Time) error
+
+		// Readiness
+		Ping(ctx context.Context) error
+		// SchemaVersion returns the version of the applied migrations, and
+		// whether a migration failed part way. Version 0 is an unmigrated store
+		SchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
+
+		// API keys, stored by the hash of the key
+		CreateAPIKey(ctx context.Context, name string, keyHash string) error
+		RevokeAPIKey(ctx context.Context, name string) error
+		// LookupAPIKey returns the name of the unrevoked key with the given
+		// hash, or APIKeyNotFound
+		LookupAPIKey(ctx context.Context, keyHash string) (string, error)
+
+		// Roles granted to authenticated callers, by their identity
+		CreateGrant(ctx context.Context, identity string, grant Grant) error
+		DeleteGrant(ctx context.Context, identity string, grant Grant) error
+		ReadGrants(ctx context.Context, identity string) ([]Grant, error)
+
+		// Diagnose checks the setup of the store, such as its permissions and
+		// extensions, and that every registered processor is reachable, for
+		// orca doctor
+		Diagnose(ctx context.Context) []Check
+	}
+
+	// Grant is a role held by a caller within a scope: the project of a
+	// processor, or the window type an emitter emits. ScopeAll covers every
+	// scope
+	Grant struct {
+		Role  string
+		Scope string
+	}
+
+	// Check is the outcome of one check of a deployment
+	Check struct {
+		Name string
+		// Err is why the check failed, nil when it passed
+		Err error
+		// Fix describes how to fix a failed check
+		Fix string
+	}
+)
+
+// ScopeAll is the scope of a grant that is not limited to a project or
+// window type
+const ScopeAll = "*"
+
+// DefaultNamespace holds the processors registered without a project
+const DefaultNamespace = "default"
+
+// Namespace returns the namespace of a project, which is the project itself
+// unless it is empty
+func Namespace(project string) string {
+	if project == "" {
+		return DefaultNamespace
+	}
+	return project
+}
+
+// WindowNamespace returns the namespace of a window emitted without one,
+// given the namespaces with its window type: the default namespace when it
+// has the window type, or else the only namespace that does. Windows whose
+// window type is in several other namespaces are ambiguous
+func WindowNamespace(namespaces []string) (string, error) {
+	if len(namespaces) == 0 || slices.Contains(namespaces, DefaultNamespace) {
+		return DefaultNamespace, nil
+	}
+	if len(namespaces) > 1 {
+		return "", fmt.Errorf(
+			"%w: window type is in namespaces %v, none of them the default, name one",
+			AmbiguousNamespace, namespaces,
+		)
+	}
+	return namespaces[0], nil
+}
+
+// custom errors
+var (
+	AlgorithmExistsUnderDifferentProcessor = fmt.Errorf(
+		"algorithm exists under a different processor",
+	)
+	APIKeyNotFound = fmt.Errorf("API key not found")
+	GrantNotFound  = fmt.Errorf("grant not found")
+	// AmbiguousNamespace is returned for calls without a namespace that
+	// could be of several namespaces
+	AmbiguousNamespace = fmt.Errorf("namespace is ambiguous")
+)
+
+// CycleAlgorithm is an algorithm of a circular dependency
+type CycleAlgorithm struct {
+	Name      string
+	Version   string
+	Processor string
+}
+
+// CircularDependencyError is returned when registering a processor would
+// make algorithms depend on themselves. From and To are the algorithms of
+// the dependency that closed the cycle, To depending on From
+type CircularDependencyError struct {
+	FromAlgoName      string
+	ToAlgoName        string
+	FromAlgoVersion   string
+	ToAlgoVersion     string
+	FromAlgoProcessor string
+	ToAlgoProcessor   string
+	// every algorithm of the cycle, starting with From and To. Each
+	// algorithm depends on the one before it, and the first on the last
+	Cycle []CycleAlgorithm
+}
+
+// NewCircularDependencyError returns the error of a cycle of algorithms,
+// given in the order of CircularDependencyError.Cycle
+func NewCircularDependencyError(cycle []CycleAlgorithm) *CircularDependencyError {
+	from, to := cycle[0], cycle[1%len(cycle)]
+	return &CircularDependencyError{
+		FromAlgoName:      from.Name,
+		ToAlgoName:        to.Name,
+		FromAlgoVersion:   from.Version,
+		ToAlgoVersion:     to.Version,
+		FromAlgoProcessor: from.Processor,
+		ToAlgoProcessor:   to.Processor,
+		Cycle:             cycle,
+	}
+}
+
+func (c *CircularDependencyError) Error() string {
+	msg := fmt.Sprintf(
+		"Circular dependency introduced between algorithm %s to %s, with versions %s and %s, of processor(s) %s and %s respectively.",
+		c.FromAlgoName,
+		c.ToAlgoName,
+		c.FromAlgoVersion,
+		c.ToAlgoVersion,
+		c.FromAlgoProcessor,
+		c.ToAlgoProcessor,
+	)
+	if len(c.Cycle) == 0 {
+		return msg
+	}
+	path := make([]string, 0, len(c.Cycle)+1)
+	for _, algo := range c.Cycle {
+		path = append(path, fmt.Sprintf("%s %s of %s", algo.Name, algo.Version, algo.Processor))
+	}
+	path = append(path, path[0])
+	return fmt.Sprintf("%s The cycle is %s, each depended on by the next.", msg, strings.Join(path, " -> "))
+}
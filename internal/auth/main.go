@@ -0,0 +1,450 @@
+// Package auth authenticates the clients of Orca core, by API key or by
+// client certificate, and carries the identity of the caller through the
+// context of its requests.
+//
+// Callers are authorized by the roles granted to their identity:
+//   - admin can make any call
+//   - processor can register processors of the namespace it is scoped to
+//   - emitter can emit windows of the window type and namespace it is
+//     scoped to, e.g. team-a/Hourly
+//   - reader can call Expose for the namespace it is scoped to
+//
+// Static API keys are admins
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authentication methods
+const (
+	MethodAPIKey     = "api_key"
+	MethodClientCert = "client_cert"
+	MethodWebhook    = "webhook"
+)
+
+// metadata keys an API key can be sent in
+const (
+	AuthorizationHeader = "authorization"
+	APIKeyHeader        = "x-api-key"
+)
+
+// roles that can be granted to an identity
+const (
+	RoleAdmin     = "admin"
+	RoleProcessor = "processor"
+	RoleEmitter   = "emitter"
+	RoleReader    = "reader"
+)
+
+// Roles lists the roles that can be granted
+var Roles = []string{RoleAdmin, RoleProcessor, RoleEmitter, RoleReader}
+
+// ScopedRoles lists the roles granted within a namespace, or a window type of
+// a namespace for emitters. The other roles are always granted for every
+// scope
+var ScopedRoles = []string{RoleProcessor, RoleEmitter, RoleReader}
+
+// KeyPrefix starts every generated API key, so that leaked keys are easy to
+// search for
+const KeyPrefix = "orca_"
+
+// services that can be called without authenticating, so that orchestrators
+// can probe readiness and tooling can list the services
+var publicServices = []string{
+	"grpc.health.v1.Health",
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+// Identity is the authenticated caller of a request, and the roles granted
+// to it
+type Identity struct {
+	Name   string
+	Method string
+	Grants []types.Grant
+}
+
+// String returns the identity as recorded against windows and processors,
+// e.g. api_key:ci-pipeline
+func (i Identity) String() string {
+	return i.Method + ":" + i.Name
+}
+
+// Has reports whether the identity holds the role within the scope. Admins
+// hold every role
+func (i Identity) Has(role string, scope string) bool {
+	return slices.ContainsFunc(i.Grants, func(grant types.Grant) bool {
+		if grant.Role == RoleAdmin {
+			return true
+		}
+		return grant.Role == role && covers(grant.Scope, scope)
+	})
+}
+
+// NamespaceScope returns the scope of a call for the namespace, or for every
+// namespace when it is empty
+func NamespaceScope(namespace string) string {
+	if namespace == "" {
+		return types.ScopeAll
+	}
+	return namespace
+}
+
+// WindowTypeScope returns the scope of emitting windows of the window type
+// in the namespace, e.g. team-a/Hourly. Either can be * for every namespace
+// or window type
+func WindowTypeScope(namespace string, windowType string) string {
+	return namespace + "/" + windowType
+}
+
+// covers reports whether a grant of the scope holds within another scope
+func covers(grantScope string, scope string) bool {
+	if grantScope == types.ScopeAll || grantScope == scope {
+		return true
+	}
+	grantNamespace, grantWindowType, ok := strings.Cut(grantScope, "/")
+	namespace, windowType, scoped := strings.Cut(scope, "/")
+	if !ok || !scoped {
+		return false
+	}
+	return (grantNamespace == types.ScopeAll || grantNamespace == namespace) &&
+		(grantWindowType == types.ScopeAll || grantWindowType == windowType)
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context carrying the identity of the caller
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	ctx = logging.With(ctx, "caller", identity.String())
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the caller, if it was authenticated
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Caller returns the identity of the caller as a string, or an empty string
+// when the request was not authenticated
+func Caller(ctx context.Context) string {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return identity.String()
+}
+
+// Store looks up the API keys and grants kept in the datalayer
+type Store interface {
+	// LookupAPIKey returns the name of the unrevoked key with the given hash,
+	// or types.APIKeyNotFound
+	LookupAPIKey(ctx context.Context, keyHash string) (string, error)
+	ReadGrants(ctx context.Context, identity string) ([]types.Grant, error)
+}
+
+// Require returns a PermissionDenied error unless the caller holds the role
+// within the scope. Calls without an identity are made internally, or with
+// authentication disabled, and are allowed
+func Require(ctx context.Context, role string, scope string) error {
+	identity, ok := FromContext(ctx)
+	if !ok || identity.Has(role, scope) {
+		return nil
+	}
+	if scope == types.ScopeAll {
+		return status.Errorf(codes.PermissionDenied, "%v does not hold the %s role", identity, role)
+	}
+	return status.Errorf(codes.PermissionDenied, "%v does not hold the %s role for %s", identity, role, scope)
+}
+
+// GrantedNamespace returns the only namespace the caller holds the role in,
+// for calls that leave out their namespace. Emitters are only counted for
+// the window type. It is empty when the caller holds the role in every
+// namespace, or was not authenticated, and types.AmbiguousNamespace when it
+// holds the role in several
+func GrantedNamespace(ctx context.Context, role string, windowType string) (string, error) {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	var namespaces []string
+	for _, grant := range identity.Grants {
+		if grant.Role == RoleAdmin || (grant.Role == role && grant.Scope == types.ScopeAll) {
+			return "", nil
+		}
+		if grant.Role != role {
+			continue
+		}
+		namespace := grant.Scope
+		if role == RoleEmitter {
+			var grantWindowType string
+			namespace, grantWindowType, _ = strings.Cut(grant.Scope, "/")
+			if grantWindowType != types.ScopeAll && grantWindowType != windowType {
+				continue
+			}
+		}
+		if namespace == types.ScopeAll {
+			return "", nil
+		}
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) > 1 {
+		slices.Sort(namespaces)
+		return "", fmt.Errorf(
+			"%w: %v holds the %s role in namespaces %v, name one",
+			types.AmbiguousNamespace, identity, role, namespaces,
+		)
+	}
+	if len(namespaces) == 1 {
+		return namespaces[0], nil
+	}
+	return "", nil
+}
+
+// ValidateGrant checks that the role exists, that only scoped roles are
+// limited to a scope, and that emitters are scoped to a window type of a
+// namespace
+func ValidateGrant(grant types.Grant) error {
+	if !slices.Contains(Roles, grant.Role) {
+		return fmt.Errorf("unknown role: %s. Must be one of: %s", grant.Role, strings.Join(Roles, ", "))
+	}
+	if grant.Scope == "" {
+		return fmt.Errorf("the scope of a grant cannot be empty")
+	}
+	if grant.Scope == types.ScopeAll {
+		return nil
+	}
+	if !slices.Contains(ScopedRoles, grant.Role) {
+		return fmt.Errorf("the %s role cannot be limited to a scope", grant.Role)
+	}
+	namespace, windowType, ok := strings.Cut(grant.Scope, "/")
+	if grant.Role == RoleEmitter && (!ok || namespace == "" || windowType == "") {
+		return fmt.Errorf("the emitter role must be scoped to <namespace>/<window type>, either of which can be *")
+	}
+	if grant.Role != RoleEmitter && ok {
+		return fmt.Errorf("the %s role must be scoped to a namespace", grant.Role)
+	}
+	return nil
+}
+
+// GenerateKey returns a new random API key
+func GenerateKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("could not generate API key: %w", err)
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashKey returns the hash an API key is stored and looked up by
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseStaticKeys parses a comma separated list of name:key pairs, as set in
+// ORCA_API_KEYS, into a map of key names by key
+func ParseStaticKeys(s string) (map[string]string, error) {
+	keys := make(map[string]string)
+	names := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(pair, ":")
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("API key must be given as name:key")
+		}
+		if names[name] {
+			return nil, fmt.Errorf("API key name %s is used more than once", name)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("API key of %s is used more than once", name)
+		}
+		names[name] = true
+		keys[key] = name
+	}
+	return keys, nil
+}
+
+// Authenticator identifies the callers of OrcaCore, by the API key sent in
+// the request metadata or else by a verified client certificate
+type Authenticator struct {
+	// names of the static keys, by key hash
+	staticKeys map[string]string
+	store      Store
+}
+
+// NewAuthenticator returns an authenticator accepting the static keys, given
+// as names by key, and the keys in the store. The store can be nil
+func NewAuthenticator(staticKeys map[string]string, store Store) *Authenticator {
+	hashed := make(map[string]string, len(staticKeys))
+	for key, name := range staticKeys {
+		hashed[HashKey(key)] = name
+	}
+	return &Authenticator{staticKeys: hashed, store: store}
+}
+
+// Authenticate identifies the caller of the request in the context, along
+// with the roles granted to it. An API key takes precedence over a client
+// certificate when both are presented
+func (a *Authenticator) Authenticate(ctx context.Context) (Identity, error) {
+	var identity Identity
+	if key := apiKey(ctx); key != "" {
+		var err error
+		identity, err = a.authenticateKey(ctx, key)
+		if err != nil {
+			return Identity{}, err
+		}
+	} else if name := clientCertName(ctx); name != "" {
+		identity = Identity{Name: name, Method: MethodClientCert}
+	} else {
+		return Identity{}, status.Error(codes.Unauthenticated, "an API key or client certificate is required")
+	}
+
+	if a.store == nil {
+		return identity, nil
+	}
+	grants, err := a.store.ReadGrants(ctx, identity.String())
+	if err != nil {
+		slog.ErrorContext(ctx, "could not read grants", "caller", identity.String(), "error", err)
+		return Identity{}, status.Error(codes.Unavailable, "could not read grants")
+	}
+	identity.Grants = append(identity.Grants, grants...)
+	return identity, nil
+}
+
+func (a *Authenticator) authenticateKey(ctx context.Context, key string) (Identity, error) {
+	keyHash := HashKey(key)
+	// static keys are set by whoever deploys Orca core, so are admins
+	if name, ok := a.staticKeys[keyHash]; ok {
+		return Identity{
+			Name:   name,
+			Method: MethodAPIKey,
+			Grants: []types.Grant{{Role: RoleAdmin, Scope: types.ScopeAll}},
+		}, nil
+	}
+	if a.store == nil {
+		return Identity{}, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	name, err := a.store.LookupAPIKey(ctx, keyHash)
+	if errors.Is(err, types.APIKeyNotFound) {
+		return Identity{}, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "could not look up API key", "error", err)
+		return Identity{}, status.Error(codes.Unavailable, "could not look up API key")
+	}
+	return Identity{Name: name, Method: MethodAPIKey}, nil
+}
+
+// apiKey returns the API key sent as a bearer token or in x-api-key
+func apiKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get(AuthorizationHeader) {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	if values := md.Get(APIKeyHeader); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// clientCertName returns the common name, or else the first DNS name, of a
+// client certificate verified against the client CA
+func clientCertName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
+
+// isPublic reports whether the full gRPC method belongs to a service that is
+// called without authenticating
+func isPublic(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return slices.Contains(publicServices, service)
+}
+
+// UnaryServerInterceptor rejects unauthenticated calls, and adds the identity
+// of the caller to the context of the others
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		identity, err := a.Authenticate(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "rejected unauthenticated call", "method", info.FullMethod, "error", err)
+			return nil, err
+		}
+		return handler(WithIdentity(ctx, identity), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		identity, err := a.Authenticate(stream.Context())
+		if err != nil {
+			slog.WarnContext(stream.Context(), "rejected unauthenticated call", "method", info.FullMethod, "error", err)
+			return err
+		}
+		return handler(srv, &identifiedStream{ServerStream: stream, ctx: WithIdentity(stream.Context(), identity)})
+	}
+}
+
+// identifiedStream replaces the context of a stream with one carrying the
+// identity of the caller
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
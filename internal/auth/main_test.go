@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"strings"
+	"testing"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type mockStore struct {
+	// key names by key hash
+	keys   map[string]string
+	grants map[string][]types.Grant
+}
+
+func (m mockStore) LookupAPIKey(ctx context.Context, keyHash string) (string, error) {
+	if keyHash == HashKey("broken") {
+		return "", errors.New("datalayer unavailable")
+	}
+	name, ok := m.keys[keyHash]
+	if !ok {
+		return "", types.APIKeyNotFound
+	}
+	return name, nil
+}
+
+func (m mockStore) ReadGrants(ctx context.Context, identity string) ([]types.Grant, error) {
+	return m.grants[identity], nil
+}
+
+func withMetadata(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func withClientCert(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	authenticator := NewAuthenticator(
+		map[string]string{"static-key": "ops"},
+		mockStore{
+			keys:   map[string]string{HashKey("stored-key"): "ci-pipeline"},
+			grants: map[string][]types.Grant{"api_key:ci-pipeline": {{Role: RoleEmitter, Scope: "team-a/Hourly"}}},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		want     Identity
+		wantCode codes.Code
+	}{
+		{name: "static bearer key", ctx: withMetadata(AuthorizationHeader, "Bearer static-key"), want: Identity{Name: "ops", Method: MethodAPIKey}},
+		{name: "stored key header", ctx: withMetadata(APIKeyHeader, "stored-key"), want: Identity{Name: "ci-pipeline", Method: MethodAPIKey}},
+		{name: "client certificate", ctx: withClientCert("processor-a"), want: Identity{Name: "processor-a", Method: MethodClientCert}},
+		{name: "unknown key", ctx: withMetadata(APIKeyHeader, "unknown"), wantCode: codes.Unauthenticated},
+		{name: "basic auth", ctx: withMetadata(AuthorizationHeader, "Basic static-key"), wantCode: codes.Unauthenticated},
+		{name: "no credentials", ctx: context.Background(), wantCode: codes.Unauthenticated},
+		{name: "store unavailable", ctx: withMetadata(APIKeyHeader, "broken"), wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authenticator.Authenticate(tt.ctx)
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("Authenticate() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if got.Name != tt.want.Name || got.Method != tt.want.Method {
+				t.Errorf("Authenticate() = %v, want %v", got, tt.want)
+			}
+			if tt.want.Name == "ci-pipeline" && !got.Has(RoleEmitter, "team-a/Hourly") {
+				t.Errorf("Authenticate() grants = %v, want those of the identity", got.Grants)
+			}
+			// static keys are admins
+			if tt.want.Name == "ops" && !got.Has(RoleAdmin, types.ScopeAll) {
+				t.Errorf("Authenticate() grants = %v, want admin", got.Grants)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := NewAuthenticator(map[string]string{"static-key": "ops"}, nil).UnaryServerInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		return Caller(ctx), nil
+	}
+
+	caller, err := interceptor(
+		withMetadata(AuthorizationHeader, "Bearer static-key"),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/OrcaCore/EmitWindow"},
+		handler,
+	)
+	if err != nil || caller != "api_key:ops" {
+		t.Errorf("interceptor() = %v, %v, want the caller of the key", caller, err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/OrcaCore/RegisterProcessor"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("interceptor() error = %v, want Unauthenticated", err)
+	}
+
+	// readiness probes do not authenticate
+	caller, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	if err != nil || caller != "" {
+		t.Errorf("interceptor() = %v, %v, want the health check served anonymously", caller, err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	caller := func(grants ...types.Grant) context.Context {
+		return WithIdentity(context.Background(), Identity{Name: "team-a", Method: MethodAPIKey, Grants: grants})
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		role    string
+		scope   string
+		allowed bool
+	}{
+		{name: "scoped grant", ctx: caller(types.Grant{Role: RoleProcessor, Scope: "team-a"}), role: RoleProcessor, scope: "team-a", allowed: true},
+		{name: "other scope", ctx: caller(types.Grant{Role: RoleProcessor, Scope: "team-a"}), role: RoleProcessor, scope: "team-b"},
+		{name: "other role", ctx: caller(types.Grant{Role: RoleProcessor, Scope: "team-a"}), role: RoleEmitter, scope: "team-a"},
+		{name: "every scope", ctx: caller(types.Grant{Role: RoleEmitter, Scope: types.ScopeAll}), role: RoleEmitter, scope: "team-a/Hourly", allowed: true},
+		{name: "window type of namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}), role: RoleEmitter, scope: "team-a/Hourly", allowed: true},
+		{name: "window type of other namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}), role: RoleEmitter, scope: "team-b/Hourly"},
+		{name: "window type of every namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "*/Hourly"}), role: RoleEmitter, scope: "team-b/Hourly", allowed: true},
+		{name: "every window type of namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/*"}), role: RoleEmitter, scope: "team-a/Daily", allowed: true},
+		{name: "namespace is not every namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}), role: RoleEmitter, scope: "*/Hourly"},
+		{name: "reader", ctx: caller(types.Grant{Role: RoleReader, Scope: types.ScopeAll}), role: RoleReader, scope: types.ScopeAll, allowed: true},
+		{name: "namespace reader", ctx: caller(types.Grant{Role: RoleReader, Scope: "team-a"}), role: RoleReader, scope: "team-a", allowed: true},
+		{name: "namespace reader of every namespace", ctx: caller(types.Grant{Role: RoleReader, Scope: "team-a"}), role: RoleReader, scope: types.ScopeAll},
+		{name: "reader cannot emit", ctx: caller(types.Grant{Role: RoleReader, Scope: types.ScopeAll}), role: RoleEmitter, scope: "team-a/Hourly"},
+		{name: "admin", ctx: caller(types.Grant{Role: RoleAdmin, Scope: types.ScopeAll}), role: RoleProcessor, scope: "team-b", allowed: true},
+		{name: "no grants", ctx: caller(), role: RoleReader, scope: types.ScopeAll},
+		{name: "unauthenticated internal call", ctx: context.Background(), role: RoleAdmin, scope: types.ScopeAll, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Require(tt.ctx, tt.role, tt.scope)
+			if tt.allowed && err != nil {
+				t.Errorf("Require() error = %v, want nil", err)
+			}
+			if !tt.allowed && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("Require() error = %v, want PermissionDenied", err)
+			}
+		})
+	}
+}
+
+func TestGrantedNamespace(t *testing.T) {
+	caller := func(grants ...types.Grant) context.Context {
+		return WithIdentity(context.Background(), Identity{Name: "team-a", Method: MethodAPIKey, Grants: grants})
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		role      string
+		want      string
+		ambiguous bool
+	}{
+		{name: "one namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}), role: RoleEmitter, want: "team-a"},
+		{name: "other window types", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}, types.Grant{Role: RoleEmitter, Scope: "team-b/Daily"}), role: RoleEmitter, want: "team-a"},
+		{name: "every window type", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-b/*"}), role: RoleEmitter, want: "team-b"},
+		{name: "several namespaces", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}, types.Grant{Role: RoleEmitter, Scope: "team-b/*"}), role: RoleEmitter, ambiguous: true},
+		{name: "every namespace", ctx: caller(types.Grant{Role: RoleEmitter, Scope: "team-a/Hourly"}, types.Grant{Role: RoleEmitter, Scope: "*/Hourly"}), role: RoleEmitter},
+		{name: "reader", ctx: caller(types.Grant{Role: RoleReader, Scope: "team-a"}), role: RoleReader, want: "team-a"},
+		{name: "admin", ctx: caller(types.Grant{Role: RoleReader, Scope: "team-a"}, types.Grant{Role: RoleAdmin, Scope: types.ScopeAll}), role: RoleReader},
+		{name: "unauthenticated internal call", ctx: context.Background(), role: RoleEmitter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GrantedNamespace(tt.ctx, tt.role, "Hourly")
+			if tt.ambiguous {
+				if !errors.Is(err, types.AmbiguousNamespace) {
+					t.Errorf("GrantedNamespace() error = %v, want AmbiguousNamespace", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("GrantedNamespace() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGrant(t *testing.T) {
+	for _, valid := range []types.Grant{
+		{Role: RoleAdmin, Scope: types.ScopeAll},
+		{Role: RoleProcessor, Scope: "team-a"},
+		{Role: RoleEmitter, Scope: "team-a/Hourly"},
+		{Role: RoleEmitter, Scope: "*/Hourly"},
+		{Role: RoleReader, Scope: "team-a"},
+	} {
+		if err := ValidateGrant(valid); err != nil {
+			t.Errorf("ValidateGrant(%v) error = %v", valid, err)
+		}
+	}
+	for _, invalid := range []types.Grant{
+		{Role: "owner", Scope: types.ScopeAll},
+		{Role: RoleAdmin, Scope: "team-a"},
+		{Role: RoleEmitter, Scope: ""},
+		{Role: RoleEmitter, Scope: "Hourly"},
+		{Role: RoleEmitter, Scope: "team-a/"},
+		{Role: RoleProcessor, Scope: "team-a/Hourly"},
+	} {
+		if err := ValidateGrant(invalid); err == nil {
+			t.Errorf("ValidateGrant(%v) error = nil, want error", invalid)
+		}
+	}
+}
+
+func TestParseStaticKeys(t *testing.T) {
+	keys, err := ParseStaticKeys(" ops:key-1, ci:key-2 ,")
+	if err != nil {
+		t.Fatalf("ParseStaticKeys() error = %v", err)
+	}
+	if keys["key-1"] != "ops" || keys["key-2"] != "ci" || len(keys) != 2 {
+		t.Errorf("ParseStaticKeys() = %v", keys)
+	}
+
+	for _, invalid := range []string{"ops", "ops:", ":key", "ops:key-1,ops:key-2", "ops:key,ci:key"} {
+		if _, err := ParseStaticKeys(invalid); err == nil {
+			t.Errorf("ParseStaticKeys(%q) error = nil, want error", invalid)
+		}
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	first, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	second, _ := GenerateKey()
+	if !strings.HasPrefix(first, KeyPrefix) || first == second {
+		t.Errorf("GenerateKey() = %q, %q, want distinct prefixed keys", first, second)
+	}
+	if HashKey(first) == first || len(HashKey(first)) != 64 {
+		t.Errorf("HashKey() = %q, want a hex SHA-256", HashKey(first))
+	}
+}
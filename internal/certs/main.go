@@ -0,0 +1,201 @@
+// Package certs loads the certificates Orca core serves and presents over
+// TLS, and reloads them when their files change so that certificates can be
+// rotated without a restart.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReloadInterval is how often the certificate files are checked for changes
+const ReloadInterval = 10 * time.Second
+
+// client certificate verification modes of a server
+const (
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// ClientAuthModes lists the client certificate verification modes
+var ClientAuthModes = []string{ClientAuthOptional, ClientAuthRequire}
+
+// Reloader holds a certificate and CA pool loaded from files, reloading them
+// when the files change. Either can be left out by passing empty paths
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewReloader loads the certificate and key pair, and the CA bundle
+func NewReloader(certFile string, keyFile string, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("a certificate and its key must be given together")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the files again if any of them changed since they were last
+// loaded, and reports whether they were. The loaded certificates are kept
+// when the new ones cannot be loaded, e.g. when only the certificate of a
+// pair has been replaced so far
+func (r *Reloader) Reload() (bool, error) {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("could not read %s: %w", path, err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	r.mu.RLock()
+	changed := len(modTimes) != len(r.modTimes)
+	for path, modTime := range modTimes {
+		changed = changed || !r.modTimes[path].Equal(modTime)
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("could not load certificate: %w", err)
+		}
+		cert = &pair
+	}
+	var caPool *x509.CertPool
+	if r.caFile != "" {
+		caPEM, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return false, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return false, fmt.Errorf("no certificates found in CA bundle %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	return true, nil
+}
+
+// Run reloads the files on every interval until the context is done
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				slog.Error("could not reload certificates, keeping the loaded ones", "certificate", r.certFile, "error", err)
+				continue
+			}
+			if reloaded {
+				slog.Info("reloaded certificates", "certificate", r.certFile, "ca", r.caFile)
+			}
+		}
+	}
+}
+
+// Certificate returns the loaded certificate
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// CAPool returns the loaded CA bundle, or nil when none was given
+func (r *Reloader) CAPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// ServerTLSConfig returns a gRPC server config presenting the current
+// certificate on every handshake. When a CA bundle was given, client certificates are
+// verified against it, and required when clientAuth is ClientAuthRequire
+func (r *Reloader) ServerTLSConfig(clientAuth string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert := r.Certificate()
+			if cert == nil {
+				return nil, fmt.Errorf("no server certificate loaded")
+			}
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}
+			if caPool := r.CAPool(); caPool != nil {
+				config.ClientCAs = caPool
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+				if clientAuth == ClientAuthRequire {
+					config.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return config, nil
+		},
+	}
+}
+
+// HTTPServerTLSConfig returns ServerTLSConfig for HTTP listeners, which
+// negotiate HTTP/1.1 as well as HTTP/2
+func (r *Reloader) HTTPServerTLSConfig(clientAuth string) *tls.Config {
+	config := r.ServerTLSConfig(clientAuth)
+	configForClient := config.GetConfigForClient
+	config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		clientConfig, err := configForClient(hello)
+		if err != nil {
+			return nil, err
+		}
+		clientConfig.NextProtos = []string{"h2", "http/1.1"}
+		return clientConfig, nil
+	}
+	return config
+}
+
+// ClientTLSConfig returns a config for connecting to a server, verifying its
+// certificate against the loaded CA bundle, or the system roots when none was
+// given, and presenting the loaded certificate as a client certificate
+func (r *Reloader) ClientTLSConfig(serverName string) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    r.CAPool(),
+	}
+	if cert := r.Certificate(); cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+	return config
+}
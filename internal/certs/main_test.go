@@ -0,0 +1,246 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// issue creates a certificate signed by the parent, or self-signed when the
+// parent is nil, returning it and its key as PEM
+func issue(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return cert, key,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func write(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects to the server with the client config, returning the
+// common name of the certificate the server presented
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) (string, error) {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// client certificates are verified after the client finishes its side
+	// of a TLS 1.3 handshake
+	if err := <-serverErr; err != nil {
+		return "", err
+	}
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server-key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca, caKey, caPEM, _ := issue(t, "orca-ca", nil, nil)
+	_, _, certPEM, keyPEM := issue(t, "first", ca, caKey)
+	_, _, clientPEM, clientKeyPEM := issue(t, "processor-a", ca, caKey)
+	start := time.Now().Add(-time.Minute)
+	write(t, certFile, certPEM, start)
+	write(t, keyFile, keyPEM, start)
+	write(t, caFile, caPEM, start)
+
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	client := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}}
+
+	if name, err := handshake(t, reloader.ServerTLSConfig(ClientAuthOptional), client); err != nil || name != "first" {
+		t.Fatalf("handshake() = %q, %v, want the first certificate", name, err)
+	}
+
+	// nothing changed
+	if reloaded, err := reloader.Reload(); reloaded || err != nil {
+		t.Errorf("Reload() = %v, %v, want no reload", reloaded, err)
+	}
+
+	// half-rotated pairs keep the loaded certificate
+	_, _, rotatedPEM, rotatedKeyPEM := issue(t, "rotated", ca, caKey)
+	write(t, certFile, rotatedPEM, start.Add(time.Second))
+	if _, err := reloader.Reload(); err == nil {
+		t.Errorf("Reload() of a mismatched pair error = nil, want error")
+	}
+	if name, err := handshake(t, reloader.ServerTLSConfig(ClientAuthOptional), client); err != nil || name != "first" {
+		t.Errorf("handshake() = %q, %v, want the first certificate kept", name, err)
+	}
+
+	write(t, keyFile, rotatedKeyPEM, start.Add(time.Second))
+	if reloaded, err := reloader.Reload(); !reloaded || err != nil {
+		t.Fatalf("Reload() = %v, %v, want a reload", reloaded, err)
+	}
+	if name, err := handshake(t, reloader.ServerTLSConfig(ClientAuthOptional), client); err != nil || name != "rotated" {
+		t.Errorf("handshake() = %q, %v, want the rotated certificate", name, err)
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caPEM, _ := issue(t, "orca-ca", nil, nil)
+	_, _, certPEM, keyPEM := issue(t, "server", ca, caKey)
+	now := time.Now()
+	write(t, filepath.Join(dir, "server.pem"), certPEM, now)
+	write(t, filepath.Join(dir, "server-key.pem"), keyPEM, now)
+	write(t, filepath.Join(dir, "ca.pem"), caPEM, now)
+
+	reloader, err := NewReloader(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	anonymous := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	if _, err := handshake(t, reloader.ServerTLSConfig(ClientAuthOptional), anonymous); err != nil {
+		t.Errorf("handshake() without a client certificate error = %v, want it optional", err)
+	}
+	if _, err := handshake(t, reloader.ServerTLSConfig(ClientAuthRequire), anonymous); err == nil {
+		t.Errorf("handshake() without a client certificate error = nil, want it required")
+	}
+}
+
+func TestHTTPServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, _, _ := issue(t, "orca-ca", nil, nil)
+	_, _, certPEM, keyPEM := issue(t, "server", ca, caKey)
+	now := time.Now()
+	write(t, filepath.Join(dir, "server.pem"), certPEM, now)
+	write(t, filepath.Join(dir, "server-key.pem"), keyPEM, now)
+
+	reloader, err := NewReloader(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "")
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	// clients that only speak HTTP/1.1 are served as well as HTTP/2 ones
+	for _, proto := range []string{"http/1.1", "h2"} {
+		client := &tls.Config{RootCAs: roots, ServerName: "localhost", NextProtos: []string{proto}}
+		if _, err := handshake(t, reloader.HTTPServerTLSConfig(ClientAuthOptional), client); err != nil {
+			t.Errorf("handshake() negotiating %s error = %v", proto, err)
+		}
+	}
+}
+
+func TestNewReloaderErrors(t *testing.T) {
+	if _, err := NewReloader("server.pem", "", ""); err == nil {
+		t.Errorf("NewReloader() without a key error = nil, want error")
+	}
+	if _, err := NewReloader(filepath.Join(t.TempDir(), "missing.pem"), "missing-key.pem", ""); err == nil {
+		t.Errorf("NewReloader() of missing files error = nil, want error")
+	}
+}
+
+func TestClientTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caPEM, _ := issue(t, "orca-ca", nil, nil)
+	_, _, serverPEM, serverKeyPEM := issue(t, "processor", ca, caKey)
+	_, _, clientPEM, clientKeyPEM := issue(t, "orca-core", ca, caKey)
+	now := time.Now()
+	write(t, filepath.Join(dir, "processor.pem"), serverPEM, now)
+	write(t, filepath.Join(dir, "processor-key.pem"), serverKeyPEM, now)
+	write(t, filepath.Join(dir, "core.pem"), clientPEM, now)
+	write(t, filepath.Join(dir, "core-key.pem"), clientKeyPEM, now)
+	write(t, filepath.Join(dir, "ca.pem"), caPEM, now)
+
+	// the processor requires a client certificate issued by the CA
+	processor, err := NewReloader(filepath.Join(dir, "processor.pem"), filepath.Join(dir, "processor-key.pem"), filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	server := processor.ServerTLSConfig(ClientAuthRequire)
+
+	core, err := NewReloader(filepath.Join(dir, "core.pem"), filepath.Join(dir, "core-key.pem"), filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	if name, err := handshake(t, server, core.ClientTLSConfig("localhost")); err != nil || name != "processor" {
+		t.Errorf("handshake() = %q, %v, want the processor verified over mTLS", name, err)
+	}
+	if _, err := handshake(t, server, core.ClientTLSConfig("processor.internal")); err == nil {
+		t.Errorf("handshake() with the wrong server name error = nil, want error")
+	}
+
+	// without a client certificate the processor refuses the connection
+	caOnly, err := NewReloader("", "", filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	if _, err := handshake(t, server, caOnly.ClientTLSConfig("localhost")); err == nil {
+		t.Errorf("handshake() without a client certificate error = nil, want error")
+	}
+}
@@ -0,0 +1,335 @@
+package dag
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Index is the graph of algorithm dependencies, held in memory so that plans
+// can be built, and dependencies checked for cycles, without reading the
+// execution paths of every algorithm. It is updated as algorithms and
+// dependencies are registered. Readers share an Index, so it is cloned to be
+// changed
+type Index struct {
+	algorithms map[int64]indexedAlgorithm
+	// the lookback of each dependency of an algorithm, by the id of the
+	// algorithm depended on
+	dependencies map[int64]map[int64]Lookback
+	// the algorithms that depend on an algorithm
+	dependants map[int64][]int64
+	// the algorithms of a window type
+	windowTypes map[int64][]int64
+}
+
+type indexedAlgorithm struct {
+	procId   int64
+	windowId int64
+}
+
+// NewIndex returns an empty Index
+func NewIndex() *Index {
+	return &Index{
+		algorithms:   make(map[int64]indexedAlgorithm),
+		dependencies: make(map[int64]map[int64]Lookback),
+		dependants:   make(map[int64][]int64),
+		windowTypes:  make(map[int64][]int64),
+	}
+}
+
+// Clone returns a copy of the index that can be changed without changing
+// the original
+func (idx *Index) Clone() *Index {
+	clone := &Index{
+		algorithms:   maps.Clone(idx.algorithms),
+		dependencies: make(map[int64]map[int64]Lookback, len(idx.dependencies)),
+		dependants:   make(map[int64][]int64, len(idx.dependants)),
+		windowTypes:  make(map[int64][]int64, len(idx.windowTypes)),
+	}
+	for algoId, deps := range idx.dependencies {
+		clone.dependencies[algoId] = maps.Clone(deps)
+	}
+	for algoId, dependants := range idx.dependants {
+		clone.dependants[algoId] = slices.Clone(dependants)
+	}
+	for windowId, algoIds := range idx.windowTypes {
+		clone.windowTypes[windowId] = slices.Clone(algoIds)
+	}
+	return clone
+}
+
+// Len returns the number of algorithms in the index
+func (idx *Index) Len() int {
+	return len(idx.algorithms)
+}
+
+// AddAlgorithm adds an algorithm of a processor, triggered by windows of the
+// window type. Adding an algorithm again has no effect
+func (idx *Index) AddAlgorithm(algoId int64, procId int64, windowId int64) {
+	if _, ok := idx.algorithms[algoId]; ok {
+		return
+	}
+	idx.algorithms[algoId] = indexedAlgorithm{procId: procId, windowId: windowId}
+	idx.windowTypes[windowId] = append(idx.windowTypes[windowId], algoId)
+}
+
+// SetDependency records that algorithm toAlgoId depends on algorithm
+// fromAlgoId, with the lookback of the dependency, replacing the lookback of
+// an existing dependency. Both algorithms must have been added
+func (idx *Index) SetDependency(fromAlgoId int64, toAlgoId int64, lookback Lookback) error {
+	for _, algoId := range []int64{fromAlgoId, toAlgoId} {
+		if _, ok := idx.algorithms[algoId]; !ok {
+			return fmt.Errorf("algorithm %d is not in the index", algoId)
+		}
+	}
+	deps, ok := idx.dependencies[toAlgoId]
+	if !ok {
+		deps = make(map[int64]Lookback)
+		idx.dependencies[toAlgoId] = deps
+	}
+	if _, ok := deps[fromAlgoId]; !ok {
+		idx.dependants[fromAlgoId] = append(idx.dependants[fromAlgoId], toAlgoId)
+	}
+	deps[fromAlgoId] = lookback
+	return nil
+}
+
+// DependsOn reports whether algorithm algoId depends on algorithm
+// dependencyId, directly or through other algorithms
+func (idx *Index) DependsOn(algoId int64, dependencyId int64) bool {
+	seen := map[int64]bool{algoId: true}
+	stack := []int64{algoId}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for depId := range idx.dependencies[current] {
+			if depId == dependencyId {
+				return true
+			}
+			if !seen[depId] {
+				seen[depId] = true
+				stack = append(stack, depId)
+			}
+		}
+	}
+	return false
+}
+
+// Triggered returns the ids of the algorithms a window of the window type
+// runs, in ascending order. These are the algorithms of the window type,
+// the algorithms they depend on, and the algorithms that depend on them
+func (idx *Index) Triggered(windowId int64) []int64 {
+	triggered := make(map[int64]bool)
+	idx.walk(idx.windowTypes[windowId], triggered, func(algoId int64) []int64 {
+		return slices.Collect(maps.Keys(idx.dependencies[algoId]))
+	})
+	dependants := make(map[int64]bool)
+	idx.walk(idx.windowTypes[windowId], dependants, func(algoId int64) []int64 {
+		return idx.dependants[algoId]
+	})
+	maps.Copy(triggered, dependants)
+	return slices.Sorted(maps.Keys(triggered))
+}
+
+// walk marks the algorithms reachable from start by next, including those
+// of start
+func (idx *Index) walk(start []int64, marked map[int64]bool, next func(algoId int64) []int64) {
+	stack := slices.Clone(start)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if marked[current] {
+			continue
+		}
+		marked[current] = true
+		stack = append(stack, next(current)...)
+	}
+}
+
+// Plan builds the execution Plan of a window of the window type. When
+// inProcessor is given, only the algorithms of processors it reports true
+// for are run, along with the algorithms they depend on
+func (idx *Index) Plan(windowId int64, inProcessor func(procId int64) bool) (Plan, error) {
+	triggered := idx.Triggered(windowId)
+	included := make(map[int64]bool, len(triggered))
+	if inProcessor == nil {
+		for _, algoId := range triggered {
+			included[algoId] = true
+		}
+	} else {
+		isTriggered := make(map[int64]bool, len(triggered))
+		var kept []int64
+		for _, algoId := range triggered {
+			isTriggered[algoId] = true
+			if inProcessor(idx.algorithms[algoId].procId) {
+				kept = append(kept, algoId)
+			}
+		}
+		idx.walk(kept, included, func(algoId int64) []int64 {
+			var deps []int64
+			for depId := range idx.dependencies[algoId] {
+				if isTriggered[depId] {
+					deps = append(deps, depId)
+				}
+			}
+			return deps
+		})
+	}
+
+	g := simple.NewDirectedGraph()
+	nodeMap := make(map[int64]Node, len(included))
+	for ii, algoId := range slices.Sorted(maps.Keys(included)) {
+		algo := idx.algorithms[algoId]
+		node := Node{
+			id:       int64(ii + 1),
+			algoId:   algoId,
+			procId:   algo.procId,
+			windowId: algo.windowId,
+			pathIdx:  ii,
+		}
+		nodeMap[algoId] = node
+		g.AddNode(node)
+	}
+
+	lookbackMap := make(map[string]Lookback)
+	for algoId, node := range nodeMap {
+		for depId, lookback := range idx.dependencies[algoId] {
+			dep, ok := nodeMap[depId]
+			if !ok {
+				continue
+			}
+			lookbackMap[fmt.Sprintf("%d.%d", depId, algoId)] = lookback
+			g.SetEdge(g.NewEdge(dep, node))
+		}
+	}
+	return planFromGraph(g, lookbackMap)
+}
+
+// HasDependency reports whether algorithm algoId depends directly on
+// algorithm dependencyId
+func (idx *Index) HasDependency(algoId int64, dependencyId int64) bool {
+	_, ok := idx.dependencies[algoId][dependencyId]
+	return ok
+}
+
+// Cycle returns the ids of the algorithms of a cycle of dependencies, or nil
+// when the dependencies are acyclic. Each algorithm of the cycle depends on
+// the one before it, and the first depends on the last. When base is given,
+// the cycle starts with a dependency that is not in base, so that the first
+// two algorithms are those of a dependency that closed the cycle
+func (idx *Index) Cycle(base *Index) []int64 {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int64]int, len(idx.algorithms))
+	for _, start := range slices.Sorted(maps.Keys(idx.algorithms)) {
+		if state[start] != unvisited {
+			continue
+		}
+		// the path of the depth first search, with the number of dependants
+		// of each algorithm on it visited so far
+		type step struct {
+			algoId     int64
+			dependants []int64
+			next       int
+		}
+		path := []*step{{algoId: start, dependants: slices.Sorted(slices.Values(idx.dependants[start]))}}
+		state[start] = visiting
+		for len(path) > 0 {
+			current := path[len(path)-1]
+			if current.next == len(current.dependants) {
+				state[current.algoId] = visited
+				path = path[:len(path)-1]
+				continue
+			}
+			dependantId := current.dependants[current.next]
+			current.next++
+			switch state[dependantId] {
+			case visiting:
+				var cycle []int64
+				for ii := len(path) - 1; ii >= 0; ii-- {
+					cycle = append(cycle, path[ii].algoId)
+					if path[ii].algoId == dependantId {
+						break
+					}
+				}
+				slices.Reverse(cycle)
+				return rotateCycle(cycle, base)
+			case unvisited:
+				state[dependantId] = visiting
+				path = append(path, &step{
+					algoId:     dependantId,
+					dependants: slices.Sorted(slices.Values(idx.dependants[dependantId])),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// rotateCycle rotates the cycle to start with a dependency that is not in
+// base
+func rotateCycle(cycle []int64, base *Index) []int64 {
+	if base == nil {
+		return cycle
+	}
+	for ii, algoId := range cycle {
+		dependantId := cycle[(ii+1)%len(cycle)]
+		if !base.HasDependency(dependantId, algoId) {
+			return slices.Concat(cycle[ii:], cycle[:ii])
+		}
+	}
+	return cycle
+}
+
+// IndexCache keeps the Index built at a version of the registry of
+// algorithms, for datalayers that store the registry in a database that
+// other instances register processors with. It is safe for concurrent use
+type IndexCache struct {
+	mu      sync.Mutex
+	index   *Index
+	version int64
+}
+
+// Get returns the index if it was built at the version of the registry, or
+// nil
+func (c *IndexCache) Get(version int64) *Index {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.index == nil || c.version != version {
+		return nil
+	}
+	return c.index
+}
+
+// Set keeps the index built at the version of the registry, unless one of a
+// later version is kept
+func (c *IndexCache) Set(index *Index, version int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.index != nil && c.version > version {
+		return
+	}
+	c.index = index
+	c.version = version
+}
+
+// Load returns the index at the version of the registry, building it with
+// build when the one kept is of another version. The index is shared, so
+// must not be changed
+func (c *IndexCache) Load(version int64, build func() (*Index, error)) (*Index, error) {
+	if index := c.Get(version); index != nil {
+		return index, nil
+	}
+	index, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(index, version)
+	return index, nil
+}
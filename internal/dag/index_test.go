@@ -0,0 +1,268 @@
+package dag
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// testIndex returns an index of the algorithms, given as
+// algoId: {procId, windowId}, and their dependencies, given as
+// {fromAlgoId, toAlgoId}
+func testIndex(t *testing.T, algorithms map[int64][2]int64, dependencies [][2]int64, lookbacks map[[2]int64]Lookback) *Index {
+	t.Helper()
+	idx := NewIndex()
+	for algoId, algo := range algorithms {
+		idx.AddAlgorithm(algoId, algo[0], algo[1])
+	}
+	for _, dep := range dependencies {
+		if err := idx.SetDependency(dep[0], dep[1], lookbacks[dep]); err != nil {
+			t.Fatalf("SetDependency() error = %v", err)
+		}
+	}
+	return idx
+}
+
+func TestIndexPlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		algorithms   map[int64][2]int64
+		dependencies [][2]int64
+		lookbacks    map[[2]int64]Lookback
+		windowId     int64
+		procIds      []int64
+		want         Plan
+	}{
+		{
+			name:         "straight line triggered from the middle",
+			algorithms:   map[int64][2]int64{1: {1, 1}, 2: {1, 2}, 3: {1, 3}},
+			dependencies: [][2]int64{{1, 2}, {2, 3}},
+			windowId:     2,
+			want: Plan{
+				Stages: []Stage{
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 1, procId: 1}}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 2, procId: 1, algoDeps: []AlgoDep{{AlgoId: 1}}}}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 3, procId: 1, algoDeps: []AlgoDep{{AlgoId: 2}}}}},
+					}},
+				},
+				AffectedProcessors: []int64{1},
+			},
+		},
+		{
+			name:         "fork and join with lookbacks",
+			algorithms:   map[int64][2]int64{1: {1, 1}, 2: {2, 1}, 3: {2, 1}, 4: {3, 1}},
+			dependencies: [][2]int64{{1, 2}, {1, 3}, {2, 4}, {3, 4}},
+			lookbacks: map[[2]int64]Lookback{
+				{1, 3}: {Count: 5},
+				{3, 4}: {Timedelta: 60, Partition: PartitionOrigin},
+			},
+			windowId: 1,
+			want: Plan{
+				Stages: []Stage{
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 1, procId: 1}}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 2, Nodes: []Node{
+							{algoId: 2, procId: 2, algoDeps: []AlgoDep{{AlgoId: 1}}},
+							{algoId: 3, procId: 2, algoDeps: []AlgoDep{{AlgoId: 1, Lookback: Lookback{Count: 5}}}},
+						}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 3, Nodes: []Node{{algoId: 4, procId: 3, algoDeps: []AlgoDep{
+							{AlgoId: 2},
+							{AlgoId: 3, Lookback: Lookback{Timedelta: 60, Partition: PartitionOrigin}},
+						}}}},
+					}},
+				},
+				AffectedProcessors: []int64{1, 2, 3},
+			},
+		},
+		{
+			name:         "algorithms of other window types that dependants depend on do not run",
+			algorithms:   map[int64][2]int64{1: {1, 1}, 2: {1, 2}, 3: {1, 3}},
+			dependencies: [][2]int64{{1, 3}, {2, 3}},
+			windowId:     1,
+			want: Plan{
+				Stages: []Stage{
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 1, procId: 1}}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 3, procId: 1, algoDeps: []AlgoDep{{AlgoId: 1}}}}},
+					}},
+				},
+				AffectedProcessors: []int64{1},
+			},
+		},
+		{
+			name:         "processors filter the algorithms, keeping their dependencies",
+			algorithms:   map[int64][2]int64{1: {1, 1}, 2: {2, 1}, 3: {3, 1}},
+			dependencies: [][2]int64{{1, 2}, {2, 3}},
+			windowId:     1,
+			procIds:      []int64{2},
+			want: Plan{
+				Stages: []Stage{
+					{Tasks: []ProcessorTask{
+						{ProcId: 1, Nodes: []Node{{algoId: 1, procId: 1}}},
+					}},
+					{Tasks: []ProcessorTask{
+						{ProcId: 2, Nodes: []Node{{algoId: 2, procId: 2, algoDeps: []AlgoDep{{AlgoId: 1}}}}},
+					}},
+				},
+				AffectedProcessors: []int64{1, 2},
+			},
+		},
+		{
+			name:       "window type without algorithms",
+			algorithms: map[int64][2]int64{1: {1, 1}},
+			windowId:   2,
+			want:       Plan{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := testIndex(t, tt.algorithms, tt.dependencies, tt.lookbacks)
+			var inProcessor func(int64) bool
+			if tt.procIds != nil {
+				inProcessor = func(procId int64) bool { return slices.Contains(tt.procIds, procId) }
+			}
+			got, err := idx.Plan(tt.windowId, inProcessor)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			got = normalisePlan(got)
+			want := normalisePlan(tt.want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Plan() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestIndexDependsOn(t *testing.T) {
+	idx := testIndex(t,
+		map[int64][2]int64{1: {1, 1}, 2: {1, 1}, 3: {1, 1}, 4: {1, 1}},
+		[][2]int64{{1, 2}, {2, 3}},
+		nil,
+	)
+	tests := []struct {
+		algoId, dependencyId int64
+		want                 bool
+	}{
+		{2, 1, true},
+		{3, 1, true},
+		{1, 3, false},
+		{4, 1, false},
+		{1, 1, false},
+	}
+	for _, tt := range tests {
+		if got := idx.DependsOn(tt.algoId, tt.dependencyId); got != tt.want {
+			t.Errorf("DependsOn(%d, %d) = %v, want %v", tt.algoId, tt.dependencyId, got, tt.want)
+		}
+	}
+
+	if err := idx.SetDependency(5, 1, Lookback{}); err == nil {
+		t.Errorf("SetDependency() of an algorithm not in the index succeeded")
+	}
+}
+
+func TestIndexClone(t *testing.T) {
+	idx := testIndex(t, map[int64][2]int64{1: {1, 1}, 2: {1, 1}}, nil, nil)
+	clone := idx.Clone()
+	clone.AddAlgorithm(3, 1, 1)
+	if err := clone.SetDependency(1, 2, Lookback{Count: 2}); err != nil {
+		t.Fatalf("SetDependency() error = %v", err)
+	}
+
+	if idx.Len() != 2 || clone.Len() != 3 {
+		t.Errorf("Len() = %d and %d, want 2 and 3", idx.Len(), clone.Len())
+	}
+	if idx.DependsOn(2, 1) || !clone.DependsOn(2, 1) {
+		t.Errorf("the dependency set on the clone changed the original")
+	}
+	if got := idx.Triggered(1); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("Triggered() = %v, want [1 2]", got)
+	}
+}
+
+func TestIndexCycle(t *testing.T) {
+	algorithms := map[int64][2]int64{1: {1, 1}, 2: {1, 1}, 3: {1, 1}, 4: {1, 1}}
+	tests := []struct {
+		name         string
+		dependencies [][2]int64
+		base         [][2]int64
+		want         []int64
+	}{
+		{
+			name:         "acyclic",
+			dependencies: [][2]int64{{1, 2}, {2, 3}, {1, 3}, {3, 4}},
+			want:         nil,
+		},
+		{
+			name:         "self dependency",
+			dependencies: [][2]int64{{1, 2}, {3, 3}},
+			want:         []int64{3},
+		},
+		{
+			name:         "cycle through every algorithm",
+			dependencies: [][2]int64{{1, 2}, {2, 3}, {3, 4}, {4, 1}},
+			want:         []int64{1, 2, 3, 4},
+		},
+		{
+			name:         "cycle starts with the dependency not in the base",
+			dependencies: [][2]int64{{1, 2}, {2, 3}, {3, 1}},
+			base:         [][2]int64{{1, 2}, {3, 1}},
+			want:         []int64{2, 3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := testIndex(t, algorithms, tt.dependencies, nil)
+			var base *Index
+			if tt.base != nil {
+				base = testIndex(t, algorithms, tt.base, nil)
+			}
+			if got := idx.Cycle(base); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexCache(t *testing.T) {
+	var cache IndexCache
+	if got := cache.Get(1); got != nil {
+		t.Errorf("Get() of an empty cache = %v, want nil", got)
+	}
+
+	builds := 0
+	build := func() (*Index, error) {
+		builds++
+		return NewIndex(), nil
+	}
+	first, err := cache.Load(1, build)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if again, _ := cache.Load(1, build); again != first || builds != 1 {
+		t.Errorf("Load() of a kept version built %d indexes, want 1", builds)
+	}
+	if cache.Get(2) != nil {
+		t.Errorf("Get() of another version returned the kept index")
+	}
+
+	// an index of an older version does not replace a newer one
+	newer := NewIndex()
+	cache.Set(newer, 3)
+	cache.Set(NewIndex(), 2)
+	if cache.Get(3) != newer {
+		t.Errorf("Set() of an older version replaced the kept index")
+	}
+}
@@ -17,9 +17,29 @@ type lookback string
 const CountLookback lookback = "CountLookback"
 const TimedeltaLookback lookback = "TimedeltaLookback"
 
+// LookbackPartition restricts the past results of a lookback to those of
+// windows that share a property with the window being processed
+type LookbackPartition int
+
+const (
+	PartitionNone LookbackPartition = iota
+	PartitionOrigin
+	PartitionMetadataField
+)
+
+// partitionLabels maps the labels used in the execution paths to partitions
+var partitionLabels = map[string]LookbackPartition{
+	"none":           PartitionNone,
+	"origin":         PartitionOrigin,
+	"metadata_field": PartitionMetadataField,
+}
+
 type Lookback struct {
 	Count     int
 	Timedelta int
+	Partition LookbackPartition
+	// id of the metadata field partitioned on, when partitioning by metadata field
+	PartitionFieldId int64
 }
 
 type AlgoDep struct {
@@ -83,12 +103,63 @@ type Stage struct {
 	Tasks []ProcessorTask
 }
 
+// StageLookback is a lookback that an algorithm in a stage requires of one
+// of its dependencies
+type StageLookback struct {
+	// id of the dependant algorithm
+	AlgoId int64
+	Dep    AlgoDep
+}
+
+// Lookbacks yields every lookback required by the nodes of the stage, so
+// that past results can be fetched for the whole stage at once
+func (s Stage) Lookbacks() iter.Seq[StageLookback] {
+	return func(yield func(StageLookback) bool) {
+		for _, task := range s.Tasks {
+			for _, node := range task.Nodes {
+				for _, dep := range node.algoDeps {
+					if !dep.NeedsLookback() {
+						continue
+					}
+					if !yield(StageLookback{AlgoId: node.algoId, Dep: dep}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
 // Plan represents the full execution plan: a sequence of stages
 type Plan struct {
 	Stages             []Stage
 	AffectedProcessors []int64
 }
 
+// Len returns the number of algorithms the plan runs
+func (p Plan) Len() int {
+	n := 0
+	for _, stage := range p.Stages {
+		for _, task := range stage.Tasks {
+			n += len(task.Nodes)
+		}
+	}
+	return n
+}
+
+// HasMetadataPartition reports whether any lookback in the plan is
+// partitioned by a metadata field
+func (p Plan) HasMetadataPartition() bool {
+	for _, stage := range p.Stages {
+		for lookback := range stage.Lookbacks() {
+			if lookback.Dep.Lookback.Partition == PartitionMetadataField {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // LayeredTopoSort returns the nodes of the directed graph g grouped into
 // layers, where each layer contains nodes that can be processed in parallel
 func LayeredTopoSort(g graph.Directed) ([][]graph.Node, error) {
@@ -150,19 +221,25 @@ func BuildPlan(
 	procExecPaths []string,
 	lookbackCounts []string,
 	lookbackTimedeltas []string,
+	lookbackPartitions []string,
+	lookbackPartitionFields []string,
 	targetWindowId int64,
 ) (Plan, error) {
 	if len(algoExecPaths) != len(windowExecPaths) ||
 		len(windowExecPaths) != len(procExecPaths) ||
 		len(procExecPaths) != len(lookbackCounts) ||
-		len(lookbackCounts) != len(lookbackTimedeltas) {
+		len(lookbackCounts) != len(lookbackTimedeltas) ||
+		len(lookbackTimedeltas) != len(lookbackPartitions) ||
+		len(lookbackPartitions) != len(lookbackPartitionFields) {
 		return Plan{}, fmt.Errorf(
-			"number of graph paths do not match: algo=%d, window=%d, proc=%d, lookbackCounts=%d,lookbackTimedeltas=%d",
+			"number of graph paths do not match: algo=%d, window=%d, proc=%d, lookbackCounts=%d,lookbackTimedeltas=%d, lookbackPartitions=%d, lookbackPartitionFields=%d",
 			len(algoExecPaths),
 			len(windowExecPaths),
 			len(procExecPaths),
 			len(lookbackCounts),
 			len(lookbackTimedeltas),
+			len(lookbackPartitions),
+			len(lookbackPartitionFields),
 		)
 	}
 
@@ -178,18 +255,24 @@ func BuildPlan(
 		windowSegments := splitPath(windowExecPaths[pathIdx])
 		lookbackCountSegments := splitPath(lookbackCounts[pathIdx])
 		lookbackTimedeltas := splitPath(lookbackTimedeltas[pathIdx])
+		lookbackPartitionSegments := splitPath(lookbackPartitions[pathIdx])
+		lookbackPartitionFieldSegments := splitPath(lookbackPartitionFields[pathIdx])
 
 		if len(algoSegments) != len(windowSegments) ||
 			len(windowSegments) != len(procSegments) ||
 			len(procSegments) != len(lookbackCountSegments) ||
-			len(lookbackCountSegments) != len(lookbackTimedeltas) {
+			len(lookbackCountSegments) != len(lookbackTimedeltas) ||
+			len(lookbackTimedeltas) != len(lookbackPartitionSegments) ||
+			len(lookbackPartitionSegments) != len(lookbackPartitionFieldSegments) {
 			return Plan{}, fmt.Errorf(
-				"number of processor segments do not match: algo=%d, window=%d, proc=%d, lookbackCount=%d, lookbackTd=%d",
+				"number of processor segments do not match: algo=%d, window=%d, proc=%d, lookbackCount=%d, lookbackTd=%d, lookbackPartition=%d, lookbackPartitionField=%d",
 				len(algoSegments),
 				len(windowSegments),
 				len(procSegments),
 				len(lookbackCountSegments),
 				len(lookbackTimedeltas),
+				len(lookbackPartitionSegments),
+				len(lookbackPartitionFieldSegments),
 			)
 		}
 
@@ -202,6 +285,14 @@ func BuildPlan(
 			windowId := mustAtoi(windowSegments[ii])
 			lookbackCount := mustAtoi(lookbackCountSegments[ii])
 			lookbackTd := mustAtoi(lookbackTimedeltas[ii])
+			lookbackPartition, ok := partitionLabels[lookbackPartitionSegments[ii]]
+			if !ok {
+				return Plan{}, fmt.Errorf(
+					"unknown lookback partition %q in path %d",
+					lookbackPartitionSegments[ii], pathIdx,
+				)
+			}
+			lookbackPartitionField := mustAtoi(lookbackPartitionFieldSegments[ii])
 
 			if pathWindowMap == nil {
 				pathWindowMap = make(map[int64]int64)
@@ -236,8 +327,10 @@ func BuildPlan(
 				_edgeStr := fmt.Sprintf("%d.%d", prevNode.algoId, node.algoId)
 				if _, ok := lookbackMap[_edgeStr]; !ok { // the edge can be populated
 					lookbackMap[_edgeStr] = Lookback{
-						Count:     lookbackCount,
-						Timedelta: lookbackTd,
+						Count:            lookbackCount,
+						Timedelta:        lookbackTd,
+						Partition:        lookbackPartition,
+						PartitionFieldId: int64(lookbackPartitionField),
 					}
 				}
 
@@ -248,6 +341,13 @@ func BuildPlan(
 		}
 	}
 
+	return planFromGraph(g, lookbackMap)
+}
+
+// planFromGraph builds the Plan of the DAG g, whose edges run from each
+// algorithm to those that depend on it, with the lookback of each edge keyed
+// by <algo_from_id>.<algo_to_id>
+func planFromGraph(g *simple.DirectedGraph, lookbackMap map[string]Lookback) (Plan, error) {
 	layers, err := LayeredTopoSort(g)
 	if err != nil {
 		return Plan{}, fmt.Errorf("error during layered topological sort: %v", err)
@@ -2,29 +2,34 @@ package dag
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 )
 
 func TestBuildPlan(t *testing.T) {
 	tests := []struct {
-		name               string
-		algoExecPath       []string
-		windowExecPath     []string
-		procExecPath       []string
-		lookbackCounts     []string
-		lookbackTimedeltas []string
-		targetWindowId     int64
-		want               Plan
-		wantErr            bool
+		name                    string
+		algoExecPath            []string
+		windowExecPath          []string
+		procExecPath            []string
+		lookbackCounts          []string
+		lookbackTimedeltas      []string
+		lookbackPartitions      []string
+		lookbackPartitionFields []string
+		targetWindowId          int64
+		want                    Plan
+		wantErr                 bool
 	}{
 		{
-			name:               "simple straight line",
-			algoExecPath:       []string{"1.2.3"},
-			windowExecPath:     []string{"1.1.1"},
-			procExecPath:       []string{"1.1.1"},
-			lookbackCounts:     []string{"0.0.0"},
-			lookbackTimedeltas: []string{"0.0.0"},
-			targetWindowId:     1,
+			name:                    "simple straight line",
+			algoExecPath:            []string{"1.2.3"},
+			windowExecPath:          []string{"1.1.1"},
+			procExecPath:            []string{"1.1.1"},
+			lookbackCounts:          []string{"0.0.0"},
+			lookbackTimedeltas:      []string{"0.0.0"},
+			lookbackPartitions:      []string{"none.none.none"},
+			lookbackPartitionFields: []string{"0.0.0"},
+			targetWindowId:          1,
 			want: Plan{
 				Stages: []Stage{
 					{Tasks: []ProcessorTask{
@@ -42,13 +47,15 @@ func TestBuildPlan(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:               "parallel roots",
-			algoExecPath:       []string{"1", "2"},
-			windowExecPath:     []string{"1", "1"},
-			procExecPath:       []string{"1", "2"},
-			lookbackCounts:     []string{"0", "0"},
-			lookbackTimedeltas: []string{"0", "0"},
-			targetWindowId:     1,
+			name:                    "parallel roots",
+			algoExecPath:            []string{"1", "2"},
+			windowExecPath:          []string{"1", "1"},
+			procExecPath:            []string{"1", "2"},
+			lookbackCounts:          []string{"0", "0"},
+			lookbackTimedeltas:      []string{"0", "0"},
+			lookbackPartitions:      []string{"none", "none"},
+			lookbackPartitionFields: []string{"0", "0"},
+			targetWindowId:          1,
 			want: Plan{
 				Stages: []Stage{
 					{Tasks: []ProcessorTask{
@@ -61,13 +68,15 @@ func TestBuildPlan(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:               "fork and join",
-			algoExecPath:       []string{"1.2.4", "1.3.4"},
-			windowExecPath:     []string{"1.1.1", "1.1.1"},
-			procExecPath:       []string{"1.2.3", "1.2.3"},
-			lookbackCounts:     []string{"0.0.0", "0.0.0"},
-			lookbackTimedeltas: []string{"0.0.0", "0.0.0"},
-			targetWindowId:     1,
+			name:                    "fork and join",
+			algoExecPath:            []string{"1.2.4", "1.3.4"},
+			windowExecPath:          []string{"1.1.1", "1.1.1"},
+			procExecPath:            []string{"1.2.3", "1.2.3"},
+			lookbackCounts:          []string{"0.0.0", "0.0.0"},
+			lookbackTimedeltas:      []string{"0.0.0", "0.0.0"},
+			lookbackPartitions:      []string{"none.none.none", "none.none.none"},
+			lookbackPartitionFields: []string{"0.0.0", "0.0.0"},
+			targetWindowId:          1,
 			want: Plan{
 				Stages: []Stage{
 					{Tasks: []ProcessorTask{
@@ -90,26 +99,30 @@ func TestBuildPlan(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:               "cycle detection",
-			algoExecPath:       []string{"1.2", "2.1"},
-			windowExecPath:     []string{"1.1", "1.1"},
-			procExecPath:       []string{"1.1", "1.1"},
-			lookbackCounts:     []string{"0.0", "0.0"},
-			lookbackTimedeltas: []string{"0.0", "0.0"},
-			targetWindowId:     1,
-			want:               Plan{},
-			wantErr:            true,
+			name:                    "cycle detection",
+			algoExecPath:            []string{"1.2", "2.1"},
+			windowExecPath:          []string{"1.1", "1.1"},
+			procExecPath:            []string{"1.1", "1.1"},
+			lookbackCounts:          []string{"0.0", "0.0"},
+			lookbackTimedeltas:      []string{"0.0", "0.0"},
+			lookbackPartitions:      []string{"none.none", "none.none"},
+			lookbackPartitionFields: []string{"0.0", "0.0"},
+			targetWindowId:          1,
+			want:                    Plan{},
+			wantErr:                 true,
 		},
 		{
-			name:               "empty inputs",
-			algoExecPath:       []string{},
-			windowExecPath:     []string{},
-			procExecPath:       []string{},
-			lookbackCounts:     []string{},
-			lookbackTimedeltas: []string{},
-			targetWindowId:     1,
-			want:               Plan{Stages: nil},
-			wantErr:            false,
+			name:                    "empty inputs",
+			algoExecPath:            []string{},
+			windowExecPath:          []string{},
+			procExecPath:            []string{},
+			lookbackCounts:          []string{},
+			lookbackTimedeltas:      []string{},
+			lookbackPartitions:      []string{},
+			lookbackPartitionFields: []string{},
+			targetWindowId:          1,
+			want:                    Plan{Stages: nil},
+			wantErr:                 false,
 		},
 		{
 			name: "complex DAG",
@@ -128,9 +141,11 @@ func TestBuildPlan(t *testing.T) {
 				"1.2.3",   // Node 3 (proc 1) -> Node 4 (proc 2) -> Node 5 (proc 3)
 				"4.5.5.6", // Node 6 (proc 4) -> Node 7 (proc 5) -> Node 8 (proc 5) -> Node 9 (proc 6)
 			},
-			lookbackCounts:     []string{"0.0.0", "0.0.0", "0.0.0.0"},
-			lookbackTimedeltas: []string{"0.0.0", "0.0.0", "0.0.0.0"},
-			targetWindowId:     1,
+			lookbackCounts:          []string{"0.0.0", "0.0.0", "0.0.0.0"},
+			lookbackTimedeltas:      []string{"0.0.0", "0.0.0", "0.0.0.0"},
+			lookbackPartitions:      []string{"none.none.none", "none.none.none", "none.none.none.none"},
+			lookbackPartitionFields: []string{"0.0.0", "0.0.0", "0.0.0.0"},
+			targetWindowId:          1,
 			want: Plan{
 				Stages: []Stage{
 					{Tasks: []ProcessorTask{
@@ -196,6 +211,16 @@ func TestBuildPlan(t *testing.T) {
 				"0.13.0",
 				"0.0.0.40",
 			},
+			lookbackPartitions: []string{
+				"none.origin.none",
+				"none.none.metadata_field",
+				"none.none.none.none",
+			},
+			lookbackPartitionFields: []string{
+				"0.0.0",
+				"0.0.7",
+				"0.0.0.0",
+			},
 			targetWindowId: 1,
 			want: Plan{
 				Stages: []Stage{
@@ -210,7 +235,7 @@ func TestBuildPlan(t *testing.T) {
 					}},
 					{Tasks: []ProcessorTask{
 						{ProcId: 2, Nodes: []Node{
-							{algoId: 2, procId: 2, algoDeps: []AlgoDep{{AlgoId: 1, Lookback: Lookback{Count: 10, Timedelta: 0}}}},
+							{algoId: 2, procId: 2, algoDeps: []AlgoDep{{AlgoId: 1, Lookback: Lookback{Count: 10, Timedelta: 0, Partition: PartitionOrigin}}}},
 							{algoId: 4, procId: 2, algoDeps: []AlgoDep{{AlgoId: 3, Lookback: Lookback{Count: 0, Timedelta: 13}}}},
 						}},
 						{ProcId: 5, Nodes: []Node{
@@ -219,7 +244,7 @@ func TestBuildPlan(t *testing.T) {
 					}},
 					{Tasks: []ProcessorTask{
 						{ProcId: 3, Nodes: []Node{
-							{algoId: 5, procId: 3, algoDeps: []AlgoDep{{AlgoId: 2, Lookback: Lookback{Count: 0, Timedelta: 100}}, {AlgoId: 4, Lookback: Lookback{Count: 103, Timedelta: 0}}}},
+							{algoId: 5, procId: 3, algoDeps: []AlgoDep{{AlgoId: 2, Lookback: Lookback{Count: 0, Timedelta: 100}}, {AlgoId: 4, Lookback: Lookback{Count: 103, Timedelta: 0, Partition: PartitionMetadataField, PartitionFieldId: 7}}}},
 						}},
 						{ProcId: 5, Nodes: []Node{
 							{algoId: 8, procId: 5, algoDeps: []AlgoDep{{AlgoId: 7, Lookback: Lookback{Count: 10, Timedelta: 0}}}},
@@ -235,6 +260,19 @@ func TestBuildPlan(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:                    "unknown lookback partition",
+			algoExecPath:            []string{"1.2"},
+			windowExecPath:          []string{"1.1"},
+			procExecPath:            []string{"1.1"},
+			lookbackCounts:          []string{"0.5"},
+			lookbackTimedeltas:      []string{"0.0"},
+			lookbackPartitions:      []string{"none.asset"},
+			lookbackPartitionFields: []string{"0.0"},
+			targetWindowId:          1,
+			want:                    Plan{},
+			wantErr:                 true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -245,6 +283,8 @@ func TestBuildPlan(t *testing.T) {
 				tt.procExecPath,
 				tt.lookbackCounts,
 				tt.lookbackTimedeltas,
+				tt.lookbackPartitions,
+				tt.lookbackPartitionFields,
 				tt.targetWindowId,
 			)
 
@@ -283,3 +323,30 @@ func normalisePlan(plan Plan) Plan {
 	}
 	return plan
 }
+
+func TestStageLookbacks(t *testing.T) {
+	stage := Stage{Tasks: []ProcessorTask{
+		{ProcId: 1, Nodes: []Node{
+			{algoId: 3, procId: 1, algoDeps: []AlgoDep{
+				{AlgoId: 1},
+				{AlgoId: 2, Lookback: Lookback{Count: 5}},
+			}},
+		}},
+		{ProcId: 2, Nodes: []Node{
+			{algoId: 4, procId: 2, algoDeps: []AlgoDep{
+				{AlgoId: 1, Lookback: Lookback{Timedelta: 40, Partition: PartitionOrigin}},
+			}},
+			{algoId: 5, procId: 2},
+		}},
+	}}
+
+	want := []StageLookback{
+		{AlgoId: 3, Dep: AlgoDep{AlgoId: 2, Lookback: Lookback{Count: 5}}},
+		{AlgoId: 4, Dep: AlgoDep{AlgoId: 1, Lookback: Lookback{Timedelta: 40, Partition: PartitionOrigin}}},
+	}
+
+	got := slices.Collect(stage.Lookbacks())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookbacks() = %#v, want %#v", got, want)
+	}
+}
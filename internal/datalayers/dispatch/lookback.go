@@ -0,0 +1,112 @@
+package dispatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/orca-telemetry/core/internal/dag"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// Partition is the partition of a lookback that the window being processed
+// is in. Lookbacks only pass on the results of windows in its partition
+type Partition struct {
+	// ByOrigin is set when the lookback is partitioned by origin. An empty
+	// origin is a partition of its own
+	ByOrigin bool
+	Origin   string
+	// Field is the metadata field the lookback is partitioned by, if any,
+	// and Value its value in the window
+	Field string
+	Value *structpb.Value
+}
+
+// LookbackPartition returns the partition of the lookback that the window is
+// in, given the names of the metadata fields by id
+func LookbackPartition(
+	lookback dag.Lookback,
+	window *pb.Window,
+	metadataFieldNames map[int64]string,
+) (Partition, error) {
+	switch lookback.Partition {
+	case dag.PartitionOrigin:
+		return Partition{ByOrigin: true, Origin: window.GetOrigin()}, nil
+	case dag.PartitionMetadataField:
+		fieldName, ok := metadataFieldNames[lookback.PartitionFieldId]
+		if !ok {
+			return Partition{}, fmt.Errorf(
+				"metadata field ID %d of lookback partition not found",
+				lookback.PartitionFieldId,
+			)
+		}
+		value, ok := window.GetMetadata().GetFields()[fieldName]
+		if !ok {
+			return Partition{}, fmt.Errorf(
+				"window is missing metadata field '%s' required to partition lookback",
+				fieldName,
+			)
+		}
+		return Partition{Field: fieldName, Value: value}, nil
+	default:
+		return Partition{}, nil
+	}
+}
+
+// Contains reports whether a past window, of the origin and metadata, is in
+// the partition
+func (p Partition) Contains(origin string, metadata *structpb.Struct) bool {
+	if p.ByOrigin && origin != p.Origin {
+		return false
+	}
+	if p.Field != "" {
+		value, ok := metadata.GetFields()[p.Field]
+		return ok && proto.Equal(p.Value, value)
+	}
+	return true
+}
+
+// LookbackSearchRange returns the window times a lookback searches between.
+// Count lookbacks are unbounded in the past, with no start, and may include
+// windows that overlap the window being processed. Timedelta lookbacks end
+// where it starts
+func LookbackSearchRange(lookback dag.Lookback, window *pb.Window) (*time.Time, time.Time) {
+	if lookback.Count > 0 {
+		return nil, window.GetTimeTo().AsTime().UTC()
+	}
+	searchTo := window.GetTimeFrom().AsTime().UTC()
+	searchFrom := searchTo.Add(-time.Duration(lookback.Timedelta))
+	return &searchFrom, searchTo
+}
+
+// Float64s converts the values of an array result to those it is stored as
+func Float64s(values []float32) []float64 {
+	converted := make([]float64, len(values))
+	for i, value := range values {
+		converted[i] = float64(value)
+	}
+	return converted
+}
+
+// Float32s converts the stored values of an array result back
+func Float32s(values []float64) []float32 {
+	converted := make([]float32, len(values))
+	for i, value := range values {
+		converted[i] = float32(value)
+	}
+	return converted
+}
+
+// UnmarshalStruct converts stored JSON, of a struct result or of window
+// metadata, back to a struct
+func UnmarshalStruct(data []byte) (*structpb.Struct, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewStruct(m)
+}
@@ -0,0 +1,54 @@
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/orca-telemetry/core/internal/dag"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+func TestLookbackPartition(t *testing.T) {
+	metadata, err := structpb.NewStruct(map[string]any{"asset_id": "pump-1"})
+	assert.NoError(t, err)
+	window := &pb.Window{Origin: "", Metadata: metadata}
+	fieldNames := map[int64]string{1: "asset_id", 2: "fleet_id"}
+	other, err := structpb.NewStruct(map[string]any{"asset_id": "pump-2"})
+	assert.NoError(t, err)
+
+	// an empty origin is a partition of its own
+	partition, err := LookbackPartition(dag.Lookback{Partition: dag.PartitionOrigin}, window, fieldNames)
+	assert.NoError(t, err)
+	assert.True(t, partition.Contains("", other))
+	assert.False(t, partition.Contains("north", metadata))
+
+	partition, err = LookbackPartition(
+		dag.Lookback{Partition: dag.PartitionMetadataField, PartitionFieldId: 1},
+		window,
+		fieldNames,
+	)
+	assert.NoError(t, err)
+	assert.True(t, partition.Contains("north", metadata))
+	assert.False(t, partition.Contains("", other))
+	assert.False(t, partition.Contains("", &structpb.Struct{}))
+
+	partition, err = LookbackPartition(dag.Lookback{}, window, fieldNames)
+	assert.NoError(t, err)
+	assert.True(t, partition.Contains("north", other))
+
+	// the window must carry the field it is partitioned by
+	_, err = LookbackPartition(
+		dag.Lookback{Partition: dag.PartitionMetadataField, PartitionFieldId: 2},
+		window,
+		fieldNames,
+	)
+	assert.Error(t, err)
+	_, err = LookbackPartition(
+		dag.Lookback{Partition: dag.PartitionMetadataField, PartitionFieldId: 3},
+		window,
+		fieldNames,
+	)
+	assert.Error(t, err)
+}
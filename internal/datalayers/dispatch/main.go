@@ -0,0 +1,581 @@
+// Package dispatch runs the execution plans of windows on processors. It is
+// shared by the datalayers, which read the processors, algorithms and past
+// results a plan needs, and store the results it produces
+package dispatch
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/certs"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/envs"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// ProcessorCheckTimeout is how long a processor has to answer the health
+// check of orca doctor
+const ProcessorCheckTimeout = 5 * time.Second
+
+// Processor is a registered processor that tasks are dispatched to
+type Processor struct {
+	ID               int64
+	Name             string
+	Runtime          string
+	Namespace        string
+	ConnectionString string
+	// overrides of the TLS settings the processor is connected to with
+	TLSServerName         string
+	TLSInsecureSkipVerify bool
+}
+
+// Algorithm is a registered algorithm
+type Algorithm struct {
+	ID      int64
+	Name    string
+	Version string
+}
+
+// LookbackKey identifies the dependency of an algorithm on another
+type LookbackKey struct {
+	DependencyID int64
+	DependantID  int64
+}
+
+// Lookback holds the past results of a dependency, either as rows or, when
+// the dependency requests an aggregate, as buckets
+type Lookback struct {
+	Rows    []*pb.AlgorithmDependencyResultRow
+	Buckets []*pb.LookbackAggregateBucket
+}
+
+// Store is the datalayer a plan reads past results from, and stores the
+// results of its algorithms to
+type Store interface {
+	// ReadLookbacks fetches the past results of every lookback in a stage
+	ReadLookbacks(ctx context.Context, stage dag.Stage) (map[LookbackKey]Lookback, error)
+	// CreateResult stores the result of an algorithm, returning its id
+	CreateResult(ctx context.Context, algorithm Algorithm, result *pb.ExecutionResult) (int64, error)
+}
+
+// Dispatcher connects to processors, with the processor certificates of
+// the configuration, if any
+type Dispatcher struct {
+	// CA bundle and client certificate processors are connected to with,
+	// nil when none are configured
+	processorCerts *certs.Reloader
+
+	mu sync.Mutex
+	// when the processor certificates were last checked for changes
+	certsCheckedAt time.Time
+}
+
+// NewDispatcher loads the processor certificates of the configuration
+func NewDispatcher() (*Dispatcher, error) {
+	config := envs.GetConfig()
+	if config.ProcessorCAFile == "" && config.ProcessorCertFile == "" {
+		return &Dispatcher{}, nil
+	}
+	processorCerts, err := certs.NewReloader(config.ProcessorCertFile, config.ProcessorKeyFile, config.ProcessorCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load processor TLS: %w", err)
+	}
+	return &Dispatcher{processorCerts: processorCerts, certsCheckedAt: time.Now()}, nil
+}
+
+// Credentials returns the transport credentials core connects to the
+// processor with. TLS is used in production, when processor certificates
+// are configured, or when the registration of the processor sets TLS, with
+// the server name overridden by the registration. Registrations can only
+// skip verification outside production
+func (d *Dispatcher) Credentials(ctx context.Context, config *envs.Config, proc Processor) credentials.TransportCredentials {
+	registeredTLS := proc.TLSServerName != "" || proc.TLSInsecureSkipVerify
+	if !config.IsProduction && d.processorCerts == nil && !registeredTLS {
+		return insecure.NewCredentials()
+	}
+
+	serverName := proc.TLSServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(proc.ConnectionString)
+		if err != nil {
+			host = proc.ConnectionString
+		}
+		serverName = host
+	}
+
+	tlsConfig := &tls.Config{ServerName: serverName}
+	if d.processorCerts != nil {
+		d.reloadCerts(ctx, time.Now())
+		tlsConfig = d.processorCerts.ClientTLSConfig(serverName)
+	}
+	// processors registered before production was enabled are verified
+	// regardless
+	switch {
+	case proc.TLSInsecureSkipVerify && config.IsProduction:
+		slog.ErrorContext(ctx, "processor is registered to skip certificate verification, which is ignored in production")
+	case proc.TLSInsecureSkipVerify:
+		slog.WarnContext(ctx, "not verifying the certificate of the processor")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	return credentials.NewTLS(tlsConfig)
+}
+
+// reloadCerts reloads the processor certificates when they have not been
+// checked for changes within certs.ReloadInterval, so that rotated
+// certificates are picked up by the next connections without checking the
+// files on every dispatch
+func (d *Dispatcher) reloadCerts(ctx context.Context, now time.Time) {
+	d.mu.Lock()
+	due := now.Sub(d.certsCheckedAt) >= certs.ReloadInterval
+	if due {
+		d.certsCheckedAt = now
+	}
+	d.mu.Unlock()
+	if !due {
+		return
+	}
+
+	reloaded, err := d.processorCerts.Reload()
+	if err != nil {
+		slog.ErrorContext(ctx, "could not reload processor certificates, keeping the loaded ones", "error", err)
+		return
+	}
+	if reloaded {
+		slog.InfoContext(ctx, "reloaded processor certificates")
+	}
+}
+
+// CheckProcessors health checks every processor, at once, for orca doctor
+func (d *Dispatcher) CheckProcessors(ctx context.Context, processors []Processor) []types.Check {
+	config := envs.GetConfig()
+	checks := make([]types.Check, len(processors))
+	var wg sync.WaitGroup
+	for ii, proc := range processors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[ii] = d.checkProcessor(ctx, config, proc)
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+func (d *Dispatcher) checkProcessor(ctx context.Context, config *envs.Config, proc Processor) types.Check {
+	check := types.Check{
+		Name: fmt.Sprintf("processor %s/%s (%s)", proc.Namespace, proc.Name, proc.Runtime),
+		Fix: fmt.Sprintf(
+			"check that the processor is running and reachable from orca at %s, or register it again with its current address",
+			proc.ConnectionString,
+		),
+	}
+
+	conn, err := grpc.NewClient(
+		proc.ConnectionString,
+		grpc.WithTransportCredentials(d.Credentials(ctx, config, proc)),
+	)
+	if err != nil {
+		check.Err = fmt.Errorf("could not connect to processor: %w", err)
+		return check
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, ProcessorCheckTimeout)
+	defer cancel()
+	resp, err := pb.NewOrcaProcessorClient(conn).HealthCheck(ctx, &pb.HealthCheckRequest{
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		check.Err = fmt.Errorf("health check failed: %w", err)
+		return check
+	}
+	if resp.GetStatus() != pb.HealthCheckResponse_STATUS_SERVING {
+		check.Err = fmt.Errorf("processor is %s: %s", resp.GetStatus(), resp.GetMessage())
+		check.Fix = "check the logs of the processor"
+	}
+	return check
+}
+
+// Run dispatches the stages of a plan to their processors in turn, passing
+// each algorithm the results of its dependencies, and storing the results
+// as they are streamed back
+func (d *Dispatcher) Run(
+	ctx context.Context,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	processorMap map[int64]Processor,
+	algorithmMap map[int64]Algorithm,
+	store Store,
+) (err error) {
+	slog.InfoContext(
+		ctx,
+		"calculated execution plan",
+		"stages",
+		len(executionPlan.Stages),
+		"processors",
+		len(executionPlan.AffectedProcessors),
+	)
+
+	// map of algorithm Ids to results
+	resultMap := make(
+		map[int64]*pb.ExecutionResult,
+	)
+
+	// get the environment
+	config := envs.GetConfig()
+
+	// for each stage, build processsings
+	slog.DebugContext(ctx, "execution plan", "execution_plan", executionPlan)
+	// spans of the stage and processor call in flight, ended with the error
+	// processing stops on, if any
+	var stageSpan, execSpan trace.Span
+	defer func() {
+		for _, span := range []trace.Span{execSpan, stageSpan} {
+			if span != nil && span.IsRecording() {
+				tracing.End(span, err)
+			}
+		}
+	}()
+
+	for stageIdx, stage := range executionPlan.Stages {
+		var stageCtx context.Context
+		stageCtx, stageSpan = tracing.Tracer.Start(ctx, "Stage", trace.WithAttributes(
+			attribute.Int("orca.stage", stageIdx),
+			attribute.Int("orca.tasks", len(stage.Tasks)),
+		))
+
+		// fetch the past results of every lookback in the stage at once
+		stageLookbacks, err := store.ReadLookbacks(stageCtx, stage)
+		if err != nil {
+			return err
+		}
+
+		stageProcessors := make(map[int64]bool)
+		for _, task := range stage.Tasks {
+			if !stageProcessors[task.ProcId] {
+				stageProcessors[task.ProcId] = true
+				metrics.StagesDispatched.WithLabelValues(processorMap[task.ProcId].Name).Inc()
+			}
+		}
+
+		for _, task := range stage.Tasks {
+			proc, ok := processorMap[task.ProcId]
+			if !ok {
+				slog.ErrorContext(stageCtx, "Processor not found for task", "processor_id", task.ProcId)
+				return fmt.Errorf("processor ID %d not found", task.ProcId)
+			}
+			taskCtx := logging.With(stageCtx, logging.KeyProcessor, proc.Name)
+			var conn *grpc.ClientConn
+
+			conn, err = grpc.NewClient(
+				proc.ConnectionString,
+				grpc.WithTransportCredentials(d.Credentials(taskCtx, config, proc)),
+			)
+			if err != nil {
+				slog.ErrorContext(taskCtx, "could not connect to processor", "error", err)
+				return fmt.Errorf("could not contact processor: %w", err)
+			}
+			// WARN: close conn when done (not deferred inside a loop)
+			defer func(conn *grpc.ClientConn) {
+				if err := conn.Close(); err != nil {
+					slog.Warn("error closing gRPC connection", "error", err)
+				}
+			}(conn)
+
+			client := pb.NewOrcaProcessorClient(conn)
+			healthCheckResponse, err := client.HealthCheck(tracing.Inject(taskCtx), &pb.HealthCheckRequest{
+				Timestamp: time.Now().Unix(),
+			})
+			if err != nil {
+				metrics.ProcessorServing.WithLabelValues(proc.Name).Set(0)
+				slog.ErrorContext(taskCtx, "issue contacting processor", "error", err)
+
+				return fmt.Errorf("issue contacting processor: %w", err)
+			}
+			if healthCheckResponse.Status != pb.HealthCheckResponse_STATUS_SERVING {
+				metrics.ProcessorServing.WithLabelValues(proc.Name).Set(0)
+				slog.ErrorContext(
+					taskCtx,
+					"cannot execute stage, processor not serving",
+					"status",
+					healthCheckResponse.Status,
+					"message",
+					healthCheckResponse.Message,
+				)
+				return fmt.Errorf("cannot execute stage, processor not serving: %w", err)
+			}
+			metrics.ProcessorServing.WithLabelValues(proc.Name).Set(1)
+
+			// generate an execution id
+			execUuid := uuid.New()
+			execId := strings.ReplaceAll(execUuid.String(), "-", "")
+			taskCtx = logging.With(taskCtx, logging.KeyExecID, execId)
+
+			// algorithms the processor is asked to run, which its results
+			// are matched back to
+			taskAlgorithms := make([]Algorithm, len(task.Nodes))
+			algorithmExecutions := make([]*pb.ExecuteAlgorithm, len(task.Nodes))
+
+			for ii, node := range task.Nodes {
+				algo, ok := algorithmMap[node.AlgoId()]
+
+				if !ok {
+					slog.ErrorContext(taskCtx, "algorithm not found", "algorithm_id", node.AlgoId())
+					return fmt.Errorf("algorithm ID %d not found", node.AlgoId())
+				}
+				taskAlgorithms[ii] = algo
+
+				algorithm_dependencies := make([]*pb.AlgorithmDependencyResult, node.LenAlgoDeps())
+
+				jj := 0
+				for algoDep := range node.AlgoDeps() {
+					// get details of the algorithm - dependencies will only
+					// exist in this block if they have run
+					algorithm_result := resultMap[algoDep.AlgoId].GetAlgorithmResult()
+
+					// log the result as the first entry before considering lookbacks
+					dep_results := []*pb.AlgorithmDependencyResultRow{
+						{
+							Result: algorithm_result.GetResult(),
+							Window: window,
+						},
+					}
+
+					lookback := stageLookbacks[LookbackKey{algoDep.AlgoId, node.AlgoId()}]
+					algorithm_dependencies[jj] = &pb.AlgorithmDependencyResult{
+						Algorithm:         algorithm_result.GetAlgorithm(),
+						Result:            append(dep_results, lookback.Rows...),
+						LookbackAggregate: lookback.Buckets,
+					}
+					jj++
+				}
+				algorithmExecutions[ii] = &pb.ExecuteAlgorithm{
+					Algorithm: &pb.Algorithm{
+						Name:    algo.Name,
+						Version: algo.Version,
+					},
+					Dependencies: algorithm_dependencies,
+				}
+			}
+
+			execReq := &pb.ExecutionRequest{
+				ExecId:              execId,
+				Window:              window,
+				AlgorithmExecutions: algorithmExecutions,
+			}
+
+			// the trace continues in the processor through the call metadata
+			var execCtx context.Context
+			execCtx, execSpan = tracing.Tracer.Start(
+				taskCtx,
+				"ExecuteDagPart",
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("orca.processor", proc.Name),
+					attribute.String("orca.exec_id", execId),
+					attribute.Int("orca.algorithms", len(algorithmExecutions)),
+				),
+			)
+
+			metrics.TasksDispatched.WithLabelValues(proc.Name).Inc()
+			execStart := time.Now()
+			observeExec := func(status string) {
+				metrics.ExecuteDagPartDuration.WithLabelValues(proc.Name, status).Observe(time.Since(execStart).Seconds())
+			}
+			stream, err := client.ExecuteDagPart(tracing.Inject(execCtx), execReq)
+			if err != nil {
+				observeExec(metrics.StatusError)
+				slog.ErrorContext(execCtx, "failed to start DAG part execution", "error", err)
+				return err
+			}
+
+			// recieve streamed execution results
+			for {
+				result, err := stream.Recv()
+				// error handling
+				if err != nil {
+					if errors.Is(err, context.Canceled) ||
+						errors.Is(err, context.DeadlineExceeded) {
+						observeExec(metrics.StatusError)
+						execSpan.RecordError(err)
+						slog.WarnContext(execCtx, "context done while receiving execution result")
+						break
+					}
+					if err == io.EOF {
+						observeExec(metrics.StatusOK)
+						slog.InfoContext(execCtx, "finished receiving execution results")
+						break
+					}
+					observeExec(metrics.StatusError)
+					slog.ErrorContext(execCtx, "error receiving execution result", "error", err)
+					return err
+				}
+
+				resultAlgorithm := result.AlgorithmResult.GetAlgorithm()
+				resultCtx := logging.With(
+					execCtx,
+					logging.KeyAlgorithm,
+					resultAlgorithm.GetName(),
+					"algorithm_version",
+					resultAlgorithm.GetVersion(),
+				)
+				slog.InfoContext(resultCtx, "received execution result")
+
+				algoIdx := slices.IndexFunc(taskAlgorithms, func(algo Algorithm) bool {
+					return algo.Name == resultAlgorithm.GetName() &&
+						algo.Version == resultAlgorithm.GetVersion()
+				})
+				if algoIdx < 0 {
+					slog.ErrorContext(resultCtx, "received a result of an algorithm the processor was not asked to run")
+					return fmt.Errorf(
+						"processor %v returned a result of algorithm %v that it was not asked to run",
+						proc.Name,
+						resultAlgorithm.GetName(),
+					)
+				}
+				algo := taskAlgorithms[algoIdx]
+
+				// add the result in to the result map
+				resultMap[algo.ID] = result
+
+				storedResult := metrics.ResultsStored.MustCurryWith(prometheus.Labels{
+					"algorithm":         resultAlgorithm.GetName(),
+					"algorithm_version": resultAlgorithm.GetVersion(),
+				})
+
+				resultId, err := store.CreateResult(ctx, algo, result)
+				if err != nil {
+					storedResult.WithLabelValues(metrics.StatusError).Inc()
+					slog.ErrorContext(resultCtx, "Error inserting result", "error", err)
+					return err
+				}
+				storedResult.WithLabelValues(metrics.StatusOK).Inc()
+				slog.InfoContext(resultCtx, "Inserted result", "result_id", resultId)
+			}
+			execSpan.End()
+		}
+		stageSpan.End()
+	}
+	return nil
+}
+
+// PastResult is a past value result of an algorithm, with the times of the
+// window it was produced for
+type PastResult struct {
+	Value    float64
+	TimeFrom time.Time
+	TimeTo   time.Time
+}
+
+// Aggregate summarises the past results of a lookback with an aggregate
+// function, for datalayers that cannot aggregate them in their queries.
+// Results are bucketed by window end time when bucket is positive, with
+// buckets aligned to the unix epoch, and buckets are ordered by time
+func Aggregate(
+	function pb.LookbackAggregate_Function,
+	percentile float64,
+	bucket time.Duration,
+	results []PastResult,
+) ([]*pb.LookbackAggregateBucket, error) {
+	groups := make(map[int64][]PastResult)
+	var keys []int64
+	for _, res := range results {
+		var key int64
+		if bucket > 0 {
+			// time.Truncate aligns to the zero time, not the epoch
+			ns := res.TimeTo.UnixNano()
+			key = ns - ((ns%int64(bucket))+int64(bucket))%int64(bucket)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], res)
+	}
+
+	buckets := make([]*pb.LookbackAggregateBucket, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		timeFrom, timeTo := group[0].TimeFrom, group[0].TimeTo
+		last := group[0]
+		values := make([]float64, len(group))
+		var sum float64
+		for ii, res := range group {
+			values[ii] = res.Value
+			sum += res.Value
+			if res.TimeFrom.Before(timeFrom) {
+				timeFrom = res.TimeFrom
+			}
+			if res.TimeTo.After(timeTo) {
+				timeTo = res.TimeTo
+			}
+			if res.TimeTo.After(last.TimeTo) {
+				last = res
+			}
+		}
+		slices.Sort(values)
+
+		var value float64
+		switch function {
+		case pb.LookbackAggregate_FUNCTION_COUNT:
+			value = float64(len(group))
+		case pb.LookbackAggregate_FUNCTION_SUM:
+			value = sum
+		case pb.LookbackAggregate_FUNCTION_MEAN:
+			value = sum / float64(len(group))
+		case pb.LookbackAggregate_FUNCTION_MIN:
+			value = values[0]
+		case pb.LookbackAggregate_FUNCTION_MAX:
+			value = values[len(values)-1]
+		case pb.LookbackAggregate_FUNCTION_LAST:
+			value = last.Value
+		case pb.LookbackAggregate_FUNCTION_PERCENTILE:
+			value = percentileCont(values, percentile)
+		default:
+			return nil, fmt.Errorf("lookback aggregate %v not supported", function)
+		}
+		buckets = append(buckets, &pb.LookbackAggregateBucket{
+			TimeFrom: timestamppb.New(timeFrom),
+			TimeTo:   timestamppb.New(timeTo),
+			Count:    uint64(len(group)),
+			Value:    value,
+		})
+	}
+	slices.SortFunc(buckets, func(a, b *pb.LookbackAggregateBucket) int {
+		return a.GetTimeFrom().AsTime().Compare(b.GetTimeFrom().AsTime())
+	})
+	return buckets, nil
+}
+
+// percentileCont interpolates the percentile of sorted values, as the
+// PERCENTILE_CONT aggregate of postgres does
+func percentileCont(sorted []float64, percentile float64) float64 {
+	pos := percentile * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(pos-float64(lower))
+}
@@ -0,0 +1,77 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orca-telemetry/core/internal/envs"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+func TestAggregate(t *testing.T) {
+	minute := func(m int) time.Time { return time.Unix(int64(m)*60, 0).UTC() }
+	results := []PastResult{
+		{Value: 1, TimeFrom: minute(0), TimeTo: minute(1)},
+		{Value: 2, TimeFrom: minute(1), TimeTo: minute(2)},
+		{Value: 4, TimeFrom: minute(2), TimeTo: minute(3)},
+		{Value: 3, TimeFrom: minute(3), TimeTo: minute(4)},
+	}
+
+	testCases := []struct {
+		name     string
+		function pb.LookbackAggregate_Function
+		expected float64
+	}{
+		{"count", pb.LookbackAggregate_FUNCTION_COUNT, 4},
+		{"sum", pb.LookbackAggregate_FUNCTION_SUM, 10},
+		{"mean", pb.LookbackAggregate_FUNCTION_MEAN, 2.5},
+		{"min", pb.LookbackAggregate_FUNCTION_MIN, 1},
+		{"max", pb.LookbackAggregate_FUNCTION_MAX, 4},
+		{"last", pb.LookbackAggregate_FUNCTION_LAST, 3},
+		{"median", pb.LookbackAggregate_FUNCTION_PERCENTILE, 2.5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets, err := Aggregate(tc.function, 0.5, 0, results)
+			assert.NoError(t, err)
+			assert.Len(t, buckets, 1)
+			assert.Equal(t, tc.expected, buckets[0].GetValue())
+			assert.Equal(t, uint64(4), buckets[0].GetCount())
+			assert.Equal(t, minute(0), buckets[0].GetTimeFrom().AsTime())
+			assert.Equal(t, minute(4), buckets[0].GetTimeTo().AsTime())
+		})
+	}
+
+	// buckets are aligned to the epoch by window end time, oldest first
+	buckets, err := Aggregate(pb.LookbackAggregate_FUNCTION_SUM, 0, 2*time.Minute, results)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, []float64{1, 6, 3}, []float64{buckets[0].GetValue(), buckets[1].GetValue(), buckets[2].GetValue()})
+
+	_, err = Aggregate(pb.LookbackAggregate_FUNCTION_UNSPECIFIED, 0, 0, results)
+	assert.Error(t, err)
+
+	buckets, err = Aggregate(pb.LookbackAggregate_FUNCTION_COUNT, 0, 0, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+}
+
+func TestCredentials(t *testing.T) {
+	dispatcher := &Dispatcher{}
+	development := &envs.Config{}
+	production := &envs.Config{IsProduction: true}
+	protocol := func(config *envs.Config, proc Processor) string {
+		return dispatcher.Credentials(context.Background(), config, proc).Info().SecurityProtocol
+	}
+
+	plain := Processor{ConnectionString: "localhost:5380"}
+	assert.Equal(t, "insecure", protocol(development, plain))
+	assert.Equal(t, "tls", protocol(production, plain))
+
+	// the TLS of a registration is honoured outside production too
+	assert.Equal(t, "tls", protocol(development, Processor{ConnectionString: "10.0.0.4:5380", TLSServerName: "processor.internal"}))
+	assert.Equal(t, "tls", protocol(development, Processor{ConnectionString: "localhost:5380", TLSInsecureSkipVerify: true}))
+}
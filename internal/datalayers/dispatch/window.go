@@ -0,0 +1,63 @@
+package dispatch
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// Window is an emitted window, stored by a datalayer, that is processed by
+// ProcessWindow
+type Window interface {
+	// RunPlan reads the processors and algorithms of the execution plan of
+	// the window, and runs it with Dispatcher.Run
+	RunPlan(ctx context.Context, executionPlan dag.Plan) error
+	// Rollup counts the window towards the rollups it is a child of,
+	// emitting the rolled up windows that are then complete
+	Rollup(ctx context.Context)
+}
+
+// ProcessWindow runs the algorithms triggered by a window and, once they
+// have all run, counts the window towards the rollups it is a child of.
+// The window holds its slots of the concurrency limits on emitting until
+// then
+func ProcessWindow(
+	ctx context.Context,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	windowID int64,
+	stored Window,
+) {
+	// frees the window's slots of the concurrency limits on emitting
+	defer ratelimit.Release(ctx)
+
+	ctx, span := tracing.Tracer.Start(ctx, "ProcessWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", window.GetWindowTypeName()),
+		attribute.String("orca.window_type_version", window.GetWindowTypeVersion()),
+		attribute.Int64("orca.window_id", windowID),
+	))
+	defer span.End()
+	ctx = logging.With(logging.WithWindow(ctx, window), logging.KeyWindowID, windowID)
+
+	if len(executionPlan.Stages) > 0 {
+		if err := stored.RunPlan(ctx, executionPlan); err != nil {
+			slog.ErrorContext(ctx, "could not process window", "error", err)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return
+		}
+	}
+
+	// rolled up windows are processed after this one, and were not admitted
+	// by the limiter, so must not free its slots again
+	stored.Rollup(ratelimit.WithRelease(ctx, nil))
+}
@@ -1,6 +1,8 @@
 // Package datalayers provides a factory function for generating a
 // datalayer client. Current supported datalayers are:
 // - PostgreSQL
+// - SQLite, embedded for local development and edge deployments
+// - Memory, for tests and ephemeral runs
 package datalayers
 
 import (
@@ -8,8 +10,10 @@ import (
 	"fmt"
 	"log/slog"
 
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/datalayers/memory"
 	psql "github.com/orca-telemetry/core/internal/datalayers/postgresql"
-	types "github.com/orca-telemetry/core/internal/types"
+	"github.com/orca-telemetry/core/internal/datalayers/sqlite"
 )
 
 // Platform resprents a database storage platform (e.g. PostgreSQL)
@@ -18,12 +22,16 @@ type Platform string
 const (
 	// PostgreSQL is the postgresql platform
 	PostgreSQL Platform = "postgresql"
+	// SQLite is a sqlite database file
+	SQLite Platform = "sqlite"
+	// Memory keeps everything in the memory of the process
+	Memory Platform = "memory"
 )
 
 // check if the platform is supported
 func (p Platform) isValid() bool {
 	switch p {
-	case PostgreSQL:
+	case PostgreSQL, SQLite, Memory:
 		return true
 	default:
 		return false
@@ -43,6 +51,10 @@ func NewDatalayerClient(
 	switch platform {
 	case PostgreSQL:
 		return psql.NewClient(ctx, connStr)
+	case SQLite:
+		return sqlite.NewClient(ctx, connStr)
+	case Memory:
+		return memory.NewClient(ctx, connStr)
 	default:
 		slog.Error(
 			"attempted to access unsuported platform",
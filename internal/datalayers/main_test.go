@@ -2,28 +2,50 @@ package datalayers
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	types "github.com/orca-telemetry/core/internal/types"
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/datalayer/conformance"
+	"github.com/orca-telemetry/core/internal/auth"
 	pb "github.com/orca-telemetry/core/protobufs/go"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 )
 
 var (
-	testConnStr string
-	testCtx     context.Context
+	testPlatform Platform
+	testConnStr  string
+	testCtx      context.Context
 )
 
+// the tests run against the datalayer named by ORCA_TEST_DATALAYER, postgresql
+// by default, as the behaviour of every datalayer should be the same
 func TestMain(m *testing.M) {
 	var cleanup func()
 	testCtx = context.Background()
-	testConnStr, cleanup = setupPgOnce(testCtx)
+	testPlatform = Platform(os.Getenv("ORCA_TEST_DATALAYER"))
+	switch testPlatform {
+	case "", PostgreSQL:
+		testPlatform = PostgreSQL
+		testConnStr, cleanup = setupPgOnce(testCtx)
+	case SQLite:
+		testConnStr, cleanup = setupSqliteOnce()
+	case Memory:
+		testConnStr, cleanup = "memory://test", func() {}
+	default:
+		panic("Unsupported ORCA_TEST_DATALAYER: " + string(testPlatform))
+	}
 
 	// runs all tests
 	code := m.Run()
@@ -65,6 +87,28 @@ func setupPgOnce(ctx context.Context) (string, func()) {
 	return connStr, cleanup
 }
 
+// confirms a sqlite database file can be created and migrated
+func setupSqliteOnce() (string, func()) {
+	dir, err := os.MkdirTemp("", "orca-test")
+	if err != nil {
+		panic("Failed to create database directory: " + err.Error())
+	}
+
+	connStr := "sqlite://" + filepath.Join(dir, "test.db")
+	err = MigrateDatalayer("sqlite", connStr)
+	if err != nil {
+		panic("Failed to migrate database: " + err.Error())
+	}
+
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			println("Failed to remove database directory:", err.Error())
+		}
+	}
+
+	return connStr, cleanup
+}
+
 // TestAddProcessor tests that several processors can be added
 func TestAddProcessor(t *testing.T) {
 
@@ -85,8 +129,7 @@ func TestAddProcessor(t *testing.T) {
 	processorConnStr_1 := mockListener_1.Addr().String()
 	processorConnStr_2 := mockListener_2.Addr().String()
 
-	// TODO: paramaterise if we have more datalayers (e.g. MySQL, SQLite) - high level function should be the same between them
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	asset_id := pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
@@ -182,8 +225,7 @@ func TestLookbackDependenciesBetweenAlgorithms(t *testing.T) {
 	processorConnStr_1 := mockListener_1.Addr().String()
 	processorConnStr_2 := mockListener_2.Addr().String()
 
-	// TODO: paramaterise if we have more datalayers (e.g. MySQL, SQLite) - high level function should be the same between them
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	asset_id := pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
@@ -287,8 +329,7 @@ func TestMetadataFieldsChangeable(t *testing.T) {
 	// get the actual address the mock server is listening on
 	processorConnStr := mockListener.Addr().String()
 
-	// TODO: paramaterise if we have more datalayers (e.g. MySQL, SQLite) - high level function should be the same between them
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	asset_id := pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
@@ -459,8 +500,7 @@ func TestWindowTypeDefintion(t *testing.T) {
 	// get the actual address the mock server is listening on
 	processorConnStr := mockListener.Addr().String()
 
-	// TODO: paramaterise if we have more datalayers (e.g. MySQL, SQLite) - high level function should be the same between them
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	asset_id := pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
@@ -508,7 +548,7 @@ func TestWindowTypeDefintion(t *testing.T) {
 }
 
 func TestCircularDependency(t *testing.T) {
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	windowType := pb.WindowType{
@@ -577,8 +617,553 @@ func TestCircularDependency(t *testing.T) {
 	assert.Equal(t, proc.GetName(), circularError.ToAlgoProcessor)
 }
 
+// TestLookbackPartition tests that partitioned lookbacks can only reference metadata fields of the
+// dependant algorithm's window type
+func TestLookbackPartition(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	asset_id := pb.MetadataField{Name: "asset_id", Description: "Unique ID of the asset"}
+
+	windowType := pb.WindowType{
+		Name:           "TestPartitionWindow",
+		Version:        "1.0.0",
+		MetadataFields: []*pb.MetadataField{&asset_id},
+	}
+
+	algo1 := pb.Algorithm{
+		Name:       "TestPartitionAlgorithm1",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_VALUE,
+	}
+
+	algo2 := pb.Algorithm{
+		Name:       "TestPartitionAlgorithm2",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_VALUE,
+	}
+
+	proc := pb.ProcessorRegistration{
+		Name:                "TestPartitionProcessor",
+		Runtime:             "Test",
+		ConnectionStr:       "Test",
+		SupportedAlgorithms: []*pb.Algorithm{&algo1, &algo2},
+	}
+
+	// 1. partition by origin
+	algo1.Dependencies = []*pb.AlgorithmDependency{
+		{
+			Name:              "TestPartitionAlgorithm2",
+			Version:           "1.0.0",
+			ProcessorName:     "TestPartitionProcessor",
+			ProcessorRuntime:  "Test",
+			Lookback:          &pb.AlgorithmDependency_LookbackNum{LookbackNum: 3},
+			LookbackPartition: pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN,
+		},
+	}
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 2. partition by a metadata field of the window type
+	algo1.Dependencies[0].LookbackPartition = pb.AlgorithmDependency_LOOKBACK_PARTITION_METADATA_FIELD
+	algo1.Dependencies[0].LookbackPartitionField = "asset_id"
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 3. partition by a field the window type does not carry
+	algo1.Dependencies[0].LookbackPartitionField = "fleet_id"
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.Error(t, err)
+}
+
+// TestLookbackAggregate tests that only value results can be aggregated beyond counting them
+func TestLookbackAggregate(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	windowType := pb.WindowType{
+		Name:    "TestAggregateWindow",
+		Version: "1.0.0",
+	}
+
+	algo1 := pb.Algorithm{
+		Name:       "TestAggregateAlgorithm1",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_VALUE,
+	}
+
+	algo2 := pb.Algorithm{
+		Name:       "TestAggregateAlgorithm2",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_VALUE,
+	}
+
+	algo3 := pb.Algorithm{
+		Name:       "TestAggregateAlgorithm3",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_STRUCT,
+	}
+
+	proc := pb.ProcessorRegistration{
+		Name:                "TestAggregateProcessor",
+		Runtime:             "Test",
+		ConnectionStr:       "Test",
+		SupportedAlgorithms: []*pb.Algorithm{&algo1, &algo2, &algo3},
+	}
+
+	// 1. a 30 day mean, bucketed by day, of a value result
+	algo1.Dependencies = []*pb.AlgorithmDependency{
+		{
+			Name:             "TestAggregateAlgorithm2",
+			Version:          "1.0.0",
+			ProcessorName:    "TestAggregateProcessor",
+			ProcessorRuntime: "Test",
+			Lookback: &pb.AlgorithmDependency_LookbackTimeDelta{
+				LookbackTimeDelta: uint64(30 * 24 * time.Hour),
+			},
+			LookbackAggregate: &pb.LookbackAggregate{
+				Function:        pb.LookbackAggregate_FUNCTION_MEAN,
+				BucketTimeDelta: uint64(24 * time.Hour),
+			},
+		},
+	}
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 2. struct results can be counted
+	algo1.Dependencies[0].Name = "TestAggregateAlgorithm3"
+	algo1.Dependencies[0].LookbackAggregate.Function = pb.LookbackAggregate_FUNCTION_COUNT
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 3. but not otherwise aggregated
+	algo1.Dependencies[0].LookbackAggregate.Function = pb.LookbackAggregate_FUNCTION_MAX
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.Error(t, err)
+}
+
+// TestScheduledWindowType tests registering a schedule on a window type and emitting its windows
+func TestScheduledWindowType(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	windowType := pb.WindowType{
+		Name:        "TestScheduledWindow",
+		Version:     "1.0.0",
+		Description: "Emitted every minute by Orca",
+		Schedule: &pb.WindowSchedule{
+			Trigger: &pb.WindowSchedule_Cron{Cron: "* * * * *"},
+			Origin:  "orca-scheduler",
+		},
+	}
+
+	algo := pb.Algorithm{
+		Name:       "TestScheduledAlgorithm",
+		Version:    "1.0.0",
+		WindowType: &windowType,
+		ResultType: pb.ResultType_NONE,
+	}
+
+	proc := pb.ProcessorRegistration{
+		Name:                "TestScheduledProcessor",
+		Runtime:             "Test",
+		ConnectionStr:       "Test",
+		SupportedAlgorithms: []*pb.Algorithm{&algo},
+	}
+
+	// 1. register the scheduled window type
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 2. the schedule is exposed with the window type
+	state, err := dlyr.Expose(testCtx, &pb.ExposeSettings{})
+	assert.NoError(t, err)
+	var exposedSchedule *pb.WindowSchedule
+	for _, exposedProc := range state.GetProcessors() {
+		for _, exposedAlgo := range exposedProc.GetSupportedAlgorithms() {
+			if exposedAlgo.GetWindowType().GetName() == windowType.GetName() {
+				exposedSchedule = exposedAlgo.GetWindowType().GetSchedule()
+			}
+		}
+	}
+	assert.Equal(t, "* * * * *", exposedSchedule.GetCron())
+	assert.Equal(t, "orca-scheduler", exposedSchedule.GetOrigin())
+
+	// 3. windows that have closed are emitted
+	err = dlyr.EmitScheduledWindows(testCtx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	// 4. invalid schedules are rejected
+	windowType.Schedule.Trigger = &pb.WindowSchedule_Cron{Cron: "every minute"}
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.Error(t, err)
+}
+
+// TestRolledUpWindowType tests registering a rollup between window types
+func TestRolledUpWindowType(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	minuteWindowType := pb.WindowType{
+		Name:        "TestMinuteWindow",
+		Version:     "1.0.0",
+		Description: "Emitted every minute",
+	}
+
+	hourlyWindowType := pb.WindowType{
+		Name:        "TestHourlyWindow",
+		Version:     "1.0.0",
+		Description: "Rolled up from minute windows",
+		Rollup: &pb.WindowRollup{
+			ChildWindowTypeName:    "TestMinuteWindow",
+			ChildWindowTypeVersion: "1.0.0",
+			Interval:               uint64(time.Hour),
+			ChildCount:             60,
+			LateWindows:            pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT,
+		},
+	}
+
+	minuteAlgo := pb.Algorithm{
+		Name:       "TestMinuteAlgorithm",
+		Version:    "1.0.0",
+		WindowType: &minuteWindowType,
+		ResultType: pb.ResultType_NONE,
+	}
+
+	hourlyAlgo := pb.Algorithm{
+		Name:       "TestHourlyAlgorithm",
+		Version:    "1.0.0",
+		WindowType: &hourlyWindowType,
+		ResultType: pb.ResultType_NONE,
+	}
+
+	proc := pb.ProcessorRegistration{
+		Name:                "TestRollupProcessor",
+		Runtime:             "Test",
+		ConnectionStr:       "Test",
+		SupportedAlgorithms: []*pb.Algorithm{&minuteAlgo, &hourlyAlgo},
+	}
+
+	// 1. register the rolled up window type
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.NoError(t, err)
+
+	// 2. the rollup is exposed with the window type
+	state, err := dlyr.Expose(testCtx, &pb.ExposeSettings{})
+	assert.NoError(t, err)
+	var exposedRollup *pb.WindowRollup
+	for _, exposedProc := range state.GetProcessors() {
+		for _, exposedAlgo := range exposedProc.GetSupportedAlgorithms() {
+			if exposedAlgo.GetWindowType().GetName() == hourlyWindowType.GetName() {
+				exposedRollup = exposedAlgo.GetWindowType().GetRollup()
+			}
+		}
+	}
+	assert.Equal(t, "TestMinuteWindow", exposedRollup.GetChildWindowTypeName())
+	assert.Equal(t, uint32(60), exposedRollup.GetChildCount())
+	assert.Equal(t, pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT, exposedRollup.GetLateWindows())
+
+	// 3. invalid rollups are rejected
+	hourlyWindowType.Rollup.TimeZone = "Mars/Olympus_Mons"
+	err = dlyr.RegisterProcessor(testCtx, &proc)
+	assert.Error(t, err)
+}
+
+// TestSchemaVersion tests that the migrated store reports the latest migration
+func TestSchemaVersion(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	assert.NoError(t, dlyr.Ping(testCtx))
+
+	latest, err := LatestMigrationVersion(string(testPlatform))
+	assert.NoError(t, err)
+
+	version, dirty, err := dlyr.SchemaVersion(testCtx)
+	assert.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, latest, version)
+}
+
+// TestMigrator tests migrating a store of its own down, to a version and
+// back up, and forcing the version of a fixed store
+func TestMigrator(t *testing.T) {
+	if testPlatform != PostgreSQL {
+		t.Skip("steps through several migrations, which only postgresql has")
+	}
+	conn, err := pgx.Connect(testCtx, testConnStr)
+	assert.NoError(t, err)
+	defer conn.Close(testCtx)
+	_, err = conn.Exec(testCtx, "CREATE DATABASE migrator_test")
+	assert.NoError(t, err)
+
+	connURL, err := url.Parse(testConnStr)
+	assert.NoError(t, err)
+	connURL.Path = "/migrator_test"
+
+	migrator, err := NewMigrator("postgresql", connURL.String())
+	assert.NoError(t, err)
+	defer migrator.Close()
+
+	latest, err := LatestMigrationVersion("postgresql")
+	assert.NoError(t, err)
+	status, err := migrator.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, MigrationStatus{Latest: latest}, status)
+
+	assert.NoError(t, migrator.Up())
+	assert.NoError(t, migrator.Up())
+	status, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, MigrationStatus{Version: latest, Latest: latest}, status)
+
+	assert.NoError(t, migrator.Down(2))
+	status, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, latest-2, status.Version)
+	assert.Error(t, migrator.Down(0))
+
+	assert.NoError(t, migrator.Goto(latest-1))
+	status, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, latest-1, status.Version)
+	assert.Error(t, migrator.Goto(latest+1))
+
+	// every migration reverts cleanly
+	assert.NoError(t, migrator.Down(int(latest)+1))
+	status, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), status.Version)
+
+	assert.NoError(t, migrator.Up())
+	assert.NoError(t, migrator.Force(int(latest)))
+	status, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.False(t, status.Dirty)
+	assert.Equal(t, latest, status.Version)
+}
+
+func TestAPIKeys(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	assert.NoError(t, dlyr.CreateAPIKey(testCtx, "ci-pipeline", "hash-1"))
+	assert.Error(t, dlyr.CreateAPIKey(testCtx, "ci-pipeline", "hash-2"))
+
+	name, err := dlyr.LookupAPIKey(testCtx, "hash-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-pipeline", name)
+
+	_, err = dlyr.LookupAPIKey(testCtx, "hash-2")
+	assert.ErrorIs(t, err, types.APIKeyNotFound)
+
+	assert.NoError(t, dlyr.RevokeAPIKey(testCtx, "ci-pipeline"))
+	_, err = dlyr.LookupAPIKey(testCtx, "hash-1")
+	assert.ErrorIs(t, err, types.APIKeyNotFound)
+	assert.ErrorIs(t, dlyr.RevokeAPIKey(testCtx, "ci-pipeline"), types.APIKeyNotFound)
+}
+
+func TestGrants(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	identity := "api_key:team-a"
+	grant := types.Grant{Role: auth.RoleProcessor, Scope: "team-a"}
+	assert.NoError(t, dlyr.CreateGrant(testCtx, identity, grant))
+	assert.NoError(t, dlyr.CreateGrant(testCtx, identity, grant))
+
+	grants, err := dlyr.ReadGrants(testCtx, identity)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Grant{grant}, grants)
+
+	// a processor of the same name in team a's project is team a's own,
+	// rather than team b's processor moved across
+	proc := &pb.ProcessorRegistration{
+		Name:          "GrantedProcessor",
+		Runtime:       "python3.10",
+		ConnectionStr: "localhost:5380",
+		ProjectName:   "team-b",
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, proc))
+
+	teamA := auth.WithIdentity(testCtx, auth.Identity{Name: "team-a", Method: auth.MethodAPIKey, Grants: grants})
+	proc.ProjectName = "team-a"
+	assert.NoError(t, dlyr.RegisterProcessor(teamA, proc))
+
+	assert.NoError(t, dlyr.DeleteGrant(testCtx, identity, grant))
+	assert.ErrorIs(t, dlyr.DeleteGrant(testCtx, identity, grant), types.GrantNotFound)
+}
+
+func TestNamespaces(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	mockProcessor, mockListener, err := StartMockOrcaProcessor(0)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond) // some time for processing to complete
+		mockProcessor.GracefulStop()
+		mockListener.Close()
+	})
+
+	windowType := &pb.WindowType{Name: "NamespacedWindow", Version: "1.0.0", Description: "Registered by both teams"}
+	mean := func() *pb.Algorithm {
+		return &pb.Algorithm{
+			Name:        "mean",
+			Version:     "1.0.0",
+			WindowType:  windowType,
+			ResultType:  pb.ResultType_VALUE,
+			Description: "Mean of the window",
+		}
+	}
+
+	// both teams register the same processor and algorithm names
+	teamA := &pb.ProcessorRegistration{
+		Name:                "analytics",
+		Runtime:             "python3.10",
+		ConnectionStr:       mockListener.Addr().String(),
+		ProjectName:         "tenant-a",
+		SupportedAlgorithms: []*pb.Algorithm{mean()},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, teamA))
+
+	report := &pb.Algorithm{
+		Name:        "report",
+		Version:     "1.0.0",
+		WindowType:  windowType,
+		ResultType:  pb.ResultType_VALUE,
+		Description: "Report on the means of both teams",
+		Dependencies: []*pb.AlgorithmDependency{
+			{Name: "mean", Version: "1.0.0", ProcessorName: "analytics", ProcessorRuntime: "python3.10"},
+			{Name: "mean", Version: "1.0.0", ProcessorName: "analytics", ProcessorRuntime: "python3.10", ProcessorNamespace: "tenant-a"},
+		},
+	}
+	teamB := &pb.ProcessorRegistration{
+		Name:                "analytics",
+		Runtime:             "python3.10",
+		ConnectionStr:       mockListener.Addr().String(),
+		ProjectName:         "tenant-b",
+		SupportedAlgorithms: []*pb.Algorithm{mean(), report},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, teamB))
+
+	for _, namespace := range []string{"tenant-a", "tenant-b"} {
+		state, err := dlyr.Expose(testCtx, &pb.ExposeSettings{Namespace: namespace})
+		assert.NoError(t, err)
+		assert.Len(t, state.GetProcessors(), 1)
+		assert.Equal(t, namespace, state.GetProcessors()[0].GetProjectName())
+	}
+
+	// dependencies are only found in other namespaces when they are named
+	teamB.SupportedAlgorithms = []*pb.Algorithm{{
+		Name:        "unknown",
+		Version:     "1.0.0",
+		WindowType:  windowType,
+		ResultType:  pb.ResultType_VALUE,
+		Description: "Depends on an algorithm of another namespace",
+		Dependencies: []*pb.AlgorithmDependency{
+			{Name: "mean", Version: "1.0.0", ProcessorName: "analytics", ProcessorRuntime: "python3.10", ProcessorNamespace: "tenant-c"},
+		},
+	}}
+	assert.Error(t, dlyr.RegisterProcessor(testCtx, teamB))
+
+	window := &pb.Window{
+		TimeFrom:          timestamppb.New(time.Unix(60, 0)),
+		TimeTo:            timestamppb.New(time.Unix(120, 0)),
+		WindowTypeName:    windowType.GetName(),
+		WindowTypeVersion: windowType.GetVersion(),
+		Origin:            "Test",
+		Metadata:          &structpb.Struct{},
+		Namespace:         "tenant-a",
+	}
+	emitStatus, err := dlyr.EmitWindow(testCtx, window)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, emitStatus.GetStatus())
+
+	// windows without a namespace are ambiguous when their window type is
+	// in several namespaces, none of them the default
+	window.Namespace = ""
+	emitStatus, err = dlyr.EmitWindow(testCtx, window)
+	assert.Error(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_TRIGGERING_FAILED, emitStatus.GetStatus())
+
+	// window types are registered within namespaces too
+	window.Namespace = "tenant-c"
+	emitStatus, err = dlyr.EmitWindow(testCtx, window)
+	assert.Error(t, err)
+	assert.Equal(t, pb.WindowEmitStatus_TRIGGERING_FAILED, emitStatus.GetStatus())
+}
+
+func TestProcessorTLS(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	proc := &pb.ProcessorRegistration{
+		Name:          "TLSProcessor",
+		Runtime:       "python3.10",
+		ConnectionStr: "10.0.0.4:5380",
+		ProjectName:   "Test",
+		Tls: &pb.ProcessorTLS{
+			ServerName:         "processor.internal",
+			InsecureSkipVerify: true,
+		},
+	}
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, proc))
+
+	// registering again replaces the overrides
+	proc.Tls = nil
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, proc))
+}
+
+func TestDiagnose(t *testing.T) {
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	mockProcessor, mockListener, err := StartMockOrcaProcessor(0)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		mockProcessor.GracefulStop()
+		mockListener.Close()
+	})
+
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, &pb.ProcessorRegistration{
+		Name:          "DoctorServing",
+		Runtime:       "python3.10",
+		ConnectionStr: mockListener.Addr().String(),
+		ProjectName:   "doctor",
+	}))
+	assert.NoError(t, dlyr.RegisterProcessor(testCtx, &pb.ProcessorRegistration{
+		Name:          "DoctorUnreachable",
+		Runtime:       "python3.10",
+		ConnectionStr: "127.0.0.1:1",
+		ProjectName:   "doctor",
+	}))
+
+	checks := make(map[string]types.Check)
+	for _, check := range dlyr.Diagnose(testCtx) {
+		checks[check.Name] = check
+	}
+	datalayerChecks := map[Platform][]string{
+		PostgreSQL: {"database permissions", "database extensions", "algorithm execution paths"},
+		SQLite:     {"database integrity", "database foreign keys"},
+	}
+	for _, name := range append(datalayerChecks[testPlatform], "processor doctor/DoctorServing (python3.10)") {
+		check, ok := checks[name]
+		assert.True(t, ok, name)
+		assert.NoError(t, check.Err, name)
+	}
+	unreachable := checks["processor doctor/DoctorUnreachable (python3.10)"]
+	assert.Error(t, unreachable.Err)
+	assert.Contains(t, unreachable.Fix, "127.0.0.1:1")
+}
+
 func TestValidDependenciesBetweenProcessors(t *testing.T) {
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	windowType := pb.WindowType{
@@ -675,7 +1260,7 @@ func TestValidDependenciesBetweenProcessors(t *testing.T) {
 }
 
 func TestAlgosSameNamesDifferentProcessors(t *testing.T) {
-	dlyr, err := NewDatalayerClient(testCtx, "postgresql", testConnStr)
+	dlyr, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
 	assert.NoError(t, err)
 
 	windowType := pb.WindowType{
@@ -717,3 +1302,88 @@ func TestAlgosSameNamesDifferentProcessors(t *testing.T) {
 	err = dlyr.RegisterProcessor(testCtx, &proc2)
 	assert.NoError(t, err)
 }
+
+// TestDagReloaded tests that a client plans windows with the algorithms
+// another client of the same store has registered since it last planned
+func TestDagReloaded(t *testing.T) {
+	mockProcessor, mockListener, err := StartMockOrcaProcessor(0)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond) // some time for processing to complete
+		mockProcessor.GracefulStop()
+		mockListener.Close()
+	})
+
+	emitter, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+	registrar, err := NewDatalayerClient(testCtx, testPlatform, testConnStr)
+	assert.NoError(t, err)
+
+	registerAndEmit := func(windowTypeName string) {
+		windowType := pb.WindowType{Name: windowTypeName, Version: "1.0.0"}
+		err := registrar.RegisterProcessor(testCtx, &pb.ProcessorRegistration{
+			Name:          "TestProcessorForDagReload",
+			Runtime:       "Test",
+			ConnectionStr: mockListener.Addr().String(),
+			SupportedAlgorithms: []*pb.Algorithm{{
+				Name:       "TestAlgorithmFor" + windowTypeName,
+				Version:    "1.0.0",
+				WindowType: &windowType,
+				ResultType: pb.ResultType_VALUE,
+			}},
+		})
+		assert.NoError(t, err)
+
+		emitStatus, err := emitter.EmitWindow(testCtx, &pb.Window{
+			TimeFrom:          &timestamppb.Timestamp{Seconds: 0},
+			TimeTo:            &timestamppb.Timestamp{Seconds: 1},
+			WindowTypeName:    windowType.GetName(),
+			WindowTypeVersion: windowType.GetVersion(),
+			Origin:            "Test",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.WindowEmitStatus_PROCESSING_TRIGGERED, emitStatus.GetStatus())
+	}
+
+	// the emitter plans the first window with the algorithms as they were,
+	// and the second with the algorithm registered since
+	registerAndEmit("TestWindowBeforeDagReload")
+	registerAndEmit("TestWindowAfterDagReload")
+}
+
+// TestConformance runs the conformance suite against empty datalayers of
+// the tested platform, a store of their own per test
+func TestConformance(t *testing.T) {
+	var stores atomic.Int64
+	conformance.Run(t, func(t *testing.T) types.Datalayer {
+		var connStr string
+		switch testPlatform {
+		case PostgreSQL:
+			conn, err := pgx.Connect(testCtx, testConnStr)
+			assert.NoError(t, err)
+			defer conn.Close(testCtx)
+			name := fmt.Sprintf("conformance_%d", stores.Add(1))
+			_, err = conn.Exec(testCtx, "CREATE DATABASE "+name)
+			assert.NoError(t, err)
+
+			connURL, err := url.Parse(testConnStr)
+			assert.NoError(t, err)
+			connURL.Path = "/" + name
+			connStr = connURL.String()
+		case SQLite:
+			connStr = "sqlite://" + filepath.Join(t.TempDir(), "conformance.db")
+		case Memory:
+			connStr = "memory://" + t.Name()
+		}
+		assert.NoError(t, MigrateDatalayer(string(testPlatform), connStr))
+
+		dlyr, err := NewDatalayerClient(testCtx, testPlatform, connStr)
+		if err != nil {
+			t.Fatalf("could not create the datalayer: %v", err)
+		}
+		if closer, ok := dlyr.(io.Closer); ok {
+			t.Cleanup(func() { closer.Close() })
+		}
+		return dlyr
+	})
+}
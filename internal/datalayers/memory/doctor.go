@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+)
+
+// Diagnose checks that every registered processor is serving. There is no
+// database to check, and the index of the algorithm DAG is changed along
+// with the algorithms, so cannot fall out of date with them
+func (d *Datalayer) Diagnose(ctx context.Context) []types.Check {
+	d.mu.Lock()
+	processors := d.sortedProcessors(func(processor) bool { return true })
+	d.mu.Unlock()
+
+	dispatchProcessors := make([]dispatch.Processor, len(processors))
+	for ii, proc := range processors {
+		dispatchProcessors[ii] = dispatchProcessor(proc)
+	}
+	return d.dispatcher.CheckProcessors(ctx, dispatchProcessors)
+}
@@ -0,0 +1,789 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/schedule"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// Datalayer keeps the registry, windows and results in memory, for tests
+// and ephemeral runs. Nothing outlives the process
+type Datalayer struct {
+	// guards everything below. Transactions hold it until they end
+	mu sync.Mutex
+
+	registry       registry
+	windows        map[int64]emittedWindow
+	results        []storedResult
+	rollupProgress map[rollupProgressKey]*rollupProgress
+	apiKeys        []apiKey
+	grants         map[string][]types.Grant
+	// last id handed out to a record. Like a database sequence, it is not
+	// rolled back
+	lastID int64
+
+	// runs execution plans on the processors
+	dispatcher *dispatch.Dispatcher
+}
+
+// registry holds the processors, window types and algorithms registered
+// with Orca, which a rolled back registration restores
+type registry struct {
+	processors     map[int64]processor
+	windowTypes    map[int64]windowType
+	metadataFields map[int64]metadataField
+	algorithms     map[int64]algorithm
+	dependencies   map[dependencyKey]dependency
+	// index of the DAG of the algorithms and their dependencies, which
+	// execution plans are built from
+	index *dag.Index
+}
+
+type processor struct {
+	id                    int64
+	name                  string
+	runtime               string
+	connectionString      string
+	namespace             string
+	registeredBy          string
+	tlsServerName         string
+	tlsInsecureSkipVerify bool
+}
+
+type windowType struct {
+	id          int64
+	namespace   string
+	name        string
+	version     string
+	description string
+	// the metadata fields windows of the type carry, in the order they
+	// were registered
+	metadataFieldIDs []int64
+	// nil when windows of the type are not emitted on a schedule
+	schedule *windowSchedule
+	// nil when windows of the type are not rolled up from another
+	rollup *pb.WindowRollup
+}
+
+type windowSchedule struct {
+	settings *pb.WindowSchedule
+	// the end of the last scheduled window emitted
+	highWaterMark time.Time
+}
+
+type metadataField struct {
+	id          int64
+	name        string
+	description string
+}
+
+type algorithm struct {
+	id           int64
+	name         string
+	version      string
+	description  string
+	processorID  int64
+	windowTypeID int64
+	resultType   pb.ResultType
+}
+
+type dependencyKey struct {
+	fromAlgorithmID int64
+	toAlgorithmID   int64
+}
+
+type dependency struct {
+	lookbackCount     int64
+	lookbackTimedelta int64
+	// label of the partition, as the database datalayers store it
+	lookbackPartition string
+	// 0 unless partitioned by a metadata field
+	lookbackPartitionFieldID int64
+	// nil when the raw past results are sent to the processor
+	lookbackAggregate *pb.LookbackAggregate
+}
+
+type emittedWindow struct {
+	id           int64
+	windowTypeID int64
+	timeFrom     time.Time
+	timeTo       time.Time
+	origin       string
+	metadata     *structpb.Struct
+	traceID      string
+	createdBy    string
+	namespace    string
+}
+
+type storedResult struct {
+	id           int64
+	windowID     int64
+	windowTypeID int64
+	algorithmID  int64
+	result       *pb.Result
+	traceID      string
+	namespace    string
+}
+
+// rollupProgressKey groups child windows into their parent window by
+// interval, origin and metadata
+type rollupProgressKey struct {
+	windowTypeID int64
+	origin       string
+	metadata     string
+	timeFrom     int64
+}
+
+type rollupProgress struct {
+	// start times of the completed child windows
+	childWindows []time.Time
+	// whether the parent window has been emitted
+	emitted bool
+}
+
+type apiKey struct {
+	name    string
+	keyHash string
+	revoked bool
+}
+
+// labels of the lookback partitions, as the database datalayers store them
+const (
+	lookbackPartitionNone          = "none"
+	lookbackPartitionOrigin        = "origin"
+	lookbackPartitionMetadataField = "metadata_field"
+)
+
+// versionPattern is the semantic version that window types and algorithms
+// are registered with
+var versionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)$`)
+
+// datalayers are shared by connection string, so that clients of the same
+// store see the same registry, as clients of the same database do
+var (
+	datalayersMu sync.Mutex
+	datalayers   = make(map[string]*Datalayer)
+)
+
+// generate a new client for the memory datalayer, from a connection string
+// of the form memory://<name>. Clients of the same name share a store
+func NewClient(ctx context.Context, connStr string) (*Datalayer, error) {
+	if connStr == "" {
+		return nil, errors.New("connection string empty")
+	}
+	name, ok := strings.CutPrefix(connStr, "memory://")
+	if !ok {
+		return nil, errors.New("connection string must start with memory://")
+	}
+
+	datalayersMu.Lock()
+	defer datalayersMu.Unlock()
+	if d, ok := datalayers[name]; ok {
+		return d, nil
+	}
+
+	dispatcher, err := dispatch.NewDispatcher()
+	if err != nil {
+		return nil, err
+	}
+	d := &Datalayer{
+		registry: registry{
+			processors:     make(map[int64]processor),
+			windowTypes:    make(map[int64]windowType),
+			metadataFields: make(map[int64]metadataField),
+			algorithms:     make(map[int64]algorithm),
+			dependencies:   make(map[dependencyKey]dependency),
+			index:          dag.NewIndex(),
+		},
+		windows:        make(map[int64]emittedWindow),
+		rollupProgress: make(map[rollupProgressKey]*rollupProgress),
+		grants:         make(map[string][]types.Grant),
+		dispatcher:     dispatcher,
+	}
+	datalayers[name] = d
+	return d, nil
+}
+
+// MemoryTx holds the lock of the datalayer until it ends. Rolling it back
+// restores the registry as it was when it began. Windows, results and keys
+// are only written once checked, so are never rolled back
+type MemoryTx struct {
+	d        *Datalayer
+	registry registry
+	done     bool
+}
+
+func (t *MemoryTx) Rollback(ctx context.Context) {
+	if t.done {
+		return
+	}
+	t.done = true
+	t.d.registry = t.registry
+	t.d.mu.Unlock()
+}
+
+func (t *MemoryTx) Commit(ctx context.Context) error {
+	if t.done {
+		return errors.New("transaction has already been committed or rolled back")
+	}
+	t.done = true
+	t.d.mu.Unlock()
+	return nil
+}
+
+func (d *Datalayer) WithTx(ctx context.Context) (types.Tx, error) {
+	d.mu.Lock()
+	return &MemoryTx{d: d, registry: d.registry.clone()}, nil
+}
+
+// clone copies the registry. Records are replaced rather than modified in
+// place, so copying the maps and the index is enough
+func (r registry) clone() registry {
+	return registry{
+		processors:     maps.Clone(r.processors),
+		windowTypes:    maps.Clone(r.windowTypes),
+		metadataFields: maps.Clone(r.metadataFields),
+		algorithms:     maps.Clone(r.algorithms),
+		dependencies:   maps.Clone(r.dependencies),
+		index:          r.index.Clone(),
+	}
+}
+
+// nextID hands out the id of a new record. Called with the lock held
+func (d *Datalayer) nextID() int64 {
+	d.lastID++
+	return d.lastID
+}
+
+// the functions below read and write the registry, and are called with the
+// lock held
+
+func (d *Datalayer) processorID(namespace, name, runtime string) (int64, bool) {
+	for id, proc := range d.registry.processors {
+		if proc.namespace == namespace && proc.name == name && proc.runtime == runtime {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (d *Datalayer) windowTypeID(namespace, name, version string) (int64, bool) {
+	for id, wt := range d.registry.windowTypes {
+		if wt.namespace == namespace && wt.name == name && wt.version == version {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// namespacedWindow returns the window as it is stored and processed, in
+// the namespace its window type resolves to when it has none
+func (d *Datalayer) namespacedWindow(window *pb.Window) (*pb.Window, error) {
+	if window.GetNamespace() != "" {
+		return window, nil
+	}
+	var namespaces []string
+	for _, wt := range d.registry.windowTypes {
+		if wt.name == window.GetWindowTypeName() && wt.version == window.GetWindowTypeVersion() {
+			namespaces = append(namespaces, wt.namespace)
+		}
+	}
+	slices.Sort(namespaces)
+	namespace, err := types.WindowNamespace(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	namespaced := proto.Clone(window).(*pb.Window)
+	namespaced.Namespace = namespace
+	return namespaced, nil
+}
+
+func (d *Datalayer) metadataFieldID(name string) (int64, bool) {
+	for id, field := range d.registry.metadataFields {
+		if field.name == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// algorithmID returns the first registered algorithm of the name and version
+// served by the processor
+func (d *Datalayer) algorithmID(name, version string, processorID int64) (int64, bool) {
+	var found int64
+	for id, algo := range d.registry.algorithms {
+		if algo.name == name && algo.version == version && algo.processorID == processorID {
+			if found == 0 || id < found {
+				found = id
+			}
+		}
+	}
+	return found, found != 0
+}
+
+// sortedProcessors returns the processors that pass the filter, in the order
+// they were registered
+func (d *Datalayer) sortedProcessors(filter func(processor) bool) []processor {
+	var procs []processor
+	for _, proc := range d.registry.processors {
+		if filter(proc) {
+			procs = append(procs, proc)
+		}
+	}
+	slices.SortFunc(procs, func(a, b processor) int { return cmp.Compare(a.id, b.id) })
+	return procs
+}
+
+func (d *Datalayer) createProcessor(
+	ctx context.Context,
+	proc *pb.ProcessorRegistration,
+) {
+	// processors are unique within their namespace, so registering under
+	// another project registers another processor
+	namespace := types.Namespace(proc.GetProjectName())
+	id, ok := d.processorID(namespace, proc.GetName(), proc.GetRuntime())
+	if !ok {
+		id = d.nextID()
+	}
+	d.registry.processors[id] = processor{
+		id:                    id,
+		name:                  proc.GetName(),
+		runtime:               proc.GetRuntime(),
+		connectionString:      proc.GetConnectionStr(),
+		namespace:             namespace,
+		registeredBy:          auth.Caller(ctx),
+		tlsServerName:         proc.GetTls().GetServerName(),
+		tlsInsecureSkipVerify: proc.GetTls().GetInsecureSkipVerify(),
+	}
+	if proc.GetTls().GetInsecureSkipVerify() {
+		slog.WarnContext(ctx, "processor registered without verifying its certificate, which is only safe in development", "processor", proc.GetName())
+	}
+}
+
+func (d *Datalayer) createMetadataField(field *pb.MetadataField) int64 {
+	id, ok := d.metadataFieldID(field.GetName())
+	if !ok {
+		id = d.nextID()
+	}
+	d.registry.metadataFields[id] = metadataField{
+		id:          id,
+		name:        field.GetName(),
+		description: field.GetDescription(),
+	}
+	return id
+}
+
+func (d *Datalayer) createMetadataFieldBridge(windowTypeId int64, metadataFieldId int64) {
+	wt := d.registry.windowTypes[windowTypeId]
+	if slices.Contains(wt.metadataFieldIDs, metadataFieldId) {
+		return
+	}
+	wt.metadataFieldIDs = append(slices.Clip(wt.metadataFieldIDs), metadataFieldId)
+	d.registry.windowTypes[windowTypeId] = wt
+}
+
+// readMetadataFieldsByWindowType returns the metadata fields of a window
+// type, ordered by name
+func (d *Datalayer) readMetadataFieldsByWindowType(windowTypeId int64) []*pb.MetadataField {
+	var metadataFields []*pb.MetadataField
+	for _, fieldId := range d.registry.windowTypes[windowTypeId].metadataFieldIDs {
+		field := d.registry.metadataFields[fieldId]
+		metadataFields = append(metadataFields, &pb.MetadataField{
+			Name:        field.name,
+			Description: field.description,
+		})
+	}
+	slices.SortFunc(metadataFields, func(a, b *pb.MetadataField) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return metadataFields
+}
+
+func (d *Datalayer) createWindowType(windowType *pb.WindowType, namespace string) (int64, error) {
+	if !versionPattern.MatchString(windowType.GetVersion()) {
+		return 0, fmt.Errorf(
+			"version %q of window type %v is not a semantic version",
+			windowType.GetVersion(),
+			windowType.GetName(),
+		)
+	}
+	id, ok := d.windowTypeID(namespace, windowType.GetName(), windowType.GetVersion())
+	if !ok {
+		id = d.nextID()
+	}
+	wt := d.registry.windowTypes[id]
+	wt.id = id
+	wt.namespace = namespace
+	wt.name = windowType.GetName()
+	wt.version = windowType.GetVersion()
+	wt.description = windowType.GetDescription()
+	d.registry.windowTypes[id] = wt
+	return id, nil
+}
+
+// sameFiringTimes reports whether two schedules fire at the same times
+func sameFiringTimes(a *pb.WindowSchedule, b *pb.WindowSchedule) bool {
+	return a.GetCron() == b.GetCron() &&
+		a.GetInterval() == b.GetInterval() &&
+		a.GetAlignment() == b.GetAlignment() &&
+		a.GetTimeZone() == b.GetTimeZone()
+}
+
+// setWindowSchedule creates or updates the schedule of a window type,
+// replacing any rollup, or removes it when the registration asks to. A
+// window type registered without a schedule keeps the one it has, as other
+// processors may share the window type
+func (d *Datalayer) setWindowSchedule(windowTypeId int64, windowType *pb.WindowType) error {
+	wt := d.registry.windowTypes[windowTypeId]
+	if windowType.GetRemoveSchedule() {
+		wt.schedule = nil
+		d.registry.windowTypes[windowTypeId] = wt
+		return nil
+	}
+	settings := windowType.GetSchedule()
+	if settings == nil {
+		return nil
+	}
+
+	// scheduled windows are emitted without metadata, so they could never
+	// carry the fields of the window type
+	if len(windowType.GetMetadataFields()) > 0 {
+		return fmt.Errorf(
+			"window type %v cannot be scheduled, as scheduled window types cannot carry metadata fields",
+			windowType.GetName(),
+		)
+	}
+
+	sched, err := schedule.FromPb(settings)
+	if err != nil {
+		return err
+	}
+
+	// the high water mark of an existing schedule is kept, so that
+	// re-registering a window type neither skips nor repeats windows, unless
+	// the times the schedule fires at change. The first window starts at the
+	// next scheduled time, so that windows always span a whole period
+	highWaterMark := sched.Next(time.Now().UTC()).UTC()
+	if wt.schedule != nil && sameFiringTimes(wt.schedule.settings, settings) {
+		highWaterMark = wt.schedule.highWaterMark
+	}
+	wt.schedule = &windowSchedule{
+		settings:      windowScheduleToPb(settings),
+		highWaterMark: highWaterMark,
+	}
+	// windows are emitted by at most one of a schedule and a rollup
+	wt.rollup = nil
+	d.registry.windowTypes[windowTypeId] = wt
+	return nil
+}
+
+// setWindowRollup creates or updates the rollup of a window type, replacing
+// any schedule, or removes it when the registration asks to. A window type
+// registered without a rollup keeps the one it has
+func (d *Datalayer) setWindowRollup(windowTypeId int64, windowType *pb.WindowType) error {
+	wt := d.registry.windowTypes[windowTypeId]
+	if windowType.GetRemoveRollup() {
+		wt.rollup = nil
+		d.registry.windowTypes[windowTypeId] = wt
+		return nil
+	}
+	windowRollup := windowType.GetRollup()
+	if windowRollup == nil {
+		return nil
+	}
+
+	_, err := rollupSchedule(windowRollup)
+	if err != nil {
+		return fmt.Errorf("invalid window rollup: %w", err)
+	}
+	if windowRollup.GetChildCount() == 0 {
+		return errors.New("invalid window rollup: child count must be positive")
+	}
+
+	wt.rollup = windowRollupToPb(windowRollup)
+	// windows are emitted by at most one of a schedule and a rollup
+	wt.schedule = nil
+	d.registry.windowTypes[windowTypeId] = wt
+	return nil
+}
+
+// rollupSchedule parses the interval of a window rollup
+func rollupSchedule(windowRollup *pb.WindowRollup) (schedule.Schedule, error) {
+	return schedule.Parse(
+		"",
+		time.Duration(windowRollup.GetInterval()),
+		time.Duration(windowRollup.GetAlignment()),
+		windowRollup.GetTimeZone(),
+		false,
+	)
+}
+
+// windowRollupToPb copies a window rollup as it is stored, with late windows
+// ignored unless they are re-emitted
+func windowRollupToPb(windowRollup *pb.WindowRollup) *pb.WindowRollup {
+	stored := proto.Clone(windowRollup).(*pb.WindowRollup)
+	if stored.GetLateWindows() != pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT {
+		stored.LateWindows = pb.WindowRollup_LATE_WINDOW_POLICY_IGNORE
+	}
+	return stored
+}
+
+// windowScheduleToPb copies a window schedule as it is stored
+func windowScheduleToPb(windowSchedule *pb.WindowSchedule) *pb.WindowSchedule {
+	return proto.Clone(windowSchedule).(*pb.WindowSchedule)
+}
+
+func (d *Datalayer) addAlgorithm(
+	algo *pb.Algorithm,
+	proc *pb.ProcessorRegistration,
+) error {
+	switch algo.GetResultType() {
+	case pb.ResultType_ARRAY, pb.ResultType_STRUCT, pb.ResultType_VALUE, pb.ResultType_NONE:
+	default:
+		return fmt.Errorf("result type %v not supported", algo.GetResultType())
+	}
+	if !versionPattern.MatchString(algo.GetVersion()) {
+		return fmt.Errorf(
+			"version %q of algorithm %v is not a semantic version",
+			algo.GetVersion(),
+			algo.GetName(),
+		)
+	}
+
+	namespace := types.Namespace(proc.GetProjectName())
+	processorId, ok := d.processorID(namespace, proc.GetName(), proc.GetRuntime())
+	if !ok {
+		return fmt.Errorf("processor %v does not exist", proc.GetName())
+	}
+	windowTypeId, ok := d.windowTypeID(namespace, algo.GetWindowType().GetName(), algo.GetWindowType().GetVersion())
+	if !ok {
+		return fmt.Errorf("window type %v does not exist", algo.GetWindowType().GetName())
+	}
+
+	// an algorithm is registered once, and kept as it was first registered
+	for _, existing := range d.registry.algorithms {
+		if existing.name == algo.GetName() &&
+			existing.version == algo.GetVersion() &&
+			existing.windowTypeID == windowTypeId &&
+			existing.processorID == processorId {
+			return nil
+		}
+	}
+	id := d.nextID()
+	d.registry.algorithms[id] = algorithm{
+		id:           id,
+		name:         algo.GetName(),
+		version:      algo.GetVersion(),
+		description:  algo.GetDescription(),
+		processorID:  processorId,
+		windowTypeID: windowTypeId,
+		resultType:   algo.GetResultType(),
+	}
+	d.registry.index.AddAlgorithm(id, processorId, windowTypeId)
+	return nil
+}
+
+func (d *Datalayer) addOverwriteAlgorithmDependency(
+	algo *pb.Algorithm,
+	proc *pb.ProcessorRegistration,
+) error {
+	// get algorithm id
+	namespace := types.Namespace(proc.GetProjectName())
+	processorId, _ := d.processorID(namespace, proc.GetName(), proc.GetRuntime())
+	algoId, ok := d.algorithmID(algo.GetName(), algo.GetVersion(), processorId)
+	if !ok {
+		slog.Error("could not get algorithm ID", "algorithm", algo)
+		return fmt.Errorf("algorithm %v of processor %v does not exist", algo.GetName(), proc.GetName())
+	}
+	for _, algoDependentOn := range algo.GetDependencies() {
+		// dependencies are looked up in the namespace of the dependant,
+		// unless another namespace is named
+		dependencyNamespace := namespace
+		if algoDependentOn.GetProcessorNamespace() != "" {
+			dependencyNamespace = algoDependentOn.GetProcessorNamespace()
+		}
+
+		// get algorithm id
+		dependencyProcessorId, _ := d.processorID(
+			dependencyNamespace,
+			algoDependentOn.GetProcessorName(),
+			algoDependentOn.GetProcessorRuntime(),
+		)
+		algoDependentOnId, ok := d.algorithmID(
+			algoDependentOn.GetName(),
+			algoDependentOn.GetVersion(),
+			dependencyProcessorId,
+		)
+		if !ok {
+			return fmt.Errorf(
+				"algorithm %v of processor %v does not exist in namespace %v",
+				algoDependentOn.GetName(),
+				algoDependentOn.GetProcessorName(),
+				dependencyNamespace,
+			)
+		}
+
+		// the lookback partition value is read from the dependant's window,
+		// so the field must be carried by the dependant's window type
+		lookbackPartition, lookbackPartitionField, err := lookbackPartitionFromPb(algoDependentOn)
+		if err != nil {
+			return err
+		}
+		var lookbackPartitionFieldId int64
+		if lookbackPartition == lookbackPartitionMetadataField {
+			hasField := slices.ContainsFunc(
+				algo.GetWindowType().GetMetadataFields(),
+				func(field *pb.MetadataField) bool {
+					return field.GetName() == lookbackPartitionField
+				},
+			)
+			lookbackPartitionFieldId, ok = d.metadataFieldID(lookbackPartitionField)
+			if !hasField || !ok {
+				return fmt.Errorf(
+					"lookback partition field %q is not a metadata field of window type %v",
+					lookbackPartitionField,
+					algo.GetWindowType().GetName(),
+				)
+			}
+		}
+
+		// only single values can be summarised beyond counting them
+		lookbackAggregate, err := lookbackAggregateFromPb(algoDependentOn)
+		if err != nil {
+			return err
+		}
+		if lookbackAggregate != nil && lookbackAggregate.GetFunction() != pb.LookbackAggregate_FUNCTION_COUNT {
+			resultType := d.registry.algorithms[algoDependentOnId].resultType
+			if resultType != pb.ResultType_VALUE {
+				return fmt.Errorf(
+					"lookback aggregate %v requires algorithm %v to produce a value result, not %v",
+					lookbackAggregate.GetFunction(),
+					algoDependentOn.GetName(),
+					resultType,
+				)
+			}
+		}
+
+		// an algorithm depending on itself is caught before the rest of the
+		// graph is checked for cycles, as in the other datalayers
+		if algoId == algoDependentOnId {
+			slog.Error("found circular dependency", "algo", algo)
+			return types.NewCircularDependencyError([]types.CycleAlgorithm{{
+				Name:      algo.GetName(),
+				Version:   algo.GetVersion(),
+				Processor: proc.GetName(),
+			}})
+		}
+
+		dep := dependency{
+			lookbackCount:            int64(algoDependentOn.GetLookbackNum()),
+			lookbackTimedelta:        int64(algoDependentOn.GetLookbackTimeDelta()),
+			lookbackPartition:        lookbackPartition,
+			lookbackPartitionFieldID: lookbackPartitionFieldId,
+			lookbackAggregate:        lookbackAggregate,
+		}
+		d.registry.dependencies[dependencyKey{
+			fromAlgorithmID: algoDependentOnId,
+			toAlgorithmID:   algoId,
+		}] = dep
+		err = d.registry.index.SetDependency(algoDependentOnId, algoId, dependencyLookback(dep))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// circularDependencyError returns the error of a cycle of algorithms, in the
+// order given by dag.Index.Cycle
+func (d *Datalayer) circularDependencyError(cycle []int64) error {
+	cycleAlgorithms := make([]types.CycleAlgorithm, len(cycle))
+	for ii, algoId := range cycle {
+		algo := d.registry.algorithms[algoId]
+		cycleAlgorithms[ii] = types.CycleAlgorithm{
+			Name:      algo.name,
+			Version:   algo.version,
+			Processor: d.registry.processors[algo.processorID].name,
+		}
+	}
+	slog.Error("found circular dependency", "cycle", cycleAlgorithms)
+	return types.NewCircularDependencyError(cycleAlgorithms)
+}
+
+// dependencyLookback converts the lookback of a dependency to the lookback
+// of an edge of the DAG
+func dependencyLookback(dep dependency) dag.Lookback {
+	partition := dag.PartitionNone
+	switch dep.lookbackPartition {
+	case lookbackPartitionOrigin:
+		partition = dag.PartitionOrigin
+	case lookbackPartitionMetadataField:
+		partition = dag.PartitionMetadataField
+	}
+	return dag.Lookback{
+		Count:            int(dep.lookbackCount),
+		Timedelta:        int(dep.lookbackTimedelta),
+		Partition:        partition,
+		PartitionFieldId: dep.lookbackPartitionFieldID,
+	}
+}
+
+// lookbackPartitionFromPb maps the lookback partition of a dependency onto
+// its label, and the name of the metadata field it is partitioned on
+func lookbackPartitionFromPb(dep *pb.AlgorithmDependency) (string, string, error) {
+	switch dep.GetLookbackPartition() {
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_NONE:
+		return lookbackPartitionNone, "", nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN:
+		return lookbackPartitionOrigin, "", nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_METADATA_FIELD:
+		return lookbackPartitionMetadataField, dep.GetLookbackPartitionField(), nil
+	default:
+		return "", "", fmt.Errorf("lookback partition %v not supported", dep.GetLookbackPartition())
+	}
+}
+
+// lookbackAggregateFromPb checks the lookback aggregate of a dependency,
+// returning a copy to store. No aggregate is nil
+func lookbackAggregateFromPb(dep *pb.AlgorithmDependency) (*pb.LookbackAggregate, error) {
+	agg := dep.GetLookbackAggregate()
+	if agg == nil {
+		return nil, nil
+	}
+	switch agg.GetFunction() {
+	case pb.LookbackAggregate_FUNCTION_COUNT,
+		pb.LookbackAggregate_FUNCTION_SUM,
+		pb.LookbackAggregate_FUNCTION_MEAN,
+		pb.LookbackAggregate_FUNCTION_MIN,
+		pb.LookbackAggregate_FUNCTION_MAX,
+		pb.LookbackAggregate_FUNCTION_LAST,
+		pb.LookbackAggregate_FUNCTION_PERCENTILE:
+	default:
+		return nil, fmt.Errorf("lookback aggregate %v not supported", agg.GetFunction())
+	}
+	if dep.GetLookbackNum() == 0 && dep.GetLookbackTimeDelta() == 0 {
+		return nil, fmt.Errorf("lookback aggregate %v requires a lookback", agg.GetFunction())
+	}
+	if agg.GetPercentile() < 0 || agg.GetPercentile() > 1 {
+		return nil, fmt.Errorf("lookback aggregate percentile %v is not between 0 and 1", agg.GetPercentile())
+	}
+	return proto.Clone(agg).(*pb.LookbackAggregate), nil
+}
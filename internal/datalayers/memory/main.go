@@ -0,0 +1,701 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// RegisterProcessor with Orca Core
+func (d *Datalayer) RegisterProcessor(
+	ctx context.Context,
+	proc *pb.ProcessorRegistration,
+) error {
+	slog.Debug("registering processor", "processor", proc.GetName(), "algorithms", len(proc.GetSupportedAlgorithms()))
+	if err := auth.Require(ctx, auth.RoleProcessor, types.Namespace(proc.GetProjectName())); err != nil {
+		return err
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	// register the processor
+	d.createProcessor(ctx, proc)
+
+	// add all algorithms first, window types are registered in the
+	// namespace of the processor
+	namespace := types.Namespace(proc.GetProjectName())
+	for _, algo := range proc.GetSupportedAlgorithms() {
+		// add window types
+		windowType := algo.GetWindowType()
+
+		// create / update the window type
+		windowTypeId, err := d.createWindowType(windowType, namespace)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the schedule of the window type
+		err = d.setWindowSchedule(windowTypeId, windowType)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the rollup of the window type
+		err = d.setWindowRollup(windowTypeId, windowType)
+		if err != nil {
+			return err
+		}
+
+		// read any existing metadata fields for the window
+		metadataFieldsAsStored := d.readMetadataFieldsByWindowType(windowTypeId)
+
+		// if there are existing fields, check they are the same as the provided window
+		// just check on metadatafield name
+		if len(metadataFieldsAsStored) > 0 {
+			if len(windowType.MetadataFields) != len(metadataFieldsAsStored) {
+				return fmt.Errorf(
+					`Metadata fields of incoming window type %v, do not match the
+					number of fields stored in the database for this window.
+					Expected: %v, got %v. Considering bumping the version of the
+					window type.`, windowType, metadataFieldsAsStored, windowType.MetadataFields,
+				)
+			}
+			metadataFieldNamesAsStored := make([]string, len(metadataFieldsAsStored))
+			for ii, field := range metadataFieldsAsStored {
+				metadataFieldNamesAsStored[ii] = field.GetName()
+			}
+			for _, metadataField := range windowType.MetadataFields {
+				if !slices.Contains(metadataFieldNamesAsStored, metadataField.GetName()) {
+					return fmt.Errorf(
+						`Recieved a metadata field %v of window type %v that is not registered
+						in the database. If you want to keep this field, bump the version
+						of the window type.`, metadataField.GetName(), windowType,
+					)
+				}
+			}
+		} else {
+			for _, metadataField := range windowType.MetadataFields {
+				metadataFieldId := d.createMetadataField(metadataField)
+				d.createMetadataFieldBridge(windowTypeId, metadataFieldId)
+			}
+		}
+
+		// create algos
+		err = d.addAlgorithm(algo, proc)
+		if err != nil {
+			slog.Error("error creating algorithm", "error", err)
+			return err
+		}
+	}
+
+	// then add the dependencies and associate the processor with all the algos
+	for _, algo := range proc.GetSupportedAlgorithms() {
+		err := d.addOverwriteAlgorithmDependency(algo, proc)
+		if err != nil {
+			// error wrapping is important here because we return some custom errors
+			return fmt.Errorf("issue adding algorithm dependency: %w", err)
+		}
+	}
+
+	// cycles can run through several algorithms of the registration, so the
+	// graph is checked once all of its dependencies are set
+	if cycle := d.registry.index.Cycle(tx.(*MemoryTx).registry.index); cycle != nil {
+		return d.circularDependencyError(cycle)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// EmitWindow with Orca core
+func (d *Datalayer) EmitWindow(
+	ctx context.Context,
+	window *pb.Window,
+) (pb.WindowEmitStatus, error) {
+	ctx = logging.WithWindow(ctx, window)
+	slog.DebugContext(ctx, "received emitted window")
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return pb.WindowEmitStatus{}, err
+	}
+
+	window, err = d.namespacedWindow(window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+	scope := auth.WindowTypeScope(window.GetNamespace(), window.GetWindowTypeName())
+	if err := auth.Require(ctx, auth.RoleEmitter, scope); err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+
+	// the window is stored before it is processed, as processing takes
+	// the lock to read past results and store results
+	if err := tx.Commit(ctx); err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return pb.WindowEmitStatus{Status: emitStatus}, nil
+}
+
+// EmitScheduledWindows emits the windows of scheduled window types that have
+// closed by now. The store belongs to a single process, so there is no
+// leader to elect. Moving high water marks stop windows being emitted twice
+func (d *Datalayer) EmitScheduledWindows(ctx context.Context, now time.Time) error {
+	d.mu.Lock()
+	var scheduled []windowType
+	for _, wt := range d.registry.windowTypes {
+		if wt.schedule != nil {
+			scheduled = append(scheduled, wt)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, wt := range scheduled {
+		sched, err := schedule.FromPb(wt.schedule.settings)
+		if err != nil {
+			slog.Error(
+				"could not parse window schedule",
+				"window_type",
+				wt.name,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		highWaterMark := wt.schedule.highWaterMark
+		for _, scheduledWindow := range sched.Due(highWaterMark, now) {
+			err := d.emitScheduledWindow(ctx, wt, highWaterMark, scheduledWindow)
+			if err != nil {
+				slog.Error(
+					"could not emit scheduled window",
+					"window_type",
+					wt.name,
+					"time_from",
+					scheduledWindow.From,
+					"error",
+					err,
+				)
+				break
+			}
+			highWaterMark = scheduledWindow.To
+		}
+	}
+	return nil
+}
+
+// emitScheduledWindow emits a single scheduled window, and advances the high
+// water mark of its schedule under the same lock
+func (d *Datalayer) emitScheduledWindow(
+	ctx context.Context,
+	wt windowType,
+	highWaterMark time.Time,
+	scheduledWindow schedule.Window,
+) (err error) {
+	ctx, span := tracing.Tracer.Start(ctx, "EmitScheduledWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", wt.name),
+		attribute.String("orca.window_type_version", wt.version),
+	))
+	defer func() { tracing.End(span, err) }()
+	ctx = logging.WithCorrelationID(ctx)
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	// the high water mark moving, or the schedule being removed, means the
+	// window has been emitted already or is no longer due
+	stored := d.registry.windowTypes[wt.id].schedule
+	if stored == nil {
+		return fmt.Errorf("window type %v is no longer scheduled", wt.name)
+	}
+	if !stored.highWaterMark.Equal(highWaterMark) {
+		return fmt.Errorf(
+			"high water mark of window schedule moved from %v to %v",
+			highWaterMark,
+			stored.highWaterMark,
+		)
+	}
+
+	window := &pb.Window{
+		TimeFrom:          timestamppb.New(scheduledWindow.From),
+		TimeTo:            timestamppb.New(scheduledWindow.To),
+		WindowTypeName:    wt.name,
+		WindowTypeVersion: wt.version,
+		Namespace:         wt.namespace,
+		Origin:            stored.settings.GetOrigin(),
+		Metadata:          &structpb.Struct{},
+	}
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, window)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.id)
+
+	advanced := d.registry.windowTypes[wt.id]
+	advanced.schedule = &windowSchedule{
+		settings:      stored.settings,
+		highWaterMark: scheduledWindow.To.UTC(),
+	}
+	d.registry.windowTypes[wt.id] = advanced
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	slog.InfoContext(logging.WithWindow(ctx, window), "emitted scheduled window", "status", emitStatus)
+
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return nil
+}
+
+// rollupWindow counts a completed child window towards its parent window in
+// a rollup, and emits the parent once enough of its children have completed
+func (d *Datalayer) rollupWindow(
+	ctx context.Context,
+	rolledUp windowType,
+	window *pb.Window,
+) (err error) {
+	ctx, traceSpan := tracing.Tracer.Start(ctx, "RollupWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", rolledUp.name),
+		attribute.String("orca.window_type_version", rolledUp.version),
+	))
+	defer func() { tracing.End(traceSpan, err) }()
+
+	rollup := rolledUp.rollup
+	sched, err := rollupSchedule(rollup)
+	if err != nil {
+		return err
+	}
+	childFrom := window.GetTimeFrom().AsTime().UTC()
+	childTo := window.GetTimeTo().AsTime().UTC()
+	span, err := sched.Span(childFrom)
+	if err != nil {
+		return err
+	}
+	if childTo.After(span.To) {
+		return fmt.Errorf(
+			"window from %v to %v does not fit within a window of rolled up window type %v",
+			childFrom,
+			childTo,
+			rolledUp.name,
+		)
+	}
+
+	// protobuf orders the fields of a struct, so equal metadata marshals to
+	// equal text
+	metadataBytes, err := window.GetMetadata().MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("could not marshal metadata: %v", err)
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	// children are grouped into parents by interval, origin and metadata
+	key := rollupProgressKey{
+		windowTypeID: rolledUp.id,
+		origin:       window.GetOrigin(),
+		metadata:     string(metadataBytes),
+		timeFrom:     span.From.UnixNano(),
+	}
+	progress, ok := d.rollupProgress[key]
+	if !ok {
+		progress = &rollupProgress{}
+		d.rollupProgress[key] = progress
+	}
+
+	// a child window emitted more than once only counts once
+	alreadyCompleted := slices.ContainsFunc(progress.childWindows, func(ts time.Time) bool {
+		return ts.Equal(childFrom)
+	})
+	if alreadyCompleted {
+		return nil
+	}
+
+	late := progress.emitted
+	emit := (!late && len(progress.childWindows)+1 >= int(rollup.GetChildCount())) ||
+		(late && rollup.GetLateWindows() == pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT)
+
+	var parent *pb.Window
+	var emitStatus pb.WindowEmitStatus_StatusEnum
+	var executionPlan dag.Plan
+	var insertedWindow emittedWindow
+	if emit {
+		parent = &pb.Window{
+			TimeFrom:          timestamppb.New(span.From),
+			TimeTo:            timestamppb.New(span.To),
+			WindowTypeName:    rolledUp.name,
+			WindowTypeVersion: rolledUp.version,
+			Origin:            window.GetOrigin(),
+			Metadata:          window.GetMetadata(),
+			Namespace:         window.GetNamespace(),
+		}
+		emitStatus, executionPlan, insertedWindow, err = d.registerWindow(ctx, parent)
+		if err != nil {
+			return err
+		}
+		progress.emitted = true
+	} else if late {
+		slog.WarnContext(
+			ctx,
+			"window completed after its rolled up window was emitted",
+			"rolled_up_window_type",
+			rolledUp.name,
+		)
+	}
+	progress.childWindows = append(progress.childWindows, childFrom)
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if emit {
+		ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.id)
+		slog.InfoContext(logging.WithWindow(ctx, parent), "emitted rolled up window", "status", emitStatus, "late", late)
+		go processWindow(tracing.Detach(ctx), d, executionPlan, parent, insertedWindow)
+	}
+	return nil
+}
+
+// registerWindow validates and stores a window, and builds the plan of the
+// algorithms it triggers, counting the window by its emit status. Called
+// with the lock held
+func (d *Datalayer) registerWindow(
+	ctx context.Context,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, emittedWindow, error) {
+	emitStatus, executionPlan, insertedWindow, err := d.insertWindow(ctx, window)
+	metrics.WindowsEmitted.WithLabelValues(
+		window.GetWindowTypeName(),
+		window.GetWindowTypeVersion(),
+		emitStatus.String(),
+	).Inc()
+	return emitStatus, executionPlan, insertedWindow, err
+}
+
+// insertWindow does the work of registerWindow
+func (d *Datalayer) insertWindow(
+	ctx context.Context,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, emittedWindow, error) {
+	// window types are those of the namespace of the window
+	windowTypeId, ok := d.windowTypeID(
+		types.Namespace(window.GetNamespace()),
+		window.GetWindowTypeName(),
+		window.GetWindowTypeVersion(),
+	)
+	if !ok {
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, emittedWindow{}, fmt.Errorf(
+			"window type does not exist - insert via window type registration: %v %v",
+			window.GetWindowTypeName(),
+			window.GetWindowTypeVersion(),
+		)
+	}
+
+	// confident that any required metadata is being supplied to the processor
+	for _, mDataField := range d.readMetadataFieldsByWindowType(windowTypeId) {
+		fieldName := mDataField.GetName()
+		if _, exists := window.GetMetadata().GetFields()[fieldName]; !exists {
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, emittedWindow{}, fmt.Errorf("required metadata field '%s' is missing", fieldName)
+		}
+	}
+
+	var inNamespace func(procId int64) bool
+	if window.GetNamespace() != "" {
+		inNamespace = func(procId int64) bool {
+			return d.registry.processors[procId].namespace == window.GetNamespace()
+		}
+	}
+
+	// fire off processings
+	_, planSpan := tracing.Tracer.Start(ctx, "BuildPlan")
+	planStart := time.Now()
+	executionPlan, err := d.registry.index.Plan(windowTypeId, inNamespace)
+	metrics.PlanBuildDuration.Observe(time.Since(planStart).Seconds())
+	planSpan.SetAttributes(
+		attribute.Int("orca.stages", len(executionPlan.Stages)),
+		attribute.Int("orca.algorithms", executionPlan.Len()),
+	)
+	tracing.End(planSpan, err)
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to build the execution plan for window",
+			"error",
+			err,
+		)
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, emittedWindow{}, err
+	}
+
+	// the metadata is copied, as the caller may reuse the window
+	metadata := &structpb.Struct{}
+	if window.GetMetadata() != nil {
+		metadata = proto.Clone(window.GetMetadata()).(*structpb.Struct)
+	}
+	insertedWindow := emittedWindow{
+		id:           d.nextID(),
+		windowTypeID: windowTypeId,
+		timeFrom:     window.GetTimeFrom().AsTime().UTC(),
+		timeTo:       window.GetTimeTo().AsTime().UTC(),
+		origin:       window.GetOrigin(),
+		metadata:     metadata,
+		traceID:      tracing.TraceID(ctx),
+		createdBy:    auth.Caller(ctx),
+		namespace:    window.GetNamespace(),
+	}
+	d.windows[insertedWindow.id] = insertedWindow
+	slog.DebugContext(ctx, "window record inserted into the datalayer", logging.KeyWindowID, insertedWindow.id)
+
+	if len(executionPlan.Stages) > 0 {
+		return pb.WindowEmitStatus_PROCESSING_TRIGGERED, executionPlan, insertedWindow, nil
+	}
+	return pb.WindowEmitStatus_NO_TRIGGERED_ALGORITHMS, executionPlan, insertedWindow, nil
+}
+
+func (d *Datalayer) Expose(
+	ctx context.Context,
+	settings *pb.ExposeSettings,
+) (*pb.InternalState, error) {
+	if err := auth.Require(ctx, auth.RoleReader, auth.NamespaceScope(settings.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var processors []processor
+	if len(settings.GetNamespace()) > 0 {
+		processors = d.sortedProcessors(func(proc processor) bool {
+			return proc.namespace == settings.GetNamespace()
+		})
+	} else if len(settings.GetExcludeProject()) > 0 {
+		processors = d.sortedProcessors(func(proc processor) bool {
+			return proc.namespace != settings.GetExcludeProject()
+		})
+	} else {
+		// read all the processors
+		processors = d.sortedProcessors(func(processor) bool { return true })
+	}
+
+	wtsMap := make(map[int64]*pb.WindowType, len(d.registry.windowTypes))
+	for _, wt := range d.registry.windowTypes {
+		var metadataFields []*pb.MetadataField
+		for _, fieldId := range wt.metadataFieldIDs {
+			field := d.registry.metadataFields[fieldId]
+			metadataFields = append(metadataFields, &pb.MetadataField{
+				Name:        field.name,
+				Description: field.description,
+			})
+		}
+		windowTypePb := &pb.WindowType{
+			Name:           wt.name,
+			Version:        wt.version,
+			Description:    wt.description,
+			MetadataFields: metadataFields,
+		}
+		if wt.schedule != nil {
+			windowTypePb.Schedule = windowScheduleToPb(wt.schedule.settings)
+		}
+		if wt.rollup != nil {
+			windowTypePb.Rollup = windowRollupToPb(wt.rollup)
+		}
+		wtsMap[wt.id] = windowTypePb
+	}
+
+	algorithms := make([]algorithm, 0, len(d.registry.algorithms))
+	for _, algo := range d.registry.algorithms {
+		algorithms = append(algorithms, algo)
+	}
+	slices.SortFunc(algorithms, func(a, b algorithm) int { return cmp.Compare(a.id, b.id) })
+
+	algosForProcessor := make(map[int64][]*pb.Algorithm)
+	for _, algo := range algorithms {
+		algosForProcessor[algo.processorID] = append(algosForProcessor[algo.processorID], &pb.Algorithm{
+			Name:        algo.name,
+			Version:     algo.version,
+			WindowType:  wtsMap[algo.windowTypeID],
+			ResultType:  algo.resultType,
+			Description: algo.description,
+		})
+	}
+
+	processorsPb := make([]*pb.ProcessorRegistration, len(processors))
+
+	for ll, p := range processors {
+		// processors may register without algorithms
+		processorsPb[ll] = &pb.ProcessorRegistration{
+			Name:                p.name,
+			Runtime:             p.runtime,
+			ProjectName:         p.namespace,
+			SupportedAlgorithms: algosForProcessor[p.id],
+		}
+	}
+
+	slog.Debug("exposed state", "processors", processorsPb)
+	return &pb.InternalState{
+		Processors: processorsPb,
+	}, nil
+}
+
+// Ping always succeeds, as the store is in the memory of the process
+func (d *Datalayer) Ping(ctx context.Context) error {
+	return nil
+}
+
+// SchemaVersion is always 0, as the store has no schema to migrate
+func (d *Datalayer) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	return 0, false, nil
+}
+
+// CreateAPIKey stores the hash of a new API key under a unique name
+func (d *Datalayer) CreateAPIKey(ctx context.Context, name string, keyHash string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range d.apiKeys {
+		// names are kept after revocation
+		if key.name == name {
+			return fmt.Errorf("an API key named %s already exists", name)
+		}
+		if key.keyHash == keyHash {
+			return errors.New("could not create API key: the key is stored already")
+		}
+	}
+	d.apiKeys = append(d.apiKeys, apiKey{name: name, keyHash: keyHash})
+	return nil
+}
+
+// RevokeAPIKey revokes the API key of the given name
+func (d *Datalayer) RevokeAPIKey(ctx context.Context, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for ii, key := range d.apiKeys {
+		if key.name == name && !key.revoked {
+			d.apiKeys[ii].revoked = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no unrevoked key named %s", types.APIKeyNotFound, name)
+}
+
+// LookupAPIKey returns the name of the unrevoked API key with the given hash
+func (d *Datalayer) LookupAPIKey(ctx context.Context, keyHash string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range d.apiKeys {
+		if key.keyHash == keyHash && !key.revoked {
+			return key.name, nil
+		}
+	}
+	return "", types.APIKeyNotFound
+}
+
+// CreateGrant grants a role to an identity, doing nothing when it is held
+// already
+func (d *Datalayer) CreateGrant(ctx context.Context, identity string, grant types.Grant) error {
+	if !slices.Contains(auth.Roles, grant.Role) {
+		return fmt.Errorf("could not create grant: unknown role %s", grant.Role)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if slices.Contains(d.grants[identity], grant) {
+		return nil
+	}
+	d.grants[identity] = append(d.grants[identity], grant)
+	return nil
+}
+
+// DeleteGrant takes a role away from an identity
+func (d *Datalayer) DeleteGrant(ctx context.Context, identity string, grant types.Grant) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ii := slices.Index(d.grants[identity], grant)
+	if ii < 0 {
+		return fmt.Errorf("%w: %s does not hold the %s role for %s", types.GrantNotFound, identity, grant.Role, grant.Scope)
+	}
+	d.grants[identity] = slices.Delete(d.grants[identity], ii, ii+1)
+	return nil
+}
+
+// ReadGrants returns the roles granted to an identity
+func (d *Datalayer) ReadGrants(ctx context.Context, identity string) ([]types.Grant, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	grants := slices.Clone(d.grants[identity])
+	slices.SortFunc(grants, func(a, b types.Grant) int {
+		if c := strings.Compare(a.Role, b.Role); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Scope, b.Scope)
+	})
+	return grants, nil
+}
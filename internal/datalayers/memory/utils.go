@@ -0,0 +1,337 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// dispatchProcessor converts a stored processor to the processor tasks are
+// dispatched to
+func dispatchProcessor(proc processor) dispatch.Processor {
+	return dispatch.Processor{
+		ID:                    proc.id,
+		Name:                  proc.name,
+		Runtime:               proc.runtime,
+		Namespace:             proc.namespace,
+		ConnectionString:      proc.connectionString,
+		TLSServerName:         proc.tlsServerName,
+		TLSInsecureSkipVerify: proc.tlsInsecureSkipVerify,
+	}
+}
+
+// processWindow runs the algorithms triggered by a stored window, and then
+// counts it towards the rollups it is a child of
+func processWindow(
+	ctx context.Context,
+	d *Datalayer,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	insertedWindow emittedWindow,
+) {
+	dispatch.ProcessWindow(ctx, executionPlan, window, insertedWindow.id, &processedWindow{
+		d:              d,
+		window:         window,
+		insertedWindow: insertedWindow,
+	})
+}
+
+// processedWindow is a stored window being processed
+type processedWindow struct {
+	d              *Datalayer
+	window         *pb.Window
+	insertedWindow emittedWindow
+}
+
+func (w *processedWindow) RunPlan(ctx context.Context, executionPlan dag.Plan) error {
+	return processTasks(ctx, w.d, executionPlan, w.window, w.insertedWindow)
+}
+
+func (w *processedWindow) Rollup(ctx context.Context) {
+	// rolled up window types are those of the namespace of the child
+	w.d.mu.Lock()
+	var rollups []windowType
+	for _, wt := range w.d.registry.windowTypes {
+		if wt.namespace == types.Namespace(w.window.GetNamespace()) &&
+			wt.rollup.GetChildWindowTypeName() == w.window.GetWindowTypeName() &&
+			wt.rollup.GetChildWindowTypeVersion() == w.window.GetWindowTypeVersion() {
+			rollups = append(rollups, wt)
+		}
+	}
+	w.d.mu.Unlock()
+
+	for _, rollup := range rollups {
+		if err := w.d.rollupWindow(ctx, rollup, w.window); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"could not roll up window",
+				"rolled_up_window_type",
+				rollup.name,
+				"error",
+				err,
+			)
+		}
+	}
+}
+
+func processTasks(
+	ctx context.Context,
+	d *Datalayer,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	insertedWindow emittedWindow,
+) error {
+	d.mu.Lock()
+
+	// get map of processors from processor ids
+	processorMap := make(map[int64]dispatch.Processor, len(executionPlan.AffectedProcessors))
+	for _, procId := range executionPlan.AffectedProcessors {
+		if proc, ok := d.registry.processors[procId]; ok {
+			processorMap[procId] = dispatchProcessor(proc)
+		}
+	}
+
+	// get map of algorithms from algorithm ids
+	algorithmMap := make(map[int64]dispatch.Algorithm)
+	for _, algo := range d.registry.algorithms {
+		if algo.windowTypeID == insertedWindow.windowTypeID {
+			algorithmMap[algo.id] = dispatch.Algorithm{ID: algo.id, Name: algo.name, Version: algo.version}
+		}
+	}
+
+	// names of the metadata fields that lookbacks are partitioned on
+	metadataFieldNames := make(map[int64]string, len(d.registry.metadataFields))
+	for _, field := range d.registry.metadataFields {
+		metadataFieldNames[field.id] = field.name
+	}
+
+	// lookback aggregates requested by the algorithms, keyed by dependency
+	lookbackAggregateMap := make(map[dispatch.LookbackKey]*pb.LookbackAggregate)
+	for key, dep := range d.registry.dependencies {
+		if _, ok := algorithmMap[key.toAlgorithmID]; ok && dep.lookbackAggregate != nil {
+			lookbackAggregateMap[dispatch.LookbackKey{
+				DependencyID: key.fromAlgorithmID,
+				DependantID:  key.toAlgorithmID,
+			}] = dep.lookbackAggregate
+		}
+	}
+
+	d.mu.Unlock()
+
+	return d.dispatcher.Run(ctx, executionPlan, window, processorMap, algorithmMap, &windowStore{
+		d:                  d,
+		window:             window,
+		insertedWindow:     insertedWindow,
+		metadataFieldNames: metadataFieldNames,
+		aggregates:         lookbackAggregateMap,
+	})
+}
+
+// windowStore reads the past results, and stores the results, of the
+// algorithms a window triggers
+type windowStore struct {
+	d                  *Datalayer
+	window             *pb.Window
+	insertedWindow     emittedWindow
+	metadataFieldNames map[int64]string
+	aggregates         map[dispatch.LookbackKey]*pb.LookbackAggregate
+}
+
+func (s *windowStore) ReadLookbacks(ctx context.Context, stage dag.Stage) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	return readStageLookbacks(ctx, s.d, stage, s.window, s.metadataFieldNames, s.aggregates)
+}
+
+func (s *windowStore) CreateResult(ctx context.Context, algo dispatch.Algorithm, result *pb.ExecutionResult) (int64, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	algorithm, ok := s.d.registry.algorithms[algo.ID]
+	if !ok {
+		return 0, fmt.Errorf("algorithm %v does not exist", algo.Name)
+	}
+	stored := storedResult{
+		id:           s.d.nextID(),
+		windowID:     s.insertedWindow.id,
+		windowTypeID: s.insertedWindow.windowTypeID,
+		algorithmID:  algo.ID,
+		result:       proto.Clone(result.GetAlgorithmResult().GetResult()).(*pb.Result),
+		traceID:      tracing.TraceID(ctx),
+		namespace:    s.d.registry.processors[algorithm.processorID].namespace,
+	}
+	s.d.results = append(s.d.results, stored)
+	return stored.id, nil
+}
+
+// lookbackResult is a past result of a lookback, with the window it was
+// produced for
+type lookbackResult struct {
+	result storedResult
+	window emittedWindow
+}
+
+// readStageLookbacks fetches the past results of every lookback in the stage
+func readStageLookbacks(
+	ctx context.Context,
+	d *Datalayer,
+	stage dag.Stage,
+	window *pb.Window,
+	metadataFieldNames map[int64]string,
+	aggregates map[dispatch.LookbackKey]*pb.LookbackAggregate,
+) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lookbacks := make(map[dispatch.LookbackKey]dispatch.Lookback)
+
+	for lookback := range stage.Lookbacks() {
+		key := dispatch.LookbackKey{DependencyID: lookback.Dep.AlgoId, DependantID: lookback.AlgoId}
+		agg, aggregated := aggregates[key]
+		queryName := "results"
+		if aggregated {
+			queryName = "aggregates"
+		}
+		queryStart := time.Now()
+		results, err := d.readResultsForLookback(lookback.Dep, window, metadataFieldNames)
+		metrics.LookbackQueryDuration.WithLabelValues(queryName).Observe(time.Since(queryStart).Seconds())
+		if err != nil {
+			return nil, err
+		}
+
+		if aggregated {
+			pastResults := make([]dispatch.PastResult, len(results))
+			for ii, res := range results {
+				pastResults[ii] = dispatch.PastResult{
+					Value:    float64(res.result.result.GetSingleValue()),
+					TimeFrom: res.window.timeFrom,
+					TimeTo:   res.window.timeTo,
+				}
+			}
+			buckets, err := dispatch.Aggregate(
+				agg.GetFunction(),
+				agg.GetPercentile(),
+				time.Duration(agg.GetBucketTimeDelta()),
+				pastResults,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if len(buckets) > 0 {
+				lookbacks[key] = dispatch.Lookback{Buckets: buckets}
+			}
+			continue
+		}
+
+		for _, res := range results {
+			row := d.lookbackResultToPb(res)
+			if row == nil {
+				slog.WarnContext(ctx, "could not assert type of result from algorithm", "algorithm_id", res.result.algorithmID)
+				continue
+			}
+			past := lookbacks[key]
+			past.Rows = append(past.Rows, row)
+			lookbacks[key] = past
+		}
+	}
+
+	return lookbacks, nil
+}
+
+// readResultsForLookback returns the latest past results of the dependency
+// of a lookback, within its search range and partition, oldest first.
+// Called with the lock held
+func (d *Datalayer) readResultsForLookback(
+	dep dag.AlgoDep,
+	window *pb.Window,
+	metadataFieldNames map[int64]string,
+) ([]lookbackResult, error) {
+	searchFrom, searchTo := dispatch.LookbackSearchRange(dep.Lookback, window)
+	partition, err := dispatch.LookbackPartition(dep.Lookback, window, metadataFieldNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []lookbackResult
+	for _, res := range d.results {
+		if res.algorithmID != dep.AlgoId {
+			continue
+		}
+		past := d.windows[res.windowID]
+		if searchFrom != nil && !past.timeFrom.After(*searchFrom) {
+			continue
+		}
+		if !past.timeTo.Before(searchTo) {
+			continue
+		}
+		if partition.Contains(past.origin, past.metadata) {
+			results = append(results, lookbackResult{result: res, window: past})
+		}
+	}
+
+	// the latest results are read, and passed on oldest first
+	slices.SortStableFunc(results, func(a, b lookbackResult) int {
+		return b.window.timeTo.Compare(a.window.timeTo)
+	})
+	if dep.Lookback.Count > 0 && len(results) > dep.Lookback.Count {
+		results = results[:dep.Lookback.Count]
+	}
+	slices.SortStableFunc(results, func(a, b lookbackResult) int {
+		if c := a.window.timeFrom.Compare(b.window.timeFrom); c != 0 {
+			return c
+		}
+		return a.window.timeTo.Compare(b.window.timeTo)
+	})
+	return results, nil
+}
+
+// lookbackResultToPb converts a past result into a dependency result row,
+// according to the result type of its algorithm. Results of algorithms that
+// do not produce a result are converted to nil. Called with the lock held
+func (d *Datalayer) lookbackResultToPb(res lookbackResult) *pb.AlgorithmDependencyResultRow {
+	var result *pb.Result
+	switch d.registry.algorithms[res.result.algorithmID].resultType {
+	case pb.ResultType_VALUE:
+		result = &pb.Result{ResultData: &pb.Result_SingleValue{
+			SingleValue: res.result.result.GetSingleValue(),
+		}}
+	case pb.ResultType_ARRAY:
+		result = &pb.Result{ResultData: &pb.Result_FloatValues{
+			FloatValues: &pb.FloatArray{Values: slices.Clone(res.result.result.GetFloatValues().GetValues())},
+		}}
+	case pb.ResultType_STRUCT:
+		resultStruct := &structpb.Struct{}
+		if res.result.result.GetStructValue() != nil {
+			resultStruct = proto.Clone(res.result.result.GetStructValue()).(*structpb.Struct)
+		}
+		result = &pb.Result{ResultData: &pb.Result_StructValue{
+			StructValue: resultStruct,
+		}}
+	default:
+		return nil
+	}
+
+	windowType := d.registry.windowTypes[res.window.windowTypeID]
+	return &pb.AlgorithmDependencyResultRow{
+		Result: result,
+		Window: &pb.Window{
+			TimeFrom:          timestamppb.New(res.window.timeFrom),
+			TimeTo:            timestamppb.New(res.window.timeTo),
+			Origin:            res.window.origin,
+			WindowTypeName:    windowType.name,
+			WindowTypeVersion: windowType.version,
+			Metadata:          proto.Clone(res.window.metadata).(*structpb.Struct),
+		},
+	}
+}
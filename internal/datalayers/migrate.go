@@ -1,38 +1,214 @@
 package datalayers
 
 import (
+	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	types "github.com/orca-telemetry/core/datalayer"
 )
 
 //go:embed postgresql/migrations/*.sql
 var PostgresqlMigrations embed.FS
 
-func MigrateDatalayer(platform string, connStr string) error {
+//go:embed sqlite/migrations/*.sql
+var SqliteMigrations embed.FS
+
+// migrationSource returns the embedded migrations of a platform
+func migrationSource(platform string) (source.Driver, error) {
+	var d source.Driver
+	var err error
 	switch platform {
 	case "postgresql":
-		d, err := iofs.New(PostgresqlMigrations, "postgresql/migrations")
-		if err != nil {
-			return fmt.Errorf("failed to load embedded migrations: %w", err)
-		}
+		d, err = iofs.New(PostgresqlMigrations, "postgresql/migrations")
+	case "sqlite":
+		d, err = iofs.New(SqliteMigrations, "sqlite/migrations")
+	default:
+		return nil, fmt.Errorf("unsuported platform: %v", platform)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+	return d, nil
+}
 
-		m, err := migrate.NewWithSourceInstance("iofs", d, connStr)
-		if err != nil {
-			return fmt.Errorf("failed to create migrator: %w", err)
-		}
+// MigrationStatus is the schema version of a store, and the version of the
+// newest migration embedded in this binary
+type MigrationStatus struct {
+	// version of the last applied migration, 0 when none has been
+	Version uint
+	// whether the last migration failed part way, leaving the store for
+	// force to mark as fixed
+	Dirty  bool
+	Latest uint
+}
 
-		if err := m.Up(); err == migrate.ErrNoChange {
-			slog.Info("no migrations needed")
-		} else if err != nil {
-			return fmt.Errorf("failed to run migrations: %w", err)
-		}
+// Migrator applies the embedded migrations of a platform to a store
+type Migrator struct {
+	platform string
+	m        *migrate.Migrate
+}
+
+// NewMigrator returns a migrator of the store at connStr, to be closed once
+// done with
+func NewMigrator(platform string, connStr string) (*Migrator, error) {
+	if platform == string(Memory) {
+		return nil, errors.New("the memory datalayer has no schema to migrate")
+	}
+	d, err := migrationSource(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", d, connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	return &Migrator{platform: platform, m: m}, nil
+}
+
+// Up applies every migration not yet applied
+func (m *Migrator) Up() error {
+	return m.run("up", m.m.Up())
+}
+
+// Down reverts the last n applied migrations
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to revert must be positive, not %d", n)
+	}
+	return m.run("down", m.m.Steps(-n))
+}
+
+// Goto migrates up or down to the given version
+func (m *Migrator) Goto(version uint) error {
+	return m.run("goto", m.m.Migrate(version))
+}
+
+// Force records the store as at the given version and not dirty, without
+// running any migration, once a failed migration has been fixed by hand.
+// A version of -1 records that no migration has been applied
+func (m *Migrator) Force(version int) error {
+	if version < -1 {
+		return fmt.Errorf("cannot force version %d", version)
+	}
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("failed to force version %d: %w", version, err)
+	}
+	return nil
+}
+
+// Status returns the schema version of the store
+func (m *Migrator) Status() (MigrationStatus, error) {
+	latest, err := LatestMigrationVersion(m.platform)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return MigrationStatus{Latest: latest}, nil
+	}
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return MigrationStatus{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+// Close releases the connection to the store
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+func (m *Migrator) run(direction string, err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		slog.Info("no migrations needed")
+		return nil
+	}
+	var short migrate.ErrShortLimit
+	if errors.As(err, &short) {
+		slog.Warn("reverted every migration, fewer than asked", "short", short.Short)
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to run migrations %s: no such migration", direction)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run migrations %s: %w", direction, err)
+	}
+	return nil
+}
 
+func MigrateDatalayer(platform string, connStr string) error {
+	if platform == string(Memory) {
+		slog.Info("the memory datalayer has no schema, so no migrations needed")
 		return nil
 	}
-	return fmt.Errorf("unsuported platform: %v", platform)
+	m, err := NewMigrator(platform, connStr)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up()
+}
+
+// LatestMigrationVersion returns the version of the newest embedded migration
+// of a platform, which a migrated store is expected to be at. The memory
+// datalayer has none, so is always at version 0
+func LatestMigrationVersion(platform string) (uint, error) {
+	if platform == string(Memory) {
+		return 0, nil
+	}
+	d, err := migrationSource(platform)
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+
+	version, err := d.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	for {
+		next, err := d.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// CheckSchema returns an error unless the store is migrated to the latest
+// embedded migration, with how to migrate it
+func CheckSchema(ctx context.Context, client types.Datalayer, platform string) error {
+	latest, err := LatestMigrationVersion(platform)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := client.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d of the datalayer failed part way. Fix it, then run orca migrate force %d", version, version)
+	}
+	if version < latest {
+		return fmt.Errorf("datalayer schema is at version %d, behind version %d of this release. Run orca migrate up", version, latest)
+	}
+	if version > latest {
+		return fmt.Errorf("datalayer schema is at version %d, ahead of version %d of this release. Upgrade orca, or migrate down with the release that migrated it", version, latest)
+	}
+	return nil
 }
@@ -0,0 +1,186 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+)
+
+// extensions the migrations depend on
+var requiredExtensions = []string{"ltree"}
+
+// Diagnose checks the permissions and extensions of the database, that the
+// algorithm execution paths are up to date with the algorithm dependencies,
+// and that every registered processor is serving
+func (d *Datalayer) Diagnose(ctx context.Context) []types.Check {
+	checks := []types.Check{
+		d.checkPermissions(ctx),
+		d.checkExtensions(ctx),
+		d.checkExecutionPaths(ctx),
+	}
+	return append(checks, d.checkProcessors(ctx)...)
+}
+
+// checkPermissions checks that the user can read and write every table, and
+// refresh the materialized views
+func (d *Datalayer) checkPermissions(ctx context.Context) types.Check {
+	check := types.Check{Name: "database permissions"}
+
+	var user, schema string
+	if err := d.conn.QueryRow(ctx, "SELECT current_user, current_schema()").Scan(&user, &schema); err != nil {
+		check.Err = fmt.Errorf("could not read the current user: %w", err)
+		return check
+	}
+
+	rows, err := d.conn.Query(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema()
+		AND NOT has_table_privilege(current_user, quote_ident(schemaname) || '.' || quote_ident(tablename), 'SELECT, INSERT, UPDATE, DELETE')
+		ORDER BY tablename`)
+	if err != nil {
+		check.Err = fmt.Errorf("could not read table privileges: %w", err)
+		return check
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			check.Err = fmt.Errorf("could not read table privileges: %w", err)
+			return check
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		check.Err = fmt.Errorf("could not read table privileges: %w", err)
+		return check
+	}
+	if len(tables) > 0 {
+		check.Err = fmt.Errorf("%s cannot read and write tables %s", user, strings.Join(tables, ", "))
+		check.Fix = fmt.Sprintf("GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA %s TO %s", schema, user)
+		return check
+	}
+
+	// the metadata fields of window types are refreshed by triggers, and the
+	// execution paths after registrations, which only the owner of a
+	// materialized view can do
+	rows, err = d.conn.Query(ctx, `
+		SELECT matviewname FROM pg_matviews
+		WHERE schemaname = current_schema()
+		AND NOT pg_has_role(current_user, matviewowner, 'USAGE')
+		ORDER BY matviewname`)
+	if err != nil {
+		check.Err = fmt.Errorf("could not read materialized view owners: %w", err)
+		return check
+	}
+	var views []string
+	for rows.Next() {
+		var view string
+		if err := rows.Scan(&view); err != nil {
+			rows.Close()
+			check.Err = fmt.Errorf("could not read materialized view owners: %w", err)
+			return check
+		}
+		views = append(views, view)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		check.Err = fmt.Errorf("could not read materialized view owners: %w", err)
+		return check
+	}
+	if len(views) > 0 {
+		check.Err = fmt.Errorf("%s cannot refresh materialized views %s", user, strings.Join(views, ", "))
+		check.Fix = fmt.Sprintf("ALTER MATERIALIZED VIEW <view> OWNER TO %s, for each view, or grant %s the role owning them", user, user)
+	}
+	return check
+}
+
+// checkExtensions checks that the extensions the schema uses are installed
+func (d *Datalayer) checkExtensions(ctx context.Context) types.Check {
+	check := types.Check{Name: "database extensions"}
+
+	var missing []string
+	err := d.conn.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(name), '{}') FROM unnest($1::text[]) AS name
+		WHERE name NOT IN (SELECT extname FROM pg_extension)`,
+		requiredExtensions,
+	).Scan(&missing)
+	if err != nil {
+		check.Err = fmt.Errorf("could not read extensions: %w", err)
+		return check
+	}
+	if len(missing) > 0 {
+		check.Err = fmt.Errorf("extensions %s are not installed", strings.Join(missing, ", "))
+		check.Fix = fmt.Sprintf(
+			"as a superuser, run CREATE EXTENSION %s. The extension is part of the postgresql-contrib package",
+			strings.Join(missing, "; CREATE EXTENSION "),
+		)
+	}
+	return check
+}
+
+// checkExecutionPaths checks that every algorithm, and every dependency
+// between algorithms, is on an execution path, and that every path is made
+// of existing algorithms
+func (d *Datalayer) checkExecutionPaths(ctx context.Context) types.Check {
+	check := types.Check{
+		Name: "algorithm execution paths",
+		Fix:  "REFRESH MATERIALIZED VIEW algorithm_execution_paths",
+	}
+
+	var missingAlgorithms, missingDependencies, staleAlgorithms int
+	err := d.conn.QueryRow(ctx, `
+		SELECT
+			(SELECT count(*) FROM algorithm a WHERE NOT EXISTS (
+				SELECT 1 FROM algorithm_execution_paths aep
+				WHERE aep.algo_id_path ~ ('*.' || a.id::TEXT || '.*')::lquery
+			)),
+			(SELECT count(*) FROM algorithm_dependency ad WHERE NOT EXISTS (
+				SELECT 1 FROM algorithm_execution_paths aep
+				WHERE aep.algo_id_path ~ ('*.' || ad.from_algorithm_id::TEXT || '.' || ad.to_algorithm_id::TEXT || '.*')::lquery
+			)),
+			(SELECT count(DISTINCT path_algo_id) FROM (
+				SELECT unnest(string_to_array(ltree2text(algo_id_path), '.'))::BIGINT AS path_algo_id
+				FROM algorithm_execution_paths
+			) paths WHERE path_algo_id NOT IN (SELECT id FROM algorithm))`,
+	).Scan(&missingAlgorithms, &missingDependencies, &staleAlgorithms)
+	if err != nil {
+		check.Err = fmt.Errorf("could not read execution paths: %w", err)
+		return check
+	}
+
+	var problems []string
+	if missingAlgorithms > 0 {
+		problems = append(problems, fmt.Sprintf("%d algorithms are on no path", missingAlgorithms))
+	}
+	if missingDependencies > 0 {
+		problems = append(problems, fmt.Sprintf("%d dependencies are on no path", missingDependencies))
+	}
+	if staleAlgorithms > 0 {
+		problems = append(problems, fmt.Sprintf("%d algorithms on paths no longer exist", staleAlgorithms))
+	}
+	if len(problems) > 0 {
+		check.Err = fmt.Errorf("execution paths are out of date with the algorithm dependencies: %s", strings.Join(problems, ", "))
+	}
+	return check
+}
+
+// checkProcessors health checks every registered processor, at once
+func (d *Datalayer) checkProcessors(ctx context.Context) []types.Check {
+	processors, err := d.queries.ReadProcessors(ctx)
+	if err != nil {
+		return []types.Check{{
+			Name: "registered processors",
+			Err:  fmt.Errorf("could not read processors: %w", err),
+		}}
+	}
+	dispatchProcessors := make([]dispatch.Processor, len(processors))
+	for ii, proc := range processors {
+		dispatchProcessors[ii] = dispatchProcessor(proc)
+	}
+	return d.dispatcher.CheckProcessors(ctx, dispatchProcessors)
+}
@@ -6,22 +6,45 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
-	types "github.com/orca-telemetry/core/internal/types"
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
 	pb "github.com/orca-telemetry/core/protobufs/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
 )
 
 type Datalayer struct {
 	queries *Queries
 	conn    *pgxpool.Pool
 	closeFn func()
+
+	// connection holding the scheduler lock, whilst this instance is the
+	// one emitting scheduled windows
+	schedulerConn *pgxpool.Conn
+	schedulerMu   sync.Mutex
+
+	// runs execution plans on the processors
+	dispatcher *dispatch.Dispatcher
+
+	// index of the algorithm DAG, as of a version of the registry, which
+	// execution plans are built from
+	dagCache dag.IndexCache
 }
 
+// schedulerLockKey is the advisory lock held by the instance that emits
+// scheduled windows
+const schedulerLockKey int64 = 0x6f726361
+
 type PgTx struct {
 	tx pgx.Tx
 }
@@ -34,12 +57,69 @@ func (t *PgTx) Commit(ctx context.Context) error {
 	return t.tx.Commit(ctx)
 }
 
+// statistics of the connection pool, collected when the datalayer is
+// registered with a prometheus registry
+var (
+	poolAcquiredConns = prometheus.NewDesc(
+		"orca_db_pool_acquired_conns", "Connections currently acquired from the pool.", nil, nil,
+	)
+	poolIdleConns = prometheus.NewDesc(
+		"orca_db_pool_idle_conns", "Idle connections in the pool.", nil, nil,
+	)
+	poolTotalConns = prometheus.NewDesc(
+		"orca_db_pool_total_conns", "Connections in the pool, including those being opened.", nil, nil,
+	)
+	poolMaxConns = prometheus.NewDesc(
+		"orca_db_pool_max_conns", "Maximum size of the pool.", nil, nil,
+	)
+	poolAcquires = prometheus.NewDesc(
+		"orca_db_pool_acquires_total", "Connections acquired from the pool.", nil, nil,
+	)
+	poolAcquireDuration = prometheus.NewDesc(
+		"orca_db_pool_acquire_duration_seconds_total", "Time spent acquiring connections from the pool.", nil, nil,
+	)
+	poolEmptyAcquires = prometheus.NewDesc(
+		"orca_db_pool_empty_acquires_total", "Acquires that waited for a connection because the pool was empty.", nil, nil,
+	)
+	poolCanceledAcquires = prometheus.NewDesc(
+		"orca_db_pool_canceled_acquires_total", "Acquires canceled by their context.", nil, nil,
+	)
+)
+
+func (d *Datalayer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolAcquireDuration
+	ch <- poolEmptyAcquires
+	ch <- poolCanceledAcquires
+}
+
+func (d *Datalayer) Collect(ch chan<- prometheus.Metric) {
+	stat := d.conn.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
 // generate a new client for the postgres datalayer
 func NewClient(ctx context.Context, connStr string) (*Datalayer, error) {
 	if connStr == "" {
 		return nil, errors.New("connection string empty")
 	}
 
+	dispatcher, err := dispatch.NewDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
 	connPool, err := pgxpool.New(ctx, connStr)
 	if err != nil {
 		slog.Error("Issue connecting to postgres", "error", err)
@@ -47,9 +127,10 @@ func NewClient(ctx context.Context, connStr string) (*Datalayer, error) {
 	}
 
 	return &Datalayer{
-		queries: New(connPool),
-		conn:    connPool,
-		closeFn: connPool.Close,
+		queries:    New(connPool),
+		conn:       connPool,
+		closeFn:    connPool.Close,
+		dispatcher: dispatcher,
 	}, nil
 }
 
@@ -71,16 +152,27 @@ func (d *Datalayer) createProcessor(
 
 	qtx := d.queries.WithTx(pgTx.tx)
 
+	// processors are unique within their namespace, so registering under
+	// another project registers another processor
 	err := qtx.CreateProcessor(ctx, CreateProcessorParams{
 		Name:             proc.GetName(),
 		Runtime:          proc.GetRuntime(),
 		ConnectionString: proc.GetConnectionStr(),
-		ProjectName:      pgtype.Text{String: proc.GetProjectName(), Valid: true},
+		Namespace:        types.Namespace(proc.GetProjectName()),
+		RegisteredBy:     callerText(ctx),
+		TlsServerName: pgtype.Text{
+			String: proc.GetTls().GetServerName(),
+			Valid:  proc.GetTls().GetServerName() != "",
+		},
+		TlsInsecureSkipVerify: proc.GetTls().GetInsecureSkipVerify(),
 	})
 	if err != nil {
 		slog.Error("could not create processor", "error", err)
 		return err
 	}
+	if proc.GetTls().GetInsecureSkipVerify() {
+		slog.WarnContext(ctx, "processor registered without verifying its certificate, which is only safe in development", "processor", proc.GetName())
+	}
 	return nil
 }
 
@@ -106,12 +198,14 @@ func (d *Datalayer) readMetadataFieldsByWindowType(
 	ctx context.Context,
 	tx types.Tx,
 	windowType *pb.WindowType,
+	namespace string,
 ) ([]*pb.MetadataField, error) {
 	pgTx := tx.(*PgTx)
 	qtx := d.queries.WithTx(pgTx.tx)
 	metadataFields, err := qtx.ReadMetadataFieldsByWindowType(ctx, ReadMetadataFieldsByWindowTypeParams{
-		WindowTypeName:    windowType.GetName(),
-		WindowTypeVersion: windowType.GetVersion(),
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      windowType.GetName(),
+		WindowTypeVersion:   windowType.GetVersion(),
 	})
 	if err != nil {
 		return []*pb.MetadataField{}, fmt.Errorf("could not read metadata fields: %v", err)
@@ -131,10 +225,12 @@ func (d *Datalayer) createWindowType(
 	ctx context.Context,
 	tx types.Tx,
 	windowType *pb.WindowType,
+	namespace string,
 ) (int64, error) {
 	pgTx := tx.(*PgTx)
 	qtx := d.queries.WithTx(pgTx.tx)
 	windowTypeId, err := qtx.CreateWindowType(ctx, CreateWindowTypeParams{
+		Namespace:   namespace,
 		Name:        windowType.GetName(),
 		Version:     windowType.GetVersion(),
 		Description: windowType.GetDescription(),
@@ -146,6 +242,232 @@ func (d *Datalayer) createWindowType(
 	return windowTypeId, nil
 }
 
+// setWindowSchedule creates or updates the schedule of a window type,
+// replacing any rollup, or removes it when the registration asks to. A
+// window type registered without a schedule keeps the one it has, as other
+// processors may share the window type
+func (d *Datalayer) setWindowSchedule(
+	ctx context.Context,
+	tx types.Tx,
+	windowTypeId int64,
+	windowType *pb.WindowType,
+) error {
+	pgTx := tx.(*PgTx)
+	qtx := d.queries.WithTx(pgTx.tx)
+
+	if windowType.GetRemoveSchedule() {
+		err := qtx.DeleteWindowSchedule(ctx, windowTypeId)
+		if err != nil {
+			slog.Error("could not delete window schedule", "error", err)
+			return err
+		}
+		return nil
+	}
+	windowSchedule := windowType.GetSchedule()
+	if windowSchedule == nil {
+		return nil
+	}
+
+	// scheduled windows are emitted without metadata, so they could never
+	// carry the fields of the window type
+	if len(windowType.GetMetadataFields()) > 0 {
+		return fmt.Errorf(
+			"window type %v cannot be scheduled, as scheduled window types cannot carry metadata fields",
+			windowType.GetName(),
+		)
+	}
+
+	sched, err := schedule.FromPb(windowSchedule)
+	if err != nil {
+		return err
+	}
+
+	// the first window starts at the next scheduled time, so that
+	// windows always span a whole period
+	err = qtx.CreateWindowSchedule(ctx, CreateWindowScheduleParams{
+		WindowTypeID: windowTypeId,
+		Cron: pgtype.Text{
+			String: windowSchedule.GetCron(),
+			Valid:  windowSchedule.GetCron() != "",
+		},
+		IntervalNs:  int64(windowSchedule.GetInterval()),
+		AlignmentNs: int64(windowSchedule.GetAlignment()),
+		TimeZone:    windowSchedule.GetTimeZone(),
+		Origin:      windowSchedule.GetOrigin(),
+		CatchUp:     windowSchedule.GetCatchUp(),
+		HighWaterMark: pgtype.Timestamp{
+			Time:  sched.Next(time.Now().UTC()),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		slog.Error("could not create window schedule", "error", err)
+		return err
+	}
+
+	// windows are emitted by at most one of a schedule and a rollup
+	err = qtx.DeleteWindowRollup(ctx, windowTypeId)
+	if err != nil {
+		slog.Error("could not delete window rollup", "error", err)
+		return err
+	}
+	return nil
+}
+
+// setWindowRollup creates or updates the rollup of a window type, replacing
+// any schedule, or removes it when the registration asks to. A window type
+// registered without a rollup keeps the one it has
+func (d *Datalayer) setWindowRollup(
+	ctx context.Context,
+	tx types.Tx,
+	windowTypeId int64,
+	windowType *pb.WindowType,
+) error {
+	pgTx := tx.(*PgTx)
+	qtx := d.queries.WithTx(pgTx.tx)
+
+	if windowType.GetRemoveRollup() {
+		err := qtx.DeleteWindowRollup(ctx, windowTypeId)
+		if err != nil {
+			slog.Error("could not delete window rollup", "error", err)
+			return err
+		}
+		return nil
+	}
+	windowRollup := windowType.GetRollup()
+	if windowRollup == nil {
+		return nil
+	}
+
+	_, err := schedule.Parse(
+		"",
+		time.Duration(windowRollup.GetInterval()),
+		time.Duration(windowRollup.GetAlignment()),
+		windowRollup.GetTimeZone(),
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid window rollup: %w", err)
+	}
+
+	lateWindowPolicy := LateWindowPolicyIgnore
+	if windowRollup.GetLateWindows() == pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT {
+		lateWindowPolicy = LateWindowPolicyReemit
+	}
+
+	err = qtx.CreateWindowRollup(ctx, CreateWindowRollupParams{
+		WindowTypeID:           windowTypeId,
+		ChildWindowTypeName:    windowRollup.GetChildWindowTypeName(),
+		ChildWindowTypeVersion: windowRollup.GetChildWindowTypeVersion(),
+		IntervalNs:             int64(windowRollup.GetInterval()),
+		AlignmentNs:            int64(windowRollup.GetAlignment()),
+		TimeZone:               windowRollup.GetTimeZone(),
+		ChildCount:             int32(windowRollup.GetChildCount()),
+		LateWindowPolicy:       lateWindowPolicy,
+	})
+	if err != nil {
+		slog.Error("could not create window rollup", "error", err)
+		return err
+	}
+
+	// windows are emitted by at most one of a schedule and a rollup
+	err = qtx.DeleteWindowSchedule(ctx, windowTypeId)
+	if err != nil {
+		slog.Error("could not delete window schedule", "error", err)
+		return err
+	}
+	return nil
+}
+
+// isSchedulerLeader reports whether this instance holds the scheduler lock,
+// taking it if it is free. The lock is held on a dedicated connection, so it
+// is released by postgres if this instance goes away
+func (d *Datalayer) isSchedulerLeader(ctx context.Context) (bool, error) {
+	d.schedulerMu.Lock()
+	defer d.schedulerMu.Unlock()
+
+	if d.schedulerConn != nil {
+		err := d.schedulerConn.Ping(ctx)
+		if err == nil {
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		slog.Warn("lost the connection holding the scheduler lock", "error", err)
+		d.schedulerConn.Release()
+		d.schedulerConn = nil
+	}
+
+	conn, err := d.conn.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	locked, err := New(conn).TryAdvisoryLock(ctx, schedulerLockKey)
+	if err != nil || !locked {
+		conn.Release()
+		return false, err
+	}
+	slog.Info("took the scheduler lock, emitting scheduled windows")
+	d.schedulerConn = conn
+	return true, nil
+}
+
+// scheduleFromRow parses a stored window schedule
+func scheduleFromRow(row ReadWindowSchedulesRow) (schedule.Schedule, error) {
+	return schedule.Parse(
+		row.Cron.String,
+		time.Duration(row.IntervalNs),
+		time.Duration(row.AlignmentNs),
+		row.TimeZone,
+		row.CatchUp,
+	)
+}
+
+// rollupScheduleFromRow parses the interval of a stored window rollup
+func rollupScheduleFromRow(row ReadWindowRollupsForChildRow) (schedule.Schedule, error) {
+	return schedule.Parse(
+		"",
+		time.Duration(row.IntervalNs),
+		time.Duration(row.AlignmentNs),
+		row.TimeZone,
+		false,
+	)
+}
+
+// windowRollupToPb converts a stored window rollup to its protobuf
+func windowRollupToPb(row WindowRollup) *pb.WindowRollup {
+	lateWindows := pb.WindowRollup_LATE_WINDOW_POLICY_IGNORE
+	if row.LateWindowPolicy == LateWindowPolicyReemit {
+		lateWindows = pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT
+	}
+	return &pb.WindowRollup{
+		ChildWindowTypeName:    row.ChildWindowTypeName,
+		ChildWindowTypeVersion: row.ChildWindowTypeVersion,
+		Interval:               uint64(row.IntervalNs),
+		Alignment:              uint64(row.AlignmentNs),
+		TimeZone:               row.TimeZone,
+		ChildCount:             uint32(row.ChildCount),
+		LateWindows:            lateWindows,
+	}
+}
+
+// windowScheduleToPb converts a stored window schedule to its protobuf
+func windowScheduleToPb(row ReadWindowSchedulesRow) *pb.WindowSchedule {
+	windowSchedule := &pb.WindowSchedule{
+		Alignment: uint64(row.AlignmentNs),
+		TimeZone:  row.TimeZone,
+		Origin:    row.Origin,
+		CatchUp:   row.CatchUp,
+	}
+	if row.Cron.Valid {
+		windowSchedule.Trigger = &pb.WindowSchedule_Cron{Cron: row.Cron.String}
+	} else {
+		windowSchedule.Trigger = &pb.WindowSchedule_Interval{Interval: uint64(row.IntervalNs)}
+	}
+	return windowSchedule
+}
+
 func (d *Datalayer) createMetadataFieldBridge(
 	ctx context.Context,
 	tx types.Tx,
@@ -165,9 +487,12 @@ func (d *Datalayer) createMetadataFieldBridge(
 	return nil
 }
 
+// addAlgorithm creates the algorithm of a processor, and adds it to the
+// index of the DAG
 func (d *Datalayer) addAlgorithm(
 	ctx context.Context,
 	tx types.Tx,
+	index *dag.Index,
 	algo *pb.Algorithm,
 	proc *pb.ProcessorRegistration,
 ) error {
@@ -190,14 +515,15 @@ func (d *Datalayer) addAlgorithm(
 	}
 
 	params := CreateAlgorithmParams{
-		Name:              algo.GetName(),
-		Version:           algo.GetVersion(),
-		Description:       algo.GetDescription(),
-		ProcessorName:     proc.GetName(),
-		ProcessorRuntime:  proc.GetRuntime(),
-		WindowTypeName:    algo.GetWindowType().GetName(),
-		WindowTypeVersion: algo.GetWindowType().GetVersion(),
-		ResultType:        resultType,
+		Name:               algo.GetName(),
+		Version:            algo.GetVersion(),
+		Description:        algo.GetDescription(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: types.Namespace(proc.GetProjectName()),
+		WindowTypeName:     algo.GetWindowType().GetName(),
+		WindowTypeVersion:  algo.GetWindowType().GetVersion(),
+		ResultType:         resultType,
 	}
 
 	err := qtx.CreateAlgorithm(ctx, params)
@@ -205,23 +531,43 @@ func (d *Datalayer) addAlgorithm(
 		slog.Error("error creating algorithm", "error", err)
 		return err
 	}
+
+	stored, err := qtx.ReadAlgorithm(ctx, ReadAlgorithmParams{
+		AlgorithmName:      algo.GetName(),
+		AlgorithmVersion:   algo.GetVersion(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: params.ProcessorNamespace,
+		WindowTypeName:     algo.GetWindowType().GetName(),
+		WindowTypeVersion:  algo.GetWindowType().GetVersion(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not read the created algorithm: %w", err)
+	}
+	index.AddAlgorithm(stored.ID, stored.ProcessorID, stored.WindowTypeID)
 	return nil
 }
 
+// addOverwriteAlgorithmDependency creates or updates the dependencies of an
+// algorithm, and sets them in the index of the DAG, rejecting dependencies
+// that would close a cycle
 func (d *Datalayer) addOverwriteAlgorithmDependency(
 	ctx context.Context,
 	tx types.Tx,
+	index *dag.Index,
 	algo *pb.Algorithm,
 	proc *pb.ProcessorRegistration,
 ) error {
 	pgTx := tx.(*PgTx)
 	qtx := d.queries.WithTx(pgTx.tx)
 	// get algorithm id
+	namespace := types.Namespace(proc.GetProjectName())
 	algoId, err := qtx.ReadAlgorithmId(ctx, ReadAlgorithmIdParams{
-		AlgorithmName:    algo.GetName(),
-		AlgorithmVersion: algo.GetVersion(),
-		ProcessorName:    proc.GetName(),
-		ProcessorRuntime: proc.GetRuntime(),
+		AlgorithmName:      algo.GetName(),
+		AlgorithmVersion:   algo.GetVersion(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: namespace,
 	})
 	if err != nil {
 		slog.Error("could not get algorithm ID", "algorithm", algo)
@@ -229,61 +575,227 @@ func (d *Datalayer) addOverwriteAlgorithmDependency(
 	}
 	dependencies := algo.GetDependencies()
 	for _, algoDependentOn := range dependencies {
+		// dependencies are looked up in the namespace of the dependant,
+		// unless another namespace is named
+		dependencyNamespace := namespace
+		if algoDependentOn.GetProcessorNamespace() != "" {
+			dependencyNamespace = algoDependentOn.GetProcessorNamespace()
+		}
+
 		// get algorithm id
 		algoDependentOnId, err := qtx.ReadAlgorithmId(ctx, ReadAlgorithmIdParams{
-			AlgorithmName:    algoDependentOn.GetName(),
-			AlgorithmVersion: algoDependentOn.GetVersion(),
-			ProcessorName:    algoDependentOn.GetProcessorName(),
-			ProcessorRuntime: algoDependentOn.GetProcessorRuntime(),
+			AlgorithmName:      algoDependentOn.GetName(),
+			AlgorithmVersion:   algoDependentOn.GetVersion(),
+			ProcessorName:      algoDependentOn.GetProcessorName(),
+			ProcessorRuntime:   algoDependentOn.GetProcessorRuntime(),
+			ProcessorNamespace: dependencyNamespace,
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf(
+				"algorithm %v of processor %v does not exist in namespace %v",
+				algoDependentOn.GetName(),
+				algoDependentOn.GetProcessorName(),
+				dependencyNamespace,
+			)
+		}
 		if err != nil {
 			return fmt.Errorf("issue getting algorithm ID of dependant: %v", err)
 		}
 
-		// get the algo execution path
-		execPaths, err := qtx.ReadAlgorithmExecutionPathsForAlgo(ctx, algoDependentOnId)
+		// the lookback partition value is read from the dependant's window,
+		// so the field must be carried by the dependant's window type
+		lookbackPartition, lookbackPartitionField, err := lookbackPartitionFromPb(algoDependentOn)
 		if err != nil {
-			slog.Error("could not obtain execution paths", "algorithm_id", algoDependentOnId)
 			return err
 		}
-		for _, algoPath := range execPaths {
-			algoIds := strings.Split(algoPath.AlgoIDPath, ".")
-			if slices.Contains(algoIds, strconv.Itoa(int(algoId))) {
-				slog.Error(
-					"found circular dependency",
-					"from_algo",
-					algoDependentOn,
-					"to_algo",
-					algo,
+		if lookbackPartition == LookbackPartitionMetadataField {
+			hasField := slices.ContainsFunc(
+				algo.GetWindowType().GetMetadataFields(),
+				func(field *pb.MetadataField) bool {
+					return field.GetName() == lookbackPartitionField.String
+				},
+			)
+			if !hasField {
+				return fmt.Errorf(
+					"lookback partition field %q is not a metadata field of window type %v",
+					lookbackPartitionField.String,
+					algo.GetWindowType().GetName(),
 				)
-				return &types.CircularDependencyError{
-					FromAlgoName:      algoDependentOn.GetName(),
-					FromAlgoVersion:   algoDependentOn.GetVersion(),
-					FromAlgoProcessor: algoDependentOn.GetProcessorName(),
-					ToAlgoName:        algo.GetName(),
-					ToAlgoVersion:     algo.GetVersion(),
-					ToAlgoProcessor:   proc.GetName(),
-				}
-			} else {
-				err = qtx.CreateAlgorithmDependency(ctx, CreateAlgorithmDependencyParams{
-					FromAlgorithmName:    algoDependentOn.GetName(),
-					FromAlgorithmVersion: algoDependentOn.GetVersion(),
-					FromProcessorName:    algoDependentOn.GetProcessorName(),
-					FromProcessorRuntime: algoDependentOn.GetProcessorRuntime(),
-					ToAlgorithmName:      algo.GetName(),
-					ToAlgorithmVersion:   algo.GetVersion(),
-					ToProcessorName:      proc.GetName(),
-					ToProcessorRuntime:   proc.GetRuntime(),
-					LookbackCount:        int64(algoDependentOn.GetLookbackNum()),
-					LookbackTimedelta:    int64(algoDependentOn.GetLookbackTimeDelta()),
-				})
-				if err != nil {
-					return fmt.Errorf("issue constructing algorithm dependency: %v", err)
-				}
+			}
+		}
 
+		// only single values can be summarised beyond counting them
+		lookbackAggregate, err := lookbackAggregateFromPb(algoDependentOn.GetLookbackAggregate())
+		if err != nil {
+			return err
+		}
+		if lookbackAggregate.Valid &&
+			lookbackAggregate.LookbackAggregateFunction != LookbackAggregateFunctionCount {
+			resultType, err := qtx.ReadAlgorithmResultType(ctx, algoDependentOnId)
+			if err != nil {
+				return fmt.Errorf("issue getting result type of dependant: %v", err)
+			}
+			if resultType != ResultTypeValue {
+				return fmt.Errorf(
+					"lookback aggregate %v requires algorithm %v to produce a value result, not %v",
+					lookbackAggregate.LookbackAggregateFunction,
+					algoDependentOn.GetName(),
+					resultType,
+				)
 			}
+		}
 
+		// an algorithm depending on itself cannot be stored, so is caught
+		// before the rest of the graph is checked for cycles
+		if algoDependentOnId == algoId {
+			slog.Error("found circular dependency", "algo", algo)
+			return types.NewCircularDependencyError([]types.CycleAlgorithm{{
+				Name:      algo.GetName(),
+				Version:   algo.GetVersion(),
+				Processor: proc.GetName(),
+			}})
+		}
+
+		dependency, err := qtx.CreateAlgorithmDependency(ctx, CreateAlgorithmDependencyParams{
+			FromAlgorithmName:           algoDependentOn.GetName(),
+			FromAlgorithmVersion:        algoDependentOn.GetVersion(),
+			FromProcessorName:           algoDependentOn.GetProcessorName(),
+			FromProcessorRuntime:        algoDependentOn.GetProcessorRuntime(),
+			FromProcessorNamespace:      dependencyNamespace,
+			ToAlgorithmName:             algo.GetName(),
+			ToAlgorithmVersion:          algo.GetVersion(),
+			ToProcessorName:             proc.GetName(),
+			ToProcessorRuntime:          proc.GetRuntime(),
+			ToProcessorNamespace:        namespace,
+			LookbackCount:               int64(algoDependentOn.GetLookbackNum()),
+			LookbackTimedelta:           int64(algoDependentOn.GetLookbackTimeDelta()),
+			LookbackPartition:           lookbackPartition,
+			LookbackPartitionField:      lookbackPartitionField,
+			LookbackAggregate:           lookbackAggregate,
+			LookbackAggregatePercentile: algoDependentOn.GetLookbackAggregate().GetPercentile(),
+			LookbackAggregateBucketTimedelta: int64(
+				algoDependentOn.GetLookbackAggregate().GetBucketTimeDelta(),
+			),
+		})
+		if err != nil {
+			return fmt.Errorf("issue constructing algorithm dependency: %v", err)
+		}
+		err = index.SetDependency(
+			dependency.FromAlgorithmID,
+			dependency.ToAlgorithmID,
+			dependencyLookback(dependency),
+		)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+// circularDependencyError returns the error of a cycle of algorithms, in the
+// order given by dag.Index.Cycle
+func (d *Datalayer) circularDependencyError(ctx context.Context, qtx *Queries, cycle []int64) error {
+	algorithms, err := qtx.ReadAlgorithmsByIDs(ctx, cycle)
+	if err != nil {
+		return fmt.Errorf("could not read the algorithms of a circular dependency: %w", err)
+	}
+	byId := make(map[int64]types.CycleAlgorithm, len(algorithms))
+	for _, algo := range algorithms {
+		byId[algo.ID] = types.CycleAlgorithm{
+			Name:      algo.Name,
+			Version:   algo.Version,
+			Processor: algo.ProcessorName,
+		}
+	}
+	cycleAlgorithms := make([]types.CycleAlgorithm, len(cycle))
+	for ii, algoId := range cycle {
+		cycleAlgorithms[ii] = byId[algoId]
+	}
+	slog.Error("found circular dependency", "cycle", cycleAlgorithms)
+	return types.NewCircularDependencyError(cycleAlgorithms)
+}
+
+// lookbackPartitionFromPb maps the lookback partition of a dependency onto
+// its datalayer representation
+func lookbackPartitionFromPb(dep *pb.AlgorithmDependency) (LookbackPartition, pgtype.Text, error) {
+	switch dep.GetLookbackPartition() {
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_NONE:
+		return LookbackPartitionNone, pgtype.Text{}, nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN:
+		return LookbackPartitionOrigin, pgtype.Text{}, nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_METADATA_FIELD:
+		return LookbackPartitionMetadataField, pgtype.Text{
+			String: dep.GetLookbackPartitionField(),
+			Valid:  true,
+		}, nil
+	default:
+		return "", pgtype.Text{}, fmt.Errorf("lookback partition %v not supported", dep.GetLookbackPartition())
+	}
+}
+
+// lookbackAggregateFromPb maps the lookback aggregate function of a dependency
+// onto its datalayer representation. No aggregate maps to NULL
+func lookbackAggregateFromPb(agg *pb.LookbackAggregate) (NullLookbackAggregateFunction, error) {
+	if agg == nil {
+		return NullLookbackAggregateFunction{}, nil
+	}
+	var function LookbackAggregateFunction
+	switch agg.GetFunction() {
+	case pb.LookbackAggregate_FUNCTION_COUNT:
+		function = LookbackAggregateFunctionCount
+	case pb.LookbackAggregate_FUNCTION_SUM:
+		function = LookbackAggregateFunctionSum
+	case pb.LookbackAggregate_FUNCTION_MEAN:
+		function = LookbackAggregateFunctionMean
+	case pb.LookbackAggregate_FUNCTION_MIN:
+		function = LookbackAggregateFunctionMin
+	case pb.LookbackAggregate_FUNCTION_MAX:
+		function = LookbackAggregateFunctionMax
+	case pb.LookbackAggregate_FUNCTION_LAST:
+		function = LookbackAggregateFunctionLast
+	case pb.LookbackAggregate_FUNCTION_PERCENTILE:
+		function = LookbackAggregateFunctionPercentile
+	default:
+		return NullLookbackAggregateFunction{}, fmt.Errorf("lookback aggregate %v not supported", agg.GetFunction())
+	}
+	return NullLookbackAggregateFunction{LookbackAggregateFunction: function, Valid: true}, nil
+}
+
+// traceIDText returns the trace ID of the context, or NULL when untraced
+func traceIDText(ctx context.Context) pgtype.Text {
+	traceID := tracing.TraceID(ctx)
+	return pgtype.Text{String: traceID, Valid: traceID != ""}
+}
+
+// callerText returns the authenticated caller of the context, or NULL when
+// the call was not authenticated
+func callerText(ctx context.Context) pgtype.Text {
+	caller := auth.Caller(ctx)
+	return pgtype.Text{String: caller, Valid: caller != ""}
+}
+
+// namespacedWindow returns the window as it is stored and processed, in
+// the namespace its window type resolves to when it has none
+func (d *Datalayer) namespacedWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (*pb.Window, error) {
+	if window.GetNamespace() != "" {
+		return window, nil
+	}
+	namespaces, err := qtx.ReadWindowTypeNamespaces(ctx, ReadWindowTypeNamespacesParams{
+		WindowTypeName:    window.GetWindowTypeName(),
+		WindowTypeVersion: window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read the namespaces of the window type: %w", err)
+	}
+	namespace, err := types.WindowNamespace(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	namespaced := proto.Clone(window).(*pb.Window)
+	namespaced.Namespace = namespace
+	return namespaced, nil
+}
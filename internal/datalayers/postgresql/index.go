@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orca-telemetry/core/internal/dag"
+)
+
+// dagIndex returns the index of the algorithm DAG at the version of the
+// registry the queries read, reloading it from the algorithm tables when
+// another instance has registered processors since it was built. The index
+// is shared, so must not be changed
+func (d *Datalayer) dagIndex(ctx context.Context, qtx *Queries) (*dag.Index, error) {
+	version, err := qtx.ReadRegistryVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the registry version: %w", err)
+	}
+	return d.dagCache.Load(version, func() (*dag.Index, error) {
+		return loadDagIndex(ctx, qtx)
+	})
+}
+
+// loadDagIndex builds the index of the algorithm DAG from the algorithm and
+// dependency tables
+func loadDagIndex(ctx context.Context, qtx *Queries) (*dag.Index, error) {
+	algorithms, err := qtx.ReadAlgorithms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read algorithms: %w", err)
+	}
+	dependencies, err := qtx.ReadAlgorithmDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read algorithm dependencies: %w", err)
+	}
+
+	index := dag.NewIndex()
+	for _, algo := range algorithms {
+		index.AddAlgorithm(algo.ID, algo.ProcessorID, algo.WindowTypeID)
+	}
+	for _, dependency := range dependencies {
+		err := index.SetDependency(
+			dependency.FromAlgorithmID,
+			dependency.ToAlgorithmID,
+			dependencyLookback(dependency),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return index, nil
+}
+
+// dependencyLookback converts the lookback of a stored dependency to the
+// lookback of an edge of the DAG
+func dependencyLookback(dependency AlgorithmDependency) dag.Lookback {
+	partition := dag.PartitionNone
+	switch dependency.LookbackPartition {
+	case LookbackPartitionOrigin:
+		partition = dag.PartitionOrigin
+	case LookbackPartitionMetadataField:
+		partition = dag.PartitionMetadataField
+	}
+	return dag.Lookback{
+		Count:            int(dependency.LookbackCount),
+		Timedelta:        int(dependency.LookbackTimedelta),
+		Partition:        partition,
+		PartitionFieldId: dependency.LookbackPartitionFieldID.Int64,
+	}
+}
@@ -3,15 +3,28 @@ package postgresql
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
-	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
 	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
 	pb "github.com/orca-telemetry/core/protobufs/go"
 )
 
@@ -20,7 +33,10 @@ func (d *Datalayer) RegisterProcessor(
 	ctx context.Context,
 	proc *pb.ProcessorRegistration,
 ) error {
-	slog.Debug("registering processor", "processor", proc)
+	slog.Debug("registering processor", "processor", proc.GetName(), "algorithms", len(proc.GetSupportedAlgorithms()))
+	if err := auth.Require(ctx, auth.RoleProcessor, types.Namespace(proc.GetProjectName())); err != nil {
+		return err
+	}
 
 	tx, err := d.WithTx(ctx)
 
@@ -35,6 +51,23 @@ func (d *Datalayer) RegisterProcessor(
 		return err
 	}
 
+	// bumping the registry version holds registrations of other instances
+	// until this one commits, so dependencies are checked for cycles
+	// against the latest algorithms
+	qtx := d.queries.WithTx(tx.(*PgTx).tx)
+	version, err := qtx.BumpRegistryVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("could not bump the registry version: %w", err)
+	}
+	base := d.dagCache.Get(version - 1)
+	if base == nil {
+		base, err = loadDagIndex(ctx, qtx)
+		if err != nil {
+			return err
+		}
+	}
+	index := base.Clone()
+
 	// register the processor
 	err = d.createProcessor(ctx, tx, proc)
 
@@ -43,19 +76,33 @@ func (d *Datalayer) RegisterProcessor(
 		return err
 	}
 
-	// add all algorithms first
+	// add all algorithms first, window types are registered in the
+	// namespace of the processor
+	namespace := types.Namespace(proc.GetProjectName())
 	for _, algo := range proc.GetSupportedAlgorithms() {
 		// add window types
 		windowType := algo.GetWindowType()
 
 		// create / update the window type
-		windowTypeId, err := d.createWindowType(ctx, tx, windowType)
+		windowTypeId, err := d.createWindowType(ctx, tx, windowType, namespace)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the schedule of the window type
+		err = d.setWindowSchedule(ctx, tx, windowTypeId, windowType)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the rollup of the window type
+		err = d.setWindowRollup(ctx, tx, windowTypeId, windowType)
 		if err != nil {
 			return err
 		}
 
 		// read any existing metadata fields for the window
-		metadataFieldsAsStored, err := d.readMetadataFieldsByWindowType(ctx, tx, windowType)
+		metadataFieldsAsStored, err := d.readMetadataFieldsByWindowType(ctx, tx, windowType, namespace)
 		if err != nil {
 			return err
 		}
@@ -101,7 +148,7 @@ func (d *Datalayer) RegisterProcessor(
 		}
 
 		// create algos
-		err = d.addAlgorithm(ctx, tx, algo, proc)
+		err = d.addAlgorithm(ctx, tx, index, algo, proc)
 		if err != nil {
 			slog.Error("error creating algorithm", "error", err)
 			return err
@@ -113,6 +160,7 @@ func (d *Datalayer) RegisterProcessor(
 		err := d.addOverwriteAlgorithmDependency(
 			ctx,
 			tx,
+			index,
 			algo,
 			proc,
 		)
@@ -122,7 +170,25 @@ func (d *Datalayer) RegisterProcessor(
 		}
 	}
 
-	return tx.Commit(ctx)
+	// cycles can run through several algorithms of the registration, so the
+	// graph is checked once all of its dependencies are set
+	if cycle := index.Cycle(base); cycle != nil {
+		return d.circularDependencyError(ctx, qtx, cycle)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	d.dagCache.Set(index, version)
+
+	// plans are built from the index, the view of execution paths is kept
+	// up to date for anything else reading it. It is refreshed concurrently
+	// once the registration is committed, so that neither registrations nor
+	// readers of the view wait on it
+	if err := d.queries.RefreshAlgorithmExecutionPaths(ctx); err != nil {
+		slog.WarnContext(ctx, "could not refresh the execution paths, orca doctor reports them as stale until they are", "error", err)
+	}
+	return nil
 }
 
 // EmitWindow with Orca core
@@ -130,7 +196,8 @@ func (d *Datalayer) EmitWindow(
 	ctx context.Context,
 	window *pb.Window,
 ) (pb.WindowEmitStatus, error) {
-	slog.Debug("recieved emitted window", "window", window)
+	ctx = logging.WithWindow(ctx, window)
+	slog.DebugContext(ctx, "received emitted window")
 
 	tx, err := d.WithTx(ctx)
 
@@ -148,40 +215,358 @@ func (d *Datalayer) EmitWindow(
 	pgTx := tx.(*PgTx)
 	qtx := d.queries.WithTx(pgTx.tx)
 
+	window, err = d.namespacedWindow(ctx, qtx, window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+	scope := auth.WindowTypeScope(window.GetNamespace(), window.GetWindowTypeName())
+	if err := auth.Require(ctx, auth.RoleEmitter, scope); err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, qtx, window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+
+	// the window is stored before it is processed, so that results and
+	// rollups only ever see committed windows. Windows that trigger nothing
+	// are processed too, as they are complete straight away
+	if err := tx.Commit(ctx); err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return pb.WindowEmitStatus{Status: emitStatus}, nil
+}
+
+// EmitScheduledWindows emits the windows of scheduled window types that have
+// closed by now. Only the instance holding the scheduler lock emits windows
+func (d *Datalayer) EmitScheduledWindows(ctx context.Context, now time.Time) error {
+	leader, err := d.isSchedulerLeader(ctx)
+	if err != nil {
+		return fmt.Errorf("could not determine scheduler leader: %w", err)
+	}
+	if !leader {
+		return nil
+	}
+
+	windowSchedules, err := d.queries.ReadWindowSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("could not read window schedules: %w", err)
+	}
+
+	for _, windowSchedule := range windowSchedules {
+		sched, err := scheduleFromRow(windowSchedule)
+		if err != nil {
+			slog.Error(
+				"could not parse window schedule",
+				"window_type",
+				windowSchedule.WindowTypeName,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		highWaterMark := windowSchedule.HighWaterMark.Time
+		for _, scheduledWindow := range sched.Due(highWaterMark, now) {
+			err := d.emitScheduledWindow(ctx, windowSchedule, highWaterMark, scheduledWindow)
+			if err != nil {
+				slog.Error(
+					"could not emit scheduled window",
+					"window_type",
+					windowSchedule.WindowTypeName,
+					"time_from",
+					scheduledWindow.From,
+					"error",
+					err,
+				)
+				break
+			}
+			highWaterMark = scheduledWindow.To
+		}
+	}
+	return nil
+}
+
+// emitScheduledWindow emits a single scheduled window, and advances the high
+// water mark of its schedule in the same transaction
+func (d *Datalayer) emitScheduledWindow(
+	ctx context.Context,
+	windowSchedule ReadWindowSchedulesRow,
+	highWaterMark time.Time,
+	scheduledWindow schedule.Window,
+) (err error) {
+	ctx, span := tracing.Tracer.Start(ctx, "EmitScheduledWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", windowSchedule.WindowTypeName),
+		attribute.String("orca.window_type_version", windowSchedule.WindowTypeVersion),
+	))
+	defer func() { tracing.End(span, err) }()
+	ctx = logging.WithCorrelationID(ctx)
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	pgTx := tx.(*PgTx)
+	qtx := d.queries.WithTx(pgTx.tx)
+
+	// the high water mark moving means the window has been emitted already
+	storedHighWaterMark, err := qtx.ReadWindowScheduleHighWaterMarkForUpdate(ctx, windowSchedule.WindowTypeID)
+	if err != nil {
+		return fmt.Errorf("could not read high water mark of window schedule: %w", err)
+	}
+	if !storedHighWaterMark.Time.Equal(highWaterMark) {
+		return fmt.Errorf(
+			"high water mark of window schedule moved from %v to %v",
+			highWaterMark,
+			storedHighWaterMark.Time,
+		)
+	}
+
+	window := &pb.Window{
+		TimeFrom:          timestamppb.New(scheduledWindow.From),
+		TimeTo:            timestamppb.New(scheduledWindow.To),
+		WindowTypeName:    windowSchedule.WindowTypeName,
+		WindowTypeVersion: windowSchedule.WindowTypeVersion,
+		Origin:            windowSchedule.Origin,
+		Metadata:          &structpb.Struct{},
+		Namespace:         windowSchedule.WindowTypeNamespace,
+	}
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, qtx, window)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.ID)
+
+	err = qtx.UpdateWindowScheduleHighWaterMark(ctx, UpdateWindowScheduleHighWaterMarkParams{
+		WindowTypeID: windowSchedule.WindowTypeID,
+		HighWaterMark: pgtype.Timestamp{
+			Time:  scheduledWindow.To,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not advance high water mark of window schedule: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	slog.InfoContext(logging.WithWindow(ctx, window), "emitted scheduled window", "status", emitStatus)
+
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return nil
+}
+
+// rollupWindow counts a completed child window towards its parent window in
+// a rollup, and emits the parent once enough of its children have completed
+func (d *Datalayer) rollupWindow(
+	ctx context.Context,
+	rollup ReadWindowRollupsForChildRow,
+	window *pb.Window,
+) (err error) {
+	ctx, traceSpan := tracing.Tracer.Start(ctx, "RollupWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", rollup.WindowTypeName),
+		attribute.String("orca.window_type_version", rollup.WindowTypeVersion),
+	))
+	defer func() { tracing.End(traceSpan, err) }()
+
+	sched, err := rollupScheduleFromRow(rollup)
+	if err != nil {
+		return err
+	}
+	childFrom := window.GetTimeFrom().AsTime().UTC()
+	childTo := window.GetTimeTo().AsTime().UTC()
+	span, err := sched.Span(childFrom)
+	if err != nil {
+		return err
+	}
+	if childTo.After(span.To) {
+		return fmt.Errorf(
+			"window from %v to %v does not fit within a window of rolled up window type %v",
+			childFrom,
+			childTo,
+			rollup.WindowTypeName,
+		)
+	}
+
+	metadataBytes, err := window.GetMetadata().MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("could not marshal metadata: %v", err)
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	pgTx := tx.(*PgTx)
+	qtx := d.queries.WithTx(pgTx.tx)
+
+	// children are grouped into parents by interval, origin and metadata
+	err = qtx.CreateWindowRollupProgress(ctx, CreateWindowRollupProgressParams{
+		WindowTypeID: rollup.WindowTypeID,
+		Origin:       window.GetOrigin(),
+		Metadata:     metadataBytes,
+		TimeFrom:     pgtype.Timestamp{Time: span.From, Valid: true},
+		TimeTo:       pgtype.Timestamp{Time: span.To, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("could not create rollup progress: %w", err)
+	}
+	progress, err := qtx.ReadWindowRollupProgressForUpdate(ctx, ReadWindowRollupProgressForUpdateParams{
+		WindowTypeID: rollup.WindowTypeID,
+		Origin:       window.GetOrigin(),
+		Metadata:     metadataBytes,
+		TimeFrom:     pgtype.Timestamp{Time: span.From, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("could not read rollup progress: %w", err)
+	}
+
+	// a child window emitted more than once only counts once
+	alreadyCompleted := slices.ContainsFunc(progress.ChildWindows, func(ts pgtype.Timestamp) bool {
+		return ts.Time.Equal(childFrom)
+	})
+	if alreadyCompleted {
+		return nil
+	}
+	childWindows := append(progress.ChildWindows, pgtype.Timestamp{Time: childFrom, Valid: true})
+
+	late := progress.Emitted.Valid
+	emit := (!late && len(childWindows) >= int(rollup.ChildCount)) ||
+		(late && rollup.LateWindowPolicy == LateWindowPolicyReemit)
+
+	emitted := progress.Emitted
+	var parent *pb.Window
+	var emitStatus pb.WindowEmitStatus_StatusEnum
+	var executionPlan dag.Plan
+	var insertedWindow RegisterWindowRow
+	if emit {
+		parent = &pb.Window{
+			TimeFrom:          timestamppb.New(span.From),
+			TimeTo:            timestamppb.New(span.To),
+			WindowTypeName:    rollup.WindowTypeName,
+			WindowTypeVersion: rollup.WindowTypeVersion,
+			Origin:            window.GetOrigin(),
+			Metadata:          window.GetMetadata(),
+			Namespace:         window.GetNamespace(),
+		}
+		emitStatus, executionPlan, insertedWindow, err = d.registerWindow(ctx, qtx, parent)
+		if err != nil {
+			return err
+		}
+		emitted = pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+	} else if late {
+		slog.WarnContext(
+			ctx,
+			"window completed after its rolled up window was emitted",
+			"rolled_up_window_type",
+			rollup.WindowTypeName,
+		)
+	}
+
+	err = qtx.UpdateWindowRollupProgress(ctx, UpdateWindowRollupProgressParams{
+		ChildWindows: childWindows,
+		Emitted:      emitted,
+		ID:           progress.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update rollup progress: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if emit {
+		ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.ID)
+		slog.InfoContext(logging.WithWindow(ctx, parent), "emitted rolled up window", "status", emitStatus, "late", late)
+		go processWindow(tracing.Detach(ctx), d, executionPlan, parent, insertedWindow)
+	}
+	return nil
+}
+
+// registerWindow validates and inserts a window, and builds the plan of the
+// algorithms it triggers, counting the window by its emit status. The window
+// is committed by the caller
+func (d *Datalayer) registerWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, RegisterWindowRow, error) {
+	emitStatus, executionPlan, insertedWindow, err := d.insertWindow(ctx, qtx, window)
+	metrics.WindowsEmitted.WithLabelValues(
+		window.GetWindowTypeName(),
+		window.GetWindowTypeVersion(),
+		emitStatus.String(),
+	).Inc()
+	return emitStatus, executionPlan, insertedWindow, err
+}
+
+// insertWindow does the work of registerWindow
+func (d *Datalayer) insertWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, RegisterWindowRow, error) {
 	// marshal metadata
 	metadata := window.GetMetadata()
 	metadataBytes, err := metadata.MarshalJSON()
 	if err != nil {
-		return pb.WindowEmitStatus{}, fmt.Errorf("could not marshal metadata: %v", err)
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not marshal metadata: %v", err)
 	}
 
+	// window types are those of the namespace of the window
+	namespace := types.Namespace(window.GetNamespace())
+
 	// check whether metadata is needed
 	metadataFields, err := qtx.ReadMetadataFieldsByWindowType(ctx, ReadMetadataFieldsByWindowTypeParams{
-		WindowTypeName:    window.GetWindowTypeName(),
-		WindowTypeVersion: window.GetWindowTypeVersion(),
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      window.GetWindowTypeName(),
+		WindowTypeVersion:   window.GetWindowTypeVersion(),
 	})
 	if err != nil {
-		return pb.WindowEmitStatus{}, fmt.Errorf("could not read metadata for window: %v", err)
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not read metadata for window: %v", err)
 	}
 
 	// confident that any required metadata is being supplied to the processor
 	if len(metadataFields) > 0 {
 		var metadataMap map[string]any
 		if err := json.Unmarshal(metadataBytes, &metadataMap); err != nil {
-			return pb.WindowEmitStatus{}, fmt.Errorf("could not unmarshal metadata for validation: %v", err)
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not unmarshal metadata for validation: %v", err)
 		}
 
 		for _, mDataField := range metadataFields {
 			fieldName := mDataField.MetadataFieldName
 			if _, exists := metadataMap[fieldName]; !exists {
-				return pb.WindowEmitStatus{}, fmt.Errorf("required metadata field '%s' is missing", fieldName)
+				return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("required metadata field '%s' is missing", fieldName)
 			}
 		}
 	}
 
 	insertedWindow, err := qtx.RegisterWindow(ctx, RegisterWindowParams{
-		WindowTypeName:    window.GetWindowTypeName(),
-		WindowTypeVersion: window.GetWindowTypeVersion(),
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      window.GetWindowTypeName(),
+		WindowTypeVersion:   window.GetWindowTypeVersion(),
 		TimeFrom: pgtype.Timestamp{
 			Time:  window.GetTimeFrom().AsTime().UTC(),
 			Valid: true,
@@ -190,78 +575,70 @@ func (d *Datalayer) EmitWindow(
 			Time:  window.GetTimeTo().AsTime().UTC(),
 			Valid: true,
 		},
-		Origin:   window.GetOrigin(),
-		Metadata: metadataBytes,
+		Origin:    window.GetOrigin(),
+		Metadata:  metadataBytes,
+		TraceID:   traceIDText(ctx),
+		CreatedBy: callerText(ctx),
+		Namespace: pgtype.Text{String: window.GetNamespace(), Valid: window.GetNamespace() != ""},
 	})
 	if err != nil {
-		slog.Error("could not insert window", "error", err)
+		slog.ErrorContext(ctx, "could not insert window", "error", err)
 		if strings.Contains(err.Error(), "(SQLSTATE 23503)") {
-			return pb.WindowEmitStatus{}, fmt.Errorf(
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf(
 				"window type does not exist - insert via window type registration: %v",
 				err.Error(),
 			)
 		}
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, err
 	}
-	slog.Debug("window record inserted into the datalayer", "window", insertedWindow)
-	execPaths, err := qtx.ReadAlgorithmExecutionPaths(
-		ctx,
-		strconv.Itoa(int(insertedWindow.WindowTypeID)),
-	)
+	slog.DebugContext(ctx, "window record inserted into the datalayer", logging.KeyWindowID, insertedWindow.ID)
+	index, err := d.dagIndex(ctx, qtx)
 	if err != nil {
-		slog.Error(
-			"could not read execution paths for window id",
-			"window_id",
-			insertedWindow,
+		slog.ErrorContext(
+			ctx,
+			"could not read the algorithm dag for window id",
+			logging.KeyWindowID,
+			insertedWindow.ID,
 			"error",
 			err,
 		)
-		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, err
 	}
-
-	// create the algo path args
-	var algoIDPaths []string
-	var windowTypeIDPaths []string
-	var procIDPaths []string
-	var lookbackCounts []string
-	var lookbackTimedeltas []string
-	for _, path := range execPaths {
-		algoIDPaths = append(algoIDPaths, path.AlgoIDPath)
-		windowTypeIDPaths = append(windowTypeIDPaths, path.WindowTypeIDPath)
-		procIDPaths = append(procIDPaths, path.ProcIDPath)
-		lookbackCounts = append(lookbackCounts, path.LookbackCountPath)
-		lookbackTimedeltas = append(lookbackTimedeltas, path.LookbackTimedeltaPath)
+	var inNamespace func(procId int64) bool
+	if window.GetNamespace() != "" {
+		processors, err := qtx.ReadProcessorsInNamespace(ctx, window.GetNamespace())
+		if err != nil {
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, fmt.Errorf("could not read processors of namespace: %w", err)
+		}
+		inNamespace = processorFilter(processors)
 	}
 
 	// fire off processings
-	executionPlan, err := dag.BuildPlan(
-		algoIDPaths,
-		windowTypeIDPaths,
-		procIDPaths,
-		lookbackCounts,
-		lookbackTimedeltas,
-		int64(insertedWindow.WindowTypeID),
+	_, planSpan := tracing.Tracer.Start(ctx, "BuildPlan")
+	planStart := time.Now()
+	executionPlan, err := index.Plan(int64(insertedWindow.WindowTypeID), inNamespace)
+	metrics.PlanBuildDuration.Observe(time.Since(planStart).Seconds())
+	planSpan.SetAttributes(
+		attribute.Int("orca.stages", len(executionPlan.Stages)),
+		attribute.Int("orca.algorithms", executionPlan.Len()),
 	)
+	tracing.End(planSpan, err)
 	if err != nil {
-		slog.Error(
-			"failed to construct execution paths for window",
-			"window",
-			insertedWindow,
+		slog.ErrorContext(
+			ctx,
+			"failed to build the execution plan for window",
+			logging.KeyWindowID,
+			insertedWindow.ID,
 			"error",
 			err,
 		)
-		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, err
 	}
 
 	if len(executionPlan.Stages) > 0 {
-		go processTasks(d, executionPlan, window, insertedWindow)
-
-		return pb.WindowEmitStatus{
-			Status: pb.WindowEmitStatus_PROCESSING_TRIGGERED,
-		}, tx.Commit(ctx)
+		return pb.WindowEmitStatus_PROCESSING_TRIGGERED, executionPlan, insertedWindow, nil
 	}
-	return pb.WindowEmitStatus{
-		Status: pb.WindowEmitStatus_NO_TRIGGERED_ALGORITHMS,
-	}, nil
+	return pb.WindowEmitStatus_NO_TRIGGERED_ALGORITHMS, executionPlan, insertedWindow, nil
 }
 
 func (d *Datalayer) Expose(
@@ -269,6 +646,9 @@ func (d *Datalayer) Expose(
 	settings *pb.ExposeSettings,
 ) (*pb.InternalState, error) {
 	// settings not handled for now
+	if err := auth.Require(ctx, auth.RoleReader, auth.NamespaceScope(settings.GetNamespace())); err != nil {
+		return nil, err
+	}
 
 	tx, err := d.WithTx(ctx)
 
@@ -287,11 +667,10 @@ func (d *Datalayer) Expose(
 
 	qtx := d.queries.WithTx(pgTx.tx)
 	var processors []Processor
-	if len(settings.ExcludeProject) > 0 {
-		processors, err = qtx.ReadProcessorExcludeProject(ctx, pgtype.Text{
-			String: settings.ExcludeProject,
-			Valid:  true,
-		})
+	if len(settings.GetNamespace()) > 0 {
+		processors, err = qtx.ReadProcessorsInNamespace(ctx, settings.GetNamespace())
+	} else if len(settings.ExcludeProject) > 0 {
+		processors, err = qtx.ReadProcessorExcludeProject(ctx, settings.ExcludeProject)
 	} else {
 		// read all the processors
 		processors, err = qtx.ReadProcessors(ctx)
@@ -333,7 +712,7 @@ func (d *Datalayer) Expose(
 	}
 	wtToMdf := make(map[string][]*pb.MetadataField)
 	for _, wtmd := range wtmdf {
-		_key := fmt.Sprintf("%v_%v", wtmd.WindowTypeName, wtmd.WindowTypeVersion)
+		_key := fmt.Sprintf("%v_%v_%v", wtmd.WindowTypeNamespace, wtmd.WindowTypeName, wtmd.WindowTypeVersion)
 
 		wtToMdf[_key] = append(wtToMdf[_key], &pb.MetadataField{
 			Name:        wtmd.MetadataFieldName,
@@ -347,9 +726,31 @@ func (d *Datalayer) Expose(
 		slog.Error("could not read window types", "error", err)
 		return nil, fmt.Errorf("could not read window types: %w", err)
 	}
+	// read all the window schedules
+	windowSchedules, err := qtx.ReadWindowSchedules(ctx)
+	if err != nil {
+		slog.Error("could not read window schedules", "error", err)
+		return nil, fmt.Errorf("could not read window schedules: %w", err)
+	}
+	schedulesMap := make(map[int64]*pb.WindowSchedule, len(windowSchedules))
+	for _, windowSchedule := range windowSchedules {
+		schedulesMap[windowSchedule.WindowTypeID] = windowScheduleToPb(windowSchedule)
+	}
+
+	// read all the window rollups
+	windowRollups, err := qtx.ReadWindowRollups(ctx)
+	if err != nil {
+		slog.Error("could not read window rollups", "error", err)
+		return nil, fmt.Errorf("could not read window rollups: %w", err)
+	}
+	rollupsMap := make(map[int64]*pb.WindowRollup, len(windowRollups))
+	for _, windowRollup := range windowRollups {
+		rollupsMap[windowRollup.WindowTypeID] = windowRollupToPb(windowRollup)
+	}
+
 	wtsMap := make(map[int64]*pb.WindowType, len(wts))
 	for _, wt := range wts {
-		metadataFields, ok := wtToMdf[fmt.Sprintf("%v_%v", wt.Name, wt.Version)]
+		metadataFields, ok := wtToMdf[fmt.Sprintf("%v_%v_%v", wt.Namespace, wt.Name, wt.Version)]
 		if !ok {
 			slog.Info("no metadata fields found for window type", "windowType", wt)
 		}
@@ -358,6 +759,8 @@ func (d *Datalayer) Expose(
 			Version:        wt.Version,
 			Description:    wt.Description,
 			MetadataFields: metadataFields,
+			Schedule:       schedulesMap[wt.ID],
+			Rollup:         rollupsMap[wt.ID],
 		}
 	}
 
@@ -397,16 +800,12 @@ func (d *Datalayer) Expose(
 	processorsPb := make([]*pb.ProcessorRegistration, len(processors))
 
 	for ll, p := range processors {
-		algos, ok := algosForProcessor[p.ID]
-		if !ok {
-			slog.Error("could not find algorithms for processor", "processorId", p.ID)
-			return nil, fmt.Errorf("could not find algorithms for processor ID: %v", p.ID)
-
-		}
+		// processors may register without algorithms
 		processorsPb[ll] = &pb.ProcessorRegistration{
 			Name:                p.Name,
 			Runtime:             p.Runtime,
-			SupportedAlgorithms: algos,
+			ProjectName:         p.Namespace,
+			SupportedAlgorithms: algosForProcessor[p.ID],
 		}
 	}
 
@@ -416,3 +815,109 @@ func (d *Datalayer) Expose(
 	}, nil
 
 }
+
+// Ping checks that postgres can be reached through the pool
+func (d *Datalayer) Ping(ctx context.Context) error {
+	return d.conn.Ping(ctx)
+}
+
+// SchemaVersion reads the version that golang-migrate recorded
+func (d *Datalayer) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := d.conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return 0, false, nil
+	case errors.As(err, &pgErr) && pgErr.Code == "42P01":
+		// undefined_table, the store has never been migrated
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("could not read schema version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+// CreateAPIKey stores the hash of a new API key under a unique name
+func (d *Datalayer) CreateAPIKey(ctx context.Context, name string, keyHash string) error {
+	err := d.queries.CreateAPIKey(ctx, CreateAPIKeyParams{Name: name, KeyHash: keyHash})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// unique_violation, names are kept after revocation
+		return fmt.Errorf("an API key named %s already exists", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not create API key: %w", err)
+	}
+	return nil
+}
+
+// RevokeAPIKey revokes the API key of the given name
+func (d *Datalayer) RevokeAPIKey(ctx context.Context, name string) error {
+	revoked, err := d.queries.RevokeAPIKey(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not revoke API key: %w", err)
+	}
+	if revoked == 0 {
+		return fmt.Errorf("%w: no unrevoked key named %s", types.APIKeyNotFound, name)
+	}
+	return nil
+}
+
+// LookupAPIKey returns the name of the unrevoked API key with the given hash
+func (d *Datalayer) LookupAPIKey(ctx context.Context, keyHash string) (string, error) {
+	name, err := d.queries.ReadAPIKeyName(ctx, keyHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", types.APIKeyNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read API key: %w", err)
+	}
+	return name, nil
+}
+
+// CreateGrant grants a role to an identity, doing nothing when it is held
+// already
+func (d *Datalayer) CreateGrant(ctx context.Context, identity string, grant types.Grant) error {
+	err := d.queries.CreateGrant(ctx, CreateGrantParams{
+		Identity: identity,
+		Role:     grant.Role,
+		Scope:    grant.Scope,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create grant: %w", err)
+	}
+	return nil
+}
+
+// DeleteGrant takes a role away from an identity
+func (d *Datalayer) DeleteGrant(ctx context.Context, identity string, grant types.Grant) error {
+	deleted, err := d.queries.DeleteGrant(ctx, DeleteGrantParams{
+		Identity: identity,
+		Role:     grant.Role,
+		Scope:    grant.Scope,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete grant: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s does not hold the %s role for %s", types.GrantNotFound, identity, grant.Role, grant.Scope)
+	}
+	return nil
+}
+
+// ReadGrants returns the roles granted to an identity
+func (d *Datalayer) ReadGrants(ctx context.Context, identity string) ([]types.Grant, error) {
+	rows, err := d.queries.ReadGrants(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("could not read grants: %w", err)
+	}
+	grants := make([]types.Grant, len(rows))
+	for i, row := range rows {
+		grants[i] = types.Grant{Role: row.Role, Scope: row.Scope}
+	}
+	return grants, nil
+}
@@ -0,0 +1,6 @@
+ALTER TABLE algorithm_dependency DROP CONSTRAINT IF EXISTS lookback_aggregate_requires_lookback;
+ALTER TABLE algorithm_dependency DROP COLUMN IF EXISTS lookback_aggregate_bucket_timedelta;
+ALTER TABLE algorithm_dependency DROP COLUMN IF EXISTS lookback_aggregate_percentile;
+ALTER TABLE algorithm_dependency DROP COLUMN IF EXISTS lookback_aggregate;
+
+DROP TYPE IF EXISTS lookback_aggregate_function;
@@ -0,0 +1,13 @@
+CREATE TYPE lookback_aggregate_function AS ENUM ('count', 'sum', 'mean', 'min', 'max', 'last', 'percentile');
+
+-- a NULL aggregate function sends the raw past results to the processor
+ALTER TABLE algorithm_dependency ADD COLUMN lookback_aggregate lookback_aggregate_function;
+ALTER TABLE algorithm_dependency ADD COLUMN lookback_aggregate_percentile DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (
+  lookback_aggregate_percentile BETWEEN 0 AND 1
+);
+ALTER TABLE algorithm_dependency ADD COLUMN lookback_aggregate_bucket_timedelta BIGINT NOT NULL DEFAULT 0 CHECK (
+  lookback_aggregate_bucket_timedelta >= 0
+);
+ALTER TABLE algorithm_dependency ADD CONSTRAINT lookback_aggregate_requires_lookback CHECK (
+  lookback_aggregate IS NULL OR lookback_count > 0 OR lookback_timedelta > 0
+);
@@ -0,0 +1,16 @@
+-- Schedules on which Orca core emits windows itself
+CREATE TABLE window_schedule (
+  window_type_id BIGINT PRIMARY KEY,
+  cron TEXT,
+  interval_ns BIGINT NOT NULL DEFAULT 0 CHECK (interval_ns >= 0),
+  alignment_ns BIGINT NOT NULL DEFAULT 0 CHECK (alignment_ns >= 0),
+  time_zone TEXT NOT NULL DEFAULT '',
+  origin TEXT NOT NULL,
+  catch_up BOOLEAN NOT NULL DEFAULT FALSE,
+  high_water_mark TIMESTAMP NOT NULL, -- end of the last emitted window
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (window_type_id) REFERENCES window_type(id) ON DELETE CASCADE,
+
+  -- either a cron expression or an interval
+  CHECK ((cron IS NULL) != (interval_ns = 0))
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS window_rollup_progress;
+
+DROP INDEX IF EXISTS idx_window_rollup_child;
+DROP TABLE IF EXISTS window_rollup;
+
+DROP TYPE IF EXISTS late_window_policy;
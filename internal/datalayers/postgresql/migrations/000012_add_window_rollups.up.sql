@@ -0,0 +1,32 @@
+CREATE TYPE late_window_policy AS ENUM ('ignore', 'reemit');
+
+-- Rollups through which windows of a (parent) window type are emitted once
+-- the windows of a finer (child) window type that they cover have completed
+CREATE TABLE window_rollup (
+  window_type_id BIGINT PRIMARY KEY,
+  child_window_type_name TEXT NOT NULL,
+  child_window_type_version TEXT NOT NULL,
+  interval_ns BIGINT NOT NULL CHECK (interval_ns > 0),
+  alignment_ns BIGINT NOT NULL DEFAULT 0 CHECK (alignment_ns >= 0),
+  time_zone TEXT NOT NULL DEFAULT '',
+  child_count INT NOT NULL CHECK (child_count > 0),
+  late_window_policy late_window_policy NOT NULL DEFAULT 'ignore',
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (window_type_id) REFERENCES window_type(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_window_rollup_child ON window_rollup (child_window_type_name, child_window_type_version);
+
+-- Completion of the child windows of each parent window
+CREATE TABLE window_rollup_progress (
+  id BIGSERIAL PRIMARY KEY,
+  window_type_id BIGINT NOT NULL,
+  origin TEXT NOT NULL,
+  metadata JSONB NOT NULL DEFAULT '{}',
+  time_from TIMESTAMP NOT NULL,
+  time_to TIMESTAMP NOT NULL,
+  child_windows TIMESTAMP[] NOT NULL DEFAULT '{}', -- start times of the completed child windows
+  emitted TIMESTAMP,                               -- when the parent window was last emitted
+  UNIQUE (window_type_id, origin, metadata, time_from),
+  FOREIGN KEY (window_type_id) REFERENCES window_type(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE results DROP COLUMN trace_id;
+ALTER TABLE windows DROP COLUMN trace_id;
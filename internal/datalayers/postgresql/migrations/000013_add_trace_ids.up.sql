@@ -0,0 +1,4 @@
+-- Trace the windows and results were produced in, so that a slow or failed
+-- window can be looked up in the tracing backend
+ALTER TABLE windows ADD COLUMN trace_id TEXT;
+ALTER TABLE results ADD COLUMN trace_id TEXT;
@@ -0,0 +1,3 @@
+ALTER TABLE processor DROP COLUMN registered_by;
+ALTER TABLE windows DROP COLUMN created_by;
+DROP TABLE api_keys;
@@ -0,0 +1,13 @@
+-- API keys of OrcaCore clients. Only the SHA-256 hash of a key is stored, the
+-- key itself is shown once when it is created
+CREATE TABLE api_keys (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  key_hash TEXT NOT NULL UNIQUE,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  revoked TIMESTAMP
+);
+
+-- The authenticated caller that emitted a window or registered a processor
+ALTER TABLE windows ADD COLUMN created_by TEXT;
+ALTER TABLE processor ADD COLUMN registered_by TEXT;
@@ -0,0 +1,11 @@
+-- Roles held by authenticated callers, by the identity they authenticate as
+-- (e.g. api_key:ci-pipeline). The scope is the project of a processor, or the
+-- window type of an emitter, with '*' covering every scope
+CREATE TABLE role_grants (
+  id BIGSERIAL PRIMARY KEY,
+  identity TEXT NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('admin', 'processor', 'emitter', 'reader')),
+  scope TEXT NOT NULL DEFAULT '*',
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (identity, role, scope)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE processor DROP COLUMN tls_insecure_skip_verify;
+ALTER TABLE processor DROP COLUMN tls_server_name;
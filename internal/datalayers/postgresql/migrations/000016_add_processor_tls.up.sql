@@ -0,0 +1,3 @@
+-- Overrides of the TLS settings core connects to a processor with
+ALTER TABLE processor ADD COLUMN tls_server_name TEXT;
+ALTER TABLE processor ADD COLUMN tls_insecure_skip_verify BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,11 @@
+DROP INDEX results_namespace_idx;
+ALTER TABLE results DROP COLUMN namespace;
+ALTER TABLE windows DROP COLUMN namespace;
+
+-- fails when processors of different namespaces share a name and runtime
+ALTER TABLE processor DROP CONSTRAINT processor_namespace_name_runtime_key;
+ALTER TABLE processor ADD CONSTRAINT processor_name_runtime_key UNIQUE (name, runtime);
+ALTER TABLE processor ADD CONSTRAINT unique_processor_for_project UNIQUE (name, runtime, namespace);
+ALTER TABLE processor ALTER COLUMN namespace DROP DEFAULT;
+ALTER TABLE processor ALTER COLUMN namespace DROP NOT NULL;
+ALTER TABLE processor RENAME COLUMN namespace TO project_name;
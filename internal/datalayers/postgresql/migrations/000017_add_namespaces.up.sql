@@ -0,0 +1,23 @@
+-- The project of a processor is the namespace it, and its algorithms, are
+-- registered in. Processors without a project are in the default namespace
+UPDATE processor SET project_name = 'default' WHERE project_name IS NULL OR project_name = '';
+ALTER TABLE processor RENAME COLUMN project_name TO namespace;
+ALTER TABLE processor ALTER COLUMN namespace SET NOT NULL;
+ALTER TABLE processor ALTER COLUMN namespace SET DEFAULT 'default';
+
+-- processors were unique on both (name, runtime) and (name, runtime, project),
+-- so processors of different projects could not share a name
+ALTER TABLE processor DROP CONSTRAINT processor_name_runtime_key;
+ALTER TABLE processor DROP CONSTRAINT unique_processor_for_project;
+ALTER TABLE processor ADD CONSTRAINT processor_namespace_name_runtime_key UNIQUE (namespace, name, runtime);
+
+-- the namespace a window was emitted to, or NULL for every namespace
+ALTER TABLE windows ADD COLUMN namespace TEXT;
+
+-- the namespace of the algorithm that produced a result
+ALTER TABLE results ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+UPDATE results r SET namespace = p.namespace
+FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE r.algorithm_id = a.id;
+CREATE INDEX results_namespace_idx ON results (namespace);
@@ -0,0 +1,21 @@
+DROP TABLE registry_version;
+
+CREATE OR REPLACE FUNCTION refresh_algorithm_exec_paths()
+RETURNS TRIGGER AS $$
+BEGIN
+    REFRESH MATERIALIZED VIEW algorithm_execution_paths;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_algorithm_execution_paths_after_algorithm_change
+AFTER INSERT OR UPDATE OR DELETE ON algorithm
+FOR EACH STATEMENT
+EXECUTE FUNCTION refresh_algorithm_exec_paths();
+
+CREATE TRIGGER refresh_algorithm_execution_paths_after_dependency_change
+AFTER INSERT OR UPDATE OR DELETE ON algorithm_dependency
+FOR EACH STATEMENT
+EXECUTE FUNCTION refresh_algorithm_exec_paths();
+
+REFRESH MATERIALIZED VIEW algorithm_execution_paths;
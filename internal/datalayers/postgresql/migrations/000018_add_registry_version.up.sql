@@ -0,0 +1,15 @@
+-- Execution plans are built from a DAG of the algorithms held by each
+-- instance of core, so the view of execution paths is no longer refreshed on
+-- every change of the algorithms. It is refreshed once per processor
+-- registration instead, for anything else reading it
+DROP TRIGGER refresh_algorithm_execution_paths_after_algorithm_change ON algorithm;
+DROP TRIGGER refresh_algorithm_execution_paths_after_dependency_change ON algorithm_dependency;
+DROP FUNCTION refresh_algorithm_exec_paths();
+
+-- The version of the algorithms and their dependencies, bumped by every
+-- processor registration, so instances know when to reload their DAG
+CREATE TABLE registry_version (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  version BIGINT NOT NULL
+);
+INSERT INTO registry_version (version) VALUES (0);
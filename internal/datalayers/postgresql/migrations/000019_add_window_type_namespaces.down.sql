@@ -0,0 +1,23 @@
+DROP MATERIALIZED VIEW window_type_metadata_fields;
+CREATE MATERIALIZED VIEW window_type_metadata_fields AS
+SELECT
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    mf.id AS metadata_field_id,
+    mf.name AS metadata_field_name,
+    mf.description AS metadata_field_description
+FROM window_type wt
+INNER JOIN metadata_fields_references mfr ON wt.id = mfr.window_type_id
+INNER JOIN metadata_fields mf ON mfr.metadata_fields_id = mf.id
+ORDER BY wt.name, wt.version, mf.name;
+
+CREATE INDEX idx_window_type_metadata_fields_lookup
+ON window_type_metadata_fields(window_type_name, window_type_version);
+
+CREATE INDEX idx_window_type_metadata_fields_field
+ON window_type_metadata_fields(metadata_field_name);
+
+-- fails when window types of different namespaces share a name and version
+ALTER TABLE window_type DROP CONSTRAINT window_type_namespace_name_version_key;
+ALTER TABLE window_type ADD CONSTRAINT window_type_name_version_key UNIQUE (name, version);
+ALTER TABLE window_type DROP COLUMN namespace;
@@ -0,0 +1,90 @@
+-- Window types are registered in the namespace of the processor registering
+-- them, so projects can define window types of the same name and version with
+-- their own metadata fields, schedules and rollups
+ALTER TABLE window_type ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE window_type DROP CONSTRAINT window_type_name_version_key;
+ALTER TABLE window_type ADD CONSTRAINT window_type_namespace_name_version_key UNIQUE (namespace, name, version);
+
+DROP MATERIALIZED VIEW window_type_metadata_fields;
+CREATE MATERIALIZED VIEW window_type_metadata_fields AS
+SELECT
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    mf.id AS metadata_field_id,
+    mf.name AS metadata_field_name,
+    mf.description AS metadata_field_description
+FROM window_type wt
+INNER JOIN metadata_fields_references mfr ON wt.id = mfr.window_type_id
+INNER JOIN metadata_fields mf ON mfr.metadata_fields_id = mf.id
+ORDER BY wt.namespace, wt.name, wt.version, mf.name;
+
+CREATE INDEX idx_window_type_metadata_fields_lookup
+ON window_type_metadata_fields(window_type_namespace, window_type_name, window_type_version);
+
+CREATE INDEX idx_window_type_metadata_fields_field
+ON window_type_metadata_fields(metadata_field_name);
+
+-- window types were shared between namespaces. Those only used by the
+-- algorithms of another namespace move to it
+UPDATE window_type SET namespace = (
+  SELECT MIN(p.namespace)
+  FROM algorithm a
+  JOIN processor p ON p.id = a.processor_id
+  WHERE a.window_type_id = window_type.id
+)
+WHERE id IN (
+  SELECT a.window_type_id
+  FROM algorithm a
+  JOIN processor p ON p.id = a.processor_id
+  GROUP BY a.window_type_id
+  HAVING COUNT(DISTINCT p.namespace) = 1 AND MIN(p.namespace) != 'default'
+);
+
+-- and every other namespace whose algorithms use a window type gets its own
+-- copy of it, along with its metadata fields, schedule and rollup
+INSERT INTO window_type (namespace, name, version, description)
+SELECT DISTINCT p.namespace, wt.name, wt.version, wt.description
+FROM algorithm a
+JOIN processor p ON p.id = a.processor_id
+JOIN window_type wt ON wt.id = a.window_type_id
+WHERE p.namespace != wt.namespace;
+
+INSERT INTO metadata_fields_references (window_type_id, metadata_fields_id)
+SELECT nwt.id, mfr.metadata_fields_id
+FROM metadata_fields_references mfr
+JOIN window_type wt ON wt.id = mfr.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+INSERT INTO window_schedule (window_type_id, cron, interval_ns, alignment_ns, time_zone, origin, catch_up, high_water_mark)
+SELECT nwt.id, ws.cron, ws.interval_ns, ws.alignment_ns, ws.time_zone, ws.origin, ws.catch_up, ws.high_water_mark
+FROM window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+INSERT INTO window_rollup (window_type_id, child_window_type_name, child_window_type_version, interval_ns, alignment_ns, time_zone, child_count, late_window_policy)
+SELECT nwt.id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy
+FROM window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+UPDATE algorithm a SET window_type_id = nwt.id
+FROM processor p, window_type wt, window_type nwt
+WHERE p.id = a.processor_id
+AND wt.id = a.window_type_id
+AND nwt.namespace = p.namespace
+AND nwt.name = wt.name
+AND nwt.version = wt.version
+AND p.namespace != 'default';
+
+UPDATE algorithm_dependency ad SET
+  from_window_type_id = fa.window_type_id,
+  to_window_type_id = ta.window_type_id
+FROM algorithm fa, algorithm ta
+WHERE fa.id = ad.from_algorithm_id
+AND ta.id = ad.to_algorithm_id;
+
+REFRESH MATERIALIZED VIEW algorithm_execution_paths;
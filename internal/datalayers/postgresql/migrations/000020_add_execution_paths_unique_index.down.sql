@@ -0,0 +1 @@
+DROP INDEX algorithm_execution_paths_algo_id_path_key;
@@ -0,0 +1,5 @@
+-- The view of execution paths is refreshed concurrently after registrations,
+-- so that neither registrations nor readers of the view wait on it, which
+-- needs a unique index. Each path is made of distinct dependencies, so is
+-- unique
+CREATE UNIQUE INDEX algorithm_execution_paths_algo_id_path_key ON algorithm_execution_paths (algo_id_path);
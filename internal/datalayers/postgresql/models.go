@@ -11,6 +11,138 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type LateWindowPolicy string
+
+const (
+	LateWindowPolicyIgnore LateWindowPolicy = "ignore"
+	LateWindowPolicyReemit LateWindowPolicy = "reemit"
+)
+
+func (e *LateWindowPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LateWindowPolicy(s)
+	case string:
+		*e = LateWindowPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LateWindowPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullLateWindowPolicy struct {
+	LateWindowPolicy LateWindowPolicy
+	Valid            bool // Valid is true if LateWindowPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLateWindowPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.LateWindowPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LateWindowPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLateWindowPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LateWindowPolicy), nil
+}
+
+type LookbackAggregateFunction string
+
+const (
+	LookbackAggregateFunctionCount      LookbackAggregateFunction = "count"
+	LookbackAggregateFunctionSum        LookbackAggregateFunction = "sum"
+	LookbackAggregateFunctionMean       LookbackAggregateFunction = "mean"
+	LookbackAggregateFunctionMin        LookbackAggregateFunction = "min"
+	LookbackAggregateFunctionMax        LookbackAggregateFunction = "max"
+	LookbackAggregateFunctionLast       LookbackAggregateFunction = "last"
+	LookbackAggregateFunctionPercentile LookbackAggregateFunction = "percentile"
+)
+
+func (e *LookbackAggregateFunction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LookbackAggregateFunction(s)
+	case string:
+		*e = LookbackAggregateFunction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LookbackAggregateFunction: %T", src)
+	}
+	return nil
+}
+
+type NullLookbackAggregateFunction struct {
+	LookbackAggregateFunction LookbackAggregateFunction
+	Valid                     bool // Valid is true if LookbackAggregateFunction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLookbackAggregateFunction) Scan(value interface{}) error {
+	if value == nil {
+		ns.LookbackAggregateFunction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LookbackAggregateFunction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLookbackAggregateFunction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LookbackAggregateFunction), nil
+}
+
+type LookbackPartition string
+
+const (
+	LookbackPartitionNone          LookbackPartition = "none"
+	LookbackPartitionOrigin        LookbackPartition = "origin"
+	LookbackPartitionMetadataField LookbackPartition = "metadata_field"
+)
+
+func (e *LookbackPartition) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LookbackPartition(s)
+	case string:
+		*e = LookbackPartition(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LookbackPartition: %T", src)
+	}
+	return nil
+}
+
+type NullLookbackPartition struct {
+	LookbackPartition LookbackPartition
+	Valid             bool // Valid is true if LookbackPartition is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLookbackPartition) Scan(value interface{}) error {
+	if value == nil {
+		ns.LookbackPartition, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LookbackPartition.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLookbackPartition) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LookbackPartition), nil
+}
+
 type ResultType string
 
 const (
@@ -67,26 +199,33 @@ type Algorithm struct {
 }
 
 type AlgorithmDependency struct {
-	ID                int64
-	FromAlgorithmID   int64
-	ToAlgorithmID     int64
-	FromWindowTypeID  int64
-	ToWindowTypeID    int64
-	FromProcessorID   int64
-	ToProcessorID     int64
-	Created           pgtype.Timestamp
-	LookbackCount     int64
-	LookbackTimedelta int64
+	ID                               int64
+	FromAlgorithmID                  int64
+	ToAlgorithmID                    int64
+	FromWindowTypeID                 int64
+	ToWindowTypeID                   int64
+	FromProcessorID                  int64
+	ToProcessorID                    int64
+	Created                          pgtype.Timestamp
+	LookbackCount                    int64
+	LookbackTimedelta                int64
+	LookbackPartition                LookbackPartition
+	LookbackPartitionFieldID         pgtype.Int8
+	LookbackAggregate                NullLookbackAggregateFunction
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
 }
 
 type AlgorithmExecutionPath struct {
-	FinalAlgoID           int64
-	NumDependencies       int32
-	AlgoIDPath            string
-	WindowTypeIDPath      string
-	ProcIDPath            string
-	LookbackCountPath     string
-	LookbackTimedeltaPath string
+	FinalAlgoID                int64
+	NumDependencies            int32
+	AlgoIDPath                 string
+	WindowTypeIDPath           string
+	ProcIDPath                 string
+	LookbackCountPath          string
+	LookbackTimedeltaPath      string
+	LookbackPartitionPath      string
+	LookbackPartitionFieldPath string
 }
 
 // Plot annotations with time ranges and metadata
@@ -114,6 +253,14 @@ type AnnotationWindowType struct {
 	WindowTypeID int64
 }
 
+type ApiKey struct {
+	ID      int64
+	Name    string
+	KeyHash string
+	Created pgtype.Timestamp
+	Revoked pgtype.Timestamp
+}
+
 type MetadataField struct {
 	ID          int64
 	Name        string
@@ -126,12 +273,20 @@ type MetadataFieldsReference struct {
 }
 
 type Processor struct {
-	ID               int64
-	Name             string
-	Runtime          string
-	ConnectionString string
-	Created          pgtype.Timestamp
-	ProjectName      pgtype.Text
+	ID                    int64
+	Name                  string
+	Runtime               string
+	ConnectionString      string
+	Created               pgtype.Timestamp
+	Namespace             string
+	RegisteredBy          pgtype.Text
+	TlsServerName         pgtype.Text
+	TlsInsecureSkipVerify bool
+}
+
+type RegistryVersion struct {
+	ID      bool
+	Version int64
 }
 
 type Result struct {
@@ -142,6 +297,16 @@ type Result struct {
 	ResultValue  pgtype.Float8
 	ResultArray  []float64
 	ResultJson   []byte
+	TraceID      pgtype.Text
+	Namespace    string
+}
+
+type RoleGrant struct {
+	ID       int64
+	Identity string
+	Role     string
+	Scope    string
+	Created  pgtype.Timestamp
 }
 
 type Window struct {
@@ -152,6 +317,44 @@ type Window struct {
 	Origin       string
 	Metadata     []byte
 	Created      pgtype.Timestamp
+	TraceID      pgtype.Text
+	CreatedBy    pgtype.Text
+	Namespace    pgtype.Text
+}
+
+type WindowRollup struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int32
+	LateWindowPolicy       LateWindowPolicy
+	Created                pgtype.Timestamp
+}
+
+type WindowRollupProgress struct {
+	ID           int64
+	WindowTypeID int64
+	Origin       string
+	Metadata     []byte
+	TimeFrom     pgtype.Timestamp
+	TimeTo       pgtype.Timestamp
+	ChildWindows []pgtype.Timestamp
+	Emitted      pgtype.Timestamp
+}
+
+type WindowSchedule struct {
+	WindowTypeID  int64
+	Cron          pgtype.Text
+	IntervalNs    int64
+	AlignmentNs   int64
+	TimeZone      string
+	Origin        string
+	CatchUp       bool
+	HighWaterMark pgtype.Timestamp
+	Created       pgtype.Timestamp
 }
 
 type WindowType struct {
@@ -160,9 +363,11 @@ type WindowType struct {
 	Version     string
 	Description string
 	Created     pgtype.Timestamp
+	Namespace   string
 }
 
 type WindowTypeMetadataField struct {
+	WindowTypeNamespace      string
 	WindowTypeName           string
 	WindowTypeVersion        string
 	MetadataFieldID          int64
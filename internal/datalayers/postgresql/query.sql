@@ -4,18 +4,24 @@ INSERT INTO processor (
   name,
   runtime,
   connection_string,
-  project_name
+  namespace,
+  registered_by,
+  tls_server_name,
+  tls_insecure_skip_verify
 ) VALUES (
   sqlc.arg('name'),
   sqlc.arg('runtime'),
   sqlc.arg('connection_string'),
-  sqlc.arg('project_name')
-) ON CONFLICT (name, runtime) DO UPDATE 
+  sqlc.arg('namespace'),
+  sqlc.narg('registered_by'),
+  sqlc.narg('tls_server_name'),
+  sqlc.arg('tls_insecure_skip_verify')
+) ON CONFLICT (namespace, name, runtime) DO UPDATE 
 SET 
-  name = EXCLUDED.name,
-  runtime = EXCLUDED.runtime,
   connection_string = EXCLUDED.connection_string,
-  project_name = EXCLUDED.project_name
+  registered_by = EXCLUDED.registered_by,
+  tls_server_name = EXCLUDED.tls_server_name,
+  tls_insecure_skip_verify = EXCLUDED.tls_insecure_skip_verify
 RETURNING id;
 
 -- name: CreateMetadataField :one
@@ -33,14 +39,16 @@ RETURNING id;
 
 -- name: CreateWindowType :one
 INSERT INTO window_type (
+  namespace,
   name,
   version,
   description
 ) VALUES (
+  sqlc.arg('namespace'),
   sqlc.arg('name'),
   sqlc.arg('version'),
   sqlc.arg('description')
-) ON CONFLICT (name, version) DO UPDATE
+) ON CONFLICT (namespace, name, version) DO UPDATE
 SET
   name = EXCLUDED.name,
   version = EXCLUDED.version,
@@ -64,10 +72,12 @@ WITH processor_id AS (
   SELECT id FROM processor p
   WHERE p.name = sqlc.arg('processor_name') 
   AND p.runtime = sqlc.arg('processor_runtime')
+  AND p.namespace = sqlc.arg('processor_namespace')
 ),
 window_type_id AS (
   SELECT id FROM window_type w
-  WHERE w.name = sqlc.arg('window_type_name') 
+  WHERE w.namespace = sqlc.arg('processor_namespace')
+  AND w.name = sqlc.arg('window_type_name') 
   AND w.version = sqlc.arg('window_type_version')
 )
 INSERT INTO algorithm (
@@ -89,7 +99,8 @@ INSERT INTO algorithm (
 -- name: ReadAlgorithmsForWindow :many
 SELECT a.* FROM algorithm a
 JOIN window_type wt ON a.window_type_id = wt.id
-WHERE wt.name = sqlc.arg('window_type_name') 
+WHERE wt.namespace = sqlc.arg('window_type_namespace')
+AND wt.name = sqlc.arg('window_type_name') 
 AND wt.version = sqlc.arg('window_type_version');
 
 -- name: ReadAlgorithms :many
@@ -99,7 +110,7 @@ SELECT a.* FROM algorithm a;
 SELECT a.* FROM algorithm a
 WHERE a.processor_id = sqlc.arg('processor_id');
 
--- name: CreateAlgorithmDependency :exec
+-- name: CreateAlgorithmDependency :one
 WITH from_algo AS (
   SELECT a.id, a.window_type_id, a.processor_id FROM algorithm a
   JOIN processor p ON a.processor_id = p.id
@@ -107,6 +118,7 @@ WITH from_algo AS (
   AND a.version = sqlc.arg('from_algorithm_version')
   AND p.name = sqlc.arg('from_processor_name')
   AND p.runtime = sqlc.arg('from_processor_runtime')
+  AND p.namespace = sqlc.arg('from_processor_namespace')
 ),
 to_algo AS (
   SELECT a.id, a.window_type_id, a.processor_id FROM algorithm a
@@ -115,6 +127,7 @@ to_algo AS (
   AND a.version = sqlc.arg('to_algorithm_version')
   AND p.name = sqlc.arg('to_processor_name')
   AND p.runtime = sqlc.arg('to_processor_runtime')
+  AND p.namespace = sqlc.arg('to_processor_namespace')
 )
 INSERT INTO algorithm_dependency (
   from_algorithm_id,
@@ -124,7 +137,12 @@ INSERT INTO algorithm_dependency (
   from_processor_id,
   to_processor_id,
   lookback_count,
-  lookback_timedelta
+  lookback_timedelta,
+  lookback_partition,
+  lookback_partition_field_id,
+  lookback_aggregate,
+  lookback_aggregate_percentile,
+  lookback_aggregate_bucket_timedelta
 ) VALUES (
   (SELECT id FROM from_algo LIMIT 1),
   (SELECT id FROM to_algo LIMIT 1),
@@ -133,7 +151,12 @@ INSERT INTO algorithm_dependency (
   (SELECT processor_id FROM from_algo LIMIT 1),
   (SELECT processor_id FROM to_algo LIMIT 1),
   sqlc.arg('lookback_count'),
-  sqlc.arg('lookback_timedelta')
+  sqlc.arg('lookback_timedelta'),
+  sqlc.arg('lookback_partition'),
+  (SELECT mf.id FROM metadata_fields mf WHERE mf.name = sqlc.narg('lookback_partition_field')),
+  sqlc.narg('lookback_aggregate'),
+  sqlc.arg('lookback_aggregate_percentile'),
+  sqlc.arg('lookback_aggregate_bucket_timedelta')
 ) ON CONFLICT (from_algorithm_id, to_algorithm_id) DO UPDATE
   SET
     from_window_type_id = excluded.from_window_type_id,
@@ -141,7 +164,13 @@ INSERT INTO algorithm_dependency (
     from_processor_id = excluded.from_processor_id,
     to_processor_id = excluded.to_processor_id,
     lookback_count = excluded.lookback_count,
-    lookback_timedelta = excluded.lookback_timedelta;
+    lookback_timedelta = excluded.lookback_timedelta,
+    lookback_partition = excluded.lookback_partition,
+    lookback_partition_field_id = excluded.lookback_partition_field_id,
+    lookback_aggregate = excluded.lookback_aggregate,
+    lookback_aggregate_percentile = excluded.lookback_aggregate_percentile,
+    lookback_aggregate_bucket_timedelta = excluded.lookback_aggregate_bucket_timedelta
+RETURNING *;
 
 -- name: ReadFromAlgorithmDependencies :many
 WITH from_algo AS (
@@ -151,6 +180,7 @@ WITH from_algo AS (
   AND a.version = sqlc.arg('from_algorithm_version')
   AND p.name = sqlc.arg('from_processor_name')
   AND p.runtime = sqlc.arg('from_processor_runtime')
+  AND p.namespace = sqlc.arg('from_processor_namespace')
 )
 SELECT ad.* FROM algorithm_dependency ad WHERE ad.from_algorithm_id = from_algo.id;
 
@@ -159,39 +189,72 @@ WITH processor_id AS (
   SELECT p.id FROM processor p
   WHERE p.name = sqlc.arg('processor_name')
   AND p.runtime = sqlc.arg('processor_runtime')
+  AND p.namespace = sqlc.arg('processor_namespace')
 )
 SELECT a.id FROM algorithm a
 WHERE a.name = sqlc.arg('algorithm_name')
 AND a.version = sqlc.arg('algorithm_version')
 AND a.processor_id = (SELECT id from processor_id);
   
--- name: ReadAlgorithmExecutionPaths :many
-SELECT aep.* FROM algorithm_execution_paths aep WHERE aep.window_type_id_path ~ ('*.' || sqlc.arg('window_type_id')::TEXT || '.*')::lquery;
+-- name: ReadAlgorithm :one
+SELECT a.* FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE a.name = sqlc.arg('algorithm_name')
+AND a.version = sqlc.arg('algorithm_version')
+AND p.name = sqlc.arg('processor_name')
+AND p.runtime = sqlc.arg('processor_runtime')
+AND p.namespace = sqlc.arg('processor_namespace')
+AND wt.namespace = p.namespace
+AND wt.name = sqlc.arg('window_type_name')
+AND wt.version = sqlc.arg('window_type_version');
+
+-- name: ReadAlgorithmDependencies :many
+SELECT ad.* FROM algorithm_dependency ad;
+
+-- name: ReadRegistryVersion :one
+SELECT version FROM registry_version;
 
--- name: ReadAlgorithmExecutionPathsForAlgo :many
-SELECT aep.* FROM algorithm_execution_paths aep WHERE aep.final_algo_id=sqlc.arg('algo_id');
+-- name: BumpRegistryVersion :one
+UPDATE registry_version SET version = version + 1 RETURNING version;
+
+-- name: RefreshAlgorithmExecutionPaths :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY algorithm_execution_paths;
 
 -- name: ReadWindowTypes :many
 SELECT wt.* FROM window_type wt;
 
+-- name: ReadWindowTypeNamespaces :many
+SELECT wt.namespace FROM window_type wt
+WHERE wt.name = sqlc.arg('window_type_name')
+AND wt.version = sqlc.arg('window_type_version')
+ORDER BY wt.namespace;
+
 -- name: RegisterWindow :one
 WITH window_type_id AS (
-  SELECT id FROM window_type 
-  WHERE name = sqlc.arg('window_type_name') 
-  AND version = sqlc.arg('window_type_version')
+  SELECT wt.id FROM window_type wt
+  WHERE wt.namespace = sqlc.arg('window_type_namespace')
+  AND wt.name = sqlc.arg('window_type_name') 
+  AND wt.version = sqlc.arg('window_type_version')
 )
 INSERT INTO windows (
   window_type_id,
   time_from, 
   time_to,
   origin, 
-  metadata
+  metadata,
+  trace_id,
+  created_by,
+  namespace
 ) VALUES (
   (SELECT id FROM window_type_id),
   sqlc.arg('time_from'),
   sqlc.arg('time_to'),
   sqlc.arg('origin'),
-  sqlc.arg('metadata')
+  sqlc.arg('metadata'),
+  sqlc.narg('trace_id'),
+  sqlc.narg('created_by'),
+  sqlc.narg('namespace')
 ) RETURNING window_type_id, id;
 
 -- name: CreateResult :one
@@ -201,21 +264,28 @@ INSERT INTO results (
   algorithm_id, 
   result_value,
   result_array,
-  result_json
+  result_json,
+  trace_id,
+  namespace
 ) VALUES (
   sqlc.arg('windows_id'),
   sqlc.arg('window_type_id'),
   sqlc.arg('algorithm_id'),
   sqlc.arg('result_value'),
   sqlc.arg('result_array'),
-  sqlc.arg('result_json')
+  sqlc.arg('result_json'),
+  sqlc.narg('trace_id'),
+  (SELECT p.namespace FROM algorithm a JOIN processor p ON a.processor_id = p.id WHERE a.id = sqlc.arg('algorithm_id'))
 ) RETURNING id;
 
 -- name: ReadProcessors :many
 SELECT * FROM processor;
 
 -- name: ReadProcessorExcludeProject :many
-SELECT * FROM processor WHERE project_name != sqlc.arg('project_name');
+SELECT * FROM processor WHERE namespace != sqlc.arg('namespace');
+
+-- name: ReadProcessorsInNamespace :many
+SELECT * FROM processor WHERE namespace = sqlc.arg('namespace');
 
 -- name: ReadProcessorsByIDs :many
 SELECT *
@@ -229,7 +299,8 @@ SELECT
     metadata_field_name,
     metadata_field_description
 FROM window_type_metadata_fields
-WHERE window_type_name = sqlc.arg('window_type_name')
+WHERE window_type_namespace = sqlc.arg('window_type_namespace')
+  AND window_type_name = sqlc.arg('window_type_name')
   AND window_type_version = sqlc.arg('window_type_version')
 ORDER BY metadata_field_name;
 
@@ -239,51 +310,331 @@ SELECT * FROM metadata_fields;
 -- name: ReadWindowTypeMetadataFields :many
 SELECT * FROM window_type_metadata_fields;
 
--- name: ReadResultsForAlgorithmByTimedelta :many
+-- name: ReadAlgorithmResultType :one
+SELECT result_type FROM algorithm WHERE id = sqlc.arg('algorithm_id');
+
+-- name: ReadAlgorithmsByIDs :many
 SELECT
-	r.id as result_id,
-    r.algorithm_id,
-    w.id as window_id,
-    r.result_value, 
-    r.result_array,
-    r.result_json,
-    wt.name as window_type_name, 
-    wt.version as window_type_version,
-    w.time_from as window_time_from,
-    w.time_to as window_time_to,
-    w.origin as window_origin,
-    w.metadata as window_metadata
+    a.id,
+    a.name,
+    a.version,
+    p.name AS processor_name
+FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.id = ANY(sqlc.arg('algorithm_ids')::bigint[]);
+
+-- name: ReadLookbackAggregatesForAlgorithms :many
+SELECT
+    ad.from_algorithm_id,
+    ad.to_algorithm_id,
+    ad.lookback_aggregate,
+    ad.lookback_aggregate_percentile,
+    ad.lookback_aggregate_bucket_timedelta
 FROM
-	results r
-JOIN windows w ON
-	w.id = r.windows_id
-JOIN window_type wt on wt.id = w.window_type_id
+    algorithm_dependency ad
 WHERE
-	r.algorithm_id = sqlc.arg('algorithm_id')
-    AND w.time_from > sqlc.arg('search_from')
-    AND w.time_to  < sqlc.arg('search_to')
-order by time_from, time_to desc;
+    ad.lookback_aggregate IS NOT NULL
+    AND ad.to_algorithm_id = ANY(sqlc.arg('algorithm_ids')::BIGINT[]);
+
+-- name: ReadResultsForLookbacks :many
+-- lookbacks are given as parallel arrays, one element per lookback. A count
+-- of 0 does not limit the lookback, a NULL search_from does not bound it, a
+-- false by_origin does not partition it by origin, and an empty metadata does
+-- not partition it by metadata
+WITH lookbacks AS (
+    -- parallel arrays are unnested together, row by row
+    SELECT
+        unnest(sqlc.arg('lookback_ids')::INT[]) AS lookback_id,
+        unnest(sqlc.arg('algorithm_ids')::BIGINT[]) AS algorithm_id,
+        unnest(sqlc.arg('counts')::INT[]) AS count,
+        unnest(sqlc.arg('search_froms')::TIMESTAMP[]) AS search_from,
+        unnest(sqlc.arg('search_tos')::TIMESTAMP[]) AS search_to,
+        unnest(sqlc.arg('origins')::TEXT[]) AS origin,
+        unnest(sqlc.arg('by_origins')::BOOLEAN[]) AS by_origin,
+        unnest(sqlc.arg('metadata')::TEXT[]) AS metadata
+)
+SELECT
+    l.lookback_id::INT AS lookback_id,
+    res.*
+FROM
+    lookbacks l
+CROSS JOIN LATERAL (
+    SELECT
+        r.id AS result_id,
+        r.algorithm_id,
+        a.result_type,
+        w.id AS window_id,
+        r.result_value,
+        r.result_array,
+        r.result_json,
+        wt.name AS window_type_name,
+        wt.version AS window_type_version,
+        w.time_from AS window_time_from,
+        w.time_to AS window_time_to,
+        w.origin AS window_origin,
+        w.metadata AS window_metadata
+    FROM
+        results r
+    JOIN algorithm a ON a.id = r.algorithm_id
+    JOIN windows w ON w.id = r.windows_id
+    JOIN window_type wt ON wt.id = w.window_type_id
+    WHERE
+        r.algorithm_id = l.algorithm_id
+        AND (l.search_from IS NULL OR w.time_from > l.search_from)
+        AND w.time_to < l.search_to
+        AND (NOT l.by_origin OR w.origin = l.origin)
+        AND (NULLIF(l.metadata, '') IS NULL OR w.metadata @> l.metadata::JSONB)
+    -- count lookbacks are of the latest past results
+    ORDER BY w.time_to DESC
+    LIMIT NULLIF(l.count, 0)
+) res
+ORDER BY l.lookback_id, res.window_time_from, res.window_time_to;
+
+-- name: ReadResultAggregatesForLookbacks :many
+-- lookbacks are given as parallel arrays, as for ReadResultsForLookbacks.
+-- Results are bucketed by window end time when bucket_seconds is positive
+WITH lookbacks AS (
+    -- parallel arrays are unnested together, row by row
+    SELECT
+        unnest(sqlc.arg('lookback_ids')::INT[]) AS lookback_id,
+        unnest(sqlc.arg('algorithm_ids')::BIGINT[]) AS algorithm_id,
+        unnest(sqlc.arg('counts')::INT[]) AS count,
+        unnest(sqlc.arg('search_froms')::TIMESTAMP[]) AS search_from,
+        unnest(sqlc.arg('search_tos')::TIMESTAMP[]) AS search_to,
+        unnest(sqlc.arg('origins')::TEXT[]) AS origin,
+        unnest(sqlc.arg('by_origins')::BOOLEAN[]) AS by_origin,
+        unnest(sqlc.arg('metadata')::TEXT[]) AS metadata,
+        unnest(sqlc.arg('percentiles')::FLOAT8[]) AS percentile,
+        unnest(sqlc.arg('bucket_seconds')::FLOAT8[]) AS bucket_seconds
+),
+lookback_results AS (
+    SELECT
+        l.lookback_id,
+        l.percentile,
+        res.result_value,
+        res.time_from,
+        res.time_to,
+        CASE WHEN l.bucket_seconds > 0 THEN
+            date_bin(make_interval(secs => l.bucket_seconds), res.time_to, TIMESTAMP 'epoch')
+        END AS bucket
+    FROM
+        lookbacks l
+    CROSS JOIN LATERAL (
+        SELECT
+            r.result_value,
+            w.time_from,
+            w.time_to
+        FROM
+            results r
+        JOIN windows w ON w.id = r.windows_id
+        WHERE
+            r.algorithm_id = l.algorithm_id
+            AND (l.search_from IS NULL OR w.time_from > l.search_from)
+            AND w.time_to < l.search_to
+            AND (NOT l.by_origin OR w.origin = l.origin)
+            AND (NULLIF(l.metadata, '') IS NULL OR w.metadata @> l.metadata::JSONB)
+        -- count lookbacks are of the latest past results
+        ORDER BY w.time_to DESC
+        LIMIT NULLIF(l.count, 0)
+    ) res
+)
+SELECT
+    lookback_id::INT AS lookback_id,
+    MIN(time_from)::TIMESTAMP AS time_from,
+    MAX(time_to)::TIMESTAMP AS time_to,
+    COUNT(*) AS result_count,
+    COALESCE(SUM(result_value), 0)::FLOAT8 AS sum_value,
+    COALESCE(AVG(result_value), 0)::FLOAT8 AS mean_value,
+    COALESCE(MIN(result_value), 0)::FLOAT8 AS min_value,
+    COALESCE(MAX(result_value), 0)::FLOAT8 AS max_value,
+    COALESCE((ARRAY_AGG(result_value ORDER BY time_to DESC))[1], 0)::FLOAT8 AS last_value,
+    COALESCE(PERCENTILE_CONT(percentile) WITHIN GROUP (ORDER BY result_value), 0)::FLOAT8 AS percentile_value
+FROM
+    lookback_results
+GROUP BY lookback_id, percentile, bucket
+ORDER BY lookback_id, MIN(time_from);
+
+-- name: CreateWindowSchedule :exec
+-- the high water mark of an existing schedule is kept, so that
+-- re-registering a window type neither skips nor repeats windows. It is
+-- reset when the times the schedule fires at change, as it would no longer
+-- be aligned to them
+INSERT INTO window_schedule (
+  window_type_id,
+  cron,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  origin,
+  catch_up,
+  high_water_mark
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.narg('cron'),
+  sqlc.arg('interval_ns'),
+  sqlc.arg('alignment_ns'),
+  sqlc.arg('time_zone'),
+  sqlc.arg('origin'),
+  sqlc.arg('catch_up'),
+  sqlc.arg('high_water_mark')
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    high_water_mark = CASE
+      WHEN window_schedule.cron IS NOT DISTINCT FROM excluded.cron
+        AND window_schedule.interval_ns = excluded.interval_ns
+        AND window_schedule.alignment_ns = excluded.alignment_ns
+        AND window_schedule.time_zone = excluded.time_zone
+      THEN window_schedule.high_water_mark
+      ELSE excluded.high_water_mark
+    END,
+    cron = excluded.cron,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    origin = excluded.origin,
+    catch_up = excluded.catch_up;
+
+-- name: DeleteWindowSchedule :exec
+DELETE FROM window_schedule WHERE window_type_id = sqlc.arg('window_type_id');
 
--- name: ReadResultsForAlgorithmByCount :many
+-- name: ReadWindowSchedules :many
 SELECT
-	r.id as result_id,
-    r.algorithm_id,
-    w.id as window_id,
-    r.result_value, 
-    r.result_array,
-    r.result_json,
-    wt.name as window_type_name, 
-    wt.version as window_type_version,
-    w.time_from as window_time_from,
-    w.time_to as window_time_to,
-    w.origin as window_origin,
-    w.metadata as window_metadata
+    ws.*,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
 FROM
-	results r
-JOIN windows w on
-	w.id = r.windows_id
-JOIN window_type wt on wt.id = w.window_type_id
+    window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id;
+
+-- name: ReadWindowScheduleHighWaterMarkForUpdate :one
+SELECT high_water_mark FROM window_schedule
+WHERE window_type_id = sqlc.arg('window_type_id')
+FOR UPDATE;
+
+-- name: UpdateWindowScheduleHighWaterMark :exec
+UPDATE window_schedule SET high_water_mark = sqlc.arg('high_water_mark')
+WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock(sqlc.arg('key')::BIGINT)::BOOLEAN AS locked;
+
+-- name: CreateWindowRollup :exec
+INSERT INTO window_rollup (
+  window_type_id,
+  child_window_type_name,
+  child_window_type_version,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  child_count,
+  late_window_policy
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.arg('child_window_type_name'),
+  sqlc.arg('child_window_type_version'),
+  sqlc.arg('interval_ns'),
+  sqlc.arg('alignment_ns'),
+  sqlc.arg('time_zone'),
+  sqlc.arg('child_count'),
+  sqlc.arg('late_window_policy')
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    child_window_type_name = excluded.child_window_type_name,
+    child_window_type_version = excluded.child_window_type_version,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    child_count = excluded.child_count,
+    late_window_policy = excluded.late_window_policy;
+
+-- name: DeleteWindowRollup :exec
+DELETE FROM window_rollup WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: ReadWindowRollups :many
+SELECT wr.* FROM window_rollup wr;
+
+-- name: ReadWindowRollupsForChild :many
+SELECT
+    wr.*,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
 WHERE
-	r.algorithm_id = sqlc.arg('algorithm_id')
-    AND w.time_to < sqlc.arg('search_to')
-ORDER by time_from,time_to desc LIMIT sqlc.arg('count');
+    wt.namespace = sqlc.arg('window_type_namespace')
+    AND wr.child_window_type_name = sqlc.arg('child_window_type_name')
+    AND wr.child_window_type_version = sqlc.arg('child_window_type_version');
+
+-- name: CreateWindowRollupProgress :exec
+INSERT INTO window_rollup_progress (
+  window_type_id,
+  origin,
+  metadata,
+  time_from,
+  time_to
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.arg('origin'),
+  sqlc.arg('metadata'),
+  sqlc.arg('time_from'),
+  sqlc.arg('time_to')
+) ON CONFLICT (window_type_id, origin, metadata, time_from) DO NOTHING;
+
+-- name: ReadWindowRollupProgressForUpdate :one
+SELECT wrp.* FROM window_rollup_progress wrp
+WHERE
+    wrp.window_type_id = sqlc.arg('window_type_id')
+    AND wrp.origin = sqlc.arg('origin')
+    AND wrp.metadata = sqlc.arg('metadata')
+    AND wrp.time_from = sqlc.arg('time_from')
+FOR UPDATE;
+
+-- name: UpdateWindowRollupProgress :exec
+UPDATE window_rollup_progress
+SET
+    child_windows = sqlc.arg('child_windows'),
+    emitted = sqlc.narg('emitted')
+WHERE id = sqlc.arg('id');
+
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (
+  name,
+  key_hash
+) VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('key_hash')
+);
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked = CURRENT_TIMESTAMP
+WHERE name = sqlc.arg('name') AND revoked IS NULL;
+
+-- name: ReadAPIKeyName :one
+SELECT name FROM api_keys
+WHERE key_hash = sqlc.arg('key_hash') AND revoked IS NULL;
+
+-- name: CreateGrant :exec
+INSERT INTO role_grants (
+  identity,
+  role,
+  scope
+) VALUES (
+  sqlc.arg('identity'),
+  sqlc.arg('role'),
+  sqlc.arg('scope')
+) ON CONFLICT (identity, role, scope) DO NOTHING;
+
+-- name: DeleteGrant :execrows
+DELETE FROM role_grants
+WHERE identity = sqlc.arg('identity')
+AND role = sqlc.arg('role')
+AND scope = sqlc.arg('scope');
+
+-- name: ReadGrants :many
+SELECT role, scope FROM role_grants
+WHERE identity = sqlc.arg('identity')
+ORDER BY role, scope;
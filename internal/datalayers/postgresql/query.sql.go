@@ -11,16 +11,49 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const bumpRegistryVersion = `-- name: BumpRegistryVersion :one
+UPDATE registry_version SET version = version + 1 RETURNING version
+`
+
+func (q *Queries) BumpRegistryVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, bumpRegistryVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :exec
+INSERT INTO api_keys (
+  name,
+  key_hash
+) VALUES (
+  $1,
+  $2
+)
+`
+
+type CreateAPIKeyParams struct {
+	Name    string
+	KeyHash string
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) error {
+	_, err := q.db.Exec(ctx, createAPIKey, arg.Name, arg.KeyHash)
+	return err
+}
+
 const createAlgorithm = `-- name: CreateAlgorithm :exec
 WITH processor_id AS (
   SELECT id FROM processor p
   WHERE p.name = $5 
   AND p.runtime = $6
+  AND p.namespace = $7
 ),
 window_type_id AS (
   SELECT id FROM window_type w
-  WHERE w.name = $7 
-  AND w.version = $8
+  WHERE w.namespace = $7
+  AND w.name = $8 
+  AND w.version = $9
 )
 INSERT INTO algorithm (
   name,
@@ -40,14 +73,15 @@ INSERT INTO algorithm (
 `
 
 type CreateAlgorithmParams struct {
-	Name              string
-	Version           string
-	Description       string
-	ResultType        ResultType
-	ProcessorName     string
-	ProcessorRuntime  string
-	WindowTypeName    string
-	WindowTypeVersion string
+	Name               string
+	Version            string
+	Description        string
+	ResultType         ResultType
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
+	WindowTypeName     string
+	WindowTypeVersion  string
 }
 
 func (q *Queries) CreateAlgorithm(ctx context.Context, arg CreateAlgorithmParams) error {
@@ -58,28 +92,31 @@ func (q *Queries) CreateAlgorithm(ctx context.Context, arg CreateAlgorithmParams
 		arg.ResultType,
 		arg.ProcessorName,
 		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
 		arg.WindowTypeName,
 		arg.WindowTypeVersion,
 	)
 	return err
 }
 
-const createAlgorithmDependency = `-- name: CreateAlgorithmDependency :exec
+const createAlgorithmDependency = `-- name: CreateAlgorithmDependency :one
 WITH from_algo AS (
   SELECT a.id, a.window_type_id, a.processor_id FROM algorithm a
   JOIN processor p ON a.processor_id = p.id
-  WHERE a.name = $3
-  AND a.version = $4
-  AND p.name = $5
-  AND p.runtime = $6
+  WHERE a.name = $8
+  AND a.version = $9
+  AND p.name = $10
+  AND p.runtime = $11
+  AND p.namespace = $12
 ),
 to_algo AS (
   SELECT a.id, a.window_type_id, a.processor_id FROM algorithm a
   JOIN processor p ON a.processor_id = p.id
-  WHERE a.name = $7
-  AND a.version = $8
-  AND p.name = $9
-  AND p.runtime = $10
+  WHERE a.name = $13
+  AND a.version = $14
+  AND p.name = $15
+  AND p.runtime = $16
+  AND p.namespace = $17
 )
 INSERT INTO algorithm_dependency (
   from_algorithm_id,
@@ -89,7 +126,12 @@ INSERT INTO algorithm_dependency (
   from_processor_id,
   to_processor_id,
   lookback_count,
-  lookback_timedelta
+  lookback_timedelta,
+  lookback_partition,
+  lookback_partition_field_id,
+  lookback_aggregate,
+  lookback_aggregate_percentile,
+  lookback_aggregate_bucket_timedelta
 ) VALUES (
   (SELECT id FROM from_algo LIMIT 1),
   (SELECT id FROM to_algo LIMIT 1),
@@ -98,7 +140,12 @@ INSERT INTO algorithm_dependency (
   (SELECT processor_id FROM from_algo LIMIT 1),
   (SELECT processor_id FROM to_algo LIMIT 1),
   $1,
-  $2
+  $2,
+  $3,
+  (SELECT mf.id FROM metadata_fields mf WHERE mf.name = $4),
+  $5,
+  $6,
+  $7
 ) ON CONFLICT (from_algorithm_id, to_algorithm_id) DO UPDATE
   SET
     from_window_type_id = excluded.from_window_type_id,
@@ -106,35 +153,96 @@ INSERT INTO algorithm_dependency (
     from_processor_id = excluded.from_processor_id,
     to_processor_id = excluded.to_processor_id,
     lookback_count = excluded.lookback_count,
-    lookback_timedelta = excluded.lookback_timedelta
+    lookback_timedelta = excluded.lookback_timedelta,
+    lookback_partition = excluded.lookback_partition,
+    lookback_partition_field_id = excluded.lookback_partition_field_id,
+    lookback_aggregate = excluded.lookback_aggregate,
+    lookback_aggregate_percentile = excluded.lookback_aggregate_percentile,
+    lookback_aggregate_bucket_timedelta = excluded.lookback_aggregate_bucket_timedelta
+RETURNING id, from_algorithm_id, to_algorithm_id, from_window_type_id, to_window_type_id, from_processor_id, to_processor_id, created, lookback_count, lookback_timedelta, lookback_partition, lookback_partition_field_id, lookback_aggregate, lookback_aggregate_percentile, lookback_aggregate_bucket_timedelta
 `
 
 type CreateAlgorithmDependencyParams struct {
-	LookbackCount        int64
-	LookbackTimedelta    int64
-	FromAlgorithmName    string
-	FromAlgorithmVersion string
-	FromProcessorName    string
-	FromProcessorRuntime string
-	ToAlgorithmName      string
-	ToAlgorithmVersion   string
-	ToProcessorName      string
-	ToProcessorRuntime   string
-}
-
-func (q *Queries) CreateAlgorithmDependency(ctx context.Context, arg CreateAlgorithmDependencyParams) error {
-	_, err := q.db.Exec(ctx, createAlgorithmDependency,
+	LookbackCount                    int64
+	LookbackTimedelta                int64
+	LookbackPartition                LookbackPartition
+	LookbackPartitionField           pgtype.Text
+	LookbackAggregate                NullLookbackAggregateFunction
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
+	FromAlgorithmName                string
+	FromAlgorithmVersion             string
+	FromProcessorName                string
+	FromProcessorRuntime             string
+	FromProcessorNamespace           string
+	ToAlgorithmName                  string
+	ToAlgorithmVersion               string
+	ToProcessorName                  string
+	ToProcessorRuntime               string
+	ToProcessorNamespace             string
+}
+
+func (q *Queries) CreateAlgorithmDependency(ctx context.Context, arg CreateAlgorithmDependencyParams) (AlgorithmDependency, error) {
+	row := q.db.QueryRow(ctx, createAlgorithmDependency,
 		arg.LookbackCount,
 		arg.LookbackTimedelta,
+		arg.LookbackPartition,
+		arg.LookbackPartitionField,
+		arg.LookbackAggregate,
+		arg.LookbackAggregatePercentile,
+		arg.LookbackAggregateBucketTimedelta,
 		arg.FromAlgorithmName,
 		arg.FromAlgorithmVersion,
 		arg.FromProcessorName,
 		arg.FromProcessorRuntime,
+		arg.FromProcessorNamespace,
 		arg.ToAlgorithmName,
 		arg.ToAlgorithmVersion,
 		arg.ToProcessorName,
 		arg.ToProcessorRuntime,
+		arg.ToProcessorNamespace,
 	)
+	var i AlgorithmDependency
+	err := row.Scan(
+		&i.ID,
+		&i.FromAlgorithmID,
+		&i.ToAlgorithmID,
+		&i.FromWindowTypeID,
+		&i.ToWindowTypeID,
+		&i.FromProcessorID,
+		&i.ToProcessorID,
+		&i.Created,
+		&i.LookbackCount,
+		&i.LookbackTimedelta,
+		&i.LookbackPartition,
+		&i.LookbackPartitionFieldID,
+		&i.LookbackAggregate,
+		&i.LookbackAggregatePercentile,
+		&i.LookbackAggregateBucketTimedelta,
+	)
+	return i, err
+}
+
+const createGrant = `-- name: CreateGrant :exec
+INSERT INTO role_grants (
+  identity,
+  role,
+  scope
+) VALUES (
+  $1,
+  $2,
+  $3
+) ON CONFLICT (identity, role, scope) DO NOTHING
+`
+
+type CreateGrantParams struct {
+	Identity string
+	Role     string
+	Scope    string
+}
+
+func (q *Queries) CreateGrant(ctx context.Context, arg CreateGrantParams) error {
+	_, err := q.db.Exec(ctx, createGrant, arg.Identity, arg.Role, arg.Scope)
 	return err
 }
 
@@ -169,26 +277,35 @@ INSERT INTO processor (
   name,
   runtime,
   connection_string,
-  project_name
+  namespace,
+  registered_by,
+  tls_server_name,
+  tls_insecure_skip_verify
 ) VALUES (
   $1,
   $2,
   $3,
-  $4
-) ON CONFLICT (name, runtime) DO UPDATE 
+  $4,
+  $5,
+  $6,
+  $7
+) ON CONFLICT (namespace, name, runtime) DO UPDATE 
 SET 
-  name = EXCLUDED.name,
-  runtime = EXCLUDED.runtime,
   connection_string = EXCLUDED.connection_string,
-  project_name = EXCLUDED.project_name
+  registered_by = EXCLUDED.registered_by,
+  tls_server_name = EXCLUDED.tls_server_name,
+  tls_insecure_skip_verify = EXCLUDED.tls_insecure_skip_verify
 RETURNING id
 `
 
 type CreateProcessorParams struct {
-	Name             string
-	Runtime          string
-	ConnectionString string
-	ProjectName      pgtype.Text
+	Name                  string
+	Runtime               string
+	ConnectionString      string
+	Namespace             string
+	RegisteredBy          pgtype.Text
+	TlsServerName         pgtype.Text
+	TlsInsecureSkipVerify bool
 }
 
 // -------------------- Core Operations ----------------------
@@ -197,7 +314,10 @@ func (q *Queries) CreateProcessor(ctx context.Context, arg CreateProcessorParams
 		arg.Name,
 		arg.Runtime,
 		arg.ConnectionString,
-		arg.ProjectName,
+		arg.Namespace,
+		arg.RegisteredBy,
+		arg.TlsServerName,
+		arg.TlsInsecureSkipVerify,
 	)
 	return err
 }
@@ -209,14 +329,18 @@ INSERT INTO results (
   algorithm_id, 
   result_value,
   result_array,
-  result_json
+  result_json,
+  trace_id,
+  namespace
 ) VALUES (
   $1,
   $2,
   $3,
   $4,
   $5,
-  $6
+  $6,
+  $7,
+  (SELECT p.namespace FROM algorithm a JOIN processor p ON a.processor_id = p.id WHERE a.id = $3)
 ) RETURNING id
 `
 
@@ -227,6 +351,7 @@ type CreateResultParams struct {
 	ResultValue  pgtype.Float8
 	ResultArray  []float64
 	ResultJson   []byte
+	TraceID      pgtype.Text
 }
 
 func (q *Queries) CreateResult(ctx context.Context, arg CreateResultParams) (int64, error) {
@@ -237,22 +362,181 @@ func (q *Queries) CreateResult(ctx context.Context, arg CreateResultParams) (int
 		arg.ResultValue,
 		arg.ResultArray,
 		arg.ResultJson,
+		arg.TraceID,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createWindowRollup = `-- name: CreateWindowRollup :exec
+INSERT INTO window_rollup (
+  window_type_id,
+  child_window_type_name,
+  child_window_type_version,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  child_count,
+  late_window_policy
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    child_window_type_name = excluded.child_window_type_name,
+    child_window_type_version = excluded.child_window_type_version,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    child_count = excluded.child_count,
+    late_window_policy = excluded.late_window_policy
+`
+
+type CreateWindowRollupParams struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int32
+	LateWindowPolicy       LateWindowPolicy
+}
+
+func (q *Queries) CreateWindowRollup(ctx context.Context, arg CreateWindowRollupParams) error {
+	_, err := q.db.Exec(ctx, createWindowRollup,
+		arg.WindowTypeID,
+		arg.ChildWindowTypeName,
+		arg.ChildWindowTypeVersion,
+		arg.IntervalNs,
+		arg.AlignmentNs,
+		arg.TimeZone,
+		arg.ChildCount,
+		arg.LateWindowPolicy,
+	)
+	return err
+}
+
+const createWindowRollupProgress = `-- name: CreateWindowRollupProgress :exec
+INSERT INTO window_rollup_progress (
+  window_type_id,
+  origin,
+  metadata,
+  time_from,
+  time_to
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5
+) ON CONFLICT (window_type_id, origin, metadata, time_from) DO NOTHING
+`
+
+type CreateWindowRollupProgressParams struct {
+	WindowTypeID int64
+	Origin       string
+	Metadata     []byte
+	TimeFrom     pgtype.Timestamp
+	TimeTo       pgtype.Timestamp
+}
+
+func (q *Queries) CreateWindowRollupProgress(ctx context.Context, arg CreateWindowRollupProgressParams) error {
+	_, err := q.db.Exec(ctx, createWindowRollupProgress,
+		arg.WindowTypeID,
+		arg.Origin,
+		arg.Metadata,
+		arg.TimeFrom,
+		arg.TimeTo,
+	)
+	return err
+}
+
+const createWindowSchedule = `-- name: CreateWindowSchedule :exec
+INSERT INTO window_schedule (
+  window_type_id,
+  cron,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  origin,
+  catch_up,
+  high_water_mark
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    high_water_mark = CASE
+      WHEN window_schedule.cron IS NOT DISTINCT FROM excluded.cron
+        AND window_schedule.interval_ns = excluded.interval_ns
+        AND window_schedule.alignment_ns = excluded.alignment_ns
+        AND window_schedule.time_zone = excluded.time_zone
+      THEN window_schedule.high_water_mark
+      ELSE excluded.high_water_mark
+    END,
+    cron = excluded.cron,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    origin = excluded.origin,
+    catch_up = excluded.catch_up
+`
+
+type CreateWindowScheduleParams struct {
+	WindowTypeID  int64
+	Cron          pgtype.Text
+	IntervalNs    int64
+	AlignmentNs   int64
+	TimeZone      string
+	Origin        string
+	CatchUp       bool
+	HighWaterMark pgtype.Timestamp
+}
+
+// the high water mark of an existing schedule is kept, so that
+// re-registering a window type neither skips nor repeats windows. It is
+// reset when the times the schedule fires at change, as it would no longer
+// be aligned to them
+func (q *Queries) CreateWindowSchedule(ctx context.Context, arg CreateWindowScheduleParams) error {
+	_, err := q.db.Exec(ctx, createWindowSchedule,
+		arg.WindowTypeID,
+		arg.Cron,
+		arg.IntervalNs,
+		arg.AlignmentNs,
+		arg.TimeZone,
+		arg.Origin,
+		arg.CatchUp,
+		arg.HighWaterMark,
+	)
+	return err
+}
+
 const createWindowType = `-- name: CreateWindowType :one
 INSERT INTO window_type (
+  namespace,
   name,
   version,
   description
 ) VALUES (
   $1,
   $2,
-  $3
-) ON CONFLICT (name, version) DO UPDATE
+  $3,
+  $4
+) ON CONFLICT (namespace, name, version) DO UPDATE
 SET
   name = EXCLUDED.name,
   version = EXCLUDED.version,
@@ -261,13 +545,19 @@ RETURNING id
 `
 
 type CreateWindowTypeParams struct {
+	Namespace   string
 	Name        string
 	Version     string
 	Description string
 }
 
 func (q *Queries) CreateWindowType(ctx context.Context, arg CreateWindowTypeParams) (int64, error) {
-	row := q.db.QueryRow(ctx, createWindowType, arg.Name, arg.Version, arg.Description)
+	row := q.db.QueryRow(ctx, createWindowType,
+		arg.Namespace,
+		arg.Name,
+		arg.Version,
+		arg.Description,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -296,59 +586,134 @@ func (q *Queries) CreateWindowTypeMetadataFieldBridge(ctx context.Context, arg C
 	return err
 }
 
-const readAlgorithmExecutionPaths = `-- name: ReadAlgorithmExecutionPaths :many
-SELECT aep.final_algo_id, aep.num_dependencies, aep.algo_id_path, aep.window_type_id_path, aep.proc_id_path, aep.lookback_count_path, aep.lookback_timedelta_path FROM algorithm_execution_paths aep WHERE aep.window_type_id_path ~ ('*.' || $1::TEXT || '.*')::lquery
+const deleteGrant = `-- name: DeleteGrant :execrows
+DELETE FROM role_grants
+WHERE identity = $1
+AND role = $2
+AND scope = $3
 `
 
-func (q *Queries) ReadAlgorithmExecutionPaths(ctx context.Context, windowTypeID string) ([]AlgorithmExecutionPath, error) {
-	rows, err := q.db.Query(ctx, readAlgorithmExecutionPaths, windowTypeID)
+type DeleteGrantParams struct {
+	Identity string
+	Role     string
+	Scope    string
+}
+
+func (q *Queries) DeleteGrant(ctx context.Context, arg DeleteGrantParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteGrant, arg.Identity, arg.Role, arg.Scope)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []AlgorithmExecutionPath
-	for rows.Next() {
-		var i AlgorithmExecutionPath
-		if err := rows.Scan(
-			&i.FinalAlgoID,
-			&i.NumDependencies,
-			&i.AlgoIDPath,
-			&i.WindowTypeIDPath,
-			&i.ProcIDPath,
-			&i.LookbackCountPath,
-			&i.LookbackTimedeltaPath,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
+}
+
+const deleteWindowRollup = `-- name: DeleteWindowRollup :exec
+DELETE FROM window_rollup WHERE window_type_id = $1
+`
+
+func (q *Queries) DeleteWindowRollup(ctx context.Context, windowTypeID int64) error {
+	_, err := q.db.Exec(ctx, deleteWindowRollup, windowTypeID)
+	return err
+}
+
+const deleteWindowSchedule = `-- name: DeleteWindowSchedule :exec
+DELETE FROM window_schedule WHERE window_type_id = $1
+`
+
+func (q *Queries) DeleteWindowSchedule(ctx context.Context, windowTypeID int64) error {
+	_, err := q.db.Exec(ctx, deleteWindowSchedule, windowTypeID)
+	return err
 }
 
-const readAlgorithmExecutionPathsForAlgo = `-- name: ReadAlgorithmExecutionPathsForAlgo :many
-SELECT aep.final_algo_id, aep.num_dependencies, aep.algo_id_path, aep.window_type_id_path, aep.proc_id_path, aep.lookback_count_path, aep.lookback_timedelta_path FROM algorithm_execution_paths aep WHERE aep.final_algo_id=$1
+const readAPIKeyName = `-- name: ReadAPIKeyName :one
+SELECT name FROM api_keys
+WHERE key_hash = $1 AND revoked IS NULL
 `
 
-func (q *Queries) ReadAlgorithmExecutionPathsForAlgo(ctx context.Context, algoID int64) ([]AlgorithmExecutionPath, error) {
-	rows, err := q.db.Query(ctx, readAlgorithmExecutionPathsForAlgo, algoID)
+func (q *Queries) ReadAPIKeyName(ctx context.Context, keyHash string) (string, error) {
+	row := q.db.QueryRow(ctx, readAPIKeyName, keyHash)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
+const readAlgorithm = `-- name: ReadAlgorithm :one
+SELECT a.id, a.name, a.version, a.processor_id, a.window_type_id, a.result_type, a.created, a.description FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE a.name = $1
+AND a.version = $2
+AND p.name = $3
+AND p.runtime = $4
+AND p.namespace = $5
+AND wt.namespace = p.namespace
+AND wt.name = $6
+AND wt.version = $7
+`
+
+type ReadAlgorithmParams struct {
+	AlgorithmName      string
+	AlgorithmVersion   string
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
+	WindowTypeName     string
+	WindowTypeVersion  string
+}
+
+func (q *Queries) ReadAlgorithm(ctx context.Context, arg ReadAlgorithmParams) (Algorithm, error) {
+	row := q.db.QueryRow(ctx, readAlgorithm,
+		arg.AlgorithmName,
+		arg.AlgorithmVersion,
+		arg.ProcessorName,
+		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
+		arg.WindowTypeName,
+		arg.WindowTypeVersion,
+	)
+	var i Algorithm
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.ProcessorID,
+		&i.WindowTypeID,
+		&i.ResultType,
+		&i.Created,
+		&i.Description,
+	)
+	return i, err
+}
+
+const readAlgorithmDependencies = `-- name: ReadAlgorithmDependencies :many
+SELECT ad.id, ad.from_algorithm_id, ad.to_algorithm_id, ad.from_window_type_id, ad.to_window_type_id, ad.from_processor_id, ad.to_processor_id, ad.created, ad.lookback_count, ad.lookback_timedelta, ad.lookback_partition, ad.lookback_partition_field_id, ad.lookback_aggregate, ad.lookback_aggregate_percentile, ad.lookback_aggregate_bucket_timedelta FROM algorithm_dependency ad
+`
+
+func (q *Queries) ReadAlgorithmDependencies(ctx context.Context) ([]AlgorithmDependency, error) {
+	rows, err := q.db.Query(ctx, readAlgorithmDependencies)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []AlgorithmExecutionPath
+	var items []AlgorithmDependency
 	for rows.Next() {
-		var i AlgorithmExecutionPath
+		var i AlgorithmDependency
 		if err := rows.Scan(
-			&i.FinalAlgoID,
-			&i.NumDependencies,
-			&i.AlgoIDPath,
-			&i.WindowTypeIDPath,
-			&i.ProcIDPath,
-			&i.LookbackCountPath,
-			&i.LookbackTimedeltaPath,
+			&i.ID,
+			&i.FromAlgorithmID,
+			&i.ToAlgorithmID,
+			&i.FromWindowTypeID,
+			&i.ToWindowTypeID,
+			&i.FromProcessorID,
+			&i.ToProcessorID,
+			&i.Created,
+			&i.LookbackCount,
+			&i.LookbackTimedelta,
+			&i.LookbackPartition,
+			&i.LookbackPartitionFieldID,
+			&i.LookbackAggregate,
+			&i.LookbackAggregatePercentile,
+			&i.LookbackAggregateBucketTimedelta,
 		); err != nil {
 			return nil, err
 		}
@@ -365,6 +730,7 @@ WITH processor_id AS (
   SELECT p.id FROM processor p
   WHERE p.name = $3
   AND p.runtime = $4
+  AND p.namespace = $5
 )
 SELECT a.id FROM algorithm a
 WHERE a.name = $1
@@ -373,10 +739,11 @@ AND a.processor_id = (SELECT id from processor_id)
 `
 
 type ReadAlgorithmIdParams struct {
-	AlgorithmName    string
-	AlgorithmVersion string
-	ProcessorName    string
-	ProcessorRuntime string
+	AlgorithmName      string
+	AlgorithmVersion   string
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
 }
 
 func (q *Queries) ReadAlgorithmId(ctx context.Context, arg ReadAlgorithmIdParams) (int64, error) {
@@ -385,12 +752,24 @@ func (q *Queries) ReadAlgorithmId(ctx context.Context, arg ReadAlgorithmIdParams
 		arg.AlgorithmVersion,
 		arg.ProcessorName,
 		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const readAlgorithmResultType = `-- name: ReadAlgorithmResultType :one
+SELECT result_type FROM algorithm WHERE id = $1
+`
+
+func (q *Queries) ReadAlgorithmResultType(ctx context.Context, algorithmID int64) (ResultType, error) {
+	row := q.db.QueryRow(ctx, readAlgorithmResultType, algorithmID)
+	var result_type ResultType
+	err := row.Scan(&result_type)
+	return result_type, err
+}
+
 const readAlgorithms = `-- name: ReadAlgorithms :many
 SELECT a.id, a.name, a.version, a.processor_id, a.window_type_id, a.result_type, a.created, a.description FROM algorithm a
 `
@@ -424,6 +803,49 @@ func (q *Queries) ReadAlgorithms(ctx context.Context) ([]Algorithm, error) {
 	return items, nil
 }
 
+const readAlgorithmsByIDs = `-- name: ReadAlgorithmsByIDs :many
+SELECT
+    a.id,
+    a.name,
+    a.version,
+    p.name AS processor_name
+FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.id = ANY($1::bigint[])
+`
+
+type ReadAlgorithmsByIDsRow struct {
+	ID            int64
+	Name          string
+	Version       string
+	ProcessorName string
+}
+
+func (q *Queries) ReadAlgorithmsByIDs(ctx context.Context, algorithmIds []int64) ([]ReadAlgorithmsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, readAlgorithmsByIDs, algorithmIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadAlgorithmsByIDsRow
+	for rows.Next() {
+		var i ReadAlgorithmsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.ProcessorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readAlgorithmsForProcessorId = `-- name: ReadAlgorithmsForProcessorId :many
 SELECT a.id, a.name, a.version, a.processor_id, a.window_type_id, a.result_type, a.created, a.description FROM algorithm a
 WHERE a.processor_id = $1
@@ -461,17 +883,19 @@ func (q *Queries) ReadAlgorithmsForProcessorId(ctx context.Context, processorID
 const readAlgorithmsForWindow = `-- name: ReadAlgorithmsForWindow :many
 SELECT a.id, a.name, a.version, a.processor_id, a.window_type_id, a.result_type, a.created, a.description FROM algorithm a
 JOIN window_type wt ON a.window_type_id = wt.id
-WHERE wt.name = $1 
-AND wt.version = $2
+WHERE wt.namespace = $1
+AND wt.name = $2 
+AND wt.version = $3
 `
 
 type ReadAlgorithmsForWindowParams struct {
-	WindowTypeName    string
-	WindowTypeVersion string
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
 }
 
 func (q *Queries) ReadAlgorithmsForWindow(ctx context.Context, arg ReadAlgorithmsForWindowParams) ([]Algorithm, error) {
-	rows, err := q.db.Query(ctx, readAlgorithmsForWindow, arg.WindowTypeName, arg.WindowTypeVersion)
+	rows, err := q.db.Query(ctx, readAlgorithmsForWindow, arg.WindowTypeNamespace, arg.WindowTypeName, arg.WindowTypeVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -507,15 +931,17 @@ WITH from_algo AS (
   AND a.version = $2
   AND p.name = $3
   AND p.runtime = $4
+  AND p.namespace = $5
 )
-SELECT ad.id, ad.from_algorithm_id, ad.to_algorithm_id, ad.from_window_type_id, ad.to_window_type_id, ad.from_processor_id, ad.to_processor_id, ad.created, ad.lookback_count, ad.lookback_timedelta FROM algorithm_dependency ad WHERE ad.from_algorithm_id = from_algo.id
+SELECT ad.id, ad.from_algorithm_id, ad.to_algorithm_id, ad.from_window_type_id, ad.to_window_type_id, ad.from_processor_id, ad.to_processor_id, ad.created, ad.lookback_count, ad.lookback_timedelta, ad.lookback_partition, ad.lookback_partition_field_id, ad.lookback_aggregate, ad.lookback_aggregate_percentile, ad.lookback_aggregate_bucket_timedelta FROM algorithm_dependency ad WHERE ad.from_algorithm_id = from_algo.id
 `
 
 type ReadFromAlgorithmDependenciesParams struct {
-	FromAlgorithmName    string
-	FromAlgorithmVersion string
-	FromProcessorName    string
-	FromProcessorRuntime string
+	FromAlgorithmName      string
+	FromAlgorithmVersion   string
+	FromProcessorName      string
+	FromProcessorRuntime   string
+	FromProcessorNamespace string
 }
 
 func (q *Queries) ReadFromAlgorithmDependencies(ctx context.Context, arg ReadFromAlgorithmDependenciesParams) ([]AlgorithmDependency, error) {
@@ -524,6 +950,7 @@ func (q *Queries) ReadFromAlgorithmDependencies(ctx context.Context, arg ReadFro
 		arg.FromAlgorithmVersion,
 		arg.FromProcessorName,
 		arg.FromProcessorRuntime,
+		arg.FromProcessorNamespace,
 	)
 	if err != nil {
 		return nil, err
@@ -543,6 +970,90 @@ func (q *Queries) ReadFromAlgorithmDependencies(ctx context.Context, arg ReadFro
 			&i.Created,
 			&i.LookbackCount,
 			&i.LookbackTimedelta,
+			&i.LookbackPartition,
+			&i.LookbackPartitionFieldID,
+			&i.LookbackAggregate,
+			&i.LookbackAggregatePercentile,
+			&i.LookbackAggregateBucketTimedelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readGrants = `-- name: ReadGrants :many
+SELECT role, scope FROM role_grants
+WHERE identity = $1
+ORDER BY role, scope
+`
+
+type ReadGrantsRow struct {
+	Role  string
+	Scope string
+}
+
+func (q *Queries) ReadGrants(ctx context.Context, identity string) ([]ReadGrantsRow, error) {
+	rows, err := q.db.Query(ctx, readGrants, identity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadGrantsRow
+	for rows.Next() {
+		var i ReadGrantsRow
+		if err := rows.Scan(&i.Role, &i.Scope); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readLookbackAggregatesForAlgorithms = `-- name: ReadLookbackAggregatesForAlgorithms :many
+SELECT
+    ad.from_algorithm_id,
+    ad.to_algorithm_id,
+    ad.lookback_aggregate,
+    ad.lookback_aggregate_percentile,
+    ad.lookback_aggregate_bucket_timedelta
+FROM
+    algorithm_dependency ad
+WHERE
+    ad.lookback_aggregate IS NOT NULL
+    AND ad.to_algorithm_id = ANY($1::BIGINT[])
+`
+
+type ReadLookbackAggregatesForAlgorithmsRow struct {
+	FromAlgorithmID                  int64
+	ToAlgorithmID                    int64
+	LookbackAggregate                NullLookbackAggregateFunction
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
+}
+
+func (q *Queries) ReadLookbackAggregatesForAlgorithms(ctx context.Context, algorithmIds []int64) ([]ReadLookbackAggregatesForAlgorithmsRow, error) {
+	rows, err := q.db.Query(ctx, readLookbackAggregatesForAlgorithms, algorithmIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadLookbackAggregatesForAlgorithmsRow
+	for rows.Next() {
+		var i ReadLookbackAggregatesForAlgorithmsRow
+		if err := rows.Scan(
+			&i.FromAlgorithmID,
+			&i.ToAlgorithmID,
+			&i.LookbackAggregate,
+			&i.LookbackAggregatePercentile,
+			&i.LookbackAggregateBucketTimedelta,
 		); err != nil {
 			return nil, err
 		}
@@ -584,14 +1095,16 @@ SELECT
     metadata_field_name,
     metadata_field_description
 FROM window_type_metadata_fields
-WHERE window_type_name = $1
-  AND window_type_version = $2
+WHERE window_type_namespace = $1
+  AND window_type_name = $2
+  AND window_type_version = $3
 ORDER BY metadata_field_name
 `
 
 type ReadMetadataFieldsByWindowTypeParams struct {
-	WindowTypeName    string
-	WindowTypeVersion string
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
 }
 
 type ReadMetadataFieldsByWindowTypeRow struct {
@@ -601,7 +1114,7 @@ type ReadMetadataFieldsByWindowTypeRow struct {
 }
 
 func (q *Queries) ReadMetadataFieldsByWindowType(ctx context.Context, arg ReadMetadataFieldsByWindowTypeParams) ([]ReadMetadataFieldsByWindowTypeRow, error) {
-	rows, err := q.db.Query(ctx, readMetadataFieldsByWindowType, arg.WindowTypeName, arg.WindowTypeVersion)
+	rows, err := q.db.Query(ctx, readMetadataFieldsByWindowType, arg.WindowTypeNamespace, arg.WindowTypeName, arg.WindowTypeVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -621,11 +1134,11 @@ func (q *Queries) ReadMetadataFieldsByWindowType(ctx context.Context, arg ReadMe
 }
 
 const readProcessorExcludeProject = `-- name: ReadProcessorExcludeProject :many
-SELECT id, name, runtime, connection_string, created, project_name FROM processor WHERE project_name != $1
+SELECT id, name, runtime, connection_string, created, namespace, registered_by, tls_server_name, tls_insecure_skip_verify FROM processor WHERE namespace != $1
 `
 
-func (q *Queries) ReadProcessorExcludeProject(ctx context.Context, projectName pgtype.Text) ([]Processor, error) {
-	rows, err := q.db.Query(ctx, readProcessorExcludeProject, projectName)
+func (q *Queries) ReadProcessorExcludeProject(ctx context.Context, namespace string) ([]Processor, error) {
+	rows, err := q.db.Query(ctx, readProcessorExcludeProject, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -639,7 +1152,10 @@ func (q *Queries) ReadProcessorExcludeProject(ctx context.Context, projectName p
 			&i.Runtime,
 			&i.ConnectionString,
 			&i.Created,
-			&i.ProjectName,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
 		); err != nil {
 			return nil, err
 		}
@@ -652,7 +1168,7 @@ func (q *Queries) ReadProcessorExcludeProject(ctx context.Context, projectName p
 }
 
 const readProcessors = `-- name: ReadProcessors :many
-SELECT id, name, runtime, connection_string, created, project_name FROM processor
+SELECT id, name, runtime, connection_string, created, namespace, registered_by, tls_server_name, tls_insecure_skip_verify FROM processor
 `
 
 func (q *Queries) ReadProcessors(ctx context.Context) ([]Processor, error) {
@@ -670,7 +1186,10 @@ func (q *Queries) ReadProcessors(ctx context.Context) ([]Processor, error) {
 			&i.Runtime,
 			&i.ConnectionString,
 			&i.Created,
-			&i.ProjectName,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
 		); err != nil {
 			return nil, err
 		}
@@ -683,7 +1202,7 @@ func (q *Queries) ReadProcessors(ctx context.Context) ([]Processor, error) {
 }
 
 const readProcessorsByIDs = `-- name: ReadProcessorsByIDs :many
-SELECT id, name, runtime, connection_string, created, project_name
+SELECT id, name, runtime, connection_string, created, namespace, registered_by, tls_server_name, tls_insecure_skip_verify
 FROM processor
 WHERE id = ANY($1::bigint[])
 ORDER BY name, runtime
@@ -704,7 +1223,10 @@ func (q *Queries) ReadProcessorsByIDs(ctx context.Context, processorIds []int64)
 			&i.Runtime,
 			&i.ConnectionString,
 			&i.Created,
-			&i.ProjectName,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
 		); err != nil {
 			return nil, err
 		}
@@ -716,40 +1238,251 @@ func (q *Queries) ReadProcessorsByIDs(ctx context.Context, processorIds []int64)
 	return items, nil
 }
 
-const readResultsForAlgorithmByCount = `-- name: ReadResultsForAlgorithmByCount :many
+const readProcessorsInNamespace = `-- name: ReadProcessorsInNamespace :many
+SELECT id, name, runtime, connection_string, created, namespace, registered_by, tls_server_name, tls_insecure_skip_verify FROM processor WHERE namespace = $1
+`
+
+func (q *Queries) ReadProcessorsInNamespace(ctx context.Context, namespace string) ([]Processor, error) {
+	rows, err := q.db.Query(ctx, readProcessorsInNamespace, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Processor
+	for rows.Next() {
+		var i Processor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Runtime,
+			&i.ConnectionString,
+			&i.Created,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readRegistryVersion = `-- name: ReadRegistryVersion :one
+SELECT version FROM registry_version
+`
+
+func (q *Queries) ReadRegistryVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, readRegistryVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const readResultAggregatesForLookbacks = `-- name: ReadResultAggregatesForLookbacks :many
+WITH lookbacks AS (
+    -- parallel arrays are unnested together, row by row
+    SELECT
+        unnest($1::INT[]) AS lookback_id,
+        unnest($2::BIGINT[]) AS algorithm_id,
+        unnest($3::INT[]) AS count,
+        unnest($4::TIMESTAMP[]) AS search_from,
+        unnest($5::TIMESTAMP[]) AS search_to,
+        unnest($6::TEXT[]) AS origin,
+        unnest($7::BOOLEAN[]) AS by_origin,
+        unnest($8::TEXT[]) AS metadata,
+        unnest($9::FLOAT8[]) AS percentile,
+        unnest($10::FLOAT8[]) AS bucket_seconds
+),
+lookback_results AS (
+    SELECT
+        l.lookback_id,
+        l.percentile,
+        res.result_value,
+        res.time_from,
+        res.time_to,
+        CASE WHEN l.bucket_seconds > 0 THEN
+            date_bin(make_interval(secs => l.bucket_seconds), res.time_to, TIMESTAMP 'epoch')
+        END AS bucket
+    FROM
+        lookbacks l
+    CROSS JOIN LATERAL (
+        SELECT
+            r.result_value,
+            w.time_from,
+            w.time_to
+        FROM
+            results r
+        JOIN windows w ON w.id = r.windows_id
+        WHERE
+            r.algorithm_id = l.algorithm_id
+            AND (l.search_from IS NULL OR w.time_from > l.search_from)
+            AND w.time_to < l.search_to
+            AND (NOT l.by_origin OR w.origin = l.origin)
+            AND (NULLIF(l.metadata, '') IS NULL OR w.metadata @> l.metadata::JSONB)
+        -- count lookbacks are of the latest past results
+        ORDER BY w.time_to DESC
+        LIMIT NULLIF(l.count, 0)
+    ) res
+)
 SELECT
-	r.id as result_id,
-    r.algorithm_id,
-    w.id as window_id,
-    r.result_value, 
-    r.result_array,
-    r.result_json,
-    wt.name as window_type_name, 
-    wt.version as window_type_version,
-    w.time_from as window_time_from,
-    w.time_to as window_time_to,
-    w.origin as window_origin,
-    w.metadata as window_metadata
+    lookback_id::INT AS lookback_id,
+    MIN(time_from)::TIMESTAMP AS time_from,
+    MAX(time_to)::TIMESTAMP AS time_to,
+    COUNT(*) AS result_count,
+    COALESCE(SUM(result_value), 0)::FLOAT8 AS sum_value,
+    COALESCE(AVG(result_value), 0)::FLOAT8 AS mean_value,
+    COALESCE(MIN(result_value), 0)::FLOAT8 AS min_value,
+    COALESCE(MAX(result_value), 0)::FLOAT8 AS max_value,
+    COALESCE((ARRAY_AGG(result_value ORDER BY time_to DESC))[1], 0)::FLOAT8 AS last_value,
+    COALESCE(PERCENTILE_CONT(percentile) WITHIN GROUP (ORDER BY result_value), 0)::FLOAT8 AS percentile_value
 FROM
-	results r
-JOIN windows w on
-	w.id = r.windows_id
-JOIN window_type wt on wt.id = w.window_type_id
-WHERE
-	r.algorithm_id = $1
-    AND w.time_to < $2
-ORDER by time_from,time_to desc LIMIT $3
+    lookback_results
+GROUP BY lookback_id, percentile, bucket
+ORDER BY lookback_id, MIN(time_from)
+`
+
+type ReadResultAggregatesForLookbacksParams struct {
+	LookbackIds   []int32
+	AlgorithmIds  []int64
+	Counts        []int32
+	SearchFroms   []pgtype.Timestamp
+	SearchTos     []pgtype.Timestamp
+	Origins       []string
+	ByOrigins     []bool
+	Metadata      []string
+	Percentiles   []float64
+	BucketSeconds []float64
+}
+
+type ReadResultAggregatesForLookbacksRow struct {
+	LookbackID      int32
+	TimeFrom        pgtype.Timestamp
+	TimeTo          pgtype.Timestamp
+	ResultCount     int64
+	SumValue        float64
+	MeanValue       float64
+	MinValue        float64
+	MaxValue        float64
+	LastValue       float64
+	PercentileValue float64
+}
+
+// lookbacks are given as parallel arrays, as for ReadResultsForLookbacks.
+// Results are bucketed by window end time when bucket_seconds is positive
+func (q *Queries) ReadResultAggregatesForLookbacks(ctx context.Context, arg ReadResultAggregatesForLookbacksParams) ([]ReadResultAggregatesForLookbacksRow, error) {
+	rows, err := q.db.Query(ctx, readResultAggregatesForLookbacks,
+		arg.LookbackIds,
+		arg.AlgorithmIds,
+		arg.Counts,
+		arg.SearchFroms,
+		arg.SearchTos,
+		arg.Origins,
+		arg.ByOrigins,
+		arg.Metadata,
+		arg.Percentiles,
+		arg.BucketSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadResultAggregatesForLookbacksRow
+	for rows.Next() {
+		var i ReadResultAggregatesForLookbacksRow
+		if err := rows.Scan(
+			&i.LookbackID,
+			&i.TimeFrom,
+			&i.TimeTo,
+			&i.ResultCount,
+			&i.SumValue,
+			&i.MeanValue,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.LastValue,
+			&i.PercentileValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readResultsForLookbacks = `-- name: ReadResultsForLookbacks :many
+WITH lookbacks AS (
+    -- parallel arrays are unnested together, row by row
+    SELECT
+        unnest($1::INT[]) AS lookback_id,
+        unnest($2::BIGINT[]) AS algorithm_id,
+        unnest($3::INT[]) AS count,
+        unnest($4::TIMESTAMP[]) AS search_from,
+        unnest($5::TIMESTAMP[]) AS search_to,
+        unnest($6::TEXT[]) AS origin,
+        unnest($7::BOOLEAN[]) AS by_origin,
+        unnest($8::TEXT[]) AS metadata
+)
+SELECT
+    l.lookback_id::INT AS lookback_id,
+    res.result_id, res.algorithm_id, res.result_type, res.window_id, res.result_value, res.result_array, res.result_json, res.window_type_name, res.window_type_version, res.window_time_from, res.window_time_to, res.window_origin, res.window_metadata
+FROM
+    lookbacks l
+CROSS JOIN LATERAL (
+    SELECT
+        r.id AS result_id,
+        r.algorithm_id,
+        a.result_type,
+        w.id AS window_id,
+        r.result_value,
+        r.result_array,
+        r.result_json,
+        wt.name AS window_type_name,
+        wt.version AS window_type_version,
+        w.time_from AS window_time_from,
+        w.time_to AS window_time_to,
+        w.origin AS window_origin,
+        w.metadata AS window_metadata
+    FROM
+        results r
+    JOIN algorithm a ON a.id = r.algorithm_id
+    JOIN windows w ON w.id = r.windows_id
+    JOIN window_type wt ON wt.id = w.window_type_id
+    WHERE
+        r.algorithm_id = l.algorithm_id
+        AND (l.search_from IS NULL OR w.time_from > l.search_from)
+        AND w.time_to < l.search_to
+        AND (NOT l.by_origin OR w.origin = l.origin)
+        AND (NULLIF(l.metadata, '') IS NULL OR w.metadata @> l.metadata::JSONB)
+    -- count lookbacks are of the latest past results
+    ORDER BY w.time_to DESC
+    LIMIT NULLIF(l.count, 0)
+) res
+ORDER BY l.lookback_id, res.window_time_from, res.window_time_to
 `
 
-type ReadResultsForAlgorithmByCountParams struct {
-	AlgorithmID pgtype.Int8
-	SearchTo    pgtype.Timestamp
-	Count       int32
+type ReadResultsForLookbacksParams struct {
+	LookbackIds  []int32
+	AlgorithmIds []int64
+	Counts       []int32
+	SearchFroms  []pgtype.Timestamp
+	SearchTos    []pgtype.Timestamp
+	Origins      []string
+	ByOrigins    []bool
+	Metadata     []string
 }
 
-type ReadResultsForAlgorithmByCountRow struct {
+type ReadResultsForLookbacksRow struct {
+	LookbackID        int32
 	ResultID          int64
 	AlgorithmID       pgtype.Int8
+	ResultType        ResultType
 	WindowID          int64
 	ResultValue       pgtype.Float8
 	ResultArray       []float64
@@ -762,18 +1495,33 @@ type ReadResultsForAlgorithmByCountRow struct {
 	WindowMetadata    []byte
 }
 
-func (q *Queries) ReadResultsForAlgorithmByCount(ctx context.Context, arg ReadResultsForAlgorithmByCountParams) ([]ReadResultsForAlgorithmByCountRow, error) {
-	rows, err := q.db.Query(ctx, readResultsForAlgorithmByCount, arg.AlgorithmID, arg.SearchTo, arg.Count)
+// lookbacks are given as parallel arrays, one element per lookback. A count
+// of 0 does not limit the lookback, a NULL search_from does not bound it, a
+// false by_origin does not partition it by origin, and an empty metadata does
+// not partition it by metadata
+func (q *Queries) ReadResultsForLookbacks(ctx context.Context, arg ReadResultsForLookbacksParams) ([]ReadResultsForLookbacksRow, error) {
+	rows, err := q.db.Query(ctx, readResultsForLookbacks,
+		arg.LookbackIds,
+		arg.AlgorithmIds,
+		arg.Counts,
+		arg.SearchFroms,
+		arg.SearchTos,
+		arg.Origins,
+		arg.ByOrigins,
+		arg.Metadata,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ReadResultsForAlgorithmByCountRow
+	var items []ReadResultsForLookbacksRow
 	for rows.Next() {
-		var i ReadResultsForAlgorithmByCountRow
+		var i ReadResultsForLookbacksRow
 		if err := rows.Scan(
+			&i.LookbackID,
 			&i.ResultID,
 			&i.AlgorithmID,
+			&i.ResultType,
 			&i.WindowID,
 			&i.ResultValue,
 			&i.ResultArray,
@@ -795,75 +1543,208 @@ func (q *Queries) ReadResultsForAlgorithmByCount(ctx context.Context, arg ReadRe
 	return items, nil
 }
 
-const readResultsForAlgorithmByTimedelta = `-- name: ReadResultsForAlgorithmByTimedelta :many
+const readWindowRollupProgressForUpdate = `-- name: ReadWindowRollupProgressForUpdate :one
+SELECT wrp.id, wrp.window_type_id, wrp.origin, wrp.metadata, wrp.time_from, wrp.time_to, wrp.child_windows, wrp.emitted FROM window_rollup_progress wrp
+WHERE
+    wrp.window_type_id = $1
+    AND wrp.origin = $2
+    AND wrp.metadata = $3
+    AND wrp.time_from = $4
+FOR UPDATE
+`
+
+type ReadWindowRollupProgressForUpdateParams struct {
+	WindowTypeID int64
+	Origin       string
+	Metadata     []byte
+	TimeFrom     pgtype.Timestamp
+}
+
+func (q *Queries) ReadWindowRollupProgressForUpdate(ctx context.Context, arg ReadWindowRollupProgressForUpdateParams) (WindowRollupProgress, error) {
+	row := q.db.QueryRow(ctx, readWindowRollupProgressForUpdate,
+		arg.WindowTypeID,
+		arg.Origin,
+		arg.Metadata,
+		arg.TimeFrom,
+	)
+	var i WindowRollupProgress
+	err := row.Scan(
+		&i.ID,
+		&i.WindowTypeID,
+		&i.Origin,
+		&i.Metadata,
+		&i.TimeFrom,
+		&i.TimeTo,
+		&i.ChildWindows,
+		&i.Emitted,
+	)
+	return i, err
+}
+
+const readWindowRollups = `-- name: ReadWindowRollups :many
+SELECT wr.window_type_id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy, wr.created FROM window_rollup wr
+`
+
+func (q *Queries) ReadWindowRollups(ctx context.Context) ([]WindowRollup, error) {
+	rows, err := q.db.Query(ctx, readWindowRollups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WindowRollup
+	for rows.Next() {
+		var i WindowRollup
+		if err := rows.Scan(
+			&i.WindowTypeID,
+			&i.ChildWindowTypeName,
+			&i.ChildWindowTypeVersion,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.ChildCount,
+			&i.LateWindowPolicy,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowRollupsForChild = `-- name: ReadWindowRollupsForChild :many
 SELECT
-	r.id as result_id,
-    r.algorithm_id,
-    w.id as window_id,
-    r.result_value, 
-    r.result_array,
-    r.result_json,
-    wt.name as window_type_name, 
-    wt.version as window_type_version,
-    w.time_from as window_time_from,
-    w.time_to as window_time_to,
-    w.origin as window_origin,
-    w.metadata as window_metadata
+    wr.window_type_id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy, wr.created,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
 FROM
-	results r
-JOIN windows w ON
-	w.id = r.windows_id
-JOIN window_type wt on wt.id = w.window_type_id
+    window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
 WHERE
-	r.algorithm_id = $1
-    AND w.time_from > $2
-    AND w.time_to  < $3
-order by time_from, time_to desc
+    wt.namespace = $1
+    AND wr.child_window_type_name = $2
+    AND wr.child_window_type_version = $3
 `
 
-type ReadResultsForAlgorithmByTimedeltaParams struct {
-	AlgorithmID pgtype.Int8
-	SearchFrom  pgtype.Timestamp
-	SearchTo    pgtype.Timestamp
+type ReadWindowRollupsForChildParams struct {
+	WindowTypeNamespace    string
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
 }
 
-type ReadResultsForAlgorithmByTimedeltaRow struct {
-	ResultID          int64
-	AlgorithmID       pgtype.Int8
-	WindowID          int64
-	ResultValue       pgtype.Float8
-	ResultArray       []float64
-	ResultJson        []byte
-	WindowTypeName    string
-	WindowTypeVersion string
-	WindowTimeFrom    pgtype.Timestamp
-	WindowTimeTo      pgtype.Timestamp
-	WindowOrigin      string
-	WindowMetadata    []byte
+type ReadWindowRollupsForChildRow struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int32
+	LateWindowPolicy       LateWindowPolicy
+	Created                pgtype.Timestamp
+	WindowTypeNamespace    string
+	WindowTypeName         string
+	WindowTypeVersion      string
 }
 
-func (q *Queries) ReadResultsForAlgorithmByTimedelta(ctx context.Context, arg ReadResultsForAlgorithmByTimedeltaParams) ([]ReadResultsForAlgorithmByTimedeltaRow, error) {
-	rows, err := q.db.Query(ctx, readResultsForAlgorithmByTimedelta, arg.AlgorithmID, arg.SearchFrom, arg.SearchTo)
+func (q *Queries) ReadWindowRollupsForChild(ctx context.Context, arg ReadWindowRollupsForChildParams) ([]ReadWindowRollupsForChildRow, error) {
+	rows, err := q.db.Query(ctx, readWindowRollupsForChild, arg.WindowTypeNamespace, arg.ChildWindowTypeName, arg.ChildWindowTypeVersion)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ReadResultsForAlgorithmByTimedeltaRow
+	var items []ReadWindowRollupsForChildRow
 	for rows.Next() {
-		var i ReadResultsForAlgorithmByTimedeltaRow
+		var i ReadWindowRollupsForChildRow
 		if err := rows.Scan(
-			&i.ResultID,
-			&i.AlgorithmID,
-			&i.WindowID,
-			&i.ResultValue,
-			&i.ResultArray,
-			&i.ResultJson,
+			&i.WindowTypeID,
+			&i.ChildWindowTypeName,
+			&i.ChildWindowTypeVersion,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.ChildCount,
+			&i.LateWindowPolicy,
+			&i.Created,
+			&i.WindowTypeNamespace,
+			&i.WindowTypeName,
+			&i.WindowTypeVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowScheduleHighWaterMarkForUpdate = `-- name: ReadWindowScheduleHighWaterMarkForUpdate :one
+SELECT high_water_mark FROM window_schedule
+WHERE window_type_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) ReadWindowScheduleHighWaterMarkForUpdate(ctx context.Context, windowTypeID int64) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, readWindowScheduleHighWaterMarkForUpdate, windowTypeID)
+	var high_water_mark pgtype.Timestamp
+	err := row.Scan(&high_water_mark)
+	return high_water_mark, err
+}
+
+const readWindowSchedules = `-- name: ReadWindowSchedules :many
+SELECT
+    ws.window_type_id, ws.cron, ws.interval_ns, ws.alignment_ns, ws.time_zone, ws.origin, ws.catch_up, ws.high_water_mark, ws.created,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id
+`
+
+type ReadWindowSchedulesRow struct {
+	WindowTypeID        int64
+	Cron                pgtype.Text
+	IntervalNs          int64
+	AlignmentNs         int64
+	TimeZone            string
+	Origin              string
+	CatchUp             bool
+	HighWaterMark       pgtype.Timestamp
+	Created             pgtype.Timestamp
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
+}
+
+func (q *Queries) ReadWindowSchedules(ctx context.Context) ([]ReadWindowSchedulesRow, error) {
+	rows, err := q.db.Query(ctx, readWindowSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadWindowSchedulesRow
+	for rows.Next() {
+		var i ReadWindowSchedulesRow
+		if err := rows.Scan(
+			&i.WindowTypeID,
+			&i.Cron,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.Origin,
+			&i.CatchUp,
+			&i.HighWaterMark,
+			&i.Created,
+			&i.WindowTypeNamespace,
 			&i.WindowTypeName,
 			&i.WindowTypeVersion,
-			&i.WindowTimeFrom,
-			&i.WindowTimeTo,
-			&i.WindowOrigin,
-			&i.WindowMetadata,
 		); err != nil {
 			return nil, err
 		}
@@ -876,7 +1757,7 @@ func (q *Queries) ReadResultsForAlgorithmByTimedelta(ctx context.Context, arg Re
 }
 
 const readWindowTypeMetadataFields = `-- name: ReadWindowTypeMetadataFields :many
-SELECT window_type_name, window_type_version, metadata_field_id, metadata_field_name, metadata_field_description FROM window_type_metadata_fields
+SELECT window_type_namespace, window_type_name, window_type_version, metadata_field_id, metadata_field_name, metadata_field_description FROM window_type_metadata_fields
 `
 
 func (q *Queries) ReadWindowTypeMetadataFields(ctx context.Context) ([]WindowTypeMetadataField, error) {
@@ -889,6 +1770,7 @@ func (q *Queries) ReadWindowTypeMetadataFields(ctx context.Context) ([]WindowTyp
 	for rows.Next() {
 		var i WindowTypeMetadataField
 		if err := rows.Scan(
+			&i.WindowTypeNamespace,
 			&i.WindowTypeName,
 			&i.WindowTypeVersion,
 			&i.MetadataFieldID,
@@ -905,8 +1787,40 @@ func (q *Queries) ReadWindowTypeMetadataFields(ctx context.Context) ([]WindowTyp
 	return items, nil
 }
 
+const readWindowTypeNamespaces = `-- name: ReadWindowTypeNamespaces :many
+SELECT wt.namespace FROM window_type wt
+WHERE wt.name = $1
+AND wt.version = $2
+ORDER BY wt.namespace
+`
+
+type ReadWindowTypeNamespacesParams struct {
+	WindowTypeName    string
+	WindowTypeVersion string
+}
+
+func (q *Queries) ReadWindowTypeNamespaces(ctx context.Context, arg ReadWindowTypeNamespacesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, readWindowTypeNamespaces, arg.WindowTypeName, arg.WindowTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, err
+		}
+		items = append(items, namespace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readWindowTypes = `-- name: ReadWindowTypes :many
-SELECT wt.id, wt.name, wt.version, wt.description, wt.created FROM window_type wt
+SELECT wt.id, wt.name, wt.version, wt.description, wt.created, wt.namespace FROM window_type wt
 `
 
 func (q *Queries) ReadWindowTypes(ctx context.Context) ([]WindowType, error) {
@@ -924,6 +1838,7 @@ func (q *Queries) ReadWindowTypes(ctx context.Context) ([]WindowType, error) {
 			&i.Version,
 			&i.Description,
 			&i.Created,
+			&i.Namespace,
 		); err != nil {
 			return nil, err
 		}
@@ -935,34 +1850,54 @@ func (q *Queries) ReadWindowTypes(ctx context.Context) ([]WindowType, error) {
 	return items, nil
 }
 
+const refreshAlgorithmExecutionPaths = `-- name: RefreshAlgorithmExecutionPaths :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY algorithm_execution_paths
+`
+
+func (q *Queries) RefreshAlgorithmExecutionPaths(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, refreshAlgorithmExecutionPaths)
+	return err
+}
+
 const registerWindow = `-- name: RegisterWindow :one
 WITH window_type_id AS (
-  SELECT id FROM window_type 
-  WHERE name = $5 
-  AND version = $6
+  SELECT wt.id FROM window_type wt
+  WHERE wt.namespace = $8
+  AND wt.name = $9 
+  AND wt.version = $10
 )
 INSERT INTO windows (
   window_type_id,
   time_from, 
   time_to,
   origin, 
-  metadata
+  metadata,
+  trace_id,
+  created_by,
+  namespace
 ) VALUES (
   (SELECT id FROM window_type_id),
   $1,
   $2,
   $3,
-  $4
+  $4,
+  $5,
+  $6,
+  $7
 ) RETURNING window_type_id, id
 `
 
 type RegisterWindowParams struct {
-	TimeFrom          pgtype.Timestamp
-	TimeTo            pgtype.Timestamp
-	Origin            string
-	Metadata          []byte
-	WindowTypeName    string
-	WindowTypeVersion string
+	TimeFrom            pgtype.Timestamp
+	TimeTo              pgtype.Timestamp
+	Origin              string
+	Metadata            []byte
+	TraceID             pgtype.Text
+	CreatedBy           pgtype.Text
+	Namespace           pgtype.Text
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
 }
 
 type RegisterWindowRow struct {
@@ -976,6 +1911,10 @@ func (q *Queries) RegisterWindow(ctx context.Context, arg RegisterWindowParams)
 		arg.TimeTo,
 		arg.Origin,
 		arg.Metadata,
+		arg.TraceID,
+		arg.CreatedBy,
+		arg.Namespace,
+		arg.WindowTypeNamespace,
 		arg.WindowTypeName,
 		arg.WindowTypeVersion,
 	)
@@ -983,3 +1922,62 @@ func (q *Queries) RegisterWindow(ctx context.Context, arg RegisterWindowParams)
 	err := row.Scan(&i.WindowTypeID, &i.ID)
 	return i, err
 }
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked = CURRENT_TIMESTAMP
+WHERE name = $1 AND revoked IS NULL
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const tryAdvisoryLock = `-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock($1::BIGINT)::BOOLEAN AS locked
+`
+
+func (q *Queries) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	row := q.db.QueryRow(ctx, tryAdvisoryLock, key)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
+
+const updateWindowRollupProgress = `-- name: UpdateWindowRollupProgress :exec
+UPDATE window_rollup_progress
+SET
+    child_windows = $1,
+    emitted = $2
+WHERE id = $3
+`
+
+type UpdateWindowRollupProgressParams struct {
+	ChildWindows []pgtype.Timestamp
+	Emitted      pgtype.Timestamp
+	ID           int64
+}
+
+func (q *Queries) UpdateWindowRollupProgress(ctx context.Context, arg UpdateWindowRollupProgressParams) error {
+	_, err := q.db.Exec(ctx, updateWindowRollupProgress, arg.ChildWindows, arg.Emitted, arg.ID)
+	return err
+}
+
+const updateWindowScheduleHighWaterMark = `-- name: UpdateWindowScheduleHighWaterMark :exec
+UPDATE window_schedule SET high_water_mark = $1
+WHERE window_type_id = $2
+`
+
+type UpdateWindowScheduleHighWaterMarkParams struct {
+	HighWaterMark pgtype.Timestamp
+	WindowTypeID  int64
+}
+
+func (q *Queries) UpdateWindowScheduleHighWaterMark(ctx context.Context, arg UpdateWindowScheduleHighWaterMarkParams) error {
+	_, err := q.db.Exec(ctx, updateWindowScheduleHighWaterMark, arg.HighWaterMark, arg.WindowTypeID)
+	return err
+}
@@ -2,513 +2,408 @@ package postgresql
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log/slog"
-	"net"
-	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/orca-telemetry/core/internal/dag"
 
-	"github.com/orca-telemetry/core/internal/envs"
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/metrics"
 	pb "github.com/orca-telemetry/core/protobufs/go"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// processorFilter reports whether a processor is one of the processors, so
+// that only the algorithms of a namespace are planned
+func processorFilter(processors []Processor) func(procId int64) bool {
+	ids := make(map[int64]bool, len(processors))
+	for _, proc := range processors {
+		ids[proc.ID] = true
+	}
+	return func(procId int64) bool {
+		return ids[procId]
+	}
+}
+
+// dispatchProcessor converts a stored processor to the processor tasks are
+// dispatched to
+func dispatchProcessor(proc Processor) dispatch.Processor {
+	return dispatch.Processor{
+		ID:                    proc.ID,
+		Name:                  proc.Name,
+		Runtime:               proc.Runtime,
+		Namespace:             proc.Namespace,
+		ConnectionString:      proc.ConnectionString,
+		TLSServerName:         proc.TlsServerName.String,
+		TLSInsecureSkipVerify: proc.TlsInsecureSkipVerify,
+	}
+}
+
+// processWindow runs the algorithms triggered by a stored window, and then
+// counts it towards the rollups it is a child of
+func processWindow(
+	ctx context.Context,
+	d *Datalayer,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	insertedWindow RegisterWindowRow,
+) {
+	dispatch.ProcessWindow(ctx, executionPlan, window, insertedWindow.ID, &processedWindow{
+		d:              d,
+		window:         window,
+		insertedWindow: insertedWindow,
+	})
+}
+
+// processedWindow is a stored window being processed
+type processedWindow struct {
+	d              *Datalayer
+	window         *pb.Window
+	insertedWindow RegisterWindowRow
+}
+
+func (w *processedWindow) RunPlan(ctx context.Context, executionPlan dag.Plan) error {
+	return processTasks(ctx, w.d, executionPlan, w.window, w.insertedWindow)
+}
+
+func (w *processedWindow) Rollup(ctx context.Context) {
+	// rolled up window types are those of the namespace of the child
+	rollups, err := w.d.queries.ReadWindowRollupsForChild(ctx, ReadWindowRollupsForChildParams{
+		WindowTypeNamespace:    types.Namespace(w.window.GetNamespace()),
+		ChildWindowTypeName:    w.window.GetWindowTypeName(),
+		ChildWindowTypeVersion: w.window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "could not read window rollups", "error", err)
+		return
+	}
+	for _, rollup := range rollups {
+		if err := w.d.rollupWindow(ctx, rollup, w.window); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"could not roll up window",
+				"rolled_up_window_type",
+				rollup.WindowTypeName,
+				"error",
+				err,
+			)
+		}
+	}
+}
+
 func processTasks(
+	ctx context.Context,
 	d *Datalayer,
 	executionPlan dag.Plan,
 	window *pb.Window,
 	insertedWindow RegisterWindowRow,
 ) error {
-	ctx := context.Background()
-	slog.Info("calculated execution paths", "execution_paths", executionPlan)
 	// get map of processors from processor ids
-	processorMap := make(
-		map[int64]Processor,
-		len(executionPlan.AffectedProcessors),
-	)
 	processors, err := d.queries.ReadProcessorsByIDs(ctx, executionPlan.AffectedProcessors)
 	if err != nil {
-		slog.Error("Processors could not be read", "error", err)
+		slog.ErrorContext(ctx, "Processors could not be read", "error", err)
 		return err
 	}
-
+	processorMap := make(map[int64]dispatch.Processor, len(processors))
 	for _, proc := range processors {
-		processorMap[proc.ID] = proc
+		processorMap[proc.ID] = dispatchProcessor(proc)
 	}
 
 	// get map of algorithms from algorithm ids
-	algorithmMap := make(
-		map[int64]Algorithm,
-	)
+	algorithms, err := d.queries.ReadAlgorithmsForWindow(ctx, ReadAlgorithmsForWindowParams{
+		WindowTypeNamespace: types.Namespace(window.GetNamespace()),
+		WindowTypeName:      window.WindowTypeName,
+		WindowTypeVersion:   window.WindowTypeVersion,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "algorithms could not be read", "error", err)
+		return err
+	}
+	algorithmMap := make(map[int64]dispatch.Algorithm, len(algorithms))
+	for _, algo := range algorithms {
+		algorithmMap[algo.ID] = dispatch.Algorithm{ID: algo.ID, Name: algo.Name, Version: algo.Version}
+	}
+
+	// names of the metadata fields that lookbacks are partitioned on
+	metadataFieldNames := make(map[int64]string)
+	if executionPlan.HasMetadataPartition() {
+		metadataFields, err := d.queries.ReadMetadataFields(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "metadata fields could not be read", "error", err)
+			return err
+		}
+		for _, field := range metadataFields {
+			metadataFieldNames[field.ID] = field.Name
+		}
+	}
+
+	// lookback aggregates requested by the algorithms, keyed by dependency
+	algorithmIds := make([]int64, 0, len(algorithmMap))
+	for algoId := range algorithmMap {
+		algorithmIds = append(algorithmIds, algoId)
+	}
+	lookbackAggregates, err := d.queries.ReadLookbackAggregatesForAlgorithms(ctx, algorithmIds)
+	if err != nil {
+		slog.ErrorContext(ctx, "lookback aggregates could not be read", "error", err)
+		return err
+	}
+	lookbackAggregateMap := make(map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow, len(lookbackAggregates))
+	for _, agg := range lookbackAggregates {
+		lookbackAggregateMap[dispatch.LookbackKey{DependencyID: agg.FromAlgorithmID, DependantID: agg.ToAlgorithmID}] = agg
+	}
 
-	// map of algorithm Ids to results
-	resultMap := make(
-		map[int64]*pb.ExecutionResult,
+	return d.dispatcher.Run(ctx, executionPlan, window, processorMap, algorithmMap, &windowStore{
+		d:                  d,
+		window:             window,
+		insertedWindow:     insertedWindow,
+		metadataFieldNames: metadataFieldNames,
+		aggregates:         lookbackAggregateMap,
+	})
+}
+
+// windowStore reads the past results, and stores the results, of the
+// algorithms a window triggers
+type windowStore struct {
+	d                  *Datalayer
+	window             *pb.Window
+	insertedWindow     RegisterWindowRow
+	metadataFieldNames map[int64]string
+	aggregates         map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow
+}
+
+func (s *windowStore) ReadLookbacks(ctx context.Context, stage dag.Stage) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	return readStageLookbacks(ctx, s.d, stage, s.window, s.metadataFieldNames, s.aggregates)
+}
+
+func (s *windowStore) CreateResult(ctx context.Context, algo dispatch.Algorithm, result *pb.ExecutionResult) (int64, error) {
+	structResult, err := protojson.Marshal(
+		result.AlgorithmResult.Result.GetStructValue(),
 	)
+	if err != nil {
+		return 0, fmt.Errorf("could not convert algorithm struct result to bytes: %w", err)
+	}
 
-	// map of execution IDs and the algorithms requested
-	algorithms, err := d.queries.ReadAlgorithmsForWindow(ctx, ReadAlgorithmsForWindowParams{
-		WindowTypeName:    window.WindowTypeName,
-		WindowTypeVersion: window.WindowTypeVersion,
+	return s.d.queries.CreateResult(ctx, CreateResultParams{
+		WindowsID:    pgtype.Int8{Valid: true, Int64: s.insertedWindow.ID},
+		WindowTypeID: pgtype.Int8{Valid: true, Int64: s.insertedWindow.WindowTypeID},
+		AlgorithmID:  pgtype.Int8{Valid: true, Int64: algo.ID},
+		ResultValue: pgtype.Float8{
+			Valid:   true,
+			Float64: float64(result.AlgorithmResult.Result.GetSingleValue()),
+		},
+		ResultArray: dispatch.Float64s(
+			result.AlgorithmResult.Result.GetFloatValues().GetValues(),
+		),
+		ResultJson: structResult,
+		TraceID:    traceIDText(ctx),
 	})
+}
 
-	for _, algo := range algorithms {
-		algorithmMap[algo.ID] = algo
+// lookbackPartitionFilter builds the origin and metadata filters of the
+// results queries that restrict a lookback to windows in its partition.
+// Unset filters are passed as NULL so the results queries ignore them
+func lookbackPartitionFilter(partition dispatch.Partition) (pgtype.Text, []byte, error) {
+	origin := pgtype.Text{String: partition.Origin, Valid: partition.ByOrigin}
+	if partition.Field == "" {
+		return origin, nil, nil
 	}
-	// get the environment
-	config := envs.GetConfig()
-
-	// for each stage, build processsings
-	slog.Debug("execution plan", "executionPlan", executionPlan)
-	for _, stage := range executionPlan.Stages {
-		for _, task := range stage.Tasks {
-			proc, ok := processorMap[task.ProcId]
-			if !ok {
-				slog.Error("Processor not found for task", "proc_id", task.ProcId)
-				return fmt.Errorf("processor ID %d not found", task.ProcId)
-			}
-			var conn *grpc.ClientConn
-
-			if config.IsProduction {
-				host, _, err := net.SplitHostPort(proc.ConnectionString)
-				if err != nil {
-					host = proc.ConnectionString
-				}
-				conn, err = grpc.NewClient(
-					proc.ConnectionString,
-					grpc.WithTransportCredentials(
-						credentials.NewTLS(
-							&tls.Config{
-								ServerName: host,
-							},
-						),
-					),
-				)
-			} else {
-				conn, err = grpc.NewClient(
-					proc.ConnectionString,
-					grpc.WithTransportCredentials(insecure.NewCredentials()),
-				)
-			}
-			if err != nil {
-				slog.Error("could not connect to processor", "proc_id", task.ProcId, "error", err)
-				return fmt.Errorf("could not contact processor: %w", err)
-			}
-			// WARN: close conn when done (not deferred inside a loop)
-			defer func(conn *grpc.ClientConn) {
-				if err := conn.Close(); err != nil {
-					slog.Warn("error closing gRPC connection", "error", err)
-				}
-			}(conn)
-
-			client := pb.NewOrcaProcessorClient(conn)
-			healthCheckResponse, err := client.HealthCheck(ctx, &pb.HealthCheckRequest{
-				Timestamp: time.Now().Unix(),
-			})
-			if err != nil {
-				slog.Error(
-					"issue contacting processor",
-					"response",
-					healthCheckResponse,
-					"processor",
-					proc,
-				)
-
-				return fmt.Errorf("issue contacting processor: %w", err)
-			}
-			if healthCheckResponse.Status != pb.HealthCheckResponse_STATUS_SERVING {
-				slog.Error(
-					"cannot execute stage, processor not serving",
-					"status",
-					healthCheckResponse.Status,
-					"message",
-					healthCheckResponse.Message,
-				)
-				return fmt.Errorf("cannot execute stage, processor not serving: %w", err)
-			}
+	metadata, err := json.Marshal(map[string]any{partition.Field: partition.Value.AsInterface()})
+	if err != nil {
+		return pgtype.Text{}, nil, fmt.Errorf("could not marshal lookback partition: %w", err)
+	}
+	return origin, metadata, nil
+}
 
-			// build list of affected Algorithms
-			var affectedAlgorithms []*pb.Algorithm
-
-			// generate an execution id
-			execUuid := uuid.New()
-			execId := strings.ReplaceAll(execUuid.String(), "-", "")
-
-			algorithmExecutions := make([]*pb.ExecuteAlgorithm, len(task.Nodes))
-
-			for ii, node := range task.Nodes {
-				algo, ok := algorithmMap[node.AlgoId()]
-
-				if !ok {
-					slog.Error("algorithm not found", "algo_id", node.AlgoId())
-					return fmt.Errorf("algorithm ID %d not found", node.AlgoId())
-				}
-
-				affectedAlgorithms = append(affectedAlgorithms, &pb.Algorithm{
-					Name:    algo.Name,
-					Version: algo.Version,
-				})
-
-				algorithm_dependencies := make([]*pb.AlgorithmDependencyResult, node.LenAlgoDeps())
-
-				// determine which results need to be included
-				jj := 0
-				for algoDep := range node.AlgoDeps() {
-					// get details of the algorithm - dependencies will only
-					// exist in this block if they have run
-					algorithm_result := resultMap[algoDep.AlgoId].GetAlgorithmResult()
-					resultData := algorithm_result.GetResult().GetResultData()
-
-					// log the result as the first entry before considering lookbacks
-					dep_results := []*pb.AlgorithmDependencyResultRow{
-						{
-							Result: algorithm_result.GetResult(),
-							Window: window,
-						},
-					}
-
-					// handle algorithm lookbacks
-					if algoDep.Lookback.Count > 0 {
-						results, err := d.queries.ReadResultsForAlgorithmByCount(ctx, ReadResultsForAlgorithmByCountParams{
-							AlgorithmID: pgtype.Int8{Int64: algoDep.AlgoId, Valid: true},
-							Count:       int32(algoDep.Lookback.Count),
-							SearchTo: pgtype.Timestamp{
-								Time:  window.GetTimeTo().AsTime().UTC(),
-								Valid: true,
-							},
-						})
-
-						if err != nil {
-							return fmt.Errorf("could not read algorithm results with lookback count %d: %w", algoDep.Lookback.Count, err)
-
-						}
-
-						for _, res := range results {
-							// parse out the window
-							windowMetadataPb, err := unmarshalToStructPb(res.WindowMetadata)
-							if err != nil {
-								return err
-							}
-							windowTimeFrom := timestamppb.Timestamp{
-								Seconds: res.WindowTimeFrom.Time.Unix(),
-								Nanos:   int32(res.WindowTimeFrom.Time.Nanosecond()),
-							}
-							windowTimeTo := timestamppb.Timestamp{
-								Seconds: res.WindowTimeTo.Time.Unix(),
-								Nanos:   int32(res.WindowTimeTo.Time.Nanosecond()),
-							}
-
-							// infer from the current algorithms result the result of
-							// the past data. this should always be consistent due to
-							// the checking that we do at processor registration time
-							if _, ok := resultData.(*pb.Result_FloatValues); ok {
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_FloatValues{
-										FloatValues: &pb.FloatArray{Values: convertFloat64ToFloat32(res.ResultArray)},
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else if _, ok := resultData.(*pb.Result_StructValue); ok {
-								var data map[string]any
-								err := json.Unmarshal(res.ResultJson, &data)
-								if err != nil {
-									return err
-								}
-
-								result_Struct, err := structpb.NewStruct(data)
-								if err != nil {
-									return err
-								}
-
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_StructValue{
-										StructValue: result_Struct,
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else if _, ok := resultData.(*pb.Result_SingleValue); ok {
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_SingleValue{
-										SingleValue: algorithm_result.GetResult().GetSingleValue(),
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else {
-								slog.Warn("could not assert type of result from algorithm", "algorithmId", res.AlgorithmID)
-							}
-						}
-						algorithm_dependencies[jj] = &pb.AlgorithmDependencyResult{
-							Algorithm: algorithm_result.GetAlgorithm(),
-							Result:    dep_results,
-						}
-
-					} else if algoDep.Lookback.Timedelta > 0 {
-						earliest_time_of_latest_result := window.GetTimeFrom().AsTime().UTC()
-						search_from := earliest_time_of_latest_result.Add(-time.Duration(algoDep.Lookback.Timedelta))
-
-						results, err := d.queries.ReadResultsForAlgorithmByTimedelta(ctx, ReadResultsForAlgorithmByTimedeltaParams{
-							AlgorithmID: pgtype.Int8{Int64: algoDep.AlgoId, Valid: true},
-							SearchFrom: pgtype.Timestamp{
-								Time:  search_from,
-								Valid: true,
-							},
-							SearchTo: pgtype.Timestamp{
-								Time:  earliest_time_of_latest_result,
-								Valid: true,
-							},
-						})
-
-						if err != nil {
-							return fmt.Errorf("could not read algorithm results with lookback count %d: %w", algoDep.Lookback.Count, err)
-
-						}
-						for _, res := range results {
-							// parse out the window
-							windowMetadataPb, err := unmarshalToStructPb(res.WindowMetadata)
-							if err != nil {
-								return err
-							}
-							windowTimeFrom := timestamppb.Timestamp{
-								Seconds: res.WindowTimeFrom.Time.Unix(),
-								Nanos:   int32(res.WindowTimeFrom.Time.Nanosecond()),
-							}
-							windowTimeTo := timestamppb.Timestamp{
-								Seconds: res.WindowTimeTo.Time.Unix(),
-								Nanos:   int32(res.WindowTimeTo.Time.Nanosecond()),
-							}
-							if _, ok := resultData.(*pb.Result_FloatValues); ok {
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_FloatValues{
-										FloatValues: &pb.FloatArray{Values: convertFloat64ToFloat32(res.ResultArray)},
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else if _, ok := resultData.(*pb.Result_StructValue); ok {
-								var data map[string]any
-								err := json.Unmarshal(res.ResultJson, &data)
-								if err != nil {
-									return err
-								}
-
-								result_Struct, err := structpb.NewStruct(data)
-								if err != nil {
-									return err
-								}
-
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_StructValue{
-										StructValue: result_Struct,
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else if _, ok := resultData.(*pb.Result_SingleValue); ok {
-								dep_results = append(dep_results, &pb.AlgorithmDependencyResultRow{
-									Result: &pb.Result{ResultData: &pb.Result_SingleValue{
-										SingleValue: algorithm_result.GetResult().GetSingleValue(),
-									}},
-									Window: &pb.Window{
-										TimeFrom:          &windowTimeFrom,
-										TimeTo:            &windowTimeTo,
-										Origin:            res.WindowOrigin,
-										WindowTypeName:    res.WindowTypeName,
-										WindowTypeVersion: res.WindowTypeVersion,
-										Metadata:          windowMetadataPb,
-									},
-								})
-							} else {
-								slog.Warn("could not assert type of result from algorithm", "algorithmId", res.AlgorithmID)
-							}
-						}
-						algorithm_dependencies[jj] = &pb.AlgorithmDependencyResult{
-							Algorithm: algorithm_result.GetAlgorithm(),
-							Result:    dep_results,
-						}
-					}
-					jj++
-				}
-				algorithmExecutions[ii] = &pb.ExecuteAlgorithm{
-					Algorithm: &pb.Algorithm{
-						Name:    algo.Name,
-						Version: algo.Version,
-					},
-					Dependencies: algorithm_dependencies,
-				}
-			}
+// readStageLookbacks fetches the past results of every lookback in the stage,
+// with one query for raw lookbacks and one for aggregated lookbacks
+func readStageLookbacks(
+	ctx context.Context,
+	d *Datalayer,
+	stage dag.Stage,
+	window *pb.Window,
+	metadataFieldNames map[int64]string,
+	aggregates map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow,
+) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	var keys []dispatch.LookbackKey
+	var raw ReadResultsForLookbacksParams
+	var aggregated ReadResultAggregatesForLookbacksParams
+	aggregateFunctions := make(map[int32]LookbackAggregateFunction)
+
+	for lookback := range stage.Lookbacks() {
+		key := dispatch.LookbackKey{DependencyID: lookback.Dep.AlgoId, DependantID: lookback.AlgoId}
+		lookbackId := int32(len(keys))
+		keys = append(keys, key)
+
+		// restrict lookbacks to windows in the same partition
+		partition, err := dispatch.LookbackPartition(lookback.Dep.Lookback, window, metadataFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		origin, metadata, err := lookbackPartitionFilter(partition)
+		if err != nil {
+			return nil, err
+		}
+		searchFrom, searchTo := lookbackSearchRange(lookback.Dep.Lookback, window)
+
+		if agg, ok := aggregates[key]; ok {
+			aggregateFunctions[lookbackId] = agg.LookbackAggregate.LookbackAggregateFunction
+			aggregated.LookbackIds = append(aggregated.LookbackIds, lookbackId)
+			aggregated.AlgorithmIds = append(aggregated.AlgorithmIds, lookback.Dep.AlgoId)
+			aggregated.Counts = append(aggregated.Counts, int32(lookback.Dep.Lookback.Count))
+			aggregated.SearchFroms = append(aggregated.SearchFroms, searchFrom)
+			aggregated.SearchTos = append(aggregated.SearchTos, searchTo)
+			aggregated.Origins = append(aggregated.Origins, origin.String)
+			aggregated.ByOrigins = append(aggregated.ByOrigins, origin.Valid)
+			aggregated.Metadata = append(aggregated.Metadata, string(metadata))
+			aggregated.Percentiles = append(aggregated.Percentiles, agg.LookbackAggregatePercentile)
+			aggregated.BucketSeconds = append(
+				aggregated.BucketSeconds,
+				time.Duration(agg.LookbackAggregateBucketTimedelta).Seconds(),
+			)
+		} else {
+			raw.LookbackIds = append(raw.LookbackIds, lookbackId)
+			raw.AlgorithmIds = append(raw.AlgorithmIds, lookback.Dep.AlgoId)
+			raw.Counts = append(raw.Counts, int32(lookback.Dep.Lookback.Count))
+			raw.SearchFroms = append(raw.SearchFroms, searchFrom)
+			raw.SearchTos = append(raw.SearchTos, searchTo)
+			raw.Origins = append(raw.Origins, origin.String)
+			raw.ByOrigins = append(raw.ByOrigins, origin.Valid)
+			raw.Metadata = append(raw.Metadata, string(metadata))
+		}
+	}
 
-			execReq := &pb.ExecutionRequest{
-				ExecId:              execId,
-				Window:              window,
-				AlgorithmExecutions: algorithmExecutions,
-			}
+	lookbacks := make(map[dispatch.LookbackKey]dispatch.Lookback, len(keys))
 
-			stream, err := client.ExecuteDagPart(ctx, execReq)
+	if len(raw.LookbackIds) > 0 {
+		queryStart := time.Now()
+		results, err := d.queries.ReadResultsForLookbacks(ctx, raw)
+		metrics.LookbackQueryDuration.WithLabelValues("results").Observe(time.Since(queryStart).Seconds())
+		if err != nil {
+			return nil, fmt.Errorf("could not read algorithm results for lookbacks: %w", err)
+		}
+		for _, res := range results {
+			row, err := lookbackResultToPb(res)
 			if err != nil {
-				slog.Error(
-					"failed to start DAG part execution",
-					"proc_id",
-					task.ProcId,
-					"error",
-					err,
-				)
-				return err
+				return nil, err
 			}
-
-			// recieve streamed execution results
-			for {
-				result, err := stream.Recv()
-				// error handling
-				if err != nil {
-					if errors.Is(err, context.Canceled) ||
-						errors.Is(err, context.DeadlineExceeded) {
-						slog.Warn(
-							"context done while receiving execution result",
-							"proc_id",
-							task.ProcId,
-						)
-						break
-					}
-					if err == io.EOF {
-						slog.Info("finished receiving execution results", "proc_id", task.ProcId)
-						break
-					}
-					slog.Error(
-						"error receiving execution result",
-						"proc_id",
-						task.ProcId,
-						"error",
-						err,
-					)
-					return err
-				}
-
-				slog.Info("received execution result",
-					"exec_id", result.GetExecId(),
-				)
-
-				var algoResultId int
-				for _, algo := range algorithms {
-					if (algo.Name == result.AlgorithmResult.GetAlgorithm().Name) &&
-						(algo.Version == result.AlgorithmResult.GetAlgorithm().Version) {
-						algoResultId = int(algo.ID)
-						break
-					}
-				}
-
-				// add the result in to the result map
-				resultMap[int64(algoResultId)] = result
-
-				structResult, err := convertStructToJsonBytes(
-					result.AlgorithmResult.Result.GetStructValue(),
-				)
-				if err != nil {
-					slog.Error(
-						"Issue converted algorithm struct result to bytes",
-						"error",
-						err,
-						"struct",
-						result.AlgorithmResult.Result.GetStructValue(),
-					)
-					return err
-				}
-
-				resultId, err := d.queries.CreateResult(ctx, CreateResultParams{
-					WindowsID:    pgtype.Int8{Valid: true, Int64: insertedWindow.ID},
-					WindowTypeID: pgtype.Int8{Valid: true, Int64: insertedWindow.WindowTypeID},
-					AlgorithmID:  pgtype.Int8{Valid: true, Int64: int64(algoResultId)},
-					ResultValue: pgtype.Float8{
-						Valid:   true,
-						Float64: float64(result.AlgorithmResult.Result.GetSingleValue()),
-					},
-					ResultArray: convertFloat32ToFloat64(
-						result.AlgorithmResult.Result.GetFloatValues().GetValues(),
-					),
-					ResultJson: structResult,
-				})
-				if err != nil {
-					slog.Error("Error inserting result", "error", err)
-					return err
-				}
-				slog.Info("Inserted result", "resultId", resultId)
+			if row == nil {
+				slog.WarnContext(ctx, "could not assert type of result from algorithm", "algorithm_id", res.AlgorithmID)
+				continue
 			}
+			key := keys[res.LookbackID]
+			lookback := lookbacks[key]
+			lookback.Rows = append(lookback.Rows, row)
+			lookbacks[key] = lookback
 		}
 	}
-	return nil
-}
 
-func convertFloat32ToFloat64(float32Slice []float32) []float64 {
-	float64Slice := make([]float64, len(float32Slice))
-	for i, value := range float32Slice {
-		float64Slice[i] = float64(value)
+	if len(aggregated.LookbackIds) > 0 {
+		queryStart := time.Now()
+		results, err := d.queries.ReadResultAggregatesForLookbacks(ctx, aggregated)
+		metrics.LookbackQueryDuration.WithLabelValues("aggregates").Observe(time.Since(queryStart).Seconds())
+		if err != nil {
+			return nil, fmt.Errorf("could not aggregate algorithm results for lookbacks: %w", err)
+		}
+		for _, res := range results {
+			value, err := lookbackAggregateValue(aggregateFunctions[res.LookbackID], res)
+			if err != nil {
+				return nil, err
+			}
+			key := keys[res.LookbackID]
+			lookback := lookbacks[key]
+			lookback.Buckets = append(lookback.Buckets, &pb.LookbackAggregateBucket{
+				TimeFrom: timestamppb.New(res.TimeFrom.Time),
+				TimeTo:   timestamppb.New(res.TimeTo.Time),
+				Count:    uint64(res.ResultCount),
+				Value:    value,
+			})
+			lookbacks[key] = lookback
+		}
 	}
-	return float64Slice
+
+	return lookbacks, nil
 }
 
-func convertFloat64ToFloat32(float64Slice []float64) []float32 {
-	float32Slice := make([]float32, len(float64Slice))
-	for i, value := range float64Slice {
-		float32Slice[i] = float32(value)
+// lookbackSearchRange returns the window times a lookback searches between,
+// as the results queries take them
+func lookbackSearchRange(lookback dag.Lookback, window *pb.Window) (pgtype.Timestamp, pgtype.Timestamp) {
+	searchFrom, searchTo := dispatch.LookbackSearchRange(lookback, window)
+	if searchFrom == nil {
+		return pgtype.Timestamp{}, pgtype.Timestamp{Time: searchTo, Valid: true}
 	}
-	return float32Slice
+	return pgtype.Timestamp{Time: *searchFrom, Valid: true}, pgtype.Timestamp{Time: searchTo, Valid: true}
 }
 
-func convertStructToJsonBytes(s *structpb.Struct) ([]byte, error) {
-	return protojson.Marshal(s)
-}
+// lookbackResultToPb converts a past result into a dependency result row,
+// according to the result type of its algorithm. Results of algorithms that
+// do not produce a result are converted to nil
+func lookbackResultToPb(res ReadResultsForLookbacksRow) (*pb.AlgorithmDependencyResultRow, error) {
+	var result *pb.Result
+	switch res.ResultType {
+	case ResultTypeValue:
+		result = &pb.Result{ResultData: &pb.Result_SingleValue{
+			SingleValue: float32(res.ResultValue.Float64),
+		}}
+	case ResultTypeArray:
+		result = &pb.Result{ResultData: &pb.Result_FloatValues{
+			FloatValues: &pb.FloatArray{Values: dispatch.Float32s(res.ResultArray)},
+		}}
+	case ResultTypeStruct:
+		resultStruct, err := dispatch.UnmarshalStruct(res.ResultJson)
+		if err != nil {
+			return nil, err
+		}
+		result = &pb.Result{ResultData: &pb.Result_StructValue{
+			StructValue: resultStruct,
+		}}
+	default:
+		return nil, nil
+	}
 
-func unmarshalToStructPb(data []byte) (*structpb.Struct, error) {
-	var m map[string]interface{}
-	if err := json.Unmarshal(data, &m); err != nil {
+	windowMetadataPb, err := dispatch.UnmarshalStruct(res.WindowMetadata)
+	if err != nil {
 		return nil, err
 	}
+	return &pb.AlgorithmDependencyResultRow{
+		Result: result,
+		Window: &pb.Window{
+			TimeFrom:          timestamppb.New(res.WindowTimeFrom.Time),
+			TimeTo:            timestamppb.New(res.WindowTimeTo.Time),
+			Origin:            res.WindowOrigin,
+			WindowTypeName:    res.WindowTypeName,
+			WindowTypeVersion: res.WindowTypeVersion,
+			Metadata:          windowMetadataPb,
+		},
+	}, nil
+}
 
-	return structpb.NewStruct(m)
+// lookbackAggregateValue picks the requested aggregate out of a bucket
+func lookbackAggregateValue(
+	function LookbackAggregateFunction,
+	row ReadResultAggregatesForLookbacksRow,
+) (float64, error) {
+	switch function {
+	case LookbackAggregateFunctionCount:
+		return float64(row.ResultCount), nil
+	case LookbackAggregateFunctionSum:
+		return row.SumValue, nil
+	case LookbackAggregateFunctionMean:
+		return row.MeanValue, nil
+	case LookbackAggregateFunctionMin:
+		return row.MinValue, nil
+	case LookbackAggregateFunctionMax:
+		return row.MaxValue, nil
+	case LookbackAggregateFunctionLast:
+		return row.LastValue, nil
+	case LookbackAggregateFunctionPercentile:
+		return row.PercentileValue, nil
+	default:
+		return 0, fmt.Errorf("lookback aggregate %v not supported", function)
+	}
 }
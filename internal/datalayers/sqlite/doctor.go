@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+)
+
+// Diagnose checks the integrity of the database file and that every
+// registered processor is serving. Execution paths are a view, so they
+// cannot fall out of date with the algorithm dependencies
+func (d *Datalayer) Diagnose(ctx context.Context) []types.Check {
+	checks := []types.Check{
+		d.checkIntegrity(ctx),
+		d.checkForeignKeys(ctx),
+	}
+	return append(checks, d.checkProcessors(ctx)...)
+}
+
+// checkIntegrity checks the database file for corruption
+func (d *Datalayer) checkIntegrity(ctx context.Context) types.Check {
+	check := types.Check{
+		Name: "database integrity",
+		Fix:  "restore the database file from a backup, or recover it with the sqlite3 .recover command",
+	}
+
+	rows, err := d.conn.QueryContext(ctx, "PRAGMA quick_check")
+	if err != nil {
+		check.Err = fmt.Errorf("could not check integrity: %w", err)
+		return check
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			check.Err = fmt.Errorf("could not check integrity: %w", err)
+			return check
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		check.Err = fmt.Errorf("could not check integrity: %w", err)
+		return check
+	}
+	if len(problems) > 0 {
+		check.Err = fmt.Errorf("database file is corrupt: %s", strings.Join(problems, ", "))
+	}
+	return check
+}
+
+// checkForeignKeys checks that no row references a row that does not exist,
+// as can happen when the file was written with foreign keys unenforced
+func (d *Datalayer) checkForeignKeys(ctx context.Context) types.Check {
+	check := types.Check{
+		Name: "database foreign keys",
+		Fix:  "run PRAGMA foreign_key_check against the database file and delete the rows it lists",
+	}
+
+	rows, err := d.conn.QueryContext(ctx, "SELECT DISTINCT \"table\" FROM pragma_foreign_key_check ORDER BY 1")
+	if err != nil {
+		check.Err = fmt.Errorf("could not check foreign keys: %w", err)
+		return check
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			check.Err = fmt.Errorf("could not check foreign keys: %w", err)
+			return check
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		check.Err = fmt.Errorf("could not check foreign keys: %w", err)
+		return check
+	}
+	if len(tables) > 0 {
+		check.Err = fmt.Errorf("tables %s reference rows that do not exist", strings.Join(tables, ", "))
+	}
+	return check
+}
+
+// checkProcessors health checks every registered processor, at once
+func (d *Datalayer) checkProcessors(ctx context.Context) []types.Check {
+	processors, err := d.queries.ReadProcessors(ctx)
+	if err != nil {
+		return []types.Check{{
+			Name: "registered processors",
+			Err:  fmt.Errorf("could not read processors: %w", err),
+		}}
+	}
+	dispatchProcessors := make([]dispatch.Processor, len(processors))
+	for ii, proc := range processors {
+		dispatchProcessors[ii] = dispatchProcessor(proc)
+	}
+	return d.dispatcher.CheckProcessors(ctx, dispatchProcessors)
+}
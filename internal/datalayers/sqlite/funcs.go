@@ -0,0 +1,811 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+type Datalayer struct {
+	queries *Queries
+	conn    *sql.DB
+
+	// runs execution plans on the processors
+	dispatcher *dispatch.Dispatcher
+
+	// index of the algorithm DAG, as of a version of the registry, which
+	// execution plans are built from
+	dagCache dag.IndexCache
+}
+
+// connection settings the datalayer relies on, added to those of the
+// connection string. Times are written in a format that sorts as text,
+// foreign keys are enforced, and transactions take the write lock when they
+// begin, waiting for other writers rather than failing part way
+var connectionParams = []string{
+	"_pragma=foreign_keys(1)",
+	"_pragma=busy_timeout(10000)",
+	"_pragma=journal_mode(WAL)",
+	"_time_format=sqlite",
+	"_txlock=immediate",
+}
+
+// values of the checked text columns
+const (
+	resultTypeStruct = "struct"
+	resultTypeArray  = "array"
+	resultTypeValue  = "value"
+	resultTypeNone   = "none"
+
+	lookbackPartitionNone          = "none"
+	lookbackPartitionOrigin        = "origin"
+	lookbackPartitionMetadataField = "metadata_field"
+
+	lookbackAggregateCount      = "count"
+	lookbackAggregateSum        = "sum"
+	lookbackAggregateMean       = "mean"
+	lookbackAggregateMin        = "min"
+	lookbackAggregateMax        = "max"
+	lookbackAggregateLast       = "last"
+	lookbackAggregatePercentile = "percentile"
+
+	lateWindowPolicyIgnore = "ignore"
+	lateWindowPolicyReemit = "reemit"
+)
+
+type SqliteTx struct {
+	tx *sql.Tx
+}
+
+func (t *SqliteTx) Rollback(ctx context.Context) {
+	t.tx.Rollback()
+}
+
+func (t *SqliteTx) Commit(ctx context.Context) error {
+	return t.tx.Commit()
+}
+
+// dataSourceName returns the path and settings of the database file of a
+// sqlite:// connection string, as the sqlite driver opens it
+func dataSourceName(connStr string) (string, error) {
+	path, params, _ := strings.Cut(strings.TrimPrefix(connStr, "sqlite://"), "?")
+	if path == "" {
+		return "", errors.New("connection string has no database file, e.g. sqlite://orca.db")
+	}
+	settings := slices.Clone(connectionParams)
+	if params != "" {
+		settings = append(settings, params)
+	}
+	return path + "?" + strings.Join(settings, "&"), nil
+}
+
+// statistics of the connection pool, collected when the datalayer is
+// registered with a prometheus registry. Names are shared with the postgres
+// datalayer, where they have an equivalent
+var (
+	poolAcquiredConns = prometheus.NewDesc(
+		"orca_db_pool_acquired_conns", "Connections currently acquired from the pool.", nil, nil,
+	)
+	poolIdleConns = prometheus.NewDesc(
+		"orca_db_pool_idle_conns", "Idle connections in the pool.", nil, nil,
+	)
+	poolTotalConns = prometheus.NewDesc(
+		"orca_db_pool_total_conns", "Connections in the pool, including those being opened.", nil, nil,
+	)
+	poolMaxConns = prometheus.NewDesc(
+		"orca_db_pool_max_conns", "Maximum size of the pool, 0 when unlimited.", nil, nil,
+	)
+	poolEmptyAcquires = prometheus.NewDesc(
+		"orca_db_pool_empty_acquires_total", "Acquires that waited for a connection because the pool was empty.", nil, nil,
+	)
+)
+
+func (d *Datalayer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolEmptyAcquires
+}
+
+func (d *Datalayer) Collect(ch chan<- prometheus.Metric) {
+	stat := d.conn.Stats()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.InUse))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.Idle))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.WaitCount))
+}
+
+// generate a new client for the sqlite datalayer, from a connection string
+// of the form sqlite://<path to database file>
+func NewClient(ctx context.Context, connStr string) (*Datalayer, error) {
+	if connStr == "" {
+		return nil, errors.New("connection string empty")
+	}
+	dsn, err := dataSourceName(connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher, err := dispatch.NewDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		slog.Error("Issue opening sqlite database", "error", err)
+		return nil, err
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		slog.Error("Issue opening sqlite database", "error", err)
+		return nil, err
+	}
+
+	return &Datalayer{
+		queries:    New(conn),
+		conn:       conn,
+		dispatcher: dispatcher,
+	}, nil
+}
+
+// Close closes the database file
+func (d *Datalayer) Close() error {
+	return d.conn.Close()
+}
+
+func (d *Datalayer) WithTx(ctx context.Context) (types.Tx, error) {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("could not start transaction", "error", err)
+		return nil, err
+	}
+	return &SqliteTx{tx: tx}, nil
+}
+
+func (d *Datalayer) createProcessor(
+	ctx context.Context,
+	tx types.Tx,
+	proc *pb.ProcessorRegistration,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	// processors are unique within their namespace, so registering under
+	// another project registers another processor
+	err := qtx.CreateProcessor(ctx, CreateProcessorParams{
+		Name:             proc.GetName(),
+		Runtime:          proc.GetRuntime(),
+		ConnectionString: proc.GetConnectionStr(),
+		Namespace:        types.Namespace(proc.GetProjectName()),
+		RegisteredBy:     callerText(ctx),
+		TlsServerName: sql.NullString{
+			String: proc.GetTls().GetServerName(),
+			Valid:  proc.GetTls().GetServerName() != "",
+		},
+		TlsInsecureSkipVerify: proc.GetTls().GetInsecureSkipVerify(),
+	})
+	if err != nil {
+		slog.Error("could not create processor", "error", err)
+		return err
+	}
+	if proc.GetTls().GetInsecureSkipVerify() {
+		slog.WarnContext(ctx, "processor registered without verifying its certificate, which is only safe in development", "processor", proc.GetName())
+	}
+	return nil
+}
+
+func (d *Datalayer) createMetadataField(
+	ctx context.Context,
+	tx types.Tx,
+	metadataField *pb.MetadataField,
+) (int64, error) {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	metadataFieldId, err := qtx.CreateMetadataField(ctx, CreateMetadataFieldParams{
+		Name:        metadataField.GetName(),
+		Description: metadataField.GetDescription(),
+	})
+	if err != nil {
+		slog.Error("could not create metadata field", "error", err)
+		return 0, err
+	}
+	return metadataFieldId, nil
+}
+
+func (d *Datalayer) readMetadataFieldsByWindowType(
+	ctx context.Context,
+	tx types.Tx,
+	windowType *pb.WindowType,
+	namespace string,
+) ([]*pb.MetadataField, error) {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	metadataFields, err := qtx.ReadMetadataFieldsByWindowType(ctx, ReadMetadataFieldsByWindowTypeParams{
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      windowType.GetName(),
+		WindowTypeVersion:   windowType.GetVersion(),
+	})
+	if err != nil {
+		return []*pb.MetadataField{}, fmt.Errorf("could not read metadata fields: %v", err)
+	}
+	metadataFieldsPb := make([]*pb.MetadataField, len(metadataFields))
+	for ii, field := range metadataFields {
+		metadataFieldsPb[ii] = &pb.MetadataField{
+			Name:        field.MetadataFieldName,
+			Description: field.MetadataFieldDescription,
+		}
+	}
+	return metadataFieldsPb, nil
+}
+
+func (d *Datalayer) createWindowType(
+	ctx context.Context,
+	tx types.Tx,
+	windowType *pb.WindowType,
+	namespace string,
+) (int64, error) {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	windowTypeId, err := qtx.CreateWindowType(ctx, CreateWindowTypeParams{
+		Namespace:   namespace,
+		Name:        windowType.GetName(),
+		Version:     windowType.GetVersion(),
+		Description: windowType.GetDescription(),
+	})
+	if err != nil {
+		slog.Error("could not create window type", "error", err)
+		return 0, err
+	}
+	return windowTypeId, nil
+}
+
+// setWindowSchedule creates or updates the schedule of a window type,
+// replacing any rollup, or removes it when the registration asks to. A
+// window type registered without a schedule keeps the one it has, as other
+// processors may share the window type
+func (d *Datalayer) setWindowSchedule(
+	ctx context.Context,
+	tx types.Tx,
+	windowTypeId int64,
+	windowType *pb.WindowType,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	if windowType.GetRemoveSchedule() {
+		err := qtx.DeleteWindowSchedule(ctx, windowTypeId)
+		if err != nil {
+			slog.Error("could not delete window schedule", "error", err)
+			return err
+		}
+		return nil
+	}
+	windowSchedule := windowType.GetSchedule()
+	if windowSchedule == nil {
+		return nil
+	}
+
+	// scheduled windows are emitted without metadata, so they could never
+	// carry the fields of the window type
+	if len(windowType.GetMetadataFields()) > 0 {
+		return fmt.Errorf(
+			"window type %v cannot be scheduled, as scheduled window types cannot carry metadata fields",
+			windowType.GetName(),
+		)
+	}
+
+	sched, err := schedule.FromPb(windowSchedule)
+	if err != nil {
+		return err
+	}
+
+	// the first window starts at the next scheduled time, so that
+	// windows always span a whole period
+	err = qtx.CreateWindowSchedule(ctx, CreateWindowScheduleParams{
+		WindowTypeID: windowTypeId,
+		Cron: sql.NullString{
+			String: windowSchedule.GetCron(),
+			Valid:  windowSchedule.GetCron() != "",
+		},
+		IntervalNs:    int64(windowSchedule.GetInterval()),
+		AlignmentNs:   int64(windowSchedule.GetAlignment()),
+		TimeZone:      windowSchedule.GetTimeZone(),
+		Origin:        windowSchedule.GetOrigin(),
+		CatchUp:       windowSchedule.GetCatchUp(),
+		HighWaterMark: sched.Next(time.Now().UTC()).UTC(),
+	})
+	if err != nil {
+		slog.Error("could not create window schedule", "error", err)
+		return err
+	}
+
+	// windows are emitted by at most one of a schedule and a rollup
+	err = qtx.DeleteWindowRollup(ctx, windowTypeId)
+	if err != nil {
+		slog.Error("could not delete window rollup", "error", err)
+		return err
+	}
+	return nil
+}
+
+// setWindowRollup creates or updates the rollup of a window type, replacing
+// any schedule, or removes it when the registration asks to. A window type
+// registered without a rollup keeps the one it has
+func (d *Datalayer) setWindowRollup(
+	ctx context.Context,
+	tx types.Tx,
+	windowTypeId int64,
+	windowType *pb.WindowType,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	if windowType.GetRemoveRollup() {
+		err := qtx.DeleteWindowRollup(ctx, windowTypeId)
+		if err != nil {
+			slog.Error("could not delete window rollup", "error", err)
+			return err
+		}
+		return nil
+	}
+	windowRollup := windowType.GetRollup()
+	if windowRollup == nil {
+		return nil
+	}
+
+	_, err := schedule.Parse(
+		"",
+		time.Duration(windowRollup.GetInterval()),
+		time.Duration(windowRollup.GetAlignment()),
+		windowRollup.GetTimeZone(),
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid window rollup: %w", err)
+	}
+
+	lateWindowPolicy := lateWindowPolicyIgnore
+	if windowRollup.GetLateWindows() == pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT {
+		lateWindowPolicy = lateWindowPolicyReemit
+	}
+
+	err = qtx.CreateWindowRollup(ctx, CreateWindowRollupParams{
+		WindowTypeID:           windowTypeId,
+		ChildWindowTypeName:    windowRollup.GetChildWindowTypeName(),
+		ChildWindowTypeVersion: windowRollup.GetChildWindowTypeVersion(),
+		IntervalNs:             int64(windowRollup.GetInterval()),
+		AlignmentNs:            int64(windowRollup.GetAlignment()),
+		TimeZone:               windowRollup.GetTimeZone(),
+		ChildCount:             int64(windowRollup.GetChildCount()),
+		LateWindowPolicy:       lateWindowPolicy,
+	})
+	if err != nil {
+		slog.Error("could not create window rollup", "error", err)
+		return err
+	}
+
+	// windows are emitted by at most one of a schedule and a rollup
+	err = qtx.DeleteWindowSchedule(ctx, windowTypeId)
+	if err != nil {
+		slog.Error("could not delete window schedule", "error", err)
+		return err
+	}
+	return nil
+}
+
+// scheduleFromRow parses a stored window schedule
+func scheduleFromRow(row ReadWindowSchedulesRow) (schedule.Schedule, error) {
+	return schedule.Parse(
+		row.Cron.String,
+		time.Duration(row.IntervalNs),
+		time.Duration(row.AlignmentNs),
+		row.TimeZone,
+		row.CatchUp,
+	)
+}
+
+// rollupScheduleFromRow parses the interval of a stored window rollup
+func rollupScheduleFromRow(row ReadWindowRollupsForChildRow) (schedule.Schedule, error) {
+	return schedule.Parse(
+		"",
+		time.Duration(row.IntervalNs),
+		time.Duration(row.AlignmentNs),
+		row.TimeZone,
+		false,
+	)
+}
+
+// windowRollupToPb converts a stored window rollup to its protobuf
+func windowRollupToPb(row WindowRollup) *pb.WindowRollup {
+	lateWindows := pb.WindowRollup_LATE_WINDOW_POLICY_IGNORE
+	if row.LateWindowPolicy == lateWindowPolicyReemit {
+		lateWindows = pb.WindowRollup_LATE_WINDOW_POLICY_REEMIT
+	}
+	return &pb.WindowRollup{
+		ChildWindowTypeName:    row.ChildWindowTypeName,
+		ChildWindowTypeVersion: row.ChildWindowTypeVersion,
+		Interval:               uint64(row.IntervalNs),
+		Alignment:              uint64(row.AlignmentNs),
+		TimeZone:               row.TimeZone,
+		ChildCount:             uint32(row.ChildCount),
+		LateWindows:            lateWindows,
+	}
+}
+
+// windowScheduleToPb converts a stored window schedule to its protobuf
+func windowScheduleToPb(row ReadWindowSchedulesRow) *pb.WindowSchedule {
+	windowSchedule := &pb.WindowSchedule{
+		Alignment: uint64(row.AlignmentNs),
+		TimeZone:  row.TimeZone,
+		Origin:    row.Origin,
+		CatchUp:   row.CatchUp,
+	}
+	if row.Cron.Valid {
+		windowSchedule.Trigger = &pb.WindowSchedule_Cron{Cron: row.Cron.String}
+	} else {
+		windowSchedule.Trigger = &pb.WindowSchedule_Interval{Interval: uint64(row.IntervalNs)}
+	}
+	return windowSchedule
+}
+
+func (d *Datalayer) createMetadataFieldBridge(
+	ctx context.Context,
+	tx types.Tx,
+	windowTypeId int64,
+	metadataFieldId int64,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	err := qtx.CreateWindowTypeMetadataFieldBridge(ctx, CreateWindowTypeMetadataFieldBridgeParams{
+		WindowTypeID:     windowTypeId,
+		MetadataFieldsID: metadataFieldId,
+	})
+	if err != nil {
+		slog.Error("could not create metadata field bridge", "error", err)
+		return err
+	}
+	return nil
+}
+
+// addAlgorithm creates the algorithm of a processor, and adds it to the
+// index of the DAG
+func (d *Datalayer) addAlgorithm(
+	ctx context.Context,
+	tx types.Tx,
+	index *dag.Index,
+	algo *pb.Algorithm,
+	proc *pb.ProcessorRegistration,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	// create algos
+	var resultType string
+	switch algo.GetResultType() {
+	case pb.ResultType_ARRAY:
+		resultType = resultTypeArray
+	case pb.ResultType_STRUCT:
+		resultType = resultTypeStruct
+	case pb.ResultType_VALUE:
+		resultType = resultTypeValue
+	case pb.ResultType_NONE:
+		resultType = resultTypeNone
+	default:
+		return fmt.Errorf("result type %v not supported", algo.GetResultType())
+	}
+
+	err := qtx.CreateAlgorithm(ctx, CreateAlgorithmParams{
+		Name:               algo.GetName(),
+		Version:            algo.GetVersion(),
+		Description:        algo.GetDescription(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: types.Namespace(proc.GetProjectName()),
+		WindowTypeName:     algo.GetWindowType().GetName(),
+		WindowTypeVersion:  algo.GetWindowType().GetVersion(),
+		ResultType:         resultType,
+	})
+	if err != nil {
+		slog.Error("error creating algorithm", "error", err)
+		return err
+	}
+
+	stored, err := qtx.ReadAlgorithm(ctx, ReadAlgorithmParams{
+		AlgorithmName:      algo.GetName(),
+		AlgorithmVersion:   algo.GetVersion(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: types.Namespace(proc.GetProjectName()),
+		WindowTypeName:     algo.GetWindowType().GetName(),
+		WindowTypeVersion:  algo.GetWindowType().GetVersion(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not read the created algorithm: %w", err)
+	}
+	index.AddAlgorithm(stored.ID, stored.ProcessorID, stored.WindowTypeID)
+	return nil
+}
+
+// addOverwriteAlgorithmDependency creates or updates the dependencies of an
+// algorithm, and sets them in the index of the DAG, rejecting dependencies
+// that would close a cycle
+func (d *Datalayer) addOverwriteAlgorithmDependency(
+	ctx context.Context,
+	tx types.Tx,
+	index *dag.Index,
+	algo *pb.Algorithm,
+	proc *pb.ProcessorRegistration,
+) error {
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	// get algorithm id
+	namespace := types.Namespace(proc.GetProjectName())
+	algoId, err := qtx.ReadAlgorithmId(ctx, ReadAlgorithmIdParams{
+		AlgorithmName:      algo.GetName(),
+		AlgorithmVersion:   algo.GetVersion(),
+		ProcessorName:      proc.GetName(),
+		ProcessorRuntime:   proc.GetRuntime(),
+		ProcessorNamespace: namespace,
+	})
+	if err != nil {
+		slog.Error("could not get algorithm ID", "algorithm", algo)
+		return err
+	}
+	for _, algoDependentOn := range algo.GetDependencies() {
+		// dependencies are looked up in the namespace of the dependant,
+		// unless another namespace is named
+		dependencyNamespace := namespace
+		if algoDependentOn.GetProcessorNamespace() != "" {
+			dependencyNamespace = algoDependentOn.GetProcessorNamespace()
+		}
+
+		// get algorithm id
+		algoDependentOnId, err := qtx.ReadAlgorithmId(ctx, ReadAlgorithmIdParams{
+			AlgorithmName:      algoDependentOn.GetName(),
+			AlgorithmVersion:   algoDependentOn.GetVersion(),
+			ProcessorName:      algoDependentOn.GetProcessorName(),
+			ProcessorRuntime:   algoDependentOn.GetProcessorRuntime(),
+			ProcessorNamespace: dependencyNamespace,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"algorithm %v of processor %v does not exist in namespace %v",
+				algoDependentOn.GetName(),
+				algoDependentOn.GetProcessorName(),
+				dependencyNamespace,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("issue getting algorithm ID of dependant: %v", err)
+		}
+
+		// the lookback partition value is read from the dependant's window,
+		// so the field must be carried by the dependant's window type
+		lookbackPartition, lookbackPartitionField, err := lookbackPartitionFromPb(algoDependentOn)
+		if err != nil {
+			return err
+		}
+		if lookbackPartition == lookbackPartitionMetadataField {
+			hasField := slices.ContainsFunc(
+				algo.GetWindowType().GetMetadataFields(),
+				func(field *pb.MetadataField) bool {
+					return field.GetName() == lookbackPartitionField.String
+				},
+			)
+			if !hasField {
+				return fmt.Errorf(
+					"lookback partition field %q is not a metadata field of window type %v",
+					lookbackPartitionField.String,
+					algo.GetWindowType().GetName(),
+				)
+			}
+		}
+
+		// only single values can be summarised beyond counting them
+		lookbackAggregate, err := lookbackAggregateFromPb(algoDependentOn.GetLookbackAggregate())
+		if err != nil {
+			return err
+		}
+		if lookbackAggregate.Valid && lookbackAggregate.String != lookbackAggregateCount {
+			resultType, err := qtx.ReadAlgorithmResultType(ctx, algoDependentOnId)
+			if err != nil {
+				return fmt.Errorf("issue getting result type of dependant: %v", err)
+			}
+			if resultType != resultTypeValue {
+				return fmt.Errorf(
+					"lookback aggregate %v requires algorithm %v to produce a value result, not %v",
+					lookbackAggregate.String,
+					algoDependentOn.GetName(),
+					resultType,
+				)
+			}
+		}
+
+		// an algorithm depending on itself cannot be stored, so is caught
+		// before the rest of the graph is checked for cycles
+		if algoDependentOnId == algoId {
+			slog.Error("found circular dependency", "algo", algo)
+			return types.NewCircularDependencyError([]types.CycleAlgorithm{{
+				Name:      algo.GetName(),
+				Version:   algo.GetVersion(),
+				Processor: proc.GetName(),
+			}})
+		}
+
+		dependency, err := qtx.CreateAlgorithmDependency(ctx, CreateAlgorithmDependencyParams{
+			FromAlgorithmID:             algoDependentOnId,
+			ToAlgorithmID:               algoId,
+			LookbackCount:               int64(algoDependentOn.GetLookbackNum()),
+			LookbackTimedelta:           int64(algoDependentOn.GetLookbackTimeDelta()),
+			LookbackPartition:           lookbackPartition,
+			LookbackPartitionField:      lookbackPartitionField,
+			LookbackAggregate:           lookbackAggregate,
+			LookbackAggregatePercentile: algoDependentOn.GetLookbackAggregate().GetPercentile(),
+			LookbackAggregateBucketTimedelta: int64(
+				algoDependentOn.GetLookbackAggregate().GetBucketTimeDelta(),
+			),
+		})
+		if err != nil {
+			return fmt.Errorf("issue constructing algorithm dependency: %v", err)
+		}
+		err = index.SetDependency(
+			dependency.FromAlgorithmID,
+			dependency.ToAlgorithmID,
+			dependencyLookback(dependency),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// circularDependencyError returns the error of a cycle of algorithms, in the
+// order given by dag.Index.Cycle
+func (d *Datalayer) circularDependencyError(ctx context.Context, qtx *Queries, cycle []int64) error {
+	algorithms, err := qtx.ReadAlgorithmsByIDs(ctx, cycle)
+	if err != nil {
+		return fmt.Errorf("could not read the algorithms of a circular dependency: %w", err)
+	}
+	byId := make(map[int64]types.CycleAlgorithm, len(algorithms))
+	for _, algo := range algorithms {
+		byId[algo.ID] = types.CycleAlgorithm{
+			Name:      algo.Name,
+			Version:   algo.Version,
+			Processor: algo.ProcessorName,
+		}
+	}
+	cycleAlgorithms := make([]types.CycleAlgorithm, len(cycle))
+	for ii, algoId := range cycle {
+		cycleAlgorithms[ii] = byId[algoId]
+	}
+	slog.Error("found circular dependency", "cycle", cycleAlgorithms)
+	return types.NewCircularDependencyError(cycleAlgorithms)
+}
+
+// lookbackPartitionFromPb maps the lookback partition of a dependency onto
+// its datalayer representation
+func lookbackPartitionFromPb(dep *pb.AlgorithmDependency) (string, sql.NullString, error) {
+	switch dep.GetLookbackPartition() {
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_NONE:
+		return lookbackPartitionNone, sql.NullString{}, nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_ORIGIN:
+		return lookbackPartitionOrigin, sql.NullString{}, nil
+	case pb.AlgorithmDependency_LOOKBACK_PARTITION_METADATA_FIELD:
+		return lookbackPartitionMetadataField, sql.NullString{
+			String: dep.GetLookbackPartitionField(),
+			Valid:  true,
+		}, nil
+	default:
+		return "", sql.NullString{}, fmt.Errorf("lookback partition %v not supported", dep.GetLookbackPartition())
+	}
+}
+
+// lookbackAggregates maps lookback aggregate functions onto their datalayer
+// representation
+var lookbackAggregates = map[pb.LookbackAggregate_Function]string{
+	pb.LookbackAggregate_FUNCTION_COUNT:      lookbackAggregateCount,
+	pb.LookbackAggregate_FUNCTION_SUM:        lookbackAggregateSum,
+	pb.LookbackAggregate_FUNCTION_MEAN:       lookbackAggregateMean,
+	pb.LookbackAggregate_FUNCTION_MIN:        lookbackAggregateMin,
+	pb.LookbackAggregate_FUNCTION_MAX:        lookbackAggregateMax,
+	pb.LookbackAggregate_FUNCTION_LAST:       lookbackAggregateLast,
+	pb.LookbackAggregate_FUNCTION_PERCENTILE: lookbackAggregatePercentile,
+}
+
+// lookbackAggregateFromPb maps the lookback aggregate function of a dependency
+// onto its datalayer representation. No aggregate maps to NULL
+func lookbackAggregateFromPb(agg *pb.LookbackAggregate) (sql.NullString, error) {
+	if agg == nil {
+		return sql.NullString{}, nil
+	}
+	function, ok := lookbackAggregates[agg.GetFunction()]
+	if !ok {
+		return sql.NullString{}, fmt.Errorf("lookback aggregate %v not supported", agg.GetFunction())
+	}
+	return sql.NullString{String: function, Valid: true}, nil
+}
+
+// lookbackAggregateToPb maps a stored lookback aggregate function back onto
+// its protobuf
+func lookbackAggregateToPb(function string) (pb.LookbackAggregate_Function, error) {
+	for pbFunction, stored := range lookbackAggregates {
+		if stored == function {
+			return pbFunction, nil
+		}
+	}
+	return pb.LookbackAggregate_FUNCTION_UNSPECIFIED, fmt.Errorf("lookback aggregate %v not supported", function)
+}
+
+// isConstraintError reports whether err is the violation of a constraint,
+// by its extended sqlite result code
+func isConstraintError(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// isUniqueViolation reports whether err is the violation of a unique
+// constraint
+func isUniqueViolation(err error) bool {
+	return isConstraintError(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+// traceIDText returns the trace ID of the context, or NULL when untraced
+func traceIDText(ctx context.Context) sql.NullString {
+	traceID := tracing.TraceID(ctx)
+	return sql.NullString{String: traceID, Valid: traceID != ""}
+}
+
+// callerText returns the authenticated caller of the context, or NULL when
+// the call was not authenticated
+func callerText(ctx context.Context) sql.NullString {
+	caller := auth.Caller(ctx)
+	return sql.NullString{String: caller, Valid: caller != ""}
+}
+
+// namespacedWindow returns the window as it is stored and processed, in
+// the namespace its window type resolves to when it has none
+func (d *Datalayer) namespacedWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (*pb.Window, error) {
+	if window.GetNamespace() != "" {
+		return window, nil
+	}
+	namespaces, err := qtx.ReadWindowTypeNamespaces(ctx, ReadWindowTypeNamespacesParams{
+		WindowTypeName:    window.GetWindowTypeName(),
+		WindowTypeVersion: window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read the namespaces of the window type: %w", err)
+	}
+	namespace, err := types.WindowNamespace(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	namespaced := proto.Clone(window).(*pb.Window)
+	namespaced.Namespace = namespace
+	return namespaced, nil
+}
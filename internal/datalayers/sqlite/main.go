@@ -0,0 +1,874 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// RegisterProcessor with Orca Core
+func (d *Datalayer) RegisterProcessor(
+	ctx context.Context,
+	proc *pb.ProcessorRegistration,
+) error {
+	slog.Debug("registering processor", "processor", proc.GetName(), "algorithms", len(proc.GetSupportedAlgorithms()))
+	if err := auth.Require(ctx, auth.RoleProcessor, types.Namespace(proc.GetProjectName())); err != nil {
+		return err
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	// the version of the registry the index of the DAG is built at is
+	// bumped by every registration
+	qtx := d.queries.WithTx(tx.(*SqliteTx).tx)
+	version, err := qtx.BumpRegistryVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("could not bump the registry version: %w", err)
+	}
+	base := d.dagCache.Get(version - 1)
+	if base == nil {
+		base, err = loadDagIndex(ctx, qtx)
+		if err != nil {
+			return err
+		}
+	}
+	index := base.Clone()
+
+	// register the processor
+	err = d.createProcessor(ctx, tx, proc)
+
+	if err != nil {
+		slog.Error("could not create processor", "error", err)
+		return err
+	}
+
+	// add all algorithms first, window types are registered in the
+	// namespace of the processor
+	namespace := types.Namespace(proc.GetProjectName())
+	for _, algo := range proc.GetSupportedAlgorithms() {
+		// add window types
+		windowType := algo.GetWindowType()
+
+		// create / update the window type
+		windowTypeId, err := d.createWindowType(ctx, tx, windowType, namespace)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the schedule of the window type
+		err = d.setWindowSchedule(ctx, tx, windowTypeId, windowType)
+		if err != nil {
+			return err
+		}
+
+		// create / update / remove the rollup of the window type
+		err = d.setWindowRollup(ctx, tx, windowTypeId, windowType)
+		if err != nil {
+			return err
+		}
+
+		// read any existing metadata fields for the window
+		metadataFieldsAsStored, err := d.readMetadataFieldsByWindowType(ctx, tx, windowType, namespace)
+		if err != nil {
+			return err
+		}
+
+		// if there are existing fields, check they are the same as the provided window
+		// just check on metadatafield name
+		if len(metadataFieldsAsStored) > 0 {
+			if len(windowType.MetadataFields) != len(metadataFieldsAsStored) {
+				return fmt.Errorf(
+					`Metadata fields of incoming window type %v, do not match the
+					number of fields stored in the database for this window.
+					Expected: %v, got %v. Considering bumping the version of the
+					window type.`, windowType, metadataFieldsAsStored, windowType.MetadataFields,
+				)
+			}
+			metadataFieldNamesAsStored := make([]string, len(metadataFieldsAsStored))
+			for ii, field := range metadataFieldsAsStored {
+				metadataFieldNamesAsStored[ii] = field.GetName()
+			}
+			for _, metadataField := range windowType.MetadataFields {
+				if !slices.Contains(metadataFieldNamesAsStored, metadataField.GetName()) {
+					return fmt.Errorf(
+						`Recieved a metadata field %v of window type %v that is not registered
+						in the database. If you want to keep this field, bump the version
+						of the window type.`, metadataField.GetName(), windowType,
+					)
+				}
+			}
+		} else {
+			for _, metadataField := range windowType.MetadataFields {
+				metadataFieldId, err := d.createMetadataField(ctx, tx, metadataField)
+				if err != nil {
+					return fmt.Errorf("sql issue creating the metadata field: %v", err)
+				}
+
+				err = d.createMetadataFieldBridge(ctx, tx, windowTypeId, metadataFieldId)
+				if err != nil {
+					return fmt.Errorf("sql issue in creating the metadata field bridge: %v", err)
+				}
+			}
+		}
+
+		// create algos
+		err = d.addAlgorithm(ctx, tx, index, algo, proc)
+		if err != nil {
+			slog.Error("error creating algorithm", "error", err)
+			return err
+		}
+	}
+
+	// then add the dependencies and associate the processor with all the algos
+	for _, algo := range proc.GetSupportedAlgorithms() {
+		err := d.addOverwriteAlgorithmDependency(
+			ctx,
+			tx,
+			index,
+			algo,
+			proc,
+		)
+		if err != nil {
+			// error wrapping is important here because we return some custom errors
+			return fmt.Errorf("issue adding algorithm dependency: %w", err)
+		}
+	}
+
+	// cycles can run through several algorithms of the registration, so the
+	// graph is checked once all of its dependencies are set
+	if cycle := index.Cycle(base); cycle != nil {
+		return d.circularDependencyError(ctx, qtx, cycle)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	d.dagCache.Set(index, version)
+	return nil
+}
+
+// EmitWindow with Orca core
+func (d *Datalayer) EmitWindow(
+	ctx context.Context,
+	window *pb.Window,
+) (pb.WindowEmitStatus, error) {
+	ctx = logging.WithWindow(ctx, window)
+	slog.DebugContext(ctx, "received emitted window")
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return pb.WindowEmitStatus{}, err
+	}
+
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	window, err = d.namespacedWindow(ctx, qtx, window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+	scope := auth.WindowTypeScope(window.GetNamespace(), window.GetWindowTypeName())
+	if err := auth.Require(ctx, auth.RoleEmitter, scope); err != nil {
+		return pb.WindowEmitStatus{Status: pb.WindowEmitStatus_TRIGGERING_FAILED}, err
+	}
+
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, qtx, window)
+	if err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+
+	// the window is stored before it is processed, so that results and
+	// rollups only ever see committed windows, and as sqlite has a single
+	// writer. Windows that trigger nothing are processed too, as they are
+	// complete straight away
+	if err := tx.Commit(ctx); err != nil {
+		return pb.WindowEmitStatus{Status: emitStatus}, err
+	}
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return pb.WindowEmitStatus{Status: emitStatus}, nil
+}
+
+// EmitScheduledWindows emits the windows of scheduled window types that have
+// closed by now. A database file has a single writer, so there is no leader
+// to elect. Moving high water marks stop windows being emitted twice
+func (d *Datalayer) EmitScheduledWindows(ctx context.Context, now time.Time) error {
+	windowSchedules, err := d.queries.ReadWindowSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("could not read window schedules: %w", err)
+	}
+
+	for _, windowSchedule := range windowSchedules {
+		sched, err := scheduleFromRow(windowSchedule)
+		if err != nil {
+			slog.Error(
+				"could not parse window schedule",
+				"window_type",
+				windowSchedule.WindowTypeName,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		highWaterMark := windowSchedule.HighWaterMark
+		for _, scheduledWindow := range sched.Due(highWaterMark, now) {
+			err := d.emitScheduledWindow(ctx, windowSchedule, highWaterMark, scheduledWindow)
+			if err != nil {
+				slog.Error(
+					"could not emit scheduled window",
+					"window_type",
+					windowSchedule.WindowTypeName,
+					"time_from",
+					scheduledWindow.From,
+					"error",
+					err,
+				)
+				break
+			}
+			highWaterMark = scheduledWindow.To
+		}
+	}
+	return nil
+}
+
+// emitScheduledWindow emits a single scheduled window, and advances the high
+// water mark of its schedule in the same transaction
+func (d *Datalayer) emitScheduledWindow(
+	ctx context.Context,
+	windowSchedule ReadWindowSchedulesRow,
+	highWaterMark time.Time,
+	scheduledWindow schedule.Window,
+) (err error) {
+	ctx, span := tracing.Tracer.Start(ctx, "EmitScheduledWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", windowSchedule.WindowTypeName),
+		attribute.String("orca.window_type_version", windowSchedule.WindowTypeVersion),
+	))
+	defer func() { tracing.End(span, err) }()
+	ctx = logging.WithCorrelationID(ctx)
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	// the high water mark moving means the window has been emitted already.
+	// Transactions take the write lock as they begin, so it cannot move
+	// until this one ends
+	storedHighWaterMark, err := qtx.ReadWindowScheduleHighWaterMark(ctx, windowSchedule.WindowTypeID)
+	if err != nil {
+		return fmt.Errorf("could not read high water mark of window schedule: %w", err)
+	}
+	if !storedHighWaterMark.Equal(highWaterMark) {
+		return fmt.Errorf(
+			"high water mark of window schedule moved from %v to %v",
+			highWaterMark,
+			storedHighWaterMark,
+		)
+	}
+
+	window := &pb.Window{
+		TimeFrom:          timestamppb.New(scheduledWindow.From),
+		TimeTo:            timestamppb.New(scheduledWindow.To),
+		WindowTypeName:    windowSchedule.WindowTypeName,
+		WindowTypeVersion: windowSchedule.WindowTypeVersion,
+		Origin:            windowSchedule.Origin,
+		Metadata:          &structpb.Struct{},
+		Namespace:         windowSchedule.WindowTypeNamespace,
+	}
+	emitStatus, executionPlan, insertedWindow, err := d.registerWindow(ctx, qtx, window)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.ID)
+
+	err = qtx.UpdateWindowScheduleHighWaterMark(ctx, UpdateWindowScheduleHighWaterMarkParams{
+		WindowTypeID:  windowSchedule.WindowTypeID,
+		HighWaterMark: scheduledWindow.To.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not advance high water mark of window schedule: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	slog.InfoContext(logging.WithWindow(ctx, window), "emitted scheduled window", "status", emitStatus)
+
+	go processWindow(tracing.Detach(ctx), d, executionPlan, window, insertedWindow)
+	return nil
+}
+
+// rollupWindow counts a completed child window towards its parent window in
+// a rollup, and emits the parent once enough of its children have completed
+func (d *Datalayer) rollupWindow(
+	ctx context.Context,
+	rollup ReadWindowRollupsForChildRow,
+	window *pb.Window,
+) (err error) {
+	ctx, traceSpan := tracing.Tracer.Start(ctx, "RollupWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", rollup.WindowTypeName),
+		attribute.String("orca.window_type_version", rollup.WindowTypeVersion),
+	))
+	defer func() { tracing.End(traceSpan, err) }()
+
+	sched, err := rollupScheduleFromRow(rollup)
+	if err != nil {
+		return err
+	}
+	childFrom := window.GetTimeFrom().AsTime().UTC()
+	childTo := window.GetTimeTo().AsTime().UTC()
+	span, err := sched.Span(childFrom)
+	if err != nil {
+		return err
+	}
+	if childTo.After(span.To) {
+		return fmt.Errorf(
+			"window from %v to %v does not fit within a window of rolled up window type %v",
+			childFrom,
+			childTo,
+			rollup.WindowTypeName,
+		)
+	}
+
+	// protobuf orders the fields of a struct, so equal metadata marshals to
+	// equal text
+	metadataBytes, err := window.GetMetadata().MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("could not marshal metadata: %v", err)
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return err
+	}
+
+	sqliteTx := tx.(*SqliteTx)
+	qtx := d.queries.WithTx(sqliteTx.tx)
+
+	// children are grouped into parents by interval, origin and metadata
+	err = qtx.CreateWindowRollupProgress(ctx, CreateWindowRollupProgressParams{
+		WindowTypeID: rollup.WindowTypeID,
+		Origin:       window.GetOrigin(),
+		Metadata:     string(metadataBytes),
+		TimeFrom:     span.From.UTC(),
+		TimeTo:       span.To.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create rollup progress: %w", err)
+	}
+	progress, err := qtx.ReadWindowRollupProgress(ctx, ReadWindowRollupProgressParams{
+		WindowTypeID: rollup.WindowTypeID,
+		Origin:       window.GetOrigin(),
+		Metadata:     string(metadataBytes),
+		TimeFrom:     span.From.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not read rollup progress: %w", err)
+	}
+
+	var childWindows []time.Time
+	if err := json.Unmarshal([]byte(progress.ChildWindows), &childWindows); err != nil {
+		return fmt.Errorf("could not unmarshal child windows of rollup progress: %w", err)
+	}
+
+	// a child window emitted more than once only counts once
+	alreadyCompleted := slices.ContainsFunc(childWindows, func(ts time.Time) bool {
+		return ts.Equal(childFrom)
+	})
+	if alreadyCompleted {
+		return nil
+	}
+	childWindows = append(childWindows, childFrom)
+	childWindowsBytes, err := json.Marshal(childWindows)
+	if err != nil {
+		return fmt.Errorf("could not marshal child windows of rollup progress: %w", err)
+	}
+
+	late := progress.Emitted.Valid
+	emit := (!late && len(childWindows) >= int(rollup.ChildCount)) ||
+		(late && rollup.LateWindowPolicy == lateWindowPolicyReemit)
+
+	emitted := progress.Emitted
+	var parent *pb.Window
+	var emitStatus pb.WindowEmitStatus_StatusEnum
+	var executionPlan dag.Plan
+	var insertedWindow RegisterWindowRow
+	if emit {
+		parent = &pb.Window{
+			TimeFrom:          timestamppb.New(span.From),
+			TimeTo:            timestamppb.New(span.To),
+			WindowTypeName:    rollup.WindowTypeName,
+			WindowTypeVersion: rollup.WindowTypeVersion,
+			Origin:            window.GetOrigin(),
+			Metadata:          window.GetMetadata(),
+			Namespace:         window.GetNamespace(),
+		}
+		emitStatus, executionPlan, insertedWindow, err = d.registerWindow(ctx, qtx, parent)
+		if err != nil {
+			return err
+		}
+		emitted = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	} else if late {
+		slog.WarnContext(
+			ctx,
+			"window completed after its rolled up window was emitted",
+			"rolled_up_window_type",
+			rollup.WindowTypeName,
+		)
+	}
+
+	err = qtx.UpdateWindowRollupProgress(ctx, UpdateWindowRollupProgressParams{
+		ChildWindows: string(childWindowsBytes),
+		Emitted:      emitted,
+		ID:           progress.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update rollup progress: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if emit {
+		ctx = logging.With(ctx, logging.KeyWindowID, insertedWindow.ID)
+		slog.InfoContext(logging.WithWindow(ctx, parent), "emitted rolled up window", "status", emitStatus, "late", late)
+		go processWindow(tracing.Detach(ctx), d, executionPlan, parent, insertedWindow)
+	}
+	return nil
+}
+
+// registerWindow validates and inserts a window, and builds the plan of the
+// algorithms it triggers, counting the window by its emit status. The window
+// is committed by the caller
+func (d *Datalayer) registerWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, RegisterWindowRow, error) {
+	emitStatus, executionPlan, insertedWindow, err := d.insertWindow(ctx, qtx, window)
+	metrics.WindowsEmitted.WithLabelValues(
+		window.GetWindowTypeName(),
+		window.GetWindowTypeVersion(),
+		emitStatus.String(),
+	).Inc()
+	return emitStatus, executionPlan, insertedWindow, err
+}
+
+// insertWindow does the work of registerWindow
+func (d *Datalayer) insertWindow(
+	ctx context.Context,
+	qtx *Queries,
+	window *pb.Window,
+) (pb.WindowEmitStatus_StatusEnum, dag.Plan, RegisterWindowRow, error) {
+	// marshal metadata
+	metadata := window.GetMetadata()
+	metadataBytes, err := metadata.MarshalJSON()
+	if err != nil {
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not marshal metadata: %v", err)
+	}
+
+	// window types are those of the namespace of the window
+	namespace := types.Namespace(window.GetNamespace())
+
+	// check whether metadata is needed
+	metadataFields, err := qtx.ReadMetadataFieldsByWindowType(ctx, ReadMetadataFieldsByWindowTypeParams{
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      window.GetWindowTypeName(),
+		WindowTypeVersion:   window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not read metadata for window: %v", err)
+	}
+
+	// confident that any required metadata is being supplied to the processor
+	if len(metadataFields) > 0 {
+		var metadataMap map[string]any
+		if err := json.Unmarshal(metadataBytes, &metadataMap); err != nil {
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("could not unmarshal metadata for validation: %v", err)
+		}
+
+		for _, mDataField := range metadataFields {
+			fieldName := mDataField.MetadataFieldName
+			if _, exists := metadataMap[fieldName]; !exists {
+				return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf("required metadata field '%s' is missing", fieldName)
+			}
+		}
+	}
+
+	insertedWindow, err := qtx.RegisterWindow(ctx, RegisterWindowParams{
+		WindowTypeNamespace: namespace,
+		WindowTypeName:      window.GetWindowTypeName(),
+		WindowTypeVersion:   window.GetWindowTypeVersion(),
+		TimeFrom:            window.GetTimeFrom().AsTime().UTC(),
+		TimeTo:              window.GetTimeTo().AsTime().UTC(),
+		Origin:              window.GetOrigin(),
+		Metadata:            sql.NullString{String: string(metadataBytes), Valid: true},
+		TraceID:             traceIDText(ctx),
+		CreatedBy:           callerText(ctx),
+		Namespace:           sql.NullString{String: window.GetNamespace(), Valid: window.GetNamespace() != ""},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "could not insert window", "error", err)
+		// the window type is looked up by name, so a missing window type
+		// leaves the window without one
+		if isConstraintError(err, sqlite3.SQLITE_CONSTRAINT_NOTNULL) {
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, fmt.Errorf(
+				"window type does not exist - insert via window type registration: %v",
+				err.Error(),
+			)
+		}
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, RegisterWindowRow{}, err
+	}
+	slog.DebugContext(ctx, "window record inserted into the datalayer", logging.KeyWindowID, insertedWindow.ID)
+	index, err := d.dagIndex(ctx, qtx)
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"could not read the algorithm dag for window id",
+			logging.KeyWindowID,
+			insertedWindow.ID,
+			"error",
+			err,
+		)
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, err
+	}
+	var inNamespace func(procId int64) bool
+	if window.GetNamespace() != "" {
+		processors, err := qtx.ReadProcessorsInNamespace(ctx, window.GetNamespace())
+		if err != nil {
+			return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, fmt.Errorf("could not read processors of namespace: %w", err)
+		}
+		inNamespace = processorFilter(processors)
+	}
+
+	// fire off processings
+	_, planSpan := tracing.Tracer.Start(ctx, "BuildPlan")
+	planStart := time.Now()
+	executionPlan, err := index.Plan(insertedWindow.WindowTypeID, inNamespace)
+	metrics.PlanBuildDuration.Observe(time.Since(planStart).Seconds())
+	planSpan.SetAttributes(
+		attribute.Int("orca.stages", len(executionPlan.Stages)),
+		attribute.Int("orca.algorithms", executionPlan.Len()),
+	)
+	tracing.End(planSpan, err)
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to build the execution plan for window",
+			logging.KeyWindowID,
+			insertedWindow.ID,
+			"error",
+			err,
+		)
+		return pb.WindowEmitStatus_TRIGGERING_FAILED, dag.Plan{}, insertedWindow, err
+	}
+
+	if len(executionPlan.Stages) > 0 {
+		return pb.WindowEmitStatus_PROCESSING_TRIGGERED, executionPlan, insertedWindow, nil
+	}
+	return pb.WindowEmitStatus_NO_TRIGGERED_ALGORITHMS, executionPlan, insertedWindow, nil
+}
+
+func (d *Datalayer) Expose(
+	ctx context.Context,
+	settings *pb.ExposeSettings,
+) (*pb.InternalState, error) {
+	if err := auth.Require(ctx, auth.RoleReader, auth.NamespaceScope(settings.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	tx, err := d.WithTx(ctx)
+
+	defer func() {
+		if tx != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err != nil {
+		slog.Error("could not start a transaction", "error", err)
+		return nil, err
+	}
+
+	sqliteTx := tx.(*SqliteTx)
+
+	qtx := d.queries.WithTx(sqliteTx.tx)
+	var processors []Processor
+	if len(settings.GetNamespace()) > 0 {
+		processors, err = qtx.ReadProcessorsInNamespace(ctx, settings.GetNamespace())
+	} else if len(settings.GetExcludeProject()) > 0 {
+		processors, err = qtx.ReadProcessorExcludeProject(ctx, settings.GetExcludeProject())
+	} else {
+		// read all the processors
+		processors, err = qtx.ReadProcessors(ctx)
+	}
+	if err != nil {
+		slog.Error("could not read processors", "error", err)
+		return nil, fmt.Errorf("could not read processors: %w", err)
+	}
+
+	// read all the algorithms
+	algorithms, err := qtx.ReadAlgorithms(ctx)
+	if err != nil {
+		slog.Error("could not read algorithms", "error", err)
+		return nil, fmt.Errorf("could not read algorithms: %w", err)
+	}
+
+	// read all the metadata fields for window types from bridge table
+	wtmdf, err := qtx.ReadWindowTypeMetadataFields(ctx)
+	if err != nil {
+		slog.Error("could not read window type metadata fields", "error", err)
+		return nil, fmt.Errorf("could not read window type metadata fields: %w", err)
+	}
+	wtToMdf := make(map[string][]*pb.MetadataField)
+	for _, wtmd := range wtmdf {
+		_key := fmt.Sprintf("%v_%v_%v", wtmd.WindowTypeNamespace, wtmd.WindowTypeName, wtmd.WindowTypeVersion)
+
+		wtToMdf[_key] = append(wtToMdf[_key], &pb.MetadataField{
+			Name:        wtmd.MetadataFieldName,
+			Description: wtmd.MetadataFieldDescription,
+		})
+	}
+
+	// read all the window types
+	wts, err := qtx.ReadWindowTypes(ctx)
+	if err != nil {
+		slog.Error("could not read window types", "error", err)
+		return nil, fmt.Errorf("could not read window types: %w", err)
+	}
+	// read all the window schedules
+	windowSchedules, err := qtx.ReadWindowSchedules(ctx)
+	if err != nil {
+		slog.Error("could not read window schedules", "error", err)
+		return nil, fmt.Errorf("could not read window schedules: %w", err)
+	}
+	schedulesMap := make(map[int64]*pb.WindowSchedule, len(windowSchedules))
+	for _, windowSchedule := range windowSchedules {
+		schedulesMap[windowSchedule.WindowTypeID] = windowScheduleToPb(windowSchedule)
+	}
+
+	// read all the window rollups
+	windowRollups, err := qtx.ReadWindowRollups(ctx)
+	if err != nil {
+		slog.Error("could not read window rollups", "error", err)
+		return nil, fmt.Errorf("could not read window rollups: %w", err)
+	}
+	rollupsMap := make(map[int64]*pb.WindowRollup, len(windowRollups))
+	for _, windowRollup := range windowRollups {
+		rollupsMap[windowRollup.WindowTypeID] = windowRollupToPb(windowRollup)
+	}
+
+	wtsMap := make(map[int64]*pb.WindowType, len(wts))
+	for _, wt := range wts {
+		metadataFields, ok := wtToMdf[fmt.Sprintf("%v_%v_%v", wt.Namespace, wt.Name, wt.Version)]
+		if !ok {
+			slog.Info("no metadata fields found for window type", "windowType", wt)
+		}
+		wtsMap[wt.ID] = &pb.WindowType{
+			Name:           wt.Name,
+			Version:        wt.Version,
+			Description:    wt.Description,
+			MetadataFields: metadataFields,
+			Schedule:       schedulesMap[wt.ID],
+			Rollup:         rollupsMap[wt.ID],
+		}
+	}
+
+	algosForProcessor := make(map[int64][]*pb.Algorithm)
+	for _, algo := range algorithms {
+		// get the window type for this algorithm
+		wt, ok := wtsMap[algo.WindowTypeID]
+		if !ok {
+			slog.Error("could not find the window type id, which algorithm depends on", "window_type_id", algo.WindowTypeID, "algorithm_id", algo.ID)
+			return nil, fmt.Errorf("could not find the window type that algorithm %v, depends on", algo.Name)
+		}
+		algosForProcessor[algo.ProcessorID] = append(algosForProcessor[algo.ProcessorID], &pb.Algorithm{
+			Name:        algo.Name,
+			Version:     algo.Version,
+			WindowType:  wt,
+			ResultType:  resultTypeToPb(algo.ResultType),
+			Description: algo.Description,
+		})
+	}
+
+	processorsPb := make([]*pb.ProcessorRegistration, len(processors))
+
+	for ll, p := range processors {
+		// processors may register without algorithms
+		processorsPb[ll] = &pb.ProcessorRegistration{
+			Name:                p.Name,
+			Runtime:             p.Runtime,
+			ProjectName:         p.Namespace,
+			SupportedAlgorithms: algosForProcessor[p.ID],
+		}
+	}
+
+	slog.Debug("exposed state", "processors", processorsPb)
+	return &pb.InternalState{
+		Processors: processorsPb,
+	}, nil
+}
+
+// Ping checks that the database file can be read
+func (d *Datalayer) Ping(ctx context.Context) error {
+	return d.conn.PingContext(ctx)
+}
+
+// SchemaVersion reads the version that golang-migrate recorded
+func (d *Datalayer) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := d.conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case err != nil && strings.Contains(err.Error(), "no such table"):
+		// the database has never been migrated
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("could not read schema version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+// CreateAPIKey stores the hash of a new API key under a unique name
+func (d *Datalayer) CreateAPIKey(ctx context.Context, name string, keyHash string) error {
+	err := d.queries.CreateAPIKey(ctx, CreateAPIKeyParams{Name: name, KeyHash: keyHash})
+	if isUniqueViolation(err) {
+		// names are kept after revocation
+		return fmt.Errorf("an API key named %s already exists", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not create API key: %w", err)
+	}
+	return nil
+}
+
+// RevokeAPIKey revokes the API key of the given name
+func (d *Datalayer) RevokeAPIKey(ctx context.Context, name string) error {
+	revoked, err := d.queries.RevokeAPIKey(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not revoke API key: %w", err)
+	}
+	if revoked == 0 {
+		return fmt.Errorf("%w: no unrevoked key named %s", types.APIKeyNotFound, name)
+	}
+	return nil
+}
+
+// LookupAPIKey returns the name of the unrevoked API key with the given hash
+func (d *Datalayer) LookupAPIKey(ctx context.Context, keyHash string) (string, error) {
+	name, err := d.queries.ReadAPIKeyName(ctx, keyHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", types.APIKeyNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read API key: %w", err)
+	}
+	return name, nil
+}
+
+// CreateGrant grants a role to an identity, doing nothing when it is held
+// already
+func (d *Datalayer) CreateGrant(ctx context.Context, identity string, grant types.Grant) error {
+	err := d.queries.CreateGrant(ctx, CreateGrantParams{
+		Identity: identity,
+		Role:     grant.Role,
+		Scope:    grant.Scope,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create grant: %w", err)
+	}
+	return nil
+}
+
+// DeleteGrant takes a role away from an identity
+func (d *Datalayer) DeleteGrant(ctx context.Context, identity string, grant types.Grant) error {
+	deleted, err := d.queries.DeleteGrant(ctx, DeleteGrantParams{
+		Identity: identity,
+		Role:     grant.Role,
+		Scope:    grant.Scope,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete grant: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s does not hold the %s role for %s", types.GrantNotFound, identity, grant.Role, grant.Scope)
+	}
+	return nil
+}
+
+// ReadGrants returns the roles granted to an identity
+func (d *Datalayer) ReadGrants(ctx context.Context, identity string) ([]types.Grant, error) {
+	rows, err := d.queries.ReadGrants(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("could not read grants: %w", err)
+	}
+	grants := make([]types.Grant, len(rows))
+	for i, row := range rows {
+		grants[i] = types.Grant{Role: row.Role, Scope: row.Scope}
+	}
+	return grants, nil
+}
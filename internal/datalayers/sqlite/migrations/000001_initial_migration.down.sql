@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS role_grants;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS window_rollup_progress;
+DROP TABLE IF EXISTS window_rollup;
+DROP TABLE IF EXISTS window_schedule;
+DROP TABLE IF EXISTS results;
+DROP TABLE IF EXISTS windows;
+DROP VIEW IF EXISTS algorithm_execution_paths;
+DROP TABLE IF EXISTS algorithm_dependency;
+DROP VIEW IF EXISTS window_type_metadata_fields;
+DROP TABLE IF EXISTS metadata_fields_references;
+DROP TABLE IF EXISTS metadata_fields;
+DROP TABLE IF EXISTS algorithm;
+DROP TABLE IF EXISTS processor;
+DROP TABLE IF EXISTS window_type;
@@ -0,0 +1,267 @@
+-- The schema of the postgres datalayer as of its migration 17, in SQLite.
+-- Enums are checked text, arrays and JSONB are JSON text, and the
+-- materialized views are plain views
+
+-- Window types that can trigger algorithms
+CREATE TABLE window_type (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  version TEXT NOT NULL CHECK (
+    version GLOB '[0-9]*.[0-9]*.[0-9]*'
+    AND version NOT GLOB '*[^0-9.]*'
+    AND version NOT GLOB '*.*.*.*'
+  ),
+  description TEXT NOT NULL,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (name, version)
+);
+
+-- Processors that can execute algorithms, unique within their namespace
+CREATE TABLE processor (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  runtime TEXT NOT NULL, -- e.g. py3.*, go1.*, etc.
+  connection_string TEXT NOT NULL, -- the gRPC string to the client
+  namespace TEXT NOT NULL DEFAULT 'default',
+  registered_by TEXT,
+  tls_server_name TEXT,
+  tls_insecure_skip_verify BOOLEAN NOT NULL DEFAULT FALSE,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (namespace, name, runtime)
+);
+
+-- Store of all the algorithms
+CREATE TABLE algorithm (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  version TEXT NOT NULL CHECK (
+    version GLOB '[0-9]*.[0-9]*.[0-9]*'
+    AND version NOT GLOB '*[^0-9.]*'
+    AND version NOT GLOB '*.*.*.*'
+  ),
+  description TEXT NOT NULL DEFAULT '',
+  processor_id INTEGER NOT NULL REFERENCES processor(id),
+  window_type_id INTEGER NOT NULL REFERENCES window_type(id),
+  result_type TEXT NOT NULL CHECK (result_type IN ('struct', 'array', 'value', 'none')),
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (name, version, window_type_id, processor_id)
+);
+
+-- All metadata fields used by window types
+CREATE TABLE metadata_fields (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL
+);
+
+-- Bridge table to handle metadata field references
+CREATE TABLE metadata_fields_references (
+  window_type_id INTEGER NOT NULL REFERENCES window_type(id) ON DELETE CASCADE,
+  metadata_fields_id INTEGER NOT NULL REFERENCES metadata_fields(id) ON DELETE CASCADE,
+  PRIMARY KEY (window_type_id, metadata_fields_id)
+);
+
+CREATE INDEX idx_metadata_fields_references_id ON metadata_fields_references (metadata_fields_id);
+
+-- Window types with their metadata fields
+CREATE VIEW window_type_metadata_fields AS
+SELECT
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    mf.id AS metadata_field_id,
+    mf.name AS metadata_field_name,
+    mf.description AS metadata_field_description
+FROM window_type wt
+INNER JOIN metadata_fields_references mfr ON wt.id = mfr.window_type_id
+INNER JOIN metadata_fields mf ON mfr.metadata_fields_id = mf.id
+ORDER BY wt.name, wt.version, mf.name;
+
+-- Store of all the dependencies between algorithms
+CREATE TABLE algorithm_dependency (
+  id INTEGER PRIMARY KEY,
+  from_algorithm_id INTEGER NOT NULL REFERENCES algorithm(id),
+  to_algorithm_id INTEGER NOT NULL REFERENCES algorithm(id),
+  from_window_type_id INTEGER NOT NULL REFERENCES window_type(id),
+  to_window_type_id INTEGER NOT NULL REFERENCES window_type(id),
+  from_processor_id INTEGER NOT NULL REFERENCES processor(id),
+  to_processor_id INTEGER NOT NULL REFERENCES processor(id),
+  lookback_count INTEGER NOT NULL DEFAULT 0 CHECK (lookback_count >= 0),
+  lookback_timedelta INTEGER NOT NULL DEFAULT 0 CHECK (lookback_timedelta >= 0),
+  lookback_partition TEXT NOT NULL DEFAULT 'none' CHECK (
+    lookback_partition IN ('none', 'origin', 'metadata_field')
+  ),
+  lookback_partition_field_id INTEGER REFERENCES metadata_fields(id),
+  -- a NULL aggregate function sends the raw past results to the processor
+  lookback_aggregate TEXT CHECK (
+    lookback_aggregate IN ('count', 'sum', 'mean', 'min', 'max', 'last', 'percentile')
+  ),
+  lookback_aggregate_percentile REAL NOT NULL DEFAULT 0 CHECK (
+    lookback_aggregate_percentile BETWEEN 0 AND 1
+  ),
+  lookback_aggregate_bucket_timedelta INTEGER NOT NULL DEFAULT 0 CHECK (
+    lookback_aggregate_bucket_timedelta >= 0
+  ),
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (from_algorithm_id, to_algorithm_id),
+
+  -- Prevent self-dependencies
+  CHECK (from_algorithm_id != to_algorithm_id),
+  CHECK ((lookback_partition = 'metadata_field') = (lookback_partition_field_id IS NOT NULL)),
+  CHECK (lookback_aggregate IS NULL OR lookback_count > 0 OR lookback_timedelta > 0)
+);
+
+-- Execution paths of the DAG, from each algorithm without dependencies to
+-- each algorithm that nothing depends on, as dot separated ids
+CREATE VIEW algorithm_execution_paths AS
+WITH RECURSIVE search_tree AS (
+    -- root nodes
+    SELECT
+        a.id AS algo_id,
+        0 AS num_dependencies,
+        CAST(a.id AS TEXT) AS algo_id_path,
+        CAST(a.processor_id AS TEXT) AS proc_id_path,
+        CAST(a.window_type_id AS TEXT) AS window_type_id_path,
+        '0' AS lookback_count_path,
+        '0' AS lookback_timedelta_path,
+        'none' AS lookback_partition_path,
+        '0' AS lookback_partition_field_path
+    FROM
+        algorithm a
+    WHERE
+        a.id NOT IN (
+            SELECT ad.to_algorithm_id
+            FROM algorithm_dependency ad
+        )
+
+    UNION ALL
+
+    SELECT
+        ad.to_algorithm_id AS algo_id,
+        st.num_dependencies + 1,
+        st.algo_id_path || '.' || ad.to_algorithm_id,
+        st.proc_id_path || '.' || ad.to_processor_id,
+        st.window_type_id_path || '.' || ad.to_window_type_id,
+        st.lookback_count_path || '.' || ad.lookback_count,
+        st.lookback_timedelta_path || '.' || ad.lookback_timedelta,
+        st.lookback_partition_path || '.' || ad.lookback_partition,
+        st.lookback_partition_field_path || '.' || COALESCE(ad.lookback_partition_field_id, 0)
+    FROM
+        algorithm_dependency ad
+    JOIN
+        search_tree st ON ad.from_algorithm_id = st.algo_id
+)
+SELECT
+    CAST(st.algo_id AS INTEGER) AS final_algo_id,
+    CAST(st.num_dependencies AS INTEGER) AS num_dependencies,
+    CAST(st.algo_id_path AS TEXT) AS algo_id_path,
+    CAST(st.window_type_id_path AS TEXT) AS window_type_id_path,
+    CAST(st.proc_id_path AS TEXT) AS proc_id_path,
+    CAST(st.lookback_count_path AS TEXT) AS lookback_count_path,
+    CAST(st.lookback_timedelta_path AS TEXT) AS lookback_timedelta_path,
+    CAST(st.lookback_partition_path AS TEXT) AS lookback_partition_path,
+    CAST(st.lookback_partition_field_path AS TEXT) AS lookback_partition_field_path
+FROM search_tree st
+WHERE
+    -- leaf nodes
+    st.algo_id NOT IN (SELECT from_algorithm_id FROM algorithm_dependency)
+    OR st.num_dependencies = 0 -- no dependencies
+ORDER BY st.num_dependencies;
+
+-- Windows that trigger algorithms
+CREATE TABLE windows (
+  id INTEGER PRIMARY KEY,
+  window_type_id INTEGER NOT NULL REFERENCES window_type(id),
+  time_from TIMESTAMP NOT NULL,
+  time_to TIMESTAMP NOT NULL,
+  origin TEXT NOT NULL,   -- the location that emitted the window
+  metadata TEXT,          -- JSON, additional contextual information e.g. unique asset identifiers
+  trace_id TEXT,
+  created_by TEXT,
+  namespace TEXT,         -- the namespace the window was emitted to, or NULL for every namespace
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_windows_origin_time_to ON windows (origin, time_to);
+
+-- Where the results are stored
+CREATE TABLE results (
+  id INTEGER PRIMARY KEY,
+  windows_id INTEGER REFERENCES windows(id),
+  window_type_id INTEGER REFERENCES window_type(id),
+  algorithm_id INTEGER REFERENCES algorithm(id),
+  result_value REAL,
+  result_array TEXT, -- JSON array of numbers
+  result_json TEXT,
+  trace_id TEXT,
+  namespace TEXT NOT NULL DEFAULT 'default'
+);
+
+CREATE INDEX idx_results_algorithm_windows ON results (algorithm_id, windows_id);
+CREATE INDEX results_namespace_idx ON results (namespace);
+
+-- Schedules on which Orca core emits windows itself
+CREATE TABLE window_schedule (
+  window_type_id INTEGER PRIMARY KEY REFERENCES window_type(id) ON DELETE CASCADE,
+  cron TEXT,
+  interval_ns INTEGER NOT NULL DEFAULT 0 CHECK (interval_ns >= 0),
+  alignment_ns INTEGER NOT NULL DEFAULT 0 CHECK (alignment_ns >= 0),
+  time_zone TEXT NOT NULL DEFAULT '',
+  origin TEXT NOT NULL,
+  catch_up BOOLEAN NOT NULL DEFAULT FALSE,
+  high_water_mark TIMESTAMP NOT NULL, -- end of the last emitted window
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  -- either a cron expression or an interval
+  CHECK ((cron IS NULL) != (interval_ns = 0))
+);
+
+-- Rollups through which windows of a (parent) window type are emitted once
+-- the windows of a finer (child) window type that they cover have completed
+CREATE TABLE window_rollup (
+  window_type_id INTEGER PRIMARY KEY REFERENCES window_type(id) ON DELETE CASCADE,
+  child_window_type_name TEXT NOT NULL,
+  child_window_type_version TEXT NOT NULL,
+  interval_ns INTEGER NOT NULL CHECK (interval_ns > 0),
+  alignment_ns INTEGER NOT NULL DEFAULT 0 CHECK (alignment_ns >= 0),
+  time_zone TEXT NOT NULL DEFAULT '',
+  child_count INTEGER NOT NULL CHECK (child_count > 0),
+  late_window_policy TEXT NOT NULL DEFAULT 'ignore' CHECK (late_window_policy IN ('ignore', 'reemit')),
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_window_rollup_child ON window_rollup (child_window_type_name, child_window_type_version);
+
+-- Completion of the child windows of each parent window
+CREATE TABLE window_rollup_progress (
+  id INTEGER PRIMARY KEY,
+  window_type_id INTEGER NOT NULL REFERENCES window_type(id) ON DELETE CASCADE,
+  origin TEXT NOT NULL,
+  metadata TEXT NOT NULL DEFAULT '{}',
+  time_from TIMESTAMP NOT NULL,
+  time_to TIMESTAMP NOT NULL,
+  child_windows TEXT NOT NULL DEFAULT '[]', -- JSON array of the start times of the completed child windows
+  emitted TIMESTAMP,                        -- when the parent window was last emitted
+  UNIQUE (window_type_id, origin, metadata, time_from)
+);
+
+-- API keys of OrcaCore clients. Only the SHA-256 hash of a key is stored, the
+-- key itself is shown once when it is created
+CREATE TABLE api_keys (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  key_hash TEXT NOT NULL UNIQUE,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  revoked TIMESTAMP
+);
+
+-- Roles held by authenticated callers, by the identity they authenticate as
+-- (e.g. api_key:ci-pipeline). The scope is the project of a processor, or the
+-- window type of an emitter, with '*' covering every scope
+CREATE TABLE role_grants (
+  id INTEGER PRIMARY KEY,
+  identity TEXT NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('admin', 'processor', 'emitter', 'reader')),
+  scope TEXT NOT NULL DEFAULT '*',
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (identity, role, scope)
+);
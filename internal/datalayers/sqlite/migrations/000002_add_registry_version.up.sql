@@ -0,0 +1,8 @@
+-- The version of the algorithms and their dependencies, bumped by every
+-- processor registration, so clients of the database know when to reload
+-- their DAG
+CREATE TABLE registry_version (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  version INTEGER NOT NULL
+);
+INSERT INTO registry_version (id, version) VALUES (1, 0);
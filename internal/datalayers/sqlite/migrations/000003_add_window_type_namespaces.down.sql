@@ -0,0 +1,31 @@
+DROP VIEW window_type_metadata_fields;
+
+-- fails when window types of different namespaces share a name and version
+CREATE TABLE window_type_old (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  version TEXT NOT NULL CHECK (
+    version GLOB '[0-9]*.[0-9]*.[0-9]*'
+    AND version NOT GLOB '*[^0-9.]*'
+    AND version NOT GLOB '*.*.*.*'
+  ),
+  description TEXT NOT NULL,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (name, version)
+);
+INSERT INTO window_type_old (id, name, version, description, created)
+SELECT id, name, version, description, created FROM window_type;
+DROP TABLE window_type;
+ALTER TABLE window_type_old RENAME TO window_type;
+
+CREATE VIEW window_type_metadata_fields AS
+SELECT
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    mf.id AS metadata_field_id,
+    mf.name AS metadata_field_name,
+    mf.description AS metadata_field_description
+FROM window_type wt
+INNER JOIN metadata_fields_references mfr ON wt.id = mfr.window_type_id
+INNER JOIN metadata_fields mf ON mfr.metadata_fields_id = mf.id
+ORDER BY wt.name, wt.version, mf.name;
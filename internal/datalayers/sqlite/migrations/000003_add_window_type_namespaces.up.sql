@@ -0,0 +1,98 @@
+-- Window types are registered in the namespace of the processor registering
+-- them, so projects can define window types of the same name and version with
+-- their own metadata fields, schedules and rollups. SQLite cannot drop a
+-- unique constraint, so the table is rebuilt
+DROP VIEW window_type_metadata_fields;
+
+CREATE TABLE window_type_new (
+  id INTEGER PRIMARY KEY,
+  namespace TEXT NOT NULL DEFAULT 'default',
+  name TEXT NOT NULL,
+  version TEXT NOT NULL CHECK (
+    version GLOB '[0-9]*.[0-9]*.[0-9]*'
+    AND version NOT GLOB '*[^0-9.]*'
+    AND version NOT GLOB '*.*.*.*'
+  ),
+  description TEXT NOT NULL,
+  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (namespace, name, version)
+);
+INSERT INTO window_type_new (id, name, version, description, created)
+SELECT id, name, version, description, created FROM window_type;
+DROP TABLE window_type;
+ALTER TABLE window_type_new RENAME TO window_type;
+
+-- Window types with their metadata fields
+CREATE VIEW window_type_metadata_fields AS
+SELECT
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    mf.id AS metadata_field_id,
+    mf.name AS metadata_field_name,
+    mf.description AS metadata_field_description
+FROM window_type wt
+INNER JOIN metadata_fields_references mfr ON wt.id = mfr.window_type_id
+INNER JOIN metadata_fields mf ON mfr.metadata_fields_id = mf.id
+ORDER BY wt.namespace, wt.name, wt.version, mf.name;
+
+-- window types were shared between namespaces. Those only used by the
+-- algorithms of another namespace move to it
+UPDATE window_type SET namespace = (
+  SELECT MIN(p.namespace)
+  FROM algorithm a
+  JOIN processor p ON p.id = a.processor_id
+  WHERE a.window_type_id = window_type.id
+)
+WHERE id IN (
+  SELECT a.window_type_id
+  FROM algorithm a
+  JOIN processor p ON p.id = a.processor_id
+  GROUP BY a.window_type_id
+  HAVING COUNT(DISTINCT p.namespace) = 1 AND MIN(p.namespace) != 'default'
+);
+
+-- and every other namespace whose algorithms use a window type gets its own
+-- copy of it, along with its metadata fields, schedule and rollup
+INSERT INTO window_type (namespace, name, version, description)
+SELECT DISTINCT p.namespace, wt.name, wt.version, wt.description
+FROM algorithm a
+JOIN processor p ON p.id = a.processor_id
+JOIN window_type wt ON wt.id = a.window_type_id
+WHERE p.namespace != wt.namespace;
+
+INSERT INTO metadata_fields_references (window_type_id, metadata_fields_id)
+SELECT nwt.id, mfr.metadata_fields_id
+FROM metadata_fields_references mfr
+JOIN window_type wt ON wt.id = mfr.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+INSERT INTO window_schedule (window_type_id, cron, interval_ns, alignment_ns, time_zone, origin, catch_up, high_water_mark)
+SELECT nwt.id, ws.cron, ws.interval_ns, ws.alignment_ns, ws.time_zone, ws.origin, ws.catch_up, ws.high_water_mark
+FROM window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+INSERT INTO window_rollup (window_type_id, child_window_type_name, child_window_type_version, interval_ns, alignment_ns, time_zone, child_count, late_window_policy)
+SELECT nwt.id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy
+FROM window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
+JOIN window_type nwt ON nwt.name = wt.name AND nwt.version = wt.version
+WHERE wt.namespace = 'default' AND nwt.namespace != 'default';
+
+UPDATE algorithm SET window_type_id = (
+  SELECT nwt.id
+  FROM processor p, window_type wt, window_type nwt
+  WHERE p.id = algorithm.processor_id
+  AND wt.id = algorithm.window_type_id
+  AND nwt.namespace = p.namespace
+  AND nwt.name = wt.name
+  AND nwt.version = wt.version
+)
+WHERE processor_id IN (SELECT id FROM processor WHERE namespace != 'default');
+
+UPDATE algorithm_dependency SET
+  from_window_type_id = (SELECT a.window_type_id FROM algorithm a WHERE a.id = algorithm_dependency.from_algorithm_id),
+  to_window_type_id = (SELECT a.window_type_id FROM algorithm a WHERE a.id = algorithm_dependency.to_algorithm_id);
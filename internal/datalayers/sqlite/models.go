@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Algorithm struct {
+	ID           int64
+	Name         string
+	Version      string
+	Description  string
+	ProcessorID  int64
+	WindowTypeID int64
+	ResultType   string
+	Created      sql.NullTime
+}
+
+type AlgorithmDependency struct {
+	ID                               int64
+	FromAlgorithmID                  int64
+	ToAlgorithmID                    int64
+	FromWindowTypeID                 int64
+	ToWindowTypeID                   int64
+	FromProcessorID                  int64
+	ToProcessorID                    int64
+	LookbackCount                    int64
+	LookbackTimedelta                int64
+	LookbackPartition                string
+	LookbackPartitionFieldID         sql.NullInt64
+	LookbackAggregate                sql.NullString
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
+	Created                          sql.NullTime
+}
+
+type AlgorithmExecutionPath struct {
+	FinalAlgoID                int64
+	NumDependencies            int64
+	AlgoIDPath                 string
+	WindowTypeIDPath           string
+	ProcIDPath                 string
+	LookbackCountPath          string
+	LookbackTimedeltaPath      string
+	LookbackPartitionPath      string
+	LookbackPartitionFieldPath string
+}
+
+type ApiKey struct {
+	ID      int64
+	Name    string
+	KeyHash string
+	Created sql.NullTime
+	Revoked sql.NullTime
+}
+
+type MetadataField struct {
+	ID          int64
+	Name        string
+	Description string
+}
+
+type MetadataFieldsReference struct {
+	WindowTypeID     int64
+	MetadataFieldsID int64
+}
+
+type Processor struct {
+	ID                    int64
+	Name                  string
+	Runtime               string
+	ConnectionString      string
+	Namespace             string
+	RegisteredBy          sql.NullString
+	TlsServerName         sql.NullString
+	TlsInsecureSkipVerify bool
+	Created               sql.NullTime
+}
+
+type RegistryVersion struct {
+	ID      int64
+	Version int64
+}
+
+type Result struct {
+	ID           int64
+	WindowsID    sql.NullInt64
+	WindowTypeID sql.NullInt64
+	AlgorithmID  sql.NullInt64
+	ResultValue  sql.NullFloat64
+	ResultArray  sql.NullString
+	ResultJson   sql.NullString
+	TraceID      sql.NullString
+	Namespace    string
+}
+
+type RoleGrant struct {
+	ID       int64
+	Identity string
+	Role     string
+	Scope    string
+	Created  sql.NullTime
+}
+
+type Window struct {
+	ID           int64
+	WindowTypeID int64
+	TimeFrom     time.Time
+	TimeTo       time.Time
+	Origin       string
+	Metadata     sql.NullString
+	TraceID      sql.NullString
+	CreatedBy    sql.NullString
+	Namespace    sql.NullString
+	Created      sql.NullTime
+}
+
+type WindowRollup struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int64
+	LateWindowPolicy       string
+	Created                sql.NullTime
+}
+
+type WindowRollupProgress struct {
+	ID           int64
+	WindowTypeID int64
+	Origin       string
+	Metadata     string
+	TimeFrom     time.Time
+	TimeTo       time.Time
+	ChildWindows string
+	Emitted      sql.NullTime
+}
+
+type WindowSchedule struct {
+	WindowTypeID  int64
+	Cron          sql.NullString
+	IntervalNs    int64
+	AlignmentNs   int64
+	TimeZone      string
+	Origin        string
+	CatchUp       bool
+	HighWaterMark time.Time
+	Created       sql.NullTime
+}
+
+type WindowType struct {
+	ID          int64
+	Namespace   string
+	Name        string
+	Version     string
+	Description string
+	Created     sql.NullTime
+}
+
+type WindowTypeMetadataField struct {
+	WindowTypeNamespace      string
+	WindowTypeName           string
+	WindowTypeVersion        string
+	MetadataFieldID          int64
+	MetadataFieldName        string
+	MetadataFieldDescription string
+}
@@ -0,0 +1,502 @@
+---------------------- Core Operations ----------------------
+-- name: CreateProcessor :exec
+INSERT INTO processor (
+  name,
+  runtime,
+  connection_string,
+  namespace,
+  registered_by,
+  tls_server_name,
+  tls_insecure_skip_verify
+) VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('runtime'),
+  sqlc.arg('connection_string'),
+  sqlc.arg('namespace'),
+  sqlc.narg('registered_by'),
+  sqlc.narg('tls_server_name'),
+  sqlc.arg('tls_insecure_skip_verify')
+) ON CONFLICT (namespace, name, runtime) DO UPDATE
+SET
+  connection_string = excluded.connection_string,
+  registered_by = excluded.registered_by,
+  tls_server_name = excluded.tls_server_name,
+  tls_insecure_skip_verify = excluded.tls_insecure_skip_verify;
+
+-- name: CreateMetadataField :one
+INSERT INTO metadata_fields (
+  name,
+  description
+) VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('description')
+) ON CONFLICT (name) DO UPDATE
+SET
+  description = excluded.description
+RETURNING id;
+
+-- name: CreateWindowType :one
+INSERT INTO window_type (
+  namespace,
+  name,
+  version,
+  description
+) VALUES (
+  sqlc.arg('namespace'),
+  sqlc.arg('name'),
+  sqlc.arg('version'),
+  sqlc.arg('description')
+) ON CONFLICT (namespace, name, version) DO UPDATE
+SET
+  description = excluded.description
+RETURNING id;
+
+-- name: CreateWindowTypeMetadataFieldBridge :exec
+INSERT INTO metadata_fields_references (
+  window_type_id,
+  metadata_fields_id
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.arg('metadata_fields_id')
+) ON CONFLICT (window_type_id, metadata_fields_id) DO NOTHING;
+
+-- name: CreateAlgorithm :exec
+INSERT INTO algorithm (
+  name,
+  version,
+  description,
+  processor_id,
+  window_type_id,
+  result_type
+) VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('version'),
+  sqlc.arg('description'),
+  (
+    SELECT p.id FROM processor p
+    WHERE p.name = sqlc.arg('processor_name')
+    AND p.runtime = sqlc.arg('processor_runtime')
+    AND p.namespace = sqlc.arg('processor_namespace')
+  ),
+  (
+    SELECT w.id FROM window_type w
+    WHERE w.namespace = sqlc.arg('processor_namespace')
+    AND w.name = sqlc.arg('window_type_name')
+    AND w.version = sqlc.arg('window_type_version')
+  ),
+  sqlc.arg('result_type')
+) ON CONFLICT DO NOTHING;
+
+-- name: ReadAlgorithmsForWindow :many
+SELECT a.* FROM algorithm a
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE wt.namespace = sqlc.arg('window_type_namespace')
+AND wt.name = sqlc.arg('window_type_name')
+AND wt.version = sqlc.arg('window_type_version');
+
+-- name: ReadAlgorithms :many
+SELECT a.* FROM algorithm a;
+
+-- name: CreateAlgorithmDependency :one
+INSERT INTO algorithm_dependency (
+  from_algorithm_id,
+  to_algorithm_id,
+  from_window_type_id,
+  to_window_type_id,
+  from_processor_id,
+  to_processor_id,
+  lookback_count,
+  lookback_timedelta,
+  lookback_partition,
+  lookback_partition_field_id,
+  lookback_aggregate,
+  lookback_aggregate_percentile,
+  lookback_aggregate_bucket_timedelta
+) VALUES (
+  sqlc.arg('from_algorithm_id'),
+  sqlc.arg('to_algorithm_id'),
+  (SELECT a.window_type_id FROM algorithm a WHERE a.id = sqlc.arg('from_algorithm_id')),
+  (SELECT a.window_type_id FROM algorithm a WHERE a.id = sqlc.arg('to_algorithm_id')),
+  (SELECT a.processor_id FROM algorithm a WHERE a.id = sqlc.arg('from_algorithm_id')),
+  (SELECT a.processor_id FROM algorithm a WHERE a.id = sqlc.arg('to_algorithm_id')),
+  sqlc.arg('lookback_count'),
+  sqlc.arg('lookback_timedelta'),
+  sqlc.arg('lookback_partition'),
+  (SELECT mf.id FROM metadata_fields mf WHERE mf.name = sqlc.narg('lookback_partition_field')),
+  sqlc.narg('lookback_aggregate'),
+  sqlc.arg('lookback_aggregate_percentile'),
+  sqlc.arg('lookback_aggregate_bucket_timedelta')
+) ON CONFLICT (from_algorithm_id, to_algorithm_id) DO UPDATE
+  SET
+    from_window_type_id = excluded.from_window_type_id,
+    to_window_type_id = excluded.to_window_type_id,
+    from_processor_id = excluded.from_processor_id,
+    to_processor_id = excluded.to_processor_id,
+    lookback_count = excluded.lookback_count,
+    lookback_timedelta = excluded.lookback_timedelta,
+    lookback_partition = excluded.lookback_partition,
+    lookback_partition_field_id = excluded.lookback_partition_field_id,
+    lookback_aggregate = excluded.lookback_aggregate,
+    lookback_aggregate_percentile = excluded.lookback_aggregate_percentile,
+    lookback_aggregate_bucket_timedelta = excluded.lookback_aggregate_bucket_timedelta
+RETURNING *;
+
+-- name: ReadAlgorithmId :one
+SELECT a.id FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.name = sqlc.arg('algorithm_name')
+AND a.version = sqlc.arg('algorithm_version')
+AND p.name = sqlc.arg('processor_name')
+AND p.runtime = sqlc.arg('processor_runtime')
+AND p.namespace = sqlc.arg('processor_namespace');
+
+-- name: ReadAlgorithm :one
+SELECT a.* FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE a.name = sqlc.arg('algorithm_name')
+AND a.version = sqlc.arg('algorithm_version')
+AND p.name = sqlc.arg('processor_name')
+AND p.runtime = sqlc.arg('processor_runtime')
+AND p.namespace = sqlc.arg('processor_namespace')
+AND wt.namespace = p.namespace
+AND wt.name = sqlc.arg('window_type_name')
+AND wt.version = sqlc.arg('window_type_version');
+
+-- name: ReadAlgorithmDependencies :many
+SELECT ad.* FROM algorithm_dependency ad;
+
+-- name: ReadRegistryVersion :one
+SELECT version FROM registry_version;
+
+-- name: BumpRegistryVersion :one
+UPDATE registry_version SET version = version + 1 RETURNING version;
+
+-- name: ReadWindowTypes :many
+SELECT wt.* FROM window_type wt;
+
+-- name: ReadWindowTypeNamespaces :many
+SELECT wt.namespace FROM window_type wt
+WHERE wt.name = sqlc.arg('window_type_name')
+AND wt.version = sqlc.arg('window_type_version')
+ORDER BY wt.namespace;
+
+-- name: RegisterWindow :one
+INSERT INTO windows (
+  window_type_id,
+  time_from,
+  time_to,
+  origin,
+  metadata,
+  trace_id,
+  created_by,
+  namespace
+) VALUES (
+  (
+    SELECT wt.id FROM window_type wt
+    WHERE wt.namespace = sqlc.arg('window_type_namespace')
+    AND wt.name = sqlc.arg('window_type_name')
+    AND wt.version = sqlc.arg('window_type_version')
+  ),
+  sqlc.arg('time_from'),
+  sqlc.arg('time_to'),
+  sqlc.arg('origin'),
+  sqlc.arg('metadata'),
+  sqlc.narg('trace_id'),
+  sqlc.narg('created_by'),
+  sqlc.narg('namespace')
+) RETURNING window_type_id, id;
+
+-- name: CreateResult :one
+INSERT INTO results (
+  windows_id,
+  window_type_id,
+  algorithm_id,
+  result_value,
+  result_array,
+  result_json,
+  trace_id,
+  namespace
+) VALUES (
+  sqlc.arg('windows_id'),
+  sqlc.arg('window_type_id'),
+  sqlc.arg('algorithm_id'),
+  sqlc.arg('result_value'),
+  sqlc.arg('result_array'),
+  sqlc.arg('result_json'),
+  sqlc.narg('trace_id'),
+  (SELECT p.namespace FROM algorithm a JOIN processor p ON a.processor_id = p.id WHERE a.id = sqlc.arg('algorithm_id'))
+) RETURNING id;
+
+-- name: ReadProcessors :many
+SELECT * FROM processor;
+
+-- name: ReadProcessorExcludeProject :many
+SELECT * FROM processor WHERE namespace != sqlc.arg('namespace');
+
+-- name: ReadProcessorsInNamespace :many
+SELECT * FROM processor WHERE namespace = sqlc.arg('namespace');
+
+-- name: ReadProcessorsByIDs :many
+SELECT *
+FROM processor
+WHERE id IN (sqlc.slice('processor_ids'))
+ORDER BY name, runtime;
+
+-- name: ReadMetadataFieldsByWindowType :many
+SELECT
+    metadata_field_id,
+    metadata_field_name,
+    metadata_field_description
+FROM window_type_metadata_fields
+WHERE window_type_namespace = sqlc.arg('window_type_namespace')
+  AND window_type_name = sqlc.arg('window_type_name')
+  AND window_type_version = sqlc.arg('window_type_version')
+ORDER BY metadata_field_name;
+
+-- name: ReadMetadataFields :many
+SELECT * FROM metadata_fields;
+
+-- name: ReadWindowTypeMetadataFields :many
+SELECT * FROM window_type_metadata_fields;
+
+-- name: ReadAlgorithmResultType :one
+SELECT result_type FROM algorithm WHERE id = sqlc.arg('algorithm_id');
+
+-- name: ReadAlgorithmsByIDs :many
+SELECT
+    a.id,
+    a.name,
+    a.version,
+    p.name AS processor_name
+FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.id IN (sqlc.slice('algorithm_ids'));
+
+-- name: ReadLookbackAggregatesForAlgorithms :many
+SELECT
+    ad.from_algorithm_id,
+    ad.to_algorithm_id,
+    ad.lookback_aggregate,
+    ad.lookback_aggregate_percentile,
+    ad.lookback_aggregate_bucket_timedelta
+FROM
+    algorithm_dependency ad
+WHERE
+    ad.lookback_aggregate IS NOT NULL
+    AND ad.to_algorithm_id IN (sqlc.slice('algorithm_ids'));
+
+-- name: ReadResultsForLookback :many
+-- the latest results of an algorithm, newest first. A negative limit does
+-- not limit the lookback, a NULL search_from does not bound it, a NULL
+-- origin or an empty partition path does not partition it
+SELECT
+    r.id AS result_id,
+    r.algorithm_id,
+    a.result_type,
+    w.id AS window_id,
+    r.result_value,
+    r.result_array,
+    r.result_json,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    w.time_from AS window_time_from,
+    w.time_to AS window_time_to,
+    w.origin AS window_origin,
+    w.metadata AS window_metadata
+FROM
+    results r
+JOIN algorithm a ON a.id = r.algorithm_id
+JOIN windows w ON w.id = r.windows_id
+JOIN window_type wt ON wt.id = w.window_type_id
+WHERE
+    r.algorithm_id = sqlc.arg('algorithm_id')
+    AND (sqlc.narg('search_from') IS NULL OR w.time_from > sqlc.narg('search_from'))
+    AND w.time_to < sqlc.arg('search_to')
+    AND (CAST(sqlc.narg('origin') AS TEXT) IS NULL OR w.origin = sqlc.narg('origin'))
+    AND (
+        CAST(sqlc.arg('partition_path') AS TEXT) = ''
+        OR json_extract(w.metadata, sqlc.arg('partition_path')) IS json_extract(CAST(sqlc.arg('partition_value') AS TEXT), '$')
+    )
+ORDER BY w.time_to DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CreateWindowSchedule :exec
+-- the high water mark of an existing schedule is kept, so that
+-- re-registering a window type neither skips nor repeats windows. It is
+-- reset when the times the schedule fires at change, as it would no longer
+-- be aligned to them
+INSERT INTO window_schedule (
+  window_type_id,
+  cron,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  origin,
+  catch_up,
+  high_water_mark
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.narg('cron'),
+  sqlc.arg('interval_ns'),
+  sqlc.arg('alignment_ns'),
+  sqlc.arg('time_zone'),
+  sqlc.arg('origin'),
+  sqlc.arg('catch_up'),
+  sqlc.arg('high_water_mark')
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    high_water_mark = CASE
+      WHEN window_schedule.cron IS excluded.cron
+        AND window_schedule.interval_ns = excluded.interval_ns
+        AND window_schedule.alignment_ns = excluded.alignment_ns
+        AND window_schedule.time_zone = excluded.time_zone
+      THEN window_schedule.high_water_mark
+      ELSE excluded.high_water_mark
+    END,
+    cron = excluded.cron,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    origin = excluded.origin,
+    catch_up = excluded.catch_up;
+
+-- name: DeleteWindowSchedule :exec
+DELETE FROM window_schedule WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: ReadWindowSchedules :many
+SELECT
+    ws.*,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id;
+
+-- name: ReadWindowScheduleHighWaterMark :one
+SELECT high_water_mark FROM window_schedule
+WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: UpdateWindowScheduleHighWaterMark :exec
+UPDATE window_schedule SET high_water_mark = sqlc.arg('high_water_mark')
+WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: CreateWindowRollup :exec
+INSERT INTO window_rollup (
+  window_type_id,
+  child_window_type_name,
+  child_window_type_version,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  child_count,
+  late_window_policy
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.arg('child_window_type_name'),
+  sqlc.arg('child_window_type_version'),
+  sqlc.arg('interval_ns'),
+  sqlc.arg('alignment_ns'),
+  sqlc.arg('time_zone'),
+  sqlc.arg('child_count'),
+  sqlc.arg('late_window_policy')
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    child_window_type_name = excluded.child_window_type_name,
+    child_window_type_version = excluded.child_window_type_version,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    child_count = excluded.child_count,
+    late_window_policy = excluded.late_window_policy;
+
+-- name: DeleteWindowRollup :exec
+DELETE FROM window_rollup WHERE window_type_id = sqlc.arg('window_type_id');
+
+-- name: ReadWindowRollups :many
+SELECT wr.* FROM window_rollup wr;
+
+-- name: ReadWindowRollupsForChild :many
+SELECT
+    wr.*,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
+WHERE
+    wt.namespace = sqlc.arg('window_type_namespace')
+    AND wr.child_window_type_name = sqlc.arg('child_window_type_name')
+    AND wr.child_window_type_version = sqlc.arg('child_window_type_version');
+
+-- name: CreateWindowRollupProgress :exec
+INSERT INTO window_rollup_progress (
+  window_type_id,
+  origin,
+  metadata,
+  time_from,
+  time_to
+) VALUES (
+  sqlc.arg('window_type_id'),
+  sqlc.arg('origin'),
+  sqlc.arg('metadata'),
+  sqlc.arg('time_from'),
+  sqlc.arg('time_to')
+) ON CONFLICT (window_type_id, origin, metadata, time_from) DO NOTHING;
+
+-- name: ReadWindowRollupProgress :one
+SELECT wrp.* FROM window_rollup_progress wrp
+WHERE
+    wrp.window_type_id = sqlc.arg('window_type_id')
+    AND wrp.origin = sqlc.arg('origin')
+    AND wrp.metadata = sqlc.arg('metadata')
+    AND wrp.time_from = sqlc.arg('time_from');
+
+-- name: UpdateWindowRollupProgress :exec
+UPDATE window_rollup_progress
+SET
+    child_windows = sqlc.arg('child_windows'),
+    emitted = sqlc.narg('emitted')
+WHERE id = sqlc.arg('id');
+
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (
+  name,
+  key_hash
+) VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('key_hash')
+);
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked = CURRENT_TIMESTAMP
+WHERE name = sqlc.arg('name') AND revoked IS NULL;
+
+-- name: ReadAPIKeyName :one
+SELECT name FROM api_keys
+WHERE key_hash = sqlc.arg('key_hash') AND revoked IS NULL;
+
+-- name: CreateGrant :exec
+INSERT INTO role_grants (
+  identity,
+  role,
+  scope
+) VALUES (
+  sqlc.arg('identity'),
+  sqlc.arg('role'),
+  sqlc.arg('scope')
+) ON CONFLICT (identity, role, scope) DO NOTHING;
+
+-- name: DeleteGrant :execrows
+DELETE FROM role_grants
+WHERE identity = sqlc.arg('identity')
+AND role = sqlc.arg('role')
+AND scope = sqlc.arg('scope');
+
+-- name: ReadGrants :many
+SELECT role, scope FROM role_grants
+WHERE identity = sqlc.arg('identity')
+ORDER BY role, scope;
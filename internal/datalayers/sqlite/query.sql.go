@@ -0,0 +1,1749 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+const bumpRegistryVersion = `-- name: BumpRegistryVersion :one
+UPDATE registry_version SET version = version + 1 RETURNING version
+`
+
+func (q *Queries) BumpRegistryVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, bumpRegistryVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :exec
+INSERT INTO api_keys (
+  name,
+  key_hash
+) VALUES (
+  ?1,
+  ?2
+)
+`
+
+type CreateAPIKeyParams struct {
+	Name    string
+	KeyHash string
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) error {
+	_, err := q.db.ExecContext(ctx, createAPIKey, arg.Name, arg.KeyHash)
+	return err
+}
+
+const createAlgorithm = `-- name: CreateAlgorithm :exec
+INSERT INTO algorithm (
+  name,
+  version,
+  description,
+  processor_id,
+  window_type_id,
+  result_type
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  (
+    SELECT p.id FROM processor p
+    WHERE p.name = ?4
+    AND p.runtime = ?5
+    AND p.namespace = ?6
+  ),
+  (
+    SELECT w.id FROM window_type w
+    WHERE w.namespace = ?6
+    AND w.name = ?7
+    AND w.version = ?8
+  ),
+  ?9
+) ON CONFLICT DO NOTHING
+`
+
+type CreateAlgorithmParams struct {
+	Name               string
+	Version            string
+	Description        string
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
+	WindowTypeName     string
+	WindowTypeVersion  string
+	ResultType         string
+}
+
+func (q *Queries) CreateAlgorithm(ctx context.Context, arg CreateAlgorithmParams) error {
+	_, err := q.db.ExecContext(ctx, createAlgorithm,
+		arg.Name,
+		arg.Version,
+		arg.Description,
+		arg.ProcessorName,
+		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
+		arg.WindowTypeName,
+		arg.WindowTypeVersion,
+		arg.ResultType,
+	)
+	return err
+}
+
+const createAlgorithmDependency = `-- name: CreateAlgorithmDependency :one
+INSERT INTO algorithm_dependency (
+  from_algorithm_id,
+  to_algorithm_id,
+  from_window_type_id,
+  to_window_type_id,
+  from_processor_id,
+  to_processor_id,
+  lookback_count,
+  lookback_timedelta,
+  lookback_partition,
+  lookback_partition_field_id,
+  lookback_aggregate,
+  lookback_aggregate_percentile,
+  lookback_aggregate_bucket_timedelta
+) VALUES (
+  ?1,
+  ?2,
+  (SELECT a.window_type_id FROM algorithm a WHERE a.id = ?1),
+  (SELECT a.window_type_id FROM algorithm a WHERE a.id = ?2),
+  (SELECT a.processor_id FROM algorithm a WHERE a.id = ?1),
+  (SELECT a.processor_id FROM algorithm a WHERE a.id = ?2),
+  ?3,
+  ?4,
+  ?5,
+  (SELECT mf.id FROM metadata_fields mf WHERE mf.name = ?6),
+  ?7,
+  ?8,
+  ?9
+) ON CONFLICT (from_algorithm_id, to_algorithm_id) DO UPDATE
+  SET
+    from_window_type_id = excluded.from_window_type_id,
+    to_window_type_id = excluded.to_window_type_id,
+    from_processor_id = excluded.from_processor_id,
+    to_processor_id = excluded.to_processor_id,
+    lookback_count = excluded.lookback_count,
+    lookback_timedelta = excluded.lookback_timedelta,
+    lookback_partition = excluded.lookback_partition,
+    lookback_partition_field_id = excluded.lookback_partition_field_id,
+    lookback_aggregate = excluded.lookback_aggregate,
+    lookback_aggregate_percentile = excluded.lookback_aggregate_percentile,
+    lookback_aggregate_bucket_timedelta = excluded.lookback_aggregate_bucket_timedelta
+RETURNING id, from_algorithm_id, to_algorithm_id, from_window_type_id, to_window_type_id, from_processor_id, to_processor_id, lookback_count, lookback_timedelta, lookback_partition, lookback_partition_field_id, lookback_aggregate, lookback_aggregate_percentile, lookback_aggregate_bucket_timedelta, created
+`
+
+type CreateAlgorithmDependencyParams struct {
+	FromAlgorithmID                  int64
+	ToAlgorithmID                    int64
+	LookbackCount                    int64
+	LookbackTimedelta                int64
+	LookbackPartition                string
+	LookbackPartitionField           sql.NullString
+	LookbackAggregate                sql.NullString
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
+}
+
+func (q *Queries) CreateAlgorithmDependency(ctx context.Context, arg CreateAlgorithmDependencyParams) (AlgorithmDependency, error) {
+	row := q.db.QueryRowContext(ctx, createAlgorithmDependency,
+		arg.FromAlgorithmID,
+		arg.ToAlgorithmID,
+		arg.LookbackCount,
+		arg.LookbackTimedelta,
+		arg.LookbackPartition,
+		arg.LookbackPartitionField,
+		arg.LookbackAggregate,
+		arg.LookbackAggregatePercentile,
+		arg.LookbackAggregateBucketTimedelta,
+	)
+	var i AlgorithmDependency
+	err := row.Scan(
+		&i.ID,
+		&i.FromAlgorithmID,
+		&i.ToAlgorithmID,
+		&i.FromWindowTypeID,
+		&i.ToWindowTypeID,
+		&i.FromProcessorID,
+		&i.ToProcessorID,
+		&i.LookbackCount,
+		&i.LookbackTimedelta,
+		&i.LookbackPartition,
+		&i.LookbackPartitionFieldID,
+		&i.LookbackAggregate,
+		&i.LookbackAggregatePercentile,
+		&i.LookbackAggregateBucketTimedelta,
+		&i.Created,
+	)
+	return i, err
+}
+
+const createGrant = `-- name: CreateGrant :exec
+INSERT INTO role_grants (
+  identity,
+  role,
+  scope
+) VALUES (
+  ?1,
+  ?2,
+  ?3
+) ON CONFLICT (identity, role, scope) DO NOTHING
+`
+
+type CreateGrantParams struct {
+	Identity string
+	Role     string
+	Scope    string
+}
+
+func (q *Queries) CreateGrant(ctx context.Context, arg CreateGrantParams) error {
+	_, err := q.db.ExecContext(ctx, createGrant, arg.Identity, arg.Role, arg.Scope)
+	return err
+}
+
+const createMetadataField = `-- name: CreateMetadataField :one
+INSERT INTO metadata_fields (
+  name,
+  description
+) VALUES (
+  ?1,
+  ?2
+) ON CONFLICT (name) DO UPDATE
+SET
+  description = excluded.description
+RETURNING id
+`
+
+type CreateMetadataFieldParams struct {
+	Name        string
+	Description string
+}
+
+func (q *Queries) CreateMetadataField(ctx context.Context, arg CreateMetadataFieldParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createMetadataField, arg.Name, arg.Description)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createProcessor = `-- name: CreateProcessor :exec
+INSERT INTO processor (
+  name,
+  runtime,
+  connection_string,
+  namespace,
+  registered_by,
+  tls_server_name,
+  tls_insecure_skip_verify
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7
+) ON CONFLICT (namespace, name, runtime) DO UPDATE
+SET
+  connection_string = excluded.connection_string,
+  registered_by = excluded.registered_by,
+  tls_server_name = excluded.tls_server_name,
+  tls_insecure_skip_verify = excluded.tls_insecure_skip_verify
+`
+
+type CreateProcessorParams struct {
+	Name                  string
+	Runtime               string
+	ConnectionString      string
+	Namespace             string
+	RegisteredBy          sql.NullString
+	TlsServerName         sql.NullString
+	TlsInsecureSkipVerify bool
+}
+
+// -------------------- Core Operations ----------------------
+func (q *Queries) CreateProcessor(ctx context.Context, arg CreateProcessorParams) error {
+	_, err := q.db.ExecContext(ctx, createProcessor,
+		arg.Name,
+		arg.Runtime,
+		arg.ConnectionString,
+		arg.Namespace,
+		arg.RegisteredBy,
+		arg.TlsServerName,
+		arg.TlsInsecureSkipVerify,
+	)
+	return err
+}
+
+const createResult = `-- name: CreateResult :one
+INSERT INTO results (
+  windows_id,
+  window_type_id,
+  algorithm_id,
+  result_value,
+  result_array,
+  result_json,
+  trace_id,
+  namespace
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  (SELECT p.namespace FROM algorithm a JOIN processor p ON a.processor_id = p.id WHERE a.id = ?3)
+) RETURNING id
+`
+
+type CreateResultParams struct {
+	WindowsID    sql.NullInt64
+	WindowTypeID sql.NullInt64
+	AlgorithmID  sql.NullInt64
+	ResultValue  sql.NullFloat64
+	ResultArray  sql.NullString
+	ResultJson   sql.NullString
+	TraceID      sql.NullString
+}
+
+func (q *Queries) CreateResult(ctx context.Context, arg CreateResultParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createResult,
+		arg.WindowsID,
+		arg.WindowTypeID,
+		arg.AlgorithmID,
+		arg.ResultValue,
+		arg.ResultArray,
+		arg.ResultJson,
+		arg.TraceID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createWindowRollup = `-- name: CreateWindowRollup :exec
+INSERT INTO window_rollup (
+  window_type_id,
+  child_window_type_name,
+  child_window_type_version,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  child_count,
+  late_window_policy
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    child_window_type_name = excluded.child_window_type_name,
+    child_window_type_version = excluded.child_window_type_version,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    child_count = excluded.child_count,
+    late_window_policy = excluded.late_window_policy
+`
+
+type CreateWindowRollupParams struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int64
+	LateWindowPolicy       string
+}
+
+func (q *Queries) CreateWindowRollup(ctx context.Context, arg CreateWindowRollupParams) error {
+	_, err := q.db.ExecContext(ctx, createWindowRollup,
+		arg.WindowTypeID,
+		arg.ChildWindowTypeName,
+		arg.ChildWindowTypeVersion,
+		arg.IntervalNs,
+		arg.AlignmentNs,
+		arg.TimeZone,
+		arg.ChildCount,
+		arg.LateWindowPolicy,
+	)
+	return err
+}
+
+const createWindowRollupProgress = `-- name: CreateWindowRollupProgress :exec
+INSERT INTO window_rollup_progress (
+  window_type_id,
+  origin,
+  metadata,
+  time_from,
+  time_to
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5
+) ON CONFLICT (window_type_id, origin, metadata, time_from) DO NOTHING
+`
+
+type CreateWindowRollupProgressParams struct {
+	WindowTypeID int64
+	Origin       string
+	Metadata     string
+	TimeFrom     time.Time
+	TimeTo       time.Time
+}
+
+func (q *Queries) CreateWindowRollupProgress(ctx context.Context, arg CreateWindowRollupProgressParams) error {
+	_, err := q.db.ExecContext(ctx, createWindowRollupProgress,
+		arg.WindowTypeID,
+		arg.Origin,
+		arg.Metadata,
+		arg.TimeFrom,
+		arg.TimeTo,
+	)
+	return err
+}
+
+const createWindowSchedule = `-- name: CreateWindowSchedule :exec
+INSERT INTO window_schedule (
+  window_type_id,
+  cron,
+  interval_ns,
+  alignment_ns,
+  time_zone,
+  origin,
+  catch_up,
+  high_water_mark
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8
+) ON CONFLICT (window_type_id) DO UPDATE
+  SET
+    high_water_mark = CASE
+      WHEN window_schedule.cron IS excluded.cron
+        AND window_schedule.interval_ns = excluded.interval_ns
+        AND window_schedule.alignment_ns = excluded.alignment_ns
+        AND window_schedule.time_zone = excluded.time_zone
+      THEN window_schedule.high_water_mark
+      ELSE excluded.high_water_mark
+    END,
+    cron = excluded.cron,
+    interval_ns = excluded.interval_ns,
+    alignment_ns = excluded.alignment_ns,
+    time_zone = excluded.time_zone,
+    origin = excluded.origin,
+    catch_up = excluded.catch_up
+`
+
+type CreateWindowScheduleParams struct {
+	WindowTypeID  int64
+	Cron          sql.NullString
+	IntervalNs    int64
+	AlignmentNs   int64
+	TimeZone      string
+	Origin        string
+	CatchUp       bool
+	HighWaterMark time.Time
+}
+
+// the high water mark of an existing schedule is kept, so that
+// re-registering a window type neither skips nor repeats windows. It is
+// reset when the times the schedule fires at change, as it would no longer
+// be aligned to them
+func (q *Queries) CreateWindowSchedule(ctx context.Context, arg CreateWindowScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, createWindowSchedule,
+		arg.WindowTypeID,
+		arg.Cron,
+		arg.IntervalNs,
+		arg.AlignmentNs,
+		arg.TimeZone,
+		arg.Origin,
+		arg.CatchUp,
+		arg.HighWaterMark,
+	)
+	return err
+}
+
+const createWindowType = `-- name: CreateWindowType :one
+INSERT INTO window_type (
+  namespace,
+  name,
+  version,
+  description
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4
+) ON CONFLICT (namespace, name, version) DO UPDATE
+SET
+  description = excluded.description
+RETURNING id
+`
+
+type CreateWindowTypeParams struct {
+	Namespace   string
+	Name        string
+	Version     string
+	Description string
+}
+
+func (q *Queries) CreateWindowType(ctx context.Context, arg CreateWindowTypeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createWindowType,
+		arg.Namespace,
+		arg.Name,
+		arg.Version,
+		arg.Description,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createWindowTypeMetadataFieldBridge = `-- name: CreateWindowTypeMetadataFieldBridge :exec
+INSERT INTO metadata_fields_references (
+  window_type_id,
+  metadata_fields_id
+) VALUES (
+  ?1,
+  ?2
+) ON CONFLICT (window_type_id, metadata_fields_id) DO NOTHING
+`
+
+type CreateWindowTypeMetadataFieldBridgeParams struct {
+	WindowTypeID     int64
+	MetadataFieldsID int64
+}
+
+func (q *Queries) CreateWindowTypeMetadataFieldBridge(ctx context.Context, arg CreateWindowTypeMetadataFieldBridgeParams) error {
+	_, err := q.db.ExecContext(ctx, createWindowTypeMetadataFieldBridge, arg.WindowTypeID, arg.MetadataFieldsID)
+	return err
+}
+
+const deleteGrant = `-- name: DeleteGrant :execrows
+DELETE FROM role_grants
+WHERE identity = ?1
+AND role = ?2
+AND scope = ?3
+`
+
+type DeleteGrantParams struct {
+	Identity string
+	Role     string
+	Scope    string
+}
+
+func (q *Queries) DeleteGrant(ctx context.Context, arg DeleteGrantParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGrant, arg.Identity, arg.Role, arg.Scope)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWindowRollup = `-- name: DeleteWindowRollup :exec
+DELETE FROM window_rollup WHERE window_type_id = ?1
+`
+
+func (q *Queries) DeleteWindowRollup(ctx context.Context, windowTypeID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWindowRollup, windowTypeID)
+	return err
+}
+
+const deleteWindowSchedule = `-- name: DeleteWindowSchedule :exec
+DELETE FROM window_schedule WHERE window_type_id = ?1
+`
+
+func (q *Queries) DeleteWindowSchedule(ctx context.Context, windowTypeID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWindowSchedule, windowTypeID)
+	return err
+}
+
+const readAPIKeyName = `-- name: ReadAPIKeyName :one
+SELECT name FROM api_keys
+WHERE key_hash = ?1 AND revoked IS NULL
+`
+
+func (q *Queries) ReadAPIKeyName(ctx context.Context, keyHash string) (string, error) {
+	row := q.db.QueryRowContext(ctx, readAPIKeyName, keyHash)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
+const readAlgorithm = `-- name: ReadAlgorithm :one
+SELECT a.id, a.name, a.version, a.description, a.processor_id, a.window_type_id, a.result_type, a.created FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE a.name = ?1
+AND a.version = ?2
+AND p.name = ?3
+AND p.runtime = ?4
+AND p.namespace = ?5
+AND wt.namespace = p.namespace
+AND wt.name = ?6
+AND wt.version = ?7
+`
+
+type ReadAlgorithmParams struct {
+	AlgorithmName      string
+	AlgorithmVersion   string
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
+	WindowTypeName     string
+	WindowTypeVersion  string
+}
+
+func (q *Queries) ReadAlgorithm(ctx context.Context, arg ReadAlgorithmParams) (Algorithm, error) {
+	row := q.db.QueryRowContext(ctx, readAlgorithm,
+		arg.AlgorithmName,
+		arg.AlgorithmVersion,
+		arg.ProcessorName,
+		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
+		arg.WindowTypeName,
+		arg.WindowTypeVersion,
+	)
+	var i Algorithm
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.Description,
+		&i.ProcessorID,
+		&i.WindowTypeID,
+		&i.ResultType,
+		&i.Created,
+	)
+	return i, err
+}
+
+const readAlgorithmDependencies = `-- name: ReadAlgorithmDependencies :many
+SELECT ad.id, ad.from_algorithm_id, ad.to_algorithm_id, ad.from_window_type_id, ad.to_window_type_id, ad.from_processor_id, ad.to_processor_id, ad.lookback_count, ad.lookback_timedelta, ad.lookback_partition, ad.lookback_partition_field_id, ad.lookback_aggregate, ad.lookback_aggregate_percentile, ad.lookback_aggregate_bucket_timedelta, ad.created FROM algorithm_dependency ad
+`
+
+func (q *Queries) ReadAlgorithmDependencies(ctx context.Context) ([]AlgorithmDependency, error) {
+	rows, err := q.db.QueryContext(ctx, readAlgorithmDependencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlgorithmDependency
+	for rows.Next() {
+		var i AlgorithmDependency
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAlgorithmID,
+			&i.ToAlgorithmID,
+			&i.FromWindowTypeID,
+			&i.ToWindowTypeID,
+			&i.FromProcessorID,
+			&i.ToProcessorID,
+			&i.LookbackCount,
+			&i.LookbackTimedelta,
+			&i.LookbackPartition,
+			&i.LookbackPartitionFieldID,
+			&i.LookbackAggregate,
+			&i.LookbackAggregatePercentile,
+			&i.LookbackAggregateBucketTimedelta,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readAlgorithmId = `-- name: ReadAlgorithmId :one
+SELECT a.id FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.name = ?1
+AND a.version = ?2
+AND p.name = ?3
+AND p.runtime = ?4
+AND p.namespace = ?5
+`
+
+type ReadAlgorithmIdParams struct {
+	AlgorithmName      string
+	AlgorithmVersion   string
+	ProcessorName      string
+	ProcessorRuntime   string
+	ProcessorNamespace string
+}
+
+func (q *Queries) ReadAlgorithmId(ctx context.Context, arg ReadAlgorithmIdParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, readAlgorithmId,
+		arg.AlgorithmName,
+		arg.AlgorithmVersion,
+		arg.ProcessorName,
+		arg.ProcessorRuntime,
+		arg.ProcessorNamespace,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const readAlgorithmResultType = `-- name: ReadAlgorithmResultType :one
+SELECT result_type FROM algorithm WHERE id = ?1
+`
+
+func (q *Queries) ReadAlgorithmResultType(ctx context.Context, algorithmID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, readAlgorithmResultType, algorithmID)
+	var result_type string
+	err := row.Scan(&result_type)
+	return result_type, err
+}
+
+const readAlgorithms = `-- name: ReadAlgorithms :many
+SELECT a.id, a.name, a.version, a.description, a.processor_id, a.window_type_id, a.result_type, a.created FROM algorithm a
+`
+
+func (q *Queries) ReadAlgorithms(ctx context.Context) ([]Algorithm, error) {
+	rows, err := q.db.QueryContext(ctx, readAlgorithms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Algorithm
+	for rows.Next() {
+		var i Algorithm
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.Description,
+			&i.ProcessorID,
+			&i.WindowTypeID,
+			&i.ResultType,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readAlgorithmsByIDs = `-- name: ReadAlgorithmsByIDs :many
+SELECT
+    a.id,
+    a.name,
+    a.version,
+    p.name AS processor_name
+FROM algorithm a
+JOIN processor p ON a.processor_id = p.id
+WHERE a.id IN (/*SLICE:algorithm_ids*/?)
+`
+
+type ReadAlgorithmsByIDsRow struct {
+	ID            int64
+	Name          string
+	Version       string
+	ProcessorName string
+}
+
+func (q *Queries) ReadAlgorithmsByIDs(ctx context.Context, algorithmIds []int64) ([]ReadAlgorithmsByIDsRow, error) {
+	query := readAlgorithmsByIDs
+	var queryParams []interface{}
+	if len(algorithmIds) > 0 {
+		for _, v := range algorithmIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:algorithm_ids*/?", strings.Repeat(",?", len(algorithmIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:algorithm_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadAlgorithmsByIDsRow
+	for rows.Next() {
+		var i ReadAlgorithmsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.ProcessorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readAlgorithmsForWindow = `-- name: ReadAlgorithmsForWindow :many
+SELECT a.id, a.name, a.version, a.description, a.processor_id, a.window_type_id, a.result_type, a.created FROM algorithm a
+JOIN window_type wt ON a.window_type_id = wt.id
+WHERE wt.namespace = ?1
+AND wt.name = ?2
+AND wt.version = ?3
+`
+
+type ReadAlgorithmsForWindowParams struct {
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
+}
+
+func (q *Queries) ReadAlgorithmsForWindow(ctx context.Context, arg ReadAlgorithmsForWindowParams) ([]Algorithm, error) {
+	rows, err := q.db.QueryContext(ctx, readAlgorithmsForWindow, arg.WindowTypeNamespace, arg.WindowTypeName, arg.WindowTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Algorithm
+	for rows.Next() {
+		var i Algorithm
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.Description,
+			&i.ProcessorID,
+			&i.WindowTypeID,
+			&i.ResultType,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readGrants = `-- name: ReadGrants :many
+SELECT role, scope FROM role_grants
+WHERE identity = ?1
+ORDER BY role, scope
+`
+
+type ReadGrantsRow struct {
+	Role  string
+	Scope string
+}
+
+func (q *Queries) ReadGrants(ctx context.Context, identity string) ([]ReadGrantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, readGrants, identity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadGrantsRow
+	for rows.Next() {
+		var i ReadGrantsRow
+		if err := rows.Scan(&i.Role, &i.Scope); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readLookbackAggregatesForAlgorithms = `-- name: ReadLookbackAggregatesForAlgorithms :many
+SELECT
+    ad.from_algorithm_id,
+    ad.to_algorithm_id,
+    ad.lookback_aggregate,
+    ad.lookback_aggregate_percentile,
+    ad.lookback_aggregate_bucket_timedelta
+FROM
+    algorithm_dependency ad
+WHERE
+    ad.lookback_aggregate IS NOT NULL
+    AND ad.to_algorithm_id IN (/*SLICE:algorithm_ids*/?)
+`
+
+type ReadLookbackAggregatesForAlgorithmsRow struct {
+	FromAlgorithmID                  int64
+	ToAlgorithmID                    int64
+	LookbackAggregate                sql.NullString
+	LookbackAggregatePercentile      float64
+	LookbackAggregateBucketTimedelta int64
+}
+
+func (q *Queries) ReadLookbackAggregatesForAlgorithms(ctx context.Context, algorithmIds []int64) ([]ReadLookbackAggregatesForAlgorithmsRow, error) {
+	query := readLookbackAggregatesForAlgorithms
+	var queryParams []interface{}
+	if len(algorithmIds) > 0 {
+		for _, v := range algorithmIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:algorithm_ids*/?", strings.Repeat(",?", len(algorithmIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:algorithm_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadLookbackAggregatesForAlgorithmsRow
+	for rows.Next() {
+		var i ReadLookbackAggregatesForAlgorithmsRow
+		if err := rows.Scan(
+			&i.FromAlgorithmID,
+			&i.ToAlgorithmID,
+			&i.LookbackAggregate,
+			&i.LookbackAggregatePercentile,
+			&i.LookbackAggregateBucketTimedelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readMetadataFields = `-- name: ReadMetadataFields :many
+SELECT id, name, description FROM metadata_fields
+`
+
+func (q *Queries) ReadMetadataFields(ctx context.Context) ([]MetadataField, error) {
+	rows, err := q.db.QueryContext(ctx, readMetadataFields)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MetadataField
+	for rows.Next() {
+		var i MetadataField
+		if err := rows.Scan(&i.ID, &i.Name, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readMetadataFieldsByWindowType = `-- name: ReadMetadataFieldsByWindowType :many
+SELECT
+    metadata_field_id,
+    metadata_field_name,
+    metadata_field_description
+FROM window_type_metadata_fields
+WHERE window_type_namespace = ?1
+  AND window_type_name = ?2
+  AND window_type_version = ?3
+ORDER BY metadata_field_name
+`
+
+type ReadMetadataFieldsByWindowTypeParams struct {
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
+}
+
+type ReadMetadataFieldsByWindowTypeRow struct {
+	MetadataFieldID          int64
+	MetadataFieldName        string
+	MetadataFieldDescription string
+}
+
+func (q *Queries) ReadMetadataFieldsByWindowType(ctx context.Context, arg ReadMetadataFieldsByWindowTypeParams) ([]ReadMetadataFieldsByWindowTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, readMetadataFieldsByWindowType, arg.WindowTypeNamespace, arg.WindowTypeName, arg.WindowTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadMetadataFieldsByWindowTypeRow
+	for rows.Next() {
+		var i ReadMetadataFieldsByWindowTypeRow
+		if err := rows.Scan(&i.MetadataFieldID, &i.MetadataFieldName, &i.MetadataFieldDescription); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readProcessorExcludeProject = `-- name: ReadProcessorExcludeProject :many
+SELECT id, name, runtime, connection_string, namespace, registered_by, tls_server_name, tls_insecure_skip_verify, created FROM processor WHERE namespace != ?1
+`
+
+func (q *Queries) ReadProcessorExcludeProject(ctx context.Context, namespace string) ([]Processor, error) {
+	rows, err := q.db.QueryContext(ctx, readProcessorExcludeProject, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Processor
+	for rows.Next() {
+		var i Processor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Runtime,
+			&i.ConnectionString,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readProcessors = `-- name: ReadProcessors :many
+SELECT id, name, runtime, connection_string, namespace, registered_by, tls_server_name, tls_insecure_skip_verify, created FROM processor
+`
+
+func (q *Queries) ReadProcessors(ctx context.Context) ([]Processor, error) {
+	rows, err := q.db.QueryContext(ctx, readProcessors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Processor
+	for rows.Next() {
+		var i Processor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Runtime,
+			&i.ConnectionString,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readProcessorsByIDs = `-- name: ReadProcessorsByIDs :many
+SELECT id, name, runtime, connection_string, namespace, registered_by, tls_server_name, tls_insecure_skip_verify, created
+FROM processor
+WHERE id IN (/*SLICE:processor_ids*/?)
+ORDER BY name, runtime
+`
+
+func (q *Queries) ReadProcessorsByIDs(ctx context.Context, processorIds []int64) ([]Processor, error) {
+	query := readProcessorsByIDs
+	var queryParams []interface{}
+	if len(processorIds) > 0 {
+		for _, v := range processorIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:processor_ids*/?", strings.Repeat(",?", len(processorIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:processor_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Processor
+	for rows.Next() {
+		var i Processor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Runtime,
+			&i.ConnectionString,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readProcessorsInNamespace = `-- name: ReadProcessorsInNamespace :many
+SELECT id, name, runtime, connection_string, namespace, registered_by, tls_server_name, tls_insecure_skip_verify, created FROM processor WHERE namespace = ?1
+`
+
+func (q *Queries) ReadProcessorsInNamespace(ctx context.Context, namespace string) ([]Processor, error) {
+	rows, err := q.db.QueryContext(ctx, readProcessorsInNamespace, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Processor
+	for rows.Next() {
+		var i Processor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Runtime,
+			&i.ConnectionString,
+			&i.Namespace,
+			&i.RegisteredBy,
+			&i.TlsServerName,
+			&i.TlsInsecureSkipVerify,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readRegistryVersion = `-- name: ReadRegistryVersion :one
+SELECT version FROM registry_version
+`
+
+func (q *Queries) ReadRegistryVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, readRegistryVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const readResultsForLookback = `-- name: ReadResultsForLookback :many
+SELECT
+    r.id AS result_id,
+    r.algorithm_id,
+    a.result_type,
+    w.id AS window_id,
+    r.result_value,
+    r.result_array,
+    r.result_json,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version,
+    w.time_from AS window_time_from,
+    w.time_to AS window_time_to,
+    w.origin AS window_origin,
+    w.metadata AS window_metadata
+FROM
+    results r
+JOIN algorithm a ON a.id = r.algorithm_id
+JOIN windows w ON w.id = r.windows_id
+JOIN window_type wt ON wt.id = w.window_type_id
+WHERE
+    r.algorithm_id = ?1
+    AND (?2 IS NULL OR w.time_from > ?2)
+    AND w.time_to < ?3
+    AND (CAST(?4 AS TEXT) IS NULL OR w.origin = ?4)
+    AND (
+        CAST(?5 AS TEXT) = ''
+        OR json_extract(w.metadata, ?5) IS json_extract(CAST(?6 AS TEXT), '$')
+    )
+ORDER BY w.time_to DESC
+LIMIT ?7
+`
+
+type ReadResultsForLookbackParams struct {
+	AlgorithmID    sql.NullInt64
+	SearchFrom     interface{}
+	SearchTo       time.Time
+	Origin         sql.NullString
+	PartitionPath  string
+	PartitionValue string
+	Limit          int64
+}
+
+type ReadResultsForLookbackRow struct {
+	ResultID          int64
+	AlgorithmID       sql.NullInt64
+	ResultType        string
+	WindowID          int64
+	ResultValue       sql.NullFloat64
+	ResultArray       sql.NullString
+	ResultJson        sql.NullString
+	WindowTypeName    string
+	WindowTypeVersion string
+	WindowTimeFrom    time.Time
+	WindowTimeTo      time.Time
+	WindowOrigin      string
+	WindowMetadata    sql.NullString
+}
+
+// the latest results of an algorithm, newest first. A negative limit does
+// not limit the lookback, a NULL search_from does not bound it, a NULL
+// origin or an empty partition path does not partition it
+func (q *Queries) ReadResultsForLookback(ctx context.Context, arg ReadResultsForLookbackParams) ([]ReadResultsForLookbackRow, error) {
+	rows, err := q.db.QueryContext(ctx, readResultsForLookback,
+		arg.AlgorithmID,
+		arg.SearchFrom,
+		arg.SearchTo,
+		arg.Origin,
+		arg.PartitionPath,
+		arg.PartitionValue,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadResultsForLookbackRow
+	for rows.Next() {
+		var i ReadResultsForLookbackRow
+		if err := rows.Scan(
+			&i.ResultID,
+			&i.AlgorithmID,
+			&i.ResultType,
+			&i.WindowID,
+			&i.ResultValue,
+			&i.ResultArray,
+			&i.ResultJson,
+			&i.WindowTypeName,
+			&i.WindowTypeVersion,
+			&i.WindowTimeFrom,
+			&i.WindowTimeTo,
+			&i.WindowOrigin,
+			&i.WindowMetadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowRollupProgress = `-- name: ReadWindowRollupProgress :one
+SELECT wrp.id, wrp.window_type_id, wrp.origin, wrp.metadata, wrp.time_from, wrp.time_to, wrp.child_windows, wrp.emitted FROM window_rollup_progress wrp
+WHERE
+    wrp.window_type_id = ?1
+    AND wrp.origin = ?2
+    AND wrp.metadata = ?3
+    AND wrp.time_from = ?4
+`
+
+type ReadWindowRollupProgressParams struct {
+	WindowTypeID int64
+	Origin       string
+	Metadata     string
+	TimeFrom     time.Time
+}
+
+func (q *Queries) ReadWindowRollupProgress(ctx context.Context, arg ReadWindowRollupProgressParams) (WindowRollupProgress, error) {
+	row := q.db.QueryRowContext(ctx, readWindowRollupProgress,
+		arg.WindowTypeID,
+		arg.Origin,
+		arg.Metadata,
+		arg.TimeFrom,
+	)
+	var i WindowRollupProgress
+	err := row.Scan(
+		&i.ID,
+		&i.WindowTypeID,
+		&i.Origin,
+		&i.Metadata,
+		&i.TimeFrom,
+		&i.TimeTo,
+		&i.ChildWindows,
+		&i.Emitted,
+	)
+	return i, err
+}
+
+const readWindowRollups = `-- name: ReadWindowRollups :many
+SELECT wr.window_type_id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy, wr.created FROM window_rollup wr
+`
+
+func (q *Queries) ReadWindowRollups(ctx context.Context) ([]WindowRollup, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowRollups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WindowRollup
+	for rows.Next() {
+		var i WindowRollup
+		if err := rows.Scan(
+			&i.WindowTypeID,
+			&i.ChildWindowTypeName,
+			&i.ChildWindowTypeVersion,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.ChildCount,
+			&i.LateWindowPolicy,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowRollupsForChild = `-- name: ReadWindowRollupsForChild :many
+SELECT
+    wr.window_type_id, wr.child_window_type_name, wr.child_window_type_version, wr.interval_ns, wr.alignment_ns, wr.time_zone, wr.child_count, wr.late_window_policy, wr.created,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_rollup wr
+JOIN window_type wt ON wt.id = wr.window_type_id
+WHERE
+    wt.namespace = ?1
+    AND wr.child_window_type_name = ?2
+    AND wr.child_window_type_version = ?3
+`
+
+type ReadWindowRollupsForChildParams struct {
+	WindowTypeNamespace    string
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+}
+
+type ReadWindowRollupsForChildRow struct {
+	WindowTypeID           int64
+	ChildWindowTypeName    string
+	ChildWindowTypeVersion string
+	IntervalNs             int64
+	AlignmentNs            int64
+	TimeZone               string
+	ChildCount             int64
+	LateWindowPolicy       string
+	Created                sql.NullTime
+	WindowTypeNamespace    string
+	WindowTypeName         string
+	WindowTypeVersion      string
+}
+
+func (q *Queries) ReadWindowRollupsForChild(ctx context.Context, arg ReadWindowRollupsForChildParams) ([]ReadWindowRollupsForChildRow, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowRollupsForChild, arg.WindowTypeNamespace, arg.ChildWindowTypeName, arg.ChildWindowTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadWindowRollupsForChildRow
+	for rows.Next() {
+		var i ReadWindowRollupsForChildRow
+		if err := rows.Scan(
+			&i.WindowTypeID,
+			&i.ChildWindowTypeName,
+			&i.ChildWindowTypeVersion,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.ChildCount,
+			&i.LateWindowPolicy,
+			&i.Created,
+			&i.WindowTypeNamespace,
+			&i.WindowTypeName,
+			&i.WindowTypeVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowScheduleHighWaterMark = `-- name: ReadWindowScheduleHighWaterMark :one
+SELECT high_water_mark FROM window_schedule
+WHERE window_type_id = ?1
+`
+
+func (q *Queries) ReadWindowScheduleHighWaterMark(ctx context.Context, windowTypeID int64) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, readWindowScheduleHighWaterMark, windowTypeID)
+	var high_water_mark time.Time
+	err := row.Scan(&high_water_mark)
+	return high_water_mark, err
+}
+
+const readWindowSchedules = `-- name: ReadWindowSchedules :many
+SELECT
+    ws.window_type_id, ws.cron, ws.interval_ns, ws.alignment_ns, ws.time_zone, ws.origin, ws.catch_up, ws.high_water_mark, ws.created,
+    wt.namespace AS window_type_namespace,
+    wt.name AS window_type_name,
+    wt.version AS window_type_version
+FROM
+    window_schedule ws
+JOIN window_type wt ON wt.id = ws.window_type_id
+`
+
+type ReadWindowSchedulesRow struct {
+	WindowTypeID        int64
+	Cron                sql.NullString
+	IntervalNs          int64
+	AlignmentNs         int64
+	TimeZone            string
+	Origin              string
+	CatchUp             bool
+	HighWaterMark       time.Time
+	Created             sql.NullTime
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
+}
+
+func (q *Queries) ReadWindowSchedules(ctx context.Context) ([]ReadWindowSchedulesRow, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadWindowSchedulesRow
+	for rows.Next() {
+		var i ReadWindowSchedulesRow
+		if err := rows.Scan(
+			&i.WindowTypeID,
+			&i.Cron,
+			&i.IntervalNs,
+			&i.AlignmentNs,
+			&i.TimeZone,
+			&i.Origin,
+			&i.CatchUp,
+			&i.HighWaterMark,
+			&i.Created,
+			&i.WindowTypeNamespace,
+			&i.WindowTypeName,
+			&i.WindowTypeVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowTypeMetadataFields = `-- name: ReadWindowTypeMetadataFields :many
+SELECT window_type_namespace, window_type_name, window_type_version, metadata_field_id, metadata_field_name, metadata_field_description FROM window_type_metadata_fields
+`
+
+func (q *Queries) ReadWindowTypeMetadataFields(ctx context.Context) ([]WindowTypeMetadataField, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowTypeMetadataFields)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WindowTypeMetadataField
+	for rows.Next() {
+		var i WindowTypeMetadataField
+		if err := rows.Scan(
+			&i.WindowTypeNamespace,
+			&i.WindowTypeName,
+			&i.WindowTypeVersion,
+			&i.MetadataFieldID,
+			&i.MetadataFieldName,
+			&i.MetadataFieldDescription,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowTypeNamespaces = `-- name: ReadWindowTypeNamespaces :many
+SELECT wt.namespace FROM window_type wt
+WHERE wt.name = ?1
+AND wt.version = ?2
+ORDER BY wt.namespace
+`
+
+type ReadWindowTypeNamespacesParams struct {
+	WindowTypeName    string
+	WindowTypeVersion string
+}
+
+func (q *Queries) ReadWindowTypeNamespaces(ctx context.Context, arg ReadWindowTypeNamespacesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowTypeNamespaces, arg.WindowTypeName, arg.WindowTypeVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, err
+		}
+		items = append(items, namespace)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWindowTypes = `-- name: ReadWindowTypes :many
+SELECT wt.id, wt.namespace, wt.name, wt.version, wt.description, wt.created FROM window_type wt
+`
+
+func (q *Queries) ReadWindowTypes(ctx context.Context) ([]WindowType, error) {
+	rows, err := q.db.QueryContext(ctx, readWindowTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WindowType
+	for rows.Next() {
+		var i WindowType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Namespace,
+			&i.Name,
+			&i.Version,
+			&i.Description,
+			&i.Created,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const registerWindow = `-- name: RegisterWindow :one
+INSERT INTO windows (
+  window_type_id,
+  time_from,
+  time_to,
+  origin,
+  metadata,
+  trace_id,
+  created_by,
+  namespace
+) VALUES (
+  (
+    SELECT wt.id FROM window_type wt
+    WHERE wt.namespace = ?1
+    AND wt.name = ?2
+    AND wt.version = ?3
+  ),
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8,
+  ?9,
+  ?10
+) RETURNING window_type_id, id
+`
+
+type RegisterWindowParams struct {
+	WindowTypeNamespace string
+	WindowTypeName      string
+	WindowTypeVersion   string
+	TimeFrom            time.Time
+	TimeTo              time.Time
+	Origin              string
+	Metadata            sql.NullString
+	TraceID             sql.NullString
+	CreatedBy           sql.NullString
+	Namespace           sql.NullString
+}
+
+type RegisterWindowRow struct {
+	WindowTypeID int64
+	ID           int64
+}
+
+func (q *Queries) RegisterWindow(ctx context.Context, arg RegisterWindowParams) (RegisterWindowRow, error) {
+	row := q.db.QueryRowContext(ctx, registerWindow,
+		arg.WindowTypeNamespace,
+		arg.WindowTypeName,
+		arg.WindowTypeVersion,
+		arg.TimeFrom,
+		arg.TimeTo,
+		arg.Origin,
+		arg.Metadata,
+		arg.TraceID,
+		arg.CreatedBy,
+		arg.Namespace,
+	)
+	var i RegisterWindowRow
+	err := row.Scan(&i.WindowTypeID, &i.ID)
+	return i, err
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked = CURRENT_TIMESTAMP
+WHERE name = ?1 AND revoked IS NULL
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAPIKey, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateWindowRollupProgress = `-- name: UpdateWindowRollupProgress :exec
+UPDATE window_rollup_progress
+SET
+    child_windows = ?1,
+    emitted = ?2
+WHERE id = ?3
+`
+
+type UpdateWindowRollupProgressParams struct {
+	ChildWindows string
+	Emitted      sql.NullTime
+	ID           int64
+}
+
+func (q *Queries) UpdateWindowRollupProgress(ctx context.Context, arg UpdateWindowRollupProgressParams) error {
+	_, err := q.db.ExecContext(ctx, updateWindowRollupProgress, arg.ChildWindows, arg.Emitted, arg.ID)
+	return err
+}
+
+const updateWindowScheduleHighWaterMark = `-- name: UpdateWindowScheduleHighWaterMark :exec
+UPDATE window_schedule SET high_water_mark = ?1
+WHERE window_type_id = ?2
+`
+
+type UpdateWindowScheduleHighWaterMarkParams struct {
+	HighWaterMark time.Time
+	WindowTypeID  int64
+}
+
+func (q *Queries) UpdateWindowScheduleHighWaterMark(ctx context.Context, arg UpdateWindowScheduleHighWaterMarkParams) error {
+	_, err := q.db.ExecContext(ctx, updateWindowScheduleHighWaterMark, arg.HighWaterMark, arg.WindowTypeID)
+	return err
+}
@@ -0,0 +1,409 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/dag"
+	"github.com/orca-telemetry/core/internal/datalayers/dispatch"
+	"github.com/orca-telemetry/core/internal/metrics"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+// processorFilter reports whether a processor is one of the processors, so
+// that only the algorithms of a namespace are planned
+func processorFilter(processors []Processor) func(procId int64) bool {
+	ids := make(map[int64]bool, len(processors))
+	for _, proc := range processors {
+		ids[proc.ID] = true
+	}
+	return func(procId int64) bool {
+		return ids[procId]
+	}
+}
+
+// dispatchProcessor converts a stored processor to the processor tasks are
+// dispatched to
+func dispatchProcessor(proc Processor) dispatch.Processor {
+	return dispatch.Processor{
+		ID:                    proc.ID,
+		Name:                  proc.Name,
+		Runtime:               proc.Runtime,
+		Namespace:             proc.Namespace,
+		ConnectionString:      proc.ConnectionString,
+		TLSServerName:         proc.TlsServerName.String,
+		TLSInsecureSkipVerify: proc.TlsInsecureSkipVerify,
+	}
+}
+
+// processWindow runs the algorithms triggered by a stored window, and then
+// counts it towards the rollups it is a child of
+func processWindow(
+	ctx context.Context,
+	d *Datalayer,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	insertedWindow RegisterWindowRow,
+) {
+	dispatch.ProcessWindow(ctx, executionPlan, window, insertedWindow.ID, &processedWindow{
+		d:              d,
+		window:         window,
+		insertedWindow: insertedWindow,
+	})
+}
+
+// processedWindow is a stored window being processed
+type processedWindow struct {
+	d              *Datalayer
+	window         *pb.Window
+	insertedWindow RegisterWindowRow
+}
+
+func (w *processedWindow) RunPlan(ctx context.Context, executionPlan dag.Plan) error {
+	return processTasks(ctx, w.d, executionPlan, w.window, w.insertedWindow)
+}
+
+func (w *processedWindow) Rollup(ctx context.Context) {
+	// rolled up window types are those of the namespace of the child
+	rollups, err := w.d.queries.ReadWindowRollupsForChild(ctx, ReadWindowRollupsForChildParams{
+		WindowTypeNamespace:    types.Namespace(w.window.GetNamespace()),
+		ChildWindowTypeName:    w.window.GetWindowTypeName(),
+		ChildWindowTypeVersion: w.window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "could not read window rollups", "error", err)
+		return
+	}
+	for _, rollup := range rollups {
+		if err := w.d.rollupWindow(ctx, rollup, w.window); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"could not roll up window",
+				"rolled_up_window_type",
+				rollup.WindowTypeName,
+				"error",
+				err,
+			)
+		}
+	}
+}
+
+func processTasks(
+	ctx context.Context,
+	d *Datalayer,
+	executionPlan dag.Plan,
+	window *pb.Window,
+	insertedWindow RegisterWindowRow,
+) error {
+	// get map of processors from processor ids
+	processors, err := d.queries.ReadProcessorsByIDs(ctx, executionPlan.AffectedProcessors)
+	if err != nil {
+		slog.ErrorContext(ctx, "Processors could not be read", "error", err)
+		return err
+	}
+	processorMap := make(map[int64]dispatch.Processor, len(processors))
+	for _, proc := range processors {
+		processorMap[proc.ID] = dispatchProcessor(proc)
+	}
+
+	// get map of algorithms from algorithm ids
+	algorithms, err := d.queries.ReadAlgorithmsForWindow(ctx, ReadAlgorithmsForWindowParams{
+		WindowTypeNamespace: types.Namespace(window.GetNamespace()),
+		WindowTypeName:      window.GetWindowTypeName(),
+		WindowTypeVersion:   window.GetWindowTypeVersion(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "algorithms could not be read", "error", err)
+		return err
+	}
+	algorithmMap := make(map[int64]dispatch.Algorithm, len(algorithms))
+	for _, algo := range algorithms {
+		algorithmMap[algo.ID] = dispatch.Algorithm{ID: algo.ID, Name: algo.Name, Version: algo.Version}
+	}
+
+	// names of the metadata fields that lookbacks are partitioned on
+	metadataFieldNames := make(map[int64]string)
+	if executionPlan.HasMetadataPartition() {
+		metadataFields, err := d.queries.ReadMetadataFields(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "metadata fields could not be read", "error", err)
+			return err
+		}
+		for _, field := range metadataFields {
+			metadataFieldNames[field.ID] = field.Name
+		}
+	}
+
+	// lookback aggregates requested by the algorithms, keyed by dependency
+	algorithmIds := make([]int64, 0, len(algorithmMap))
+	for algoId := range algorithmMap {
+		algorithmIds = append(algorithmIds, algoId)
+	}
+	lookbackAggregates, err := d.queries.ReadLookbackAggregatesForAlgorithms(ctx, algorithmIds)
+	if err != nil {
+		slog.ErrorContext(ctx, "lookback aggregates could not be read", "error", err)
+		return err
+	}
+	lookbackAggregateMap := make(map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow, len(lookbackAggregates))
+	for _, agg := range lookbackAggregates {
+		lookbackAggregateMap[dispatch.LookbackKey{DependencyID: agg.FromAlgorithmID, DependantID: agg.ToAlgorithmID}] = agg
+	}
+
+	return d.dispatcher.Run(ctx, executionPlan, window, processorMap, algorithmMap, &windowStore{
+		d:                  d,
+		window:             window,
+		insertedWindow:     insertedWindow,
+		metadataFieldNames: metadataFieldNames,
+		aggregates:         lookbackAggregateMap,
+	})
+}
+
+// windowStore reads the past results, and stores the results, of the
+// algorithms a window triggers
+type windowStore struct {
+	d                  *Datalayer
+	window             *pb.Window
+	insertedWindow     RegisterWindowRow
+	metadataFieldNames map[int64]string
+	aggregates         map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow
+}
+
+func (s *windowStore) ReadLookbacks(ctx context.Context, stage dag.Stage) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	return readStageLookbacks(ctx, s.d, stage, s.window, s.metadataFieldNames, s.aggregates)
+}
+
+func (s *windowStore) CreateResult(ctx context.Context, algo dispatch.Algorithm, result *pb.ExecutionResult) (int64, error) {
+	structResult, err := protojson.Marshal(
+		result.AlgorithmResult.Result.GetStructValue(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not convert algorithm struct result to bytes: %w", err)
+	}
+	arrayResult, err := json.Marshal(dispatch.Float64s(
+		result.AlgorithmResult.Result.GetFloatValues().GetValues(),
+	))
+	if err != nil {
+		return 0, fmt.Errorf("could not convert algorithm array result to bytes: %w", err)
+	}
+
+	return s.d.queries.CreateResult(ctx, CreateResultParams{
+		WindowsID:    sql.NullInt64{Valid: true, Int64: s.insertedWindow.ID},
+		WindowTypeID: sql.NullInt64{Valid: true, Int64: s.insertedWindow.WindowTypeID},
+		AlgorithmID:  sql.NullInt64{Valid: true, Int64: algo.ID},
+		ResultValue: sql.NullFloat64{
+			Valid:   true,
+			Float64: float64(result.AlgorithmResult.Result.GetSingleValue()),
+		},
+		ResultArray: sql.NullString{String: string(arrayResult), Valid: true},
+		ResultJson:  sql.NullString{String: string(structResult), Valid: true},
+		TraceID:     traceIDText(ctx),
+	})
+}
+
+// lookbackPartitionFilter builds the origin and metadata filters of the
+// results query that restrict a lookback to windows in its partition. The
+// metadata filter is a JSON path and the JSON of the value found there. An
+// unset origin filter is NULL and an unset metadata filter is empty, so the
+// results query ignores them
+func lookbackPartitionFilter(partition dispatch.Partition) (sql.NullString, string, string, error) {
+	origin := sql.NullString{String: partition.Origin, Valid: partition.ByOrigin}
+	if partition.Field == "" {
+		return origin, "", "", nil
+	}
+	valueBytes, err := json.Marshal(partition.Value.AsInterface())
+	if err != nil {
+		return sql.NullString{}, "", "", fmt.Errorf("could not marshal lookback partition: %w", err)
+	}
+	path, err := json.Marshal(partition.Field)
+	if err != nil {
+		return sql.NullString{}, "", "", fmt.Errorf("could not marshal lookback partition: %w", err)
+	}
+	return origin, "$." + string(path), string(valueBytes), nil
+}
+
+// readStageLookbacks fetches the past results of every lookback in the stage,
+// with one query per lookback. Aggregates are computed from the results,
+// as sqlite has no percentile aggregate
+func readStageLookbacks(
+	ctx context.Context,
+	d *Datalayer,
+	stage dag.Stage,
+	window *pb.Window,
+	metadataFieldNames map[int64]string,
+	aggregates map[dispatch.LookbackKey]ReadLookbackAggregatesForAlgorithmsRow,
+) (map[dispatch.LookbackKey]dispatch.Lookback, error) {
+	lookbacks := make(map[dispatch.LookbackKey]dispatch.Lookback)
+
+	for lookback := range stage.Lookbacks() {
+		key := dispatch.LookbackKey{DependencyID: lookback.Dep.AlgoId, DependantID: lookback.AlgoId}
+
+		// restrict lookbacks to windows in the same partition
+		partition, err := dispatch.LookbackPartition(lookback.Dep.Lookback, window, metadataFieldNames)
+		if err != nil {
+			return nil, err
+		}
+		origin, partitionPath, partitionValue, err := lookbackPartitionFilter(partition)
+		if err != nil {
+			return nil, err
+		}
+		searchFrom, searchTo := lookbackSearchRange(lookback.Dep.Lookback, window)
+		limit := int64(lookback.Dep.Lookback.Count)
+		if limit == 0 {
+			limit = -1
+		}
+
+		agg, aggregated := aggregates[key]
+		queryName := "results"
+		if aggregated {
+			queryName = "aggregates"
+		}
+		queryStart := time.Now()
+		results, err := d.queries.ReadResultsForLookback(ctx, ReadResultsForLookbackParams{
+			AlgorithmID:    sql.NullInt64{Int64: lookback.Dep.AlgoId, Valid: true},
+			SearchFrom:     searchFrom,
+			SearchTo:       searchTo,
+			Origin:         origin,
+			PartitionPath:  partitionPath,
+			PartitionValue: partitionValue,
+			Limit:          limit,
+		})
+		metrics.LookbackQueryDuration.WithLabelValues(queryName).Observe(time.Since(queryStart).Seconds())
+		if err != nil {
+			return nil, fmt.Errorf("could not read algorithm results for lookbacks: %w", err)
+		}
+
+		// the latest results are read, and passed on oldest first
+		slices.SortStableFunc(results, func(a, b ReadResultsForLookbackRow) int {
+			if c := a.WindowTimeFrom.Compare(b.WindowTimeFrom); c != 0 {
+				return c
+			}
+			return a.WindowTimeTo.Compare(b.WindowTimeTo)
+		})
+
+		if aggregated {
+			function, err := lookbackAggregateToPb(agg.LookbackAggregate.String)
+			if err != nil {
+				return nil, err
+			}
+			pastResults := make([]dispatch.PastResult, len(results))
+			for ii, res := range results {
+				pastResults[ii] = dispatch.PastResult{
+					Value:    res.ResultValue.Float64,
+					TimeFrom: res.WindowTimeFrom,
+					TimeTo:   res.WindowTimeTo,
+				}
+			}
+			buckets, err := dispatch.Aggregate(
+				function,
+				agg.LookbackAggregatePercentile,
+				time.Duration(agg.LookbackAggregateBucketTimedelta),
+				pastResults,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if len(buckets) > 0 {
+				lookbacks[key] = dispatch.Lookback{Buckets: buckets}
+			}
+			continue
+		}
+
+		for _, res := range results {
+			row, err := lookbackResultToPb(res)
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				slog.WarnContext(ctx, "could not assert type of result from algorithm", "algorithm_id", res.AlgorithmID)
+				continue
+			}
+			past := lookbacks[key]
+			past.Rows = append(past.Rows, row)
+			lookbacks[key] = past
+		}
+	}
+
+	return lookbacks, nil
+}
+
+// lookbackSearchRange returns the window times a lookback searches between,
+// as the results query takes them
+func lookbackSearchRange(lookback dag.Lookback, window *pb.Window) (sql.NullTime, time.Time) {
+	searchFrom, searchTo := dispatch.LookbackSearchRange(lookback, window)
+	if searchFrom == nil {
+		return sql.NullTime{}, searchTo
+	}
+	return sql.NullTime{Time: *searchFrom, Valid: true}, searchTo
+}
+
+// lookbackResultToPb converts a past result into a dependency result row,
+// according to the result type of its algorithm. Results of algorithms that
+// do not produce a result are converted to nil
+func lookbackResultToPb(res ReadResultsForLookbackRow) (*pb.AlgorithmDependencyResultRow, error) {
+	var result *pb.Result
+	switch res.ResultType {
+	case resultTypeValue:
+		result = &pb.Result{ResultData: &pb.Result_SingleValue{
+			SingleValue: float32(res.ResultValue.Float64),
+		}}
+	case resultTypeArray:
+		var values []float64
+		if err := json.Unmarshal([]byte(res.ResultArray.String), &values); err != nil {
+			return nil, err
+		}
+		result = &pb.Result{ResultData: &pb.Result_FloatValues{
+			FloatValues: &pb.FloatArray{Values: dispatch.Float32s(values)},
+		}}
+	case resultTypeStruct:
+		resultStruct, err := dispatch.UnmarshalStruct([]byte(res.ResultJson.String))
+		if err != nil {
+			return nil, err
+		}
+		result = &pb.Result{ResultData: &pb.Result_StructValue{
+			StructValue: resultStruct,
+		}}
+	default:
+		return nil, nil
+	}
+
+	windowMetadataPb, err := dispatch.UnmarshalStruct([]byte(res.WindowMetadata.String))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AlgorithmDependencyResultRow{
+		Result: result,
+		Window: &pb.Window{
+			TimeFrom:          timestamppb.New(res.WindowTimeFrom),
+			TimeTo:            timestamppb.New(res.WindowTimeTo),
+			Origin:            res.WindowOrigin,
+			WindowTypeName:    res.WindowTypeName,
+			WindowTypeVersion: res.WindowTypeVersion,
+			Metadata:          windowMetadataPb,
+		},
+	}, nil
+}
+
+// resultTypeToPb maps a stored result type back onto its protobuf
+func resultTypeToPb(resultType string) pb.ResultType {
+	switch resultType {
+	case resultTypeStruct:
+		return pb.ResultType_STRUCT
+	case resultTypeValue:
+		return pb.ResultType_VALUE
+	case resultTypeNone:
+		return pb.ResultType_NONE
+	case resultTypeArray:
+		return pb.ResultType_ARRAY
+	default:
+		return pb.ResultType_NOT_SPECIFIED
+	}
+}
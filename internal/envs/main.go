@@ -1,63 +1,253 @@
 package envs
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is read from the config file, when one is given, with environment
+// variables overriding it
 type Config struct {
-	IsProduction     bool
-	ConnectionString string
-	Port             int
-	Platform         string
-	LogLevel         string
+	IsProduction     bool   `yaml:"production" toml:"production"`
+	ConnectionString string `yaml:"connection_string" toml:"connection_string"`
+	Port             int    `yaml:"port" toml:"port"`
+	Platform         string `yaml:"-" toml:"-"`
+	LogLevel         string `yaml:"log_level" toml:"log_level"`
+	LogFormat        string `yaml:"log_format" toml:"log_format"`
+
+	// webhook listener, disabled when the port is 0
+	WebhookPort       int    `yaml:"webhook_port" toml:"webhook_port"`
+	WebhookConfigPath string `yaml:"webhook_config" toml:"webhook_config"`
+
+	// HTTP/JSON gateway, disabled when the port is 0
+	GatewayPort int `yaml:"gateway_port" toml:"gateway_port"`
+
+	// prometheus /metrics endpoint, disabled when the port is 0
+	MetricsPort int `yaml:"metrics_port" toml:"metrics_port"`
+
+	// span exporter (otlp or file), disabled when empty
+	TracesExporter string `yaml:"traces_exporter" toml:"traces_exporter"`
+	TracesFile     string `yaml:"traces_file" toml:"traces_file"`
+
+	// authentication of OrcaCore clients, by API key or client certificate
+	AuthEnabled bool   `yaml:"auth_enabled" toml:"auth_enabled"`
+	APIKeys     string `yaml:"api_keys" toml:"api_keys"`
+
+	// server TLS, disabled when no certificate is given. Client certificates
+	// are verified against the client CA, when given, and required when the
+	// client auth mode is require
+	TLSCertFile     string `yaml:"tls_cert_file" toml:"tls_cert_file"`
+	TLSKeyFile      string `yaml:"tls_key_file" toml:"tls_key_file"`
+	TLSClientCAFile string `yaml:"tls_client_ca_file" toml:"tls_client_ca_file"`
+	TLSClientAuth   string `yaml:"tls_client_auth" toml:"tls_client_auth"`
+
+	// TLS of the connections to processors. Processors are verified against
+	// the CA bundle, or the system roots when none is given, and presented
+	// the client certificate when given
+	ProcessorCAFile   string `yaml:"processor_ca_file" toml:"processor_ca_file"`
+	ProcessorCertFile string `yaml:"processor_cert_file" toml:"processor_cert_file"`
+	ProcessorKeyFile  string `yaml:"processor_key_file" toml:"processor_key_file"`
+
+	// limits on emitting windows, by origin, caller or window type.
+	// Unlimited when empty
+	RateLimits        string `yaml:"rate_limits" toml:"rate_limits"`
+	ConcurrencyLimits string `yaml:"concurrency_limits" toml:"concurrency_limits"`
 }
 
+// Reloadable lists the settings, by their config file names, that take
+// effect on reload. The others only take effect on restart
+var Reloadable = []string{"log_level", "rate_limits", "concurrency_limits"}
+
 var (
+	configMu       sync.Mutex
 	configInstance *Config
-	configOnce     sync.Once
+	configFile     string
 )
 
-// GetConfig returns the singleton configuration instance
+// SetConfigFile sets the config file read in place of ORCA_CONFIG_FILE, e.g.
+// when given on the command line
+func SetConfigFile(path string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	configFile = path
+}
+
+// GetConfig returns the singleton configuration instance. When the config
+// file cannot be read, the configuration of the environment alone is used
 func GetConfig() *Config {
-	configOnce.Do(func() {
-		configInstance = loadConfig()
-	})
+	configMu.Lock()
+	defer configMu.Unlock()
+	if configInstance == nil {
+		config, err := loadConfig(configFilePath())
+		if err != nil {
+			slog.Error("could not read config file", "error", err)
+			config, _ = loadConfig("")
+		}
+		configInstance = config
+	}
 	return configInstance
 }
 
-// loadConfig loads configuration from environment variables
-func loadConfig() *Config {
-	config := &Config{}
+// ReloadConfig forces a reload of the configuration. The new configuration
+// replaces the current one only once it has been read and, when validate is
+// given, passed validation
+func ReloadConfig(validate func(*Config) error) (*Config, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config, err := loadConfig(configFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if validate != nil {
+		if err := validate(config); err != nil {
+			return nil, err
+		}
+	}
+	configInstance = config
+	return config, nil
+}
 
-	orcaEnv := os.Getenv("ORCA_ENV")
-	config.IsProduction = orcaEnv == "production" || orcaEnv == "prod"
+// RestartRequired returns the config file names of the settings that differ
+// in next, and only take effect on restart
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	current, updated := reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem()
+	for i := range current.NumField() {
+		name, _, _ := strings.Cut(current.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "-" || slices.Contains(Reloadable, name) {
+			continue
+		}
+		if !current.Field(i).Equal(updated.Field(i)) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
 
-	config.ConnectionString = os.Getenv("ORCA_CONNECTION_STRING")
+func configFilePath() string {
+	if configFile != "" {
+		return configFile
+	}
+	return os.Getenv("ORCA_CONFIG_FILE")
+}
 
-	config.Port = 4040
-	if portStr := os.Getenv("ORCA_PORT"); portStr != "" {
-		if parsedPort, err := strconv.Atoi(portStr); err == nil {
-			config.Port = parsedPort
+// loadConfig loads configuration from the config file, when given, and then
+// from environment variables
+func loadConfig(path string) (*Config, error) {
+	config := &Config{
+		Port:          4040,
+		LogLevel:      "INFO",
+		LogFormat:     "text",
+		TracesFile:    "orca-traces.json",
+		TLSClientAuth: "optional",
+	}
+	if path != "" {
+		if err := readConfigFile(path, config); err != nil {
+			return nil, err
 		}
 	}
 
-	config.LogLevel = "INFO"
-	if logLevel := os.Getenv("ORCA_LOG_LEVEL"); logLevel != "" {
-		config.LogLevel = strings.ToUpper(logLevel)
+	if orcaEnv, ok := os.LookupEnv("ORCA_ENV"); ok {
+		config.IsProduction = orcaEnv == "production" || orcaEnv == "prod"
 	}
 
+	envString(&config.ConnectionString, "ORCA_CONNECTION_STRING")
+	envInt(&config.Port, "ORCA_PORT")
+	envString(&config.LogLevel, "ORCA_LOG_LEVEL")
+	envString(&config.LogFormat, "ORCA_LOG_FORMAT")
+
+	envInt(&config.WebhookPort, "ORCA_WEBHOOK_PORT")
+	envString(&config.WebhookConfigPath, "ORCA_WEBHOOK_CONFIG")
+	envInt(&config.GatewayPort, "ORCA_GATEWAY_PORT")
+	envInt(&config.MetricsPort, "ORCA_METRICS_PORT")
+
+	envString(&config.TracesExporter, "ORCA_TRACES_EXPORTER")
+	envString(&config.TracesFile, "ORCA_TRACES_FILE")
+
+	if authEnabled, ok := os.LookupEnv("ORCA_AUTH_ENABLED"); ok {
+		authEnabled = strings.ToLower(authEnabled)
+		config.AuthEnabled = authEnabled == "true" || authEnabled == "1"
+	}
+	envString(&config.APIKeys, "ORCA_API_KEYS")
+
+	envString(&config.TLSCertFile, "ORCA_TLS_CERT_FILE")
+	envString(&config.TLSKeyFile, "ORCA_TLS_KEY_FILE")
+	envString(&config.TLSClientCAFile, "ORCA_TLS_CLIENT_CA_FILE")
+	envString(&config.TLSClientAuth, "ORCA_TLS_CLIENT_AUTH")
+	envString(&config.ProcessorCAFile, "ORCA_PROCESSOR_CA_FILE")
+	envString(&config.ProcessorCertFile, "ORCA_PROCESSOR_CERT_FILE")
+	envString(&config.ProcessorKeyFile, "ORCA_PROCESSOR_KEY_FILE")
+
+	envString(&config.RateLimits, "ORCA_RATE_LIMITS")
+	envString(&config.ConcurrencyLimits, "ORCA_CONCURRENCY_LIMITS")
+
+	config.LogLevel = strings.ToUpper(config.LogLevel)
+	config.LogFormat = strings.ToLower(config.LogFormat)
+	config.TracesExporter = strings.ToLower(config.TracesExporter)
+	config.TLSClientAuth = strings.ToLower(config.TLSClientAuth)
+
 	config.Platform = inferPlatformFromConnectionString(config.ConnectionString)
 
-	return config
+	return config, nil
+}
+
+// readConfigFile reads a YAML or TOML config file, by its extension, over
+// the defaults in config. Unknown settings are rejected, so that typos are
+// not silently ignored
+func readConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("could not parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), config)
+		if err != nil {
+			return fmt.Errorf("could not parse config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("unknown setting in config file %s: %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// envString overrides a setting with an environment variable, when set
+func envString(setting *string, name string) {
+	if value := os.Getenv(name); value != "" {
+		*setting = value
+	}
 }
 
-// ReloadConfig forces a reload of the configuration
-func ReloadConfig() *Config {
-	configOnce = sync.Once{}
-	return GetConfig()
+// envInt overrides a setting with an environment variable, when set to a
+// number
+func envInt(setting *int, name string) {
+	if value := os.Getenv(name); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			*setting = parsed
+		}
+	}
 }
 
 // inferPlatformFromConnectionString determines the database platform from the connection string
@@ -68,6 +258,14 @@ func inferPlatformFromConnectionString(connStr string) string {
 
 	connStr = strings.ToLower(connStr)
 
+	// a sqlite file path may contain the name of another database
+	if strings.HasPrefix(connStr, "sqlite://") {
+		return "sqlite"
+	}
+	if strings.HasPrefix(connStr, "memory://") {
+		return "memory"
+	}
+
 	// Check for PostgreSQL patterns
 	if strings.HasPrefix(connStr, "postgresql://") ||
 		strings.HasPrefix(connStr, "postgres://") ||
@@ -85,8 +283,7 @@ func inferPlatformFromConnectionString(connStr string) string {
 	// SQLite
 	if strings.HasSuffix(connStr, ".db") ||
 		strings.HasSuffix(connStr, ".sqlite") ||
-		strings.HasSuffix(connStr, ".sqlite3") ||
-		strings.HasPrefix(connStr, "sqlite://") {
+		strings.HasSuffix(connStr, ".sqlite3") {
 		return "sqlite"
 	}
 
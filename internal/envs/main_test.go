@@ -0,0 +1,109 @@
+package envs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFile(t *testing.T) {
+	files := map[string]string{
+		"orca.yaml": "connection_string: postgresql://orca@localhost/orca\nport: 5050\nlog_level: debug\nauth_enabled: true\nrate_limits: caller:10/s\n",
+		"orca.toml": "connection_string = \"postgresql://orca@localhost/orca\"\nport = 5050\nlog_level = \"debug\"\nauth_enabled = true\nrate_limits = \"caller:10/s\"\n",
+	}
+	for name, contents := range files {
+		t.Run(name, func(t *testing.T) {
+			config, err := loadConfig(writeConfigFile(t, name, contents))
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if config.Port != 5050 || config.LogLevel != "DEBUG" || !config.AuthEnabled || config.RateLimits != "caller:10/s" {
+				t.Errorf("loadConfig() = %+v", config)
+			}
+			if config.Platform != "postgresql" {
+				t.Errorf("platform = %q, want it inferred from the connection string", config.Platform)
+			}
+			// settings missing from the file keep their defaults
+			if config.LogFormat != "text" || config.TLSClientAuth != "optional" {
+				t.Errorf("loadConfig() = %+v, want defaults kept", config)
+			}
+		})
+	}
+}
+
+func TestConfigFileEnvOverride(t *testing.T) {
+	path := writeConfigFile(t, "orca.yml", "port: 5050\nlog_level: debug\nproduction: true\n")
+	t.Setenv("ORCA_PORT", "6060")
+	t.Setenv("ORCA_ENV", "development")
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if config.Port != 6060 || config.IsProduction {
+		t.Errorf("loadConfig() = %+v, want the environment to override the file", config)
+	}
+	if config.LogLevel != "DEBUG" {
+		t.Errorf("log level = %q, want the file's setting", config.LogLevel)
+	}
+}
+
+func TestConfigFileInvalid(t *testing.T) {
+	for name, contents := range map[string]string{
+		"unknown.yaml": "prot: 5050\n",
+		"unknown.toml": "prot = 5050\n",
+		"invalid.yaml": "port: [\n",
+		"orca.json":    "{}",
+	} {
+		if _, err := loadConfig(writeConfigFile(t, name, contents)); err == nil {
+			t.Errorf("loadConfig(%s) error = nil, want error", name)
+		}
+	}
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loadConfig() of a missing file error = nil, want error")
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	path := writeConfigFile(t, "orca.yaml", "log_level: info\nport: 5050\n")
+	SetConfigFile(path)
+	t.Cleanup(func() {
+		SetConfigFile("")
+		configInstance = nil
+	})
+	current, err := ReloadConfig(nil)
+	if err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+
+	os.WriteFile(path, []byte("log_level: warn\nport: 5051\n"), 0o600)
+	reloaded, err := ReloadConfig(func(*Config) error { return nil })
+	if err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+	if GetConfig() != reloaded || reloaded.LogLevel != "WARN" {
+		t.Errorf("GetConfig() = %+v, want the reloaded configuration", GetConfig())
+	}
+	if changed := current.RestartRequired(reloaded); !slices.Equal(changed, []string{"port"}) {
+		t.Errorf("RestartRequired() = %v, want only the port", changed)
+	}
+
+	// an invalid configuration does not replace the current one
+	os.WriteFile(path, []byte("log_level: [\n"), 0o600)
+	if _, err := ReloadConfig(nil); err == nil {
+		t.Error("ReloadConfig() error = nil, want error")
+	}
+	if GetConfig() != reloaded {
+		t.Error("GetConfig() changed after a failed reload")
+	}
+}
@@ -0,0 +1,249 @@
+// Package gateway serves a JSON transcoding of the OrcaCore gRPC service over
+// HTTP, for clients such as shell scripts and browsers that cannot speak gRPC.
+//
+// Every unary RPC is served at POST /v1/<Method>, taking the request message
+// as JSON and returning the response message as JSON. An OpenAPI description
+// of the routes is served at GET /openapi.json.
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// PathPrefix is prepended to the method name in the route of each RPC
+const PathPrefix = "/v1/"
+
+// maxBodyBytes bounds the size of a request body
+const maxBodyBytes = 4 << 20
+
+var (
+	unmarshalOptions = protojson.UnmarshalOptions{}
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+type (
+	// Error is the body of an error response
+	Error struct {
+		Code       string      `json:"code"`
+		Message    string      `json:"message"`
+		Violations []Violation `json:"violations,omitempty"`
+	}
+
+	// Violation is a failed validation rule of a request field
+	Violation struct {
+		Field   string `json:"field"`
+		Rule    string `json:"rule"`
+		Message string `json:"message"`
+	}
+)
+
+// NewHandler builds the HTTP handler transcoding requests onto the OrcaCore
+// methods of server. Calls pass through the interceptor, if not nil, with the
+// Authorization and X-Api-Key headers as incoming metadata
+func NewHandler(server pb.OrcaCoreServer, interceptor grpc.UnaryServerInterceptor) http.Handler {
+	mux := http.NewServeMux()
+	for _, method := range pb.OrcaCore_ServiceDesc.Methods {
+		mux.Handle("POST "+PathPrefix+method.MethodName, &methodHandler{
+			server:      server,
+			method:      method,
+			interceptor: interceptor,
+		})
+	}
+
+	openAPI, err := json.MarshalIndent(buildOpenAPI(pb.File_service_proto.Services().ByName("OrcaCore")), "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("could not marshal OpenAPI description: %v", err))
+	}
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	return mux
+}
+
+type methodHandler struct {
+	server      pb.OrcaCoreServer
+	method      grpc.MethodDesc
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// headers passed on to the interceptor as gRPC metadata
+var forwardedHeaders = []string{"Authorization", "X-Api-Key"}
+
+func (h *methodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, Error{
+			Code:    codes.ResourceExhausted.String(),
+			Message: "request body too large",
+		})
+		return
+	}
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+
+	// the generated handler decodes the request through dec, so the same
+	// code path as gRPC serves the call
+	dec := func(msg any) error {
+		if err := unmarshalOptions.Unmarshal(body, msg.(proto.Message)); err != nil {
+			return &decodeError{err: err}
+		}
+		return nil
+	}
+	md := metadata.MD{}
+	for _, header := range forwardedHeaders {
+		if values := r.Header.Values(header); len(values) > 0 {
+			md.Set(header, values...)
+		}
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	// client certificates identify callers as they do over gRPC
+	if r.TLS != nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{
+			Addr:     remoteAddr(r.RemoteAddr),
+			AuthInfo: credentials.TLSInfo{State: *r.TLS},
+		})
+	}
+	resp, err := h.method.Handler(h.server, ctx, dec, h.interceptor)
+	if err != nil {
+		h.writeCallError(w, err)
+		return
+	}
+
+	out, err := marshalOptions.Marshal(resp.(proto.Message))
+	if err != nil {
+		slog.Error("could not marshal gateway response", "method", h.method.MethodName, "error", err)
+		writeError(w, http.StatusInternalServerError, Error{
+			Code:    codes.Internal.String(),
+			Message: "could not marshal response",
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+// remoteAddr returns the address of the client of a request, or nil when it
+// cannot be parsed
+func remoteAddr(addr string) net.Addr {
+	addrPort, err := netip.ParseAddrPort(addr)
+	if err != nil {
+		return nil
+	}
+	return net.TCPAddrFromAddrPort(addrPort)
+}
+
+// writeCallError maps the error of an RPC onto an HTTP response
+func (h *methodHandler) writeCallError(w http.ResponseWriter, err error) {
+	var validationErr *protovalidate.ValidationError
+	if errors.As(err, &validationErr) {
+		body := Error{
+			Code:    codes.InvalidArgument.String(),
+			Message: "request failed validation",
+		}
+		for _, violation := range validationErr.Violations {
+			body.Violations = append(body.Violations, Violation{
+				Field:   protovalidate.FieldPathString(violation.Proto.GetField()),
+				Rule:    violation.Proto.GetConstraintId(),
+				Message: violation.Proto.GetMessage(),
+			})
+		}
+		writeError(w, http.StatusBadRequest, body)
+		return
+	}
+
+	var decodeErr *decodeError
+	if errors.As(err, &decodeErr) {
+		writeError(w, http.StatusBadRequest, Error{
+			Code:    codes.InvalidArgument.String(),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	st := status.Convert(err)
+	if st.Code() == codes.Unknown || st.Code() == codes.Internal {
+		slog.Error("gateway call failed", "method", h.method.MethodName, "error", err)
+	}
+	if retryAfter, ok := ratelimit.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+	}
+	writeError(w, httpStatusFromCode(st.Code()), Error{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	})
+}
+
+// retryAfterSeconds formats a retry delay as the whole seconds of a
+// Retry-After header, rounded up
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+func writeError(w http.ResponseWriter, httpStatus int, body Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(body)
+}
+
+// httpStatusFromCode maps gRPC status codes onto HTTP status codes, following
+// https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// decodeError is a request body that is not valid JSON for the message
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return fmt.Sprintf("invalid request body: %v", e.err) }
+func (e *decodeError) Unwrap() error { return e.err }
@@ -0,0 +1,237 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type mockServer struct {
+	pb.UnimplementedOrcaCoreServer
+	windows []*pb.Window
+}
+
+func (m *mockServer) EmitWindow(ctx context.Context, window *pb.Window) (*pb.WindowEmitStatus, error) {
+	if err := protovalidate.Validate(window); err != nil {
+		return nil, err
+	}
+	if window.GetWindowTypeName() == "Missing" {
+		return nil, status.Error(codes.NotFound, "window type not found")
+	}
+	if window.GetWindowTypeName() == "Broken" {
+		return nil, errors.New("datalayer unavailable")
+	}
+	if window.GetWindowTypeName() == "Flooded" {
+		return nil, floodedErr
+	}
+	m.windows = append(m.windows, window)
+	return &pb.WindowEmitStatus{Status: pb.WindowEmitStatus_PROCESSING_TRIGGERED}, nil
+}
+
+// floodedErr is the error of a window over the rate limit of its window type
+var floodedErr = func() error {
+	limiter := ratelimit.NewLimiter(ratelimit.Limits{Rates: map[string]ratelimit.Rate{
+		ratelimit.KeyWindowType: {Count: 1, Per: time.Minute, Burst: 1},
+	}})
+	limiter.Acquire(map[string]string{ratelimit.KeyWindowType: "Flooded"})
+	_, err := limiter.Acquire(map[string]string{ratelimit.KeyWindowType: "Flooded"})
+	return err
+}()
+
+func TestEmitWindow(t *testing.T) {
+	window := func(name string) string {
+		return `{
+			"time_from": "2025-03-01T00:00:00Z",
+			"time_to": "2025-03-01T01:00:00Z",
+			"window_type_name": "` + name + `",
+			"window_type_version": "1.0.0",
+			"origin": "curl"
+		}`
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		wantCode       int
+		wantErrorCode  string
+		wantField      string
+		wantRetryAfter string
+	}{
+		{name: "valid", body: window("Hourly"), wantCode: http.StatusOK},
+		{
+			name:     "json field names",
+			body:     `{"timeFrom": "2025-03-01T00:00:00Z", "timeTo": "2025-03-01T01:00:00Z", "windowTypeName": "Hourly", "windowTypeVersion": "1.0.0", "origin": "curl"}`,
+			wantCode: http.StatusOK,
+		},
+		{name: "invalid json", body: `{"time_from": 3`, wantCode: http.StatusBadRequest, wantErrorCode: "InvalidArgument"},
+		{name: "unknown field", body: `{"colour": "red"}`, wantCode: http.StatusBadRequest, wantErrorCode: "InvalidArgument"},
+		{name: "failed validation", body: `{}`, wantCode: http.StatusBadRequest, wantErrorCode: "InvalidArgument", wantField: "time_from"},
+		{name: "status error", body: window("Missing"), wantCode: http.StatusNotFound, wantErrorCode: "NotFound"},
+		{name: "plain error", body: window("Broken"), wantCode: http.StatusInternalServerError, wantErrorCode: "Unknown"},
+		{name: "rate limited", body: window("Flooded"), wantCode: http.StatusTooManyRequests, wantErrorCode: "ResourceExhausted", wantRetryAfter: "60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &mockServer{}
+			handler := NewHandler(server, nil)
+
+			req := httptest.NewRequest(http.MethodPost, PathPrefix+"EmitWindow", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			if tt.wantCode == http.StatusOK {
+				var resp map[string]any
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("could not decode response: %v", err)
+				}
+				if resp["status"] != "PROCESSING_TRIGGERED" {
+					t.Errorf("status = %v, want PROCESSING_TRIGGERED", resp["status"])
+				}
+				if len(server.windows) != 1 || server.windows[0].GetOrigin() != "curl" {
+					t.Errorf("windows = %v, want the emitted window", server.windows)
+				}
+				return
+			}
+
+			var body Error
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("could not decode error: %v", err)
+			}
+			if body.Code != tt.wantErrorCode {
+				t.Errorf("code = %v, want %v", body.Code, tt.wantErrorCode)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.wantField != "" {
+				found := false
+				for _, violation := range body.Violations {
+					found = found || violation.Field == tt.wantField
+				}
+				if !found {
+					t.Errorf("violations = %v, want one on %v", body.Violations, tt.wantField)
+				}
+			}
+		})
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	handler := NewHandler(&mockServer{}, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, PathPrefix+"Expose", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	// rejects calls without the key, as an authenticating interceptor would
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if info.FullMethod != pb.OrcaCore_Expose_FullMethodName || len(md.Get("authorization")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "an API key is required")
+		}
+		return handler(ctx, req)
+	}
+	handler := NewHandler(&mockServer{}, interceptor)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, PathPrefix+"Expose", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, PathPrefix+"Expose", nil)
+	req.Header.Set("Authorization", "Bearer key")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want the call to reach the server", rec.Code)
+	}
+}
+
+func TestInterceptorPeer(t *testing.T) {
+	// the TLS state of the request reaches the interceptor, so that client
+	// certificates identify callers
+	var tlsInfo credentials.TLSInfo
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if p, ok := peer.FromContext(ctx); ok {
+			tlsInfo, _ = p.AuthInfo.(credentials.TLSInfo)
+		}
+		return handler(ctx, req)
+	}
+	handler := NewHandler(&mockServer{}, interceptor)
+
+	req := httptest.NewRequest(http.MethodPost, PathPrefix+"Expose", nil)
+	req.TLS = &tls.ConnectionState{ServerName: "orca.example.com"}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if tlsInfo.State.ServerName != "orca.example.com" {
+		t.Errorf("TLS server name = %q, want the TLS state of the request", tlsInfo.State.ServerName)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	handler := NewHandler(&mockServer{}, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var doc struct {
+		Paths      map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("could not decode OpenAPI description: %v", err)
+	}
+
+	for _, method := range pb.OrcaCore_ServiceDesc.Methods {
+		if _, ok := doc.Paths[PathPrefix+method.MethodName]; !ok {
+			t.Errorf("no path for %v", method.MethodName)
+		}
+	}
+
+	// every referenced schema must be described
+	for name, schema := range doc.Components.Schemas {
+		if schema == nil {
+			t.Errorf("schema %v is empty", name)
+		}
+	}
+	window, ok := doc.Components.Schemas["Window"]
+	if !ok {
+		t.Fatalf("no schema for Window")
+	}
+	properties := window["properties"].(map[string]any)
+	timeFrom := properties["time_from"].(map[string]any)
+	if timeFrom["format"] != "date-time" {
+		t.Errorf("time_from schema = %v, want a date-time", timeFrom)
+	}
+	if !strings.Contains(rec.Body.String(), "#/components/schemas/ProcessorRegistration") {
+		t.Errorf("RegisterProcessor does not refer to the ProcessorRegistration schema")
+	}
+}
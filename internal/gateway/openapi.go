@@ -0,0 +1,216 @@
+package gateway
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// schema is an OpenAPI 3 schema object
+type schema map[string]any
+
+// buildOpenAPI describes the gateway routes of a service, with a schema for
+// every message reachable from its methods. Schemas follow the protojson
+// mapping with proto field names
+func buildOpenAPI(service protoreflect.ServiceDescriptor) map[string]any {
+	schemas := map[string]any{
+		"Error": schema{
+			"type": "object",
+			"properties": schema{
+				"code":    schema{"type": "string"},
+				"message": schema{"type": "string"},
+				"violations": schema{
+					"type": "array",
+					"items": schema{
+						"type": "object",
+						"properties": schema{
+							"field":   schema{"type": "string"},
+							"rule":    schema{"type": "string"},
+							"message": schema{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+	errorResponse := func(description string) schema {
+		return schema{
+			"description": description,
+			"content": schema{
+				"application/json": schema{"schema": ref("Error")},
+			},
+		}
+	}
+
+	paths := map[string]any{}
+	methods := service.Methods()
+	for i := range methods.Len() {
+		method := methods.Get(i)
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			continue
+		}
+		addMessageSchema(schemas, method.Input())
+		addMessageSchema(schemas, method.Output())
+
+		paths[PathPrefix+string(method.Name())] = schema{
+			"post": schema{
+				"operationId": string(method.Name()),
+				"requestBody": schema{
+					"required": true,
+					"content": schema{
+						"application/json": schema{"schema": ref(schemaName(method.Input()))},
+					},
+				},
+				"responses": schema{
+					"200": schema{
+						"description": "OK",
+						"content": schema{
+							"application/json": schema{"schema": ref(schemaName(method.Output()))},
+						},
+					},
+					"400":     errorResponse("The request is invalid, e.g. it failed validation"),
+					"default": errorResponse("The call failed"),
+				},
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":   string(service.FullName()),
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": schema{
+			"schemas": schemas,
+		},
+	}
+}
+
+// addMessageSchema adds the schema of a message, and of the messages and
+// enums it refers to
+func addMessageSchema(schemas map[string]any, message protoreflect.MessageDescriptor) {
+	name := schemaName(message)
+	if _, ok := schemas[name]; ok || isWellKnown(message) {
+		return
+	}
+
+	properties := schema{}
+	// placeholder so that recursive messages terminate
+	schemas[name] = nil
+
+	fields := message.Fields()
+	var required []string
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		properties[string(field.Name())] = fieldSchema(schemas, field)
+		if field.Cardinality() == protoreflect.Required {
+			required = append(required, string(field.Name()))
+		}
+	}
+
+	s := schema{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	schemas[name] = s
+}
+
+// fieldSchema returns the schema of a field, adding the schemas it refers to
+func fieldSchema(schemas map[string]any, field protoreflect.FieldDescriptor) schema {
+	if field.IsMap() {
+		return schema{
+			"type":                 "object",
+			"additionalProperties": singularSchema(schemas, field.MapValue()),
+		}
+	}
+	s := singularSchema(schemas, field)
+	if field.IsList() {
+		return schema{"type": "array", "items": s}
+	}
+	return s
+}
+
+// singularSchema returns the schema of a single value of a field
+func singularSchema(schemas map[string]any, field protoreflect.FieldDescriptor) schema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return schema{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return schema{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return schema{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// protojson encodes 64 bit integers as strings
+		return schema{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return schema{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return schema{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return schema{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return schema{"type": "string"}
+	case protoreflect.BytesKind:
+		return schema{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range values.Len() {
+			names[i] = string(values.Get(i).Name())
+		}
+		return schema{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		message := field.Message()
+		if wellKnown, ok := wellKnownSchema(message); ok {
+			return wellKnown
+		}
+		addMessageSchema(schemas, message)
+		return ref(schemaName(message))
+	default:
+		return schema{}
+	}
+}
+
+// wellKnownSchema returns the protojson schema of a well known type
+func wellKnownSchema(message protoreflect.MessageDescriptor) (schema, bool) {
+	switch message.FullName() {
+	case "google.protobuf.Timestamp":
+		return schema{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return schema{"type": "string", "example": "1.5s"}, true
+	case "google.protobuf.Struct":
+		return schema{"type": "object", "additionalProperties": true}, true
+	case "google.protobuf.Value":
+		return schema{}, true
+	case "google.protobuf.ListValue":
+		return schema{"type": "array", "items": schema{}}, true
+	case "google.protobuf.Empty":
+		return schema{"type": "object"}, true
+	default:
+		return nil, false
+	}
+}
+
+func isWellKnown(message protoreflect.MessageDescriptor) bool {
+	_, ok := wellKnownSchema(message)
+	return ok
+}
+
+// schemaName names the schema of a message without its package, with nested
+// messages joined by dots, e.g. "ProcessorRegistration.Algorithm"
+func schemaName(message protoreflect.MessageDescriptor) string {
+	name := string(message.FullName())
+	if pkg := string(message.ParentFile().Package()); pkg != "" {
+		name = strings.TrimPrefix(name, pkg+".")
+	}
+	return name
+}
+
+func ref(name string) schema {
+	return schema{"$ref": "#/components/schemas/" + name}
+}
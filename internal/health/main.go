@@ -0,0 +1,137 @@
+// Package health keeps the status of the standard gRPC health service in line
+// with the dependencies of each Orca core service, so that orchestrators such
+// as Kubernetes can probe whether core is ready.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CheckInterval is how often the checks are run
+const CheckInterval = 5 * time.Second
+
+// CheckTimeout bounds the run time of a single check
+const CheckTimeout = 3 * time.Second
+
+// Overall is the service name that reports the status of the whole server
+const Overall = ""
+
+type (
+	// Check returns an error when a dependency is not ready
+	Check struct {
+		Name string
+		Fn   func(ctx context.Context) error
+	}
+
+	// Monitor runs the checks and sets the status of each service
+	Monitor struct {
+		server   *health.Server
+		mu       sync.Mutex
+		services map[string][]Check
+		failing  map[string]error
+		shutdown bool
+	}
+)
+
+// NewMonitor creates a monitor of the services on a health server. Services
+// are NOT_SERVING until their checks have first passed
+func NewMonitor(server *health.Server) *Monitor {
+	server.SetServingStatus(Overall, healthpb.HealthCheckResponse_NOT_SERVING)
+	return &Monitor{
+		server:   server,
+		services: map[string][]Check{Overall: nil},
+		failing:  map[string]error{},
+	}
+}
+
+// SetChecks sets the checks that a service depends on
+func (m *Monitor) SetChecks(service string, checks ...Check) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.services[service] = checks
+	if !m.shutdown {
+		m.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// Run checks the services every interval, until the context is done
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.CheckOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce runs every check once and updates the status of the services
+func (m *Monitor) CheckOnce(ctx context.Context) {
+	m.mu.Lock()
+	services := make(map[string][]Check, len(m.services))
+	for service, checks := range m.services {
+		services[service] = checks
+	}
+	m.mu.Unlock()
+
+	// checks shared between services are run once
+	results := map[string]error{}
+	for _, checks := range services {
+		for _, check := range checks {
+			if _, ok := results[check.Name]; ok {
+				continue
+			}
+			checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+			results[check.Name] = check.Fn(checkCtx)
+			cancel()
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, err := range results {
+		previous, wasFailing := m.failing[name]
+		switch {
+		case err != nil && (!wasFailing || previous.Error() != err.Error()):
+			slog.Warn("health check failing", "check", name, "error", err)
+			m.failing[name] = err
+		case err == nil && wasFailing:
+			slog.Info("health check recovered", "check", name)
+			delete(m.failing, name)
+		}
+	}
+
+	if m.shutdown {
+		return
+	}
+	for service, checks := range services {
+		status := healthpb.HealthCheckResponse_SERVING
+		for _, check := range checks {
+			if results[check.Name] != nil {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				break
+			}
+		}
+		m.server.SetServingStatus(service, status)
+	}
+}
+
+// Shutdown sets every service to NOT_SERVING for good, so that traffic is
+// drained before the server stops
+func (m *Monitor) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shutdown = true
+	m.server.Shutdown()
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(t *testing.T, server *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestMonitor(t *testing.T) {
+	var workersErr error
+	datalayer := Check{Name: "datalayer", Fn: func(ctx context.Context) error { return nil }}
+	workers := Check{Name: "workers", Fn: func(ctx context.Context) error { return workersErr }}
+
+	server := health.NewServer()
+	monitor := NewMonitor(server)
+	monitor.SetChecks("orca.OrcaCore", datalayer)
+	monitor.SetChecks(Overall, datalayer, workers)
+
+	// not ready before the first checks
+	for _, service := range []string{Overall, "orca.OrcaCore"} {
+		if got := status(t, server, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("initial status of %q = %v, want NOT_SERVING", service, got)
+		}
+	}
+
+	monitor.CheckOnce(context.Background())
+	if got := status(t, server, Overall); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", got)
+	}
+
+	// a failing check only affects the services depending on it
+	workersErr = errors.New("scheduler stalled")
+	monitor.CheckOnce(context.Background())
+	if got := status(t, server, Overall); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("overall status = %v, want NOT_SERVING", got)
+	}
+	if got := status(t, server, "orca.OrcaCore"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("orca.OrcaCore status = %v, want SERVING", got)
+	}
+
+	workersErr = nil
+	monitor.CheckOnce(context.Background())
+	if got := status(t, server, Overall); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("recovered status = %v, want SERVING", got)
+	}
+
+	// passing checks cannot bring services back after shutdown
+	monitor.Shutdown()
+	monitor.CheckOnce(context.Background())
+	for _, service := range []string{Overall, "orca.OrcaCore"} {
+		if got := status(t, server, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("status of %q after shutdown = %v, want NOT_SERVING", service, got)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package logging builds the slog handler of Orca core. Attributes attached
+// to a context are added to every record logged with that context, so that
+// the lines of one window or processor call can be correlated.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats lists the supported log formats
+var Formats = []string{FormatText, FormatJSON}
+
+// consistent attribute keys
+const (
+	KeyCorrelationID     = "correlation_id"
+	KeyTraceID           = "trace_id"
+	KeyWindowID          = "window_id"
+	KeyWindowType        = "window_type"
+	KeyWindowTypeVersion = "window_type_version"
+	KeyOrigin            = "origin"
+	KeyExecID            = "exec_id"
+	KeyProcessor         = "processor"
+	KeyAlgorithm         = "algorithm"
+)
+
+// MaxValueLength is the longest string value logged before it is truncated
+const MaxValueLength = 512
+
+const redacted = "[REDACTED]"
+
+// keys whose values are never logged
+var sensitiveKeys = []string{"password", "secret", "token", "api_key", "authorization", "connection_string"}
+
+// NewHandler returns a handler writing records in the given format to w
+func NewHandler(w io.Writer, format string, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceAttr}
+	switch format {
+	case FormatText, "":
+		return &contextHandler{Handler: slog.NewTextHandler(w, opts)}, nil
+	case FormatJSON:
+		return &contextHandler{Handler: slog.NewJSONHandler(w, opts)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s", format)
+	}
+}
+
+type contextKey struct{}
+
+// With returns a context carrying the given attributes for the records
+// logged with it. Attributes already attached are kept, unless replaced by an
+// attribute of the same key
+func With(ctx context.Context, args ...any) context.Context {
+	attrs := slog.Group("", args...).Value.Group()
+	existing, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, attr := range existing {
+		replaced := slices.ContainsFunc(attrs, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if !replaced {
+			merged = append(merged, attr)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// WithWindow returns a context carrying the window type, version and origin
+// of a window, which identify it until it is stored with an id
+func WithWindow(ctx context.Context, window *pb.Window) context.Context {
+	return With(
+		ctx,
+		KeyWindowType,
+		window.GetWindowTypeName(),
+		KeyWindowTypeVersion,
+		window.GetWindowTypeVersion(),
+		KeyOrigin,
+		window.GetOrigin(),
+	)
+}
+
+// WithCorrelationID attaches a correlation ID to the context, unless it has
+// one already. The trace ID is reused when the context is traced, so that
+// log lines can be matched to their spans
+func WithCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	id := uuid.NewString()
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		id = spanContext.TraceID().String()
+	}
+	return With(ctx, KeyCorrelationID, id)
+}
+
+// CorrelationID returns the correlation ID attached to the context, if any
+func CorrelationID(ctx context.Context) string {
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	for _, attr := range attrs {
+		if attr.Key == KeyCorrelationID {
+			return attr.Value.String()
+		}
+	}
+	return ""
+}
+
+// contextHandler adds the attributes attached to the context, and the trace
+// ID of its span, to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.AddAttrs(slog.String(KeyTraceID, spanContext.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// replaceAttr redacts sensitive values, summarises windows and truncates
+// long values
+func replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindAny {
+		switch v := value.Any().(type) {
+		case *pb.Window:
+			return Window(attr.Key, v)
+		case proto.Message:
+			data, err := protojson.Marshal(v)
+			if err != nil {
+				return slog.String(attr.Key, fmt.Sprintf("%T", v))
+			}
+			return slog.String(attr.Key, truncate(string(data)))
+		case error:
+			return slog.String(attr.Key, truncate(v.Error()))
+		default:
+			return slog.String(attr.Key, truncate(fmt.Sprintf("%+v", v)))
+		}
+	}
+	if value.Kind() == slog.KindString {
+		return slog.String(attr.Key, truncate(value.String()))
+	}
+	return attr
+}
+
+// Window logs the identifying fields of a window, leaving out its metadata
+func Window(key string, window *pb.Window) slog.Attr {
+	if window == nil {
+		return slog.Any(key, nil)
+	}
+	return slog.Group(key,
+		"type", window.GetWindowTypeName(),
+		"version", window.GetWindowTypeVersion(),
+		"origin", window.GetOrigin(),
+		"time_from", window.GetTimeFrom().AsTime(),
+		"time_to", window.GetTimeTo().AsTime(),
+	)
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxValueLength {
+		return s
+	}
+	return fmt.Sprintf("%s...(%d bytes truncated)", s[:MaxValueLength], len(s)-MaxValueLength)
+}
@@ -2,19 +2,42 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/bufbuild/protovalidate-go"
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
 	dlyr "github.com/orca-telemetry/core/internal/datalayers"
-	types "github.com/orca-telemetry/core/internal/types"
+	"github.com/orca-telemetry/core/internal/envs"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	"github.com/orca-telemetry/core/internal/schedule"
+	"github.com/orca-telemetry/core/internal/tracing"
 	pb "github.com/orca-telemetry/core/protobufs/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 type (
 	OrcaCoreServer struct {
 		pb.UnimplementedOrcaCoreServer
-		client types.Datalayer
+		client   types.Datalayer
+		platform dlyr.Platform
+
+		// limits on emitting windows, none until SetEmitLimits is called
+		limiter *ratelimit.Limiter
+
+		// unix nanoseconds of the last scheduler pass
+		lastSchedulerPass atomic.Int64
 	}
 )
 
@@ -22,6 +45,10 @@ var (
 	MAX_PROCESSORS = 20
 )
 
+// schedulerStallTimeout is how long the scheduler can go without a pass
+// before it is reported as stalled. Passes can take a while when catching up
+const schedulerStallTimeout = time.Minute
+
 // NewServer produces a new ORCA gRPC server
 func NewServer(
 	ctx context.Context,
@@ -41,12 +68,67 @@ func NewServer(
 		return nil, err
 	}
 
+	// datalayers that report their own statistics, e.g. of a connection pool
+	if collector, ok := client.(prometheus.Collector); ok {
+		if err := metrics.Registry.Register(collector); err != nil {
+			slog.Warn("could not register datalayer metrics", "error", err)
+		}
+	}
+
 	s := &OrcaCoreServer{
-		client: client,
+		client:   client,
+		platform: platform,
+		limiter:  ratelimit.NewLimiter(ratelimit.Limits{}),
 	}
 	return s, nil
 }
 
+// SetEmitLimits replaces the rate and concurrency limits on EmitWindow
+func (o *OrcaCoreServer) SetEmitLimits(limits ratelimit.Limits) {
+	o.limiter.SetLimits(limits)
+}
+
+// RunScheduler emits the windows of scheduled window types until the context
+// is done. When several instances run, only one of them emits windows
+func (o *OrcaCoreServer) RunScheduler(ctx context.Context) {
+	slog.Info("starting window scheduler")
+	schedule.Run(ctx, o, schedule.PollInterval)
+}
+
+// EmitScheduledWindows records the scheduler pass, so that a stalled
+// scheduler is reported by CheckWorkers, and emits the due windows
+func (o *OrcaCoreServer) EmitScheduledWindows(ctx context.Context, now time.Time) error {
+	o.lastSchedulerPass.Store(time.Now().UnixNano())
+	return o.client.EmitScheduledWindows(ctx, now)
+}
+
+// CheckDatalayer returns an error when the datalayer cannot be reached
+func (o *OrcaCoreServer) CheckDatalayer(ctx context.Context) error {
+	if err := o.client.Ping(ctx); err != nil {
+		return fmt.Errorf("datalayer unreachable: %w", err)
+	}
+	return nil
+}
+
+// CheckSchema returns an error unless the datalayer is migrated to the
+// latest version
+func (o *OrcaCoreServer) CheckSchema(ctx context.Context) error {
+	return dlyr.CheckSchema(ctx, o.client, string(o.platform))
+}
+
+// CheckWorkers returns an error when the background scheduler has not made a
+// pass recently
+func (o *OrcaCoreServer) CheckWorkers(ctx context.Context) error {
+	last := o.lastSchedulerPass.Load()
+	if last == 0 {
+		return fmt.Errorf("window scheduler has not started")
+	}
+	if since := time.Since(time.Unix(0, last)); since > schedulerStallTimeout {
+		return fmt.Errorf("window scheduler has not run for %v", since.Round(time.Second))
+	}
+	return nil
+}
+
 // validate a protobuf via protovalidate
 func validate[T proto.Message](msg T) error {
 	v, err := protovalidate.New()
@@ -72,12 +154,23 @@ func (o *OrcaCoreServer) RegisterProcessor(
 	if err != nil {
 		return nil, err
 	}
-	slog.Info("registering processor")
+	if err := auth.Require(ctx, auth.RoleProcessor, types.Namespace(proc.GetProjectName())); err != nil {
+		return nil, err
+	}
+	// window data is only sent to processors that prove who they are in
+	// production
+	if proc.GetTls().GetInsecureSkipVerify() && envs.GetConfig().IsProduction {
+		return nil, status.Error(
+			codes.FailedPrecondition,
+			"processors cannot skip certificate verification in production",
+		)
+	}
+	slog.Info("registering processor", "processor", proc.GetName())
 	err = o.client.RegisterProcessor(ctx, proc)
 	if err != nil {
 		return nil, err
 	}
-	slog.Debug("registered processor", "processor", proc)
+	slog.Debug("registered processor", "processor", proc.GetName())
 	return &pb.Status{
 		Received: true,
 		Message:  "Successfully registered processor",
@@ -87,17 +180,73 @@ func (o *OrcaCoreServer) RegisterProcessor(
 func (o *OrcaCoreServer) EmitWindow(
 	ctx context.Context,
 	window *pb.Window,
-) (*pb.WindowEmitStatus, error) {
-	slog.Debug("Recieved Window", "window", window)
-	err := validate(window)
+) (_ *pb.WindowEmitStatus, err error) {
+	// continue the trace of the caller, if it sent one
+	ctx, span := tracing.Tracer.Start(tracing.Extract(ctx), "EmitWindow", trace.WithAttributes(
+		attribute.String("orca.window_type", window.GetWindowTypeName()),
+		attribute.String("orca.window_type_version", window.GetWindowTypeVersion()),
+		attribute.String("orca.origin", window.GetOrigin()),
+	))
+	defer func() { tracing.End(span, err) }()
+	ctx = logging.WithWindow(logging.WithCorrelationID(ctx), window)
+
+	slog.DebugContext(ctx, "received window")
+	err = validate(window)
 	if err != nil {
 		return nil, err
 	}
-	slog.Info("emitting window", "window", window)
+	// callers that can only emit the window type in one namespace emit
+	// windows without a namespace to it. Windows left without one are of the
+	// namespace the datalayer resolves, which checks the caller can emit them
+	if window.GetNamespace() == "" {
+		namespace, err := auth.GrantedNamespace(ctx, auth.RoleEmitter, window.GetWindowTypeName())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if namespace != "" {
+			window = proto.Clone(window).(*pb.Window)
+			window.Namespace = namespace
+		}
+	}
+	scope := auth.WindowTypeScope(auth.NamespaceScope(window.GetNamespace()), window.GetWindowTypeName())
+	if err := auth.Require(ctx, auth.RoleEmitter, scope); err != nil {
+		return nil, err
+	}
+	release, err := o.limiter.Acquire(map[string]string{
+		ratelimit.KeyOrigin:     window.GetOrigin(),
+		ratelimit.KeyCaller:     auth.Caller(ctx),
+		ratelimit.KeyWindowType: window.GetWindowTypeName(),
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "rejected window over its limits", "error", err)
+		return nil, err
+	}
+	// the window holds its slots until it has been processed
+	ctx = ratelimit.WithRelease(ctx, release)
+
+	slog.InfoContext(ctx, "emitting window")
 	windowEmitStatus, err := o.client.EmitWindow(ctx, window)
+	if err != nil {
+		release()
+	}
+	if errors.Is(err, types.AmbiguousNamespace) {
+		err = status.Error(codes.InvalidArgument, err.Error())
+	}
+	span.SetAttributes(attribute.String("orca.status", windowEmitStatus.GetStatus().String()))
 	return &windowEmitStatus, err
 }
 
+// LookupAPIKey looks up the API keys stored in the datalayer, for the
+// authentication of clients
+func (o *OrcaCoreServer) LookupAPIKey(ctx context.Context, keyHash string) (string, error) {
+	return o.client.LookupAPIKey(ctx, keyHash)
+}
+
+// ReadGrants reads the roles granted to an authenticated client
+func (o *OrcaCoreServer) ReadGrants(ctx context.Context, identity string) ([]types.Grant, error) {
+	return o.client.ReadGrants(ctx, identity)
+}
+
 func (o *OrcaCoreServer) Expose(
 	ctx context.Context,
 	settings *pb.ExposeSettings,
@@ -107,6 +256,49 @@ func (o *OrcaCoreServer) Expose(
 	if err != nil {
 		return nil, err
 	}
+	// readers of one namespace expose it without naming it
+	if settings.GetNamespace() == "" && settings.GetExcludeProject() == "" {
+		namespace, err := auth.GrantedNamespace(ctx, auth.RoleReader, "")
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if namespace != "" {
+			settings = proto.Clone(settings).(*pb.ExposeSettings)
+			settings.Namespace = namespace
+		}
+	}
+	if err := auth.Require(ctx, auth.RoleReader, auth.NamespaceScope(settings.GetNamespace())); err != nil {
+		return nil, err
+	}
 	internalState, err := o.client.Expose(ctx, settings)
 	return internalState, err
 }
+
+// Usage reports the use of the limits on EmitWindow
+func (o *OrcaCoreServer) Usage(
+	ctx context.Context,
+	req *pb.UsageRequest,
+) (*pb.UsageReport, error) {
+	err := validate(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.Require(ctx, auth.RoleAdmin, types.ScopeAll); err != nil {
+		return nil, err
+	}
+	report := &pb.UsageReport{}
+	for _, usage := range o.limiter.Usage() {
+		if req.GetKey() != "" && usage.Key != req.GetKey() {
+			continue
+		}
+		report.Usage = append(report.Usage, &pb.KeyUsage{
+			Key:      usage.Key,
+			Value:    usage.Value,
+			Allowed:  usage.Allowed,
+			Rejected: usage.Rejected,
+			InFlight: uint32(usage.InFlight),
+			Tokens:   usage.Tokens,
+		})
+	}
+	return report, nil
+}
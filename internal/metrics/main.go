@@ -0,0 +1,98 @@
+// Package metrics holds the Prometheus metrics of Orca core, and serves them
+// for scraping.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "orca"
+
+// Registry holds every Orca core metric, along with the Go runtime and
+// process metrics
+var Registry = prometheus.NewRegistry()
+
+// statuses of calls that can fail
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+var (
+	WindowsEmitted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "windows_emitted_total",
+		Help:      "Windows emitted, by window type and emit status.",
+	}, []string{"window_type", "window_type_version", "status"})
+
+	PlanBuildDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "plan_build_duration_seconds",
+		Help:      "Time taken to build the execution plan of a window.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	})
+
+	StagesDispatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stages_dispatched_total",
+		Help:      "Execution plan stages dispatched, by processor taking part in the stage.",
+	}, []string{"processor"})
+
+	TasksDispatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_dispatched_total",
+		Help:      "Tasks dispatched to processors, by processor.",
+	}, []string{"processor"})
+
+	ExecuteDagPartDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "execute_dag_part_duration_seconds",
+		Help:      "Time from calling ExecuteDagPart on a processor until its results have all been received, by processor and status.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 3, 10),
+	}, []string{"processor", "status"})
+
+	ResultsStored = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "results_stored_total",
+		Help:      "Algorithm results received from processors, by algorithm and whether they were stored.",
+	}, []string{"algorithm", "algorithm_version", "status"})
+
+	LookbackQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "lookback_query_duration_seconds",
+		Help:      "Time taken to read the lookbacks of a stage, by query.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 3, 10),
+	}, []string{"query"})
+
+	ProcessorServing = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_serving",
+		Help:      "Whether a processor reported serving on its last health check (1) or not (0).",
+	}, []string{"processor"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		WindowsEmitted,
+		PlanBuildDuration,
+		StagesDispatched,
+		TasksDispatched,
+		ExecuteDagPartDuration,
+		ResultsStored,
+		LookbackQueryDuration,
+		ProcessorServing,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry}))
+	return mux
+}
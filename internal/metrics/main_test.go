@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	WindowsEmitted.WithLabelValues("TestWindow", "1.0.0", "PROCESSING_TRIGGERED").Inc()
+	PlanBuildDuration.Observe(0.001)
+	ExecuteDagPartDuration.WithLabelValues("TestProcessor", StatusOK).Observe(0.1)
+	ProcessorServing.WithLabelValues("TestProcessor").Set(1)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`orca_windows_emitted_total{status="PROCESSING_TRIGGERED",window_type="TestWindow",window_type_version="1.0.0"} 1`,
+		`orca_plan_build_duration_seconds_count 1`,
+		`orca_execute_dag_part_duration_seconds_count{processor="TestProcessor",status="ok"} 1`,
+		`orca_processor_serving{processor="TestProcessor"} 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+}
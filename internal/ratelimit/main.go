@@ -0,0 +1,399 @@
+// Package ratelimit limits the rate of windows emitted to Orca core, and the
+// number of them being processed at once, per origin, caller or window type,
+// so that one emitter cannot starve the others.
+//
+// Rates are token buckets, refilled at the rate of the limit up to its burst.
+// A window holds a slot of each concurrency limit until it has been
+// processed. Windows over a limit are rejected with RESOURCE_EXHAUSTED,
+// carrying a RetryInfo of when to try again.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// keys windows are limited by
+const (
+	KeyOrigin     = "origin"
+	KeyCaller     = "caller"
+	KeyWindowType = "window_type"
+)
+
+// Keys lists the keys windows can be limited by
+var Keys = []string{KeyOrigin, KeyCaller, KeyWindowType}
+
+// ConcurrencyRetryDelay is the retry hint given when too many windows are
+// being processed, as when a slot frees up is not known
+const ConcurrencyRetryDelay = time.Second
+
+// IdleTimeout is how long the usage of a key is kept after its last window,
+// once it holds no slots and its bucket has refilled
+const IdleTimeout = 10 * time.Minute
+
+// rate units, by suffix
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+type (
+	// Rate allows Count windows per Per, in bursts of up to Burst windows
+	Rate struct {
+		Count int
+		Per   time.Duration
+		Burst int
+	}
+
+	// Limits holds the rates and concurrency limits of each key. Keys
+	// without a limit are not limited
+	Limits struct {
+		Rates       map[string]Rate
+		Concurrency map[string]int
+	}
+
+	// Usage is the use of the limits by one value of a key, e.g. the
+	// window type Hourly
+	Usage struct {
+		Key      string
+		Value    string
+		Allowed  uint64
+		Rejected uint64
+		InFlight int
+		// tokens left in the bucket, when the key has a rate
+		Tokens float64
+	}
+)
+
+// ParseRates parses a comma separated list of key:count/unit[:burst] rates,
+// as set in ORCA_RATE_LIMITS, e.g. "caller:100/m,window_type:20/s:40". The
+// burst defaults to the count
+func ParseRates(s string) (map[string]Rate, error) {
+	rates := make(map[string]Rate)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("rate limit must be given as key:count/unit[:burst], not %q", entry)
+		}
+		key, err := parseKey(parts[0], rates)
+		if err != nil {
+			return nil, err
+		}
+		countStr, unit, ok := strings.Cut(parts[1], "/")
+		per, knownUnit := units[unit]
+		if !ok || !knownUnit {
+			return nil, fmt.Errorf("rate limit %q must be per s, m or h", entry)
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("rate limit %q must allow a positive number of windows", entry)
+		}
+		burst := count
+		if len(parts) == 3 {
+			burst, err = strconv.Atoi(parts[2])
+			if err != nil || burst <= 0 {
+				return nil, fmt.Errorf("burst of rate limit %q must be positive", entry)
+			}
+		}
+		rates[key] = Rate{Count: count, Per: per, Burst: burst}
+	}
+	return rates, nil
+}
+
+// ParseConcurrency parses a comma separated list of key:count limits on the
+// windows being processed at once, as set in ORCA_CONCURRENCY_LIMITS, e.g.
+// "caller:50,origin:5"
+func ParseConcurrency(s string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		keyStr, countStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("concurrency limit must be given as key:count, not %q", entry)
+		}
+		key, err := parseKey(keyStr, limits)
+		if err != nil {
+			return nil, err
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("concurrency limit %q must be positive", entry)
+		}
+		limits[key] = count
+	}
+	return limits, nil
+}
+
+func parseKey[V any](key string, seen map[string]V) (string, error) {
+	key = strings.TrimSpace(key)
+	if !slices.Contains(Keys, key) {
+		return "", fmt.Errorf("unknown limit key: %s. Must be one of: %s", key, strings.Join(Keys, ", "))
+	}
+	if _, ok := seen[key]; ok {
+		return "", fmt.Errorf("limit of %s is given more than once", key)
+	}
+	return key, nil
+}
+
+type usageKey struct {
+	key   string
+	value string
+}
+
+type usage struct {
+	allowed  uint64
+	rejected uint64
+	inFlight int
+	lastSeen time.Time
+
+	// tokens in the bucket as of updated
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter admits windows within the limits of their keys
+type Limiter struct {
+	mu        sync.Mutex
+	limits    Limits
+	usage     map[usageKey]*usage
+	lastPrune time.Time
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewLimiter returns a limiter enforcing the limits
+func NewLimiter(limits Limits) *Limiter {
+	return &Limiter{
+		limits: limits,
+		usage:  make(map[usageKey]*usage),
+		now:    time.Now,
+	}
+}
+
+// SetLimits replaces the limits. Usage so far is kept
+func (l *Limiter) SetLimits(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+}
+
+// Acquire admits a window with the given value of each key, e.g. its window
+// type, taking a token of each rate and a slot of each concurrency limit.
+// Keys with an empty value, such as the caller when authentication is
+// disabled, are not limited. The returned release frees the slots once the
+// window has been processed, and can be called more than once
+func (l *Limiter) Acquire(values map[string]string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	// every limit is checked before any is taken, so a rejected window
+	// uses up none of them
+	var admitted, rated []*usage
+	for _, key := range Keys {
+		value := values[key]
+		if value == "" {
+			continue
+		}
+		rate, hasRate := l.limits.Rates[key]
+		concurrency, hasConcurrency := l.limits.Concurrency[key]
+		if !hasRate && !hasConcurrency {
+			continue
+		}
+
+		u := l.usageOf(key, value, now)
+		if hasRate {
+			u.refill(rate, now)
+			if u.tokens < 1 {
+				u.rejected++
+				wait := time.Duration((1 - u.tokens) / rate.perNanosecond())
+				return nil, exhausted(key, value, fmt.Sprintf("rate limit of %d windows per %v", rate.Count, rate.Per), wait)
+			}
+		}
+		if hasConcurrency && u.inFlight >= concurrency {
+			u.rejected++
+			return nil, exhausted(key, value, fmt.Sprintf("limit of %d windows processing at once", concurrency), ConcurrencyRetryDelay)
+		}
+		if hasRate {
+			rated = append(rated, u)
+		}
+		admitted = append(admitted, u)
+	}
+
+	for _, u := range rated {
+		u.tokens--
+	}
+	for _, u := range admitted {
+		u.allowed++
+		u.inFlight++
+		u.lastSeen = now
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, u := range admitted {
+				u.inFlight--
+			}
+		})
+	}, nil
+}
+
+// Usage returns the usage of every key value seen recently, ordered by key
+// and value
+func (l *Limiter) Usage() []Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usages := make([]Usage, 0, len(l.usage))
+	for k, u := range l.usage {
+		if rate, ok := l.limits.Rates[k.key]; ok {
+			u.refill(rate, now)
+		}
+		usages = append(usages, Usage{
+			Key:      k.key,
+			Value:    k.value,
+			Allowed:  u.allowed,
+			Rejected: u.rejected,
+			InFlight: u.inFlight,
+			Tokens:   u.tokens,
+		})
+	}
+	slices.SortFunc(usages, func(a, b Usage) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return usages
+}
+
+func (l *Limiter) usageOf(key string, value string, now time.Time) *usage {
+	k := usageKey{key: key, value: value}
+	u, ok := l.usage[k]
+	if !ok {
+		u = &usage{updated: now, lastSeen: now}
+		if rate, ok := l.limits.Rates[key]; ok {
+			u.tokens = float64(rate.Burst)
+		}
+		l.usage[k] = u
+	}
+	return u
+}
+
+// prune forgets keys that have been idle for IdleTimeout, so that e.g. every
+// origin ever seen is not kept. Keys holding slots, or with buckets yet to
+// refill, are kept
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < IdleTimeout {
+		return
+	}
+	l.lastPrune = now
+	for k, u := range l.usage {
+		if u.inFlight > 0 || now.Sub(u.lastSeen) < IdleTimeout {
+			continue
+		}
+		// forgetting a bucket that has not refilled would refill it
+		if rate, ok := l.limits.Rates[k.key]; ok {
+			u.refill(rate, now)
+			if u.tokens < float64(rate.Burst) {
+				continue
+			}
+		}
+		delete(l.usage, k)
+	}
+}
+
+func (r Rate) perNanosecond() float64 {
+	return float64(r.Count) / float64(r.Per)
+}
+
+// refill adds the tokens accrued since the bucket was last updated
+func (u *usage) refill(rate Rate, now time.Time) {
+	elapsed := now.Sub(u.updated)
+	if elapsed > 0 {
+		u.tokens = math.Min(float64(rate.Burst), u.tokens+float64(elapsed)*rate.perNanosecond())
+	}
+	u.updated = now
+}
+
+// exhausted returns the RESOURCE_EXHAUSTED error of a window over a limit,
+// with the retry delay and the violated quota as details
+func exhausted(key string, value string, limit string, retryAfter time.Duration) error {
+	retryAfter = retryAfter.Round(time.Millisecond)
+	if retryAfter < time.Millisecond {
+		retryAfter = time.Millisecond
+	}
+	description := fmt.Sprintf("%s %s is over its %s", key, value, limit)
+	st, err := status.New(
+		codes.ResourceExhausted,
+		fmt.Sprintf("%s, retry in %v", description, retryAfter),
+	).WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     key + ":" + value,
+			Description: description,
+		}}},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, description)
+	}
+	return st.Err()
+}
+
+// RetryAfter returns the retry delay carried by a RESOURCE_EXHAUSTED error,
+// for clients such as the gateway to pass on as Retry-After
+func RetryAfter(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+			return retryInfo.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+type releaseKey struct{}
+
+// WithRelease returns a context carrying the release of an admitted window,
+// so that it is released by whatever finishes processing the window. A nil
+// release carries none, for windows derived from an admitted window, which
+// must not free its slots
+func WithRelease(ctx context.Context, release func()) context.Context {
+	return context.WithValue(ctx, releaseKey{}, release)
+}
+
+// Release frees the slots of the window being processed in the context, if
+// it was admitted by a limiter
+func Release(ctx context.Context) {
+	if release, ok := ctx.Value(releaseKey{}).(func()); ok && release != nil {
+		release()
+	}
+}
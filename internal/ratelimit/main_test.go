@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLimiter(limits Limits) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := NewLimiter(limits)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter, clock := newTestLimiter(Limits{Rates: map[string]Rate{
+		KeyWindowType: {Count: 1, Per: time.Second, Burst: 2},
+	}})
+	hourly := map[string]string{KeyWindowType: "Hourly", KeyOrigin: "sensor-1"}
+
+	// the burst is allowed straight away
+	for range 2 {
+		if _, err := limiter.Acquire(hourly); err != nil {
+			t.Fatalf("Acquire() error = %v, want the burst allowed", err)
+		}
+	}
+	_, err := limiter.Acquire(hourly)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Acquire() error = %v, want ResourceExhausted", err)
+	}
+	if retryAfter, ok := RetryAfter(err); !ok || retryAfter != time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want 1s", retryAfter, ok)
+	}
+
+	// other window types have their own bucket
+	if _, err := limiter.Acquire(map[string]string{KeyWindowType: "Daily"}); err != nil {
+		t.Errorf("Acquire() of another window type error = %v", err)
+	}
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	_, err = limiter.Acquire(hourly)
+	if retryAfter, _ := RetryAfter(err); retryAfter != 500*time.Millisecond {
+		t.Errorf("RetryAfter() = %v, want the time until a token is refilled", retryAfter)
+	}
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	if _, err := limiter.Acquire(hourly); err != nil {
+		t.Errorf("Acquire() after refilling error = %v", err)
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	limiter, _ := newTestLimiter(Limits{
+		Rates:       map[string]Rate{KeyOrigin: {Count: 10, Per: time.Second, Burst: 10}},
+		Concurrency: map[string]int{KeyCaller: 1},
+	})
+	window := map[string]string{KeyCaller: "api_key:ci", KeyOrigin: "ci"}
+
+	release, err := limiter.Acquire(window)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	_, err = limiter.Acquire(window)
+	if retryAfter, ok := RetryAfter(err); !ok || retryAfter != ConcurrencyRetryDelay {
+		t.Fatalf("Acquire() error = %v, want ResourceExhausted with a retry hint", err)
+	}
+
+	// a rejected window takes no tokens of the limits it was within
+	usage := limiter.Usage()
+	if len(usage) != 2 || usage[1].Key != KeyOrigin || usage[1].Tokens != 9 {
+		t.Errorf("Usage() = %+v, want one token of the origin taken", usage)
+	}
+	if usage[0].Key != KeyCaller || usage[0].Allowed != 1 || usage[0].Rejected != 1 || usage[0].InFlight != 1 {
+		t.Errorf("Usage() of the caller = %+v", usage[0])
+	}
+
+	// releasing through the context of the window frees the slot once
+	ctx := WithRelease(context.Background(), release)
+	Release(ctx)
+	Release(ctx)
+	if usage := limiter.Usage(); usage[0].InFlight != 0 {
+		t.Errorf("in flight = %d after release, want 0", usage[0].InFlight)
+	}
+	if _, err := limiter.Acquire(window); err != nil {
+		t.Errorf("Acquire() after release error = %v", err)
+	}
+}
+
+func TestDerivedRelease(t *testing.T) {
+	limiter, _ := newTestLimiter(Limits{Concurrency: map[string]int{KeyWindowType: 1}})
+	release, err := limiter.Acquire(map[string]string{KeyWindowType: "Hourly"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// windows derived from the window, e.g. rolled up from it, do not free
+	// its slot
+	ctx := WithRelease(context.Background(), release)
+	Release(WithRelease(ctx, nil))
+	if usage := limiter.Usage(); usage[0].InFlight != 1 {
+		t.Errorf("in flight = %d after releasing a derived window, want 1", usage[0].InFlight)
+	}
+	Release(ctx)
+	if usage := limiter.Usage(); usage[0].InFlight != 0 {
+		t.Errorf("in flight = %d after release, want 0", usage[0].InFlight)
+	}
+}
+
+func TestUnlimited(t *testing.T) {
+	limiter, clock := newTestLimiter(Limits{Rates: map[string]Rate{KeyCaller: {Count: 1, Per: time.Hour, Burst: 1}}})
+
+	// windows without a caller, e.g. with authentication disabled, are not
+	// limited by caller
+	for range 3 {
+		if _, err := limiter.Acquire(map[string]string{KeyWindowType: "Hourly"}); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+	if usage := limiter.Usage(); len(usage) != 0 {
+		t.Errorf("Usage() = %+v, want none for unlimited keys", usage)
+	}
+
+	// idle keys are forgotten, once their windows have been processed and
+	// their buckets have refilled
+	release, _ := limiter.Acquire(map[string]string{KeyCaller: "api_key:ci"})
+	release()
+	clock.now = clock.now.Add(2 * IdleTimeout)
+	limiter.Acquire(map[string]string{KeyCaller: "api_key:other"})
+	if usage := limiter.Usage(); len(usage) != 2 {
+		t.Errorf("Usage() = %+v, want the caller kept until its bucket refills", usage)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	limiter.Acquire(map[string]string{KeyCaller: "api_key:other"})
+	if usage := limiter.Usage(); len(usage) != 1 || usage[0].Value != "api_key:other" {
+		t.Errorf("Usage() = %+v, want the idle caller forgotten", usage)
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates(" caller:100/m, window_type:20/s:40 ,")
+	if err != nil {
+		t.Fatalf("ParseRates() error = %v", err)
+	}
+	if rates[KeyCaller] != (Rate{Count: 100, Per: time.Minute, Burst: 100}) {
+		t.Errorf("caller rate = %+v", rates[KeyCaller])
+	}
+	if rates[KeyWindowType] != (Rate{Count: 20, Per: time.Second, Burst: 40}) {
+		t.Errorf("window type rate = %+v", rates[KeyWindowType])
+	}
+
+	for _, invalid := range []string{"caller", "team:1/s", "caller:1/d", "caller:0/s", "caller:1/s:0", "caller:1/s,caller:2/s", "caller:x/s"} {
+		if _, err := ParseRates(invalid); err == nil {
+			t.Errorf("ParseRates(%q) error = nil, want error", invalid)
+		}
+	}
+}
+
+func TestParseConcurrency(t *testing.T) {
+	limits, err := ParseConcurrency("origin:5,caller:50")
+	if err != nil {
+		t.Fatalf("ParseConcurrency() error = %v", err)
+	}
+	if limits[KeyOrigin] != 5 || limits[KeyCaller] != 50 || len(limits) != 2 {
+		t.Errorf("ParseConcurrency() = %v", limits)
+	}
+
+	for _, invalid := range []string{"origin", "origin:0", "origin:-1", "team:1", "origin:1,origin:2"} {
+		if _, err := ParseConcurrency(invalid); err == nil {
+			t.Errorf("ParseConcurrency(%q) error = nil, want error", invalid)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package schedule computes the time spans of windows that Orca core emits
+// itself, for window types with a schedule or a rollup, and runs the loop
+// that emits scheduled windows.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	_ "time/tzdata" // time zones must resolve in minimal containers
+
+	pb "github.com/orca-telemetry/core/protobufs/go"
+	"github.com/robfig/cron/v3"
+)
+
+// PollInterval is how often due windows are checked for
+const PollInterval = time.Second
+
+// MaxWindowsPerPass bounds the windows emitted for a schedule in a single
+// pass, so that catching up on a long outage does not starve other schedules
+const MaxWindowsPerPass = 100
+
+// epoch that interval windows are aligned to, in wall clock time
+var epoch = time.Unix(0, 0).UTC()
+
+// Schedule is a parsed window schedule
+type Schedule struct {
+	cron      cron.Schedule
+	interval  time.Duration
+	alignment time.Duration
+	location  *time.Location
+	CatchUp   bool
+}
+
+// Window is the time span of a scheduled window
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+// Emitter emits the windows that are due on their schedules
+type Emitter interface {
+	EmitScheduledWindows(ctx context.Context, now time.Time) error
+}
+
+// Parse a schedule from either a cron expression or an interval. The
+// alignment offsets interval windows, and the time zone defaults to UTC
+func Parse(
+	cronExpr string,
+	interval time.Duration,
+	alignment time.Duration,
+	timeZone string,
+	catchUp bool,
+) (Schedule, error) {
+	if (cronExpr == "") == (interval == 0) {
+		return Schedule{}, fmt.Errorf("schedule requires exactly one of a cron expression or an interval")
+	}
+	if interval < 0 || alignment < 0 {
+		return Schedule{}, fmt.Errorf("schedule interval and alignment cannot be negative")
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule time zone %q: %w", timeZone, err)
+	}
+
+	s := Schedule{
+		interval:  interval,
+		alignment: alignment,
+		location:  location,
+		CatchUp:   catchUp,
+	}
+	if cronExpr != "" {
+		s.cron, err = cron.ParseStandard(cronExpr)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule cron expression %q: %w", cronExpr, err)
+		}
+	}
+	return s, nil
+}
+
+// FromPb parses the schedule of a window type
+func FromPb(schedule *pb.WindowSchedule) (Schedule, error) {
+	return Parse(
+		schedule.GetCron(),
+		time.Duration(schedule.GetInterval()),
+		time.Duration(schedule.GetAlignment()),
+		schedule.GetTimeZone(),
+		schedule.GetCatchUp(),
+	)
+}
+
+// Next returns the first scheduled time strictly after t, in UTC. A zero
+// time is returned when the schedule never fires again
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.cron != nil {
+		next := s.cron.Next(t.In(s.location))
+		if next.IsZero() {
+			return next
+		}
+		return next.UTC()
+	}
+
+	// intervals are counted in wall clock time, so that e.g. daily windows
+	// stay on local midnight across daylight saving changes
+	wall := s.toWallClock(t)
+	since := wall.Sub(epoch) - s.alignment
+	offset := since % s.interval
+	if offset < 0 {
+		offset += s.interval
+	}
+	for next := wall.Add(s.interval - offset); ; next = next.Add(s.interval) {
+		// wall clock times repeat when the clocks go back
+		if scheduled := s.fromWallClock(next); scheduled.After(t) {
+			return scheduled
+		}
+	}
+}
+
+// Span returns the scheduled window that contains t. Only interval
+// schedules have spans, as cron schedules cannot be walked backwards
+func (s Schedule) Span(t time.Time) (Window, error) {
+	if s.cron != nil {
+		return Window{}, fmt.Errorf("spans are not supported by cron schedules")
+	}
+	to := s.Next(t)
+	from := s.fromWallClock(s.toWallClock(to).Add(-s.interval))
+	return Window{From: from, To: to}, nil
+}
+
+// toWallClock returns the wall clock time of t in the schedule's time zone,
+// as a UTC time
+func (s Schedule) toWallClock(t time.Time) time.Time {
+	local := t.In(s.location)
+	return time.Date(
+		local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), local.Nanosecond(),
+		time.UTC,
+	)
+}
+
+// fromWallClock returns the UTC time of a wall clock time in the schedule's
+// time zone
+func (s Schedule) fromWallClock(wall time.Time) time.Time {
+	return time.Date(
+		wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(),
+		s.location,
+	).UTC()
+}
+
+// Due returns the windows that have closed by now, starting at the high
+// water mark. Without catch up only the most recent window is returned,
+// which is found without walking the windows missed since the high water
+// mark
+func (s Schedule) Due(highWaterMark time.Time, now time.Time) []Window {
+	var windows []Window
+	from := highWaterMark
+	if !s.CatchUp {
+		from = s.lastStart(highWaterMark, now)
+	}
+	for {
+		to := s.Next(from)
+		if !to.After(from) || to.After(now) {
+			return windows
+		}
+		if s.CatchUp {
+			windows = append(windows, Window{From: from, To: to})
+			if len(windows) == MaxWindowsPerPass {
+				return windows
+			}
+		} else {
+			windows = []Window{{From: from, To: to}}
+		}
+		from = to
+	}
+}
+
+// lastStart returns the start of the last window closed by now, or the high
+// water mark when the window starts before it
+func (s Schedule) lastStart(highWaterMark time.Time, now time.Time) time.Time {
+	if s.cron == nil {
+		// the window containing now is open, so the last closed window ends
+		// where it starts
+		open, _ := s.Span(now)
+		from := s.fromWallClock(s.toWallClock(open.From).Add(-s.interval))
+		if from.Before(highWaterMark) {
+			return highWaterMark
+		}
+		return from
+	}
+
+	// cron schedules cannot be walked backwards, so the search looks twice
+	// as far back each time, until it holds two scheduled times
+	for lookback := time.Minute; lookback > 0; lookback *= 2 {
+		start := now.Add(-lookback)
+		if !start.After(highWaterMark) {
+			break
+		}
+		from := s.Next(start)
+		if to := s.Next(from); !from.IsZero() && to.After(from) && !to.After(now) {
+			return from
+		}
+	}
+	return highWaterMark
+}
+
+// Run emits the windows that are due every poll interval, until the
+// context is done
+func Run(ctx context.Context, emitter Emitter, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := emitter.EmitScheduledWindows(ctx, time.Now().UTC()); err != nil {
+			slog.Error("could not emit scheduled windows", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
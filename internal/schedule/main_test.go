@@ -0,0 +1,210 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, cronExpr string, interval, alignment time.Duration, timeZone string, catchUp bool) Schedule {
+	t.Helper()
+	s, err := Parse(cronExpr, interval, alignment, timeZone, catchUp)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return s
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		cronExpr string
+		interval time.Duration
+		timeZone string
+		wantErr  bool
+	}{
+		{name: "cron", cronExpr: "0 * * * *"},
+		{name: "cron descriptor", cronExpr: "@daily", timeZone: "Europe/London"},
+		{name: "interval", interval: time.Hour},
+		{name: "neither", wantErr: true},
+		{name: "both", cronExpr: "@hourly", interval: time.Hour, wantErr: true},
+		{name: "invalid cron", cronExpr: "every hour", wantErr: true},
+		{name: "invalid time zone", interval: time.Hour, timeZone: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.cronExpr, tt.interval, 0, tt.timeZone, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from     time.Time
+		want     time.Time
+	}{
+		{
+			name:     "hourly cron",
+			schedule: mustParse(t, "0 * * * *", 0, 0, "", false),
+			from:     time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "cron on the hour is strictly after",
+			schedule: mustParse(t, "0 * * * *", 0, 0, "", false),
+			from:     time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily cron in time zone",
+			schedule: mustParse(t, "@daily", 0, 0, "America/New_York", false),
+			from:     time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 2, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "aligned interval",
+			schedule: mustParse(t, "", 15*time.Minute, 5*time.Minute, "", false),
+			from:     time.Date(2025, 3, 1, 10, 7, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 1, 10, 20, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily interval follows local midnight across daylight saving",
+			schedule: mustParse(t, "", 24*time.Hour, 0, "Europe/London", false),
+			from:     time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 30, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	hwm := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 3, 1, 13, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		catchUp bool
+		now     time.Time
+		want    []Window
+	}{
+		{
+			name:    "catch up emits every missed window",
+			catchUp: true,
+			now:     now,
+			want: []Window{
+				{From: hwm, To: hwm.Add(time.Hour)},
+				{From: hwm.Add(time.Hour), To: hwm.Add(2 * time.Hour)},
+				{From: hwm.Add(2 * time.Hour), To: hwm.Add(3 * time.Hour)},
+			},
+		},
+		{
+			name:    "without catch up only the latest window is emitted",
+			catchUp: false,
+			now:     now,
+			want: []Window{
+				{From: hwm.Add(2 * time.Hour), To: hwm.Add(3 * time.Hour)},
+			},
+		},
+		{
+			name:    "nothing due before the window closes",
+			catchUp: true,
+			now:     hwm.Add(59 * time.Minute),
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustParse(t, "", time.Hour, 0, "", tt.catchUp)
+			if got := s.Due(hwm, tt.now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("without catch up missed windows are skipped", func(t *testing.T) {
+		// a year of minute windows would take seconds to walk every poll
+		s := mustParse(t, "", time.Minute, 0, "", false)
+		now := hwm.AddDate(1, 0, 0).Add(30 * time.Second)
+		want := []Window{{From: hwm.AddDate(1, 0, 0).Add(-time.Minute), To: hwm.AddDate(1, 0, 0)}}
+		if got := s.Due(hwm, now); !reflect.DeepEqual(got, want) {
+			t.Errorf("Due() = %v, want %v", got, want)
+		}
+
+		s = mustParse(t, "*/5 * * * *", 0, 0, "", false)
+		want = []Window{{From: hwm.AddDate(1, 0, 0).Add(-5 * time.Minute), To: hwm.AddDate(1, 0, 0)}}
+		if got := s.Due(hwm, now); !reflect.DeepEqual(got, want) {
+			t.Errorf("Due() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("without catch up windows start at the high water mark", func(t *testing.T) {
+		// e.g. after the interval changed
+		s := mustParse(t, "", time.Hour, 0, "", false)
+		moved := hwm.Add(90 * time.Minute)
+		want := []Window{{From: moved, To: hwm.Add(2 * time.Hour)}}
+		if got := s.Due(moved, hwm.Add(150*time.Minute)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Due() = %v, want %v", got, want)
+		}
+
+		s = mustParse(t, "0 * * * *", 0, 0, "", false)
+		if got := s.Due(moved, hwm.Add(150*time.Minute)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Due() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("catch up is bounded per pass", func(t *testing.T) {
+		s := mustParse(t, "", time.Minute, 0, "", true)
+		got := s.Due(hwm, hwm.Add(24*time.Hour))
+		if len(got) != MaxWindowsPerPass {
+			t.Errorf("len(Due()) = %d, want %d", len(got), MaxWindowsPerPass)
+		}
+	})
+}
+
+func TestSpan(t *testing.T) {
+	hourly := mustParse(t, "", time.Hour, 0, "", false)
+	got, err := hourly.Span(time.Date(2025, 3, 1, 10, 59, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Span() error = %v", err)
+	}
+	want := Window{
+		From: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Span() = %v, want %v", got, want)
+	}
+
+	// the day the clocks go forward is 23 hours long
+	daily := mustParse(t, "", 24*time.Hour, 0, "Europe/London", false)
+	got, err = daily.Span(time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Span() error = %v", err)
+	}
+	want = Window{
+		From: time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 30, 23, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Span() = %v, want %v", got, want)
+	}
+
+	cron := mustParse(t, "@hourly", 0, 0, "", false)
+	if _, err := cron.Span(time.Now()); err == nil {
+		t.Errorf("Span() of cron schedule error = nil, want error")
+	}
+}
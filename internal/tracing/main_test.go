@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestPropagation(t *testing.T) {
+	shutdown, err := Setup(context.Background(), ExporterFile, filepath.Join(t.TempDir(), "traces.json"))
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer shutdown(context.Background())
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "EmitWindow")
+	defer span.End()
+	traceID := TraceID(ctx)
+	if len(traceID) != 32 {
+		t.Fatalf("TraceID() = %q, want a 32 character hex ID", traceID)
+	}
+
+	// outgoing metadata of core becomes the incoming metadata of a processor
+	outgoing, ok := metadata.FromOutgoingContext(Inject(ctx))
+	if !ok || len(outgoing.Get("traceparent")) == 0 {
+		t.Fatalf("Inject() metadata = %v, want a traceparent", outgoing)
+	}
+	incoming := metadata.NewIncomingContext(context.Background(), outgoing)
+	if got := TraceID(Extract(incoming)); got != traceID {
+		t.Errorf("TraceID(Extract()) = %q, want %q", got, traceID)
+	}
+
+	// detached contexts keep the trace but not the cancellation
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	detached := Detach(cancelled)
+	if detached.Err() != nil {
+		t.Errorf("Detach() context error = %v, want nil", detached.Err())
+	}
+	if got := TraceID(detached); got != traceID {
+		t.Errorf("TraceID(Detach()) = %q, want %q", got, traceID)
+	}
+
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() of untraced context = %q, want empty", got)
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), ExporterFile, path)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "BuildPlan")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read trace file: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name":"BuildPlan"`) {
+		t.Errorf("trace file does not contain the span: %s", data)
+	}
+}
+
+func TestSetupUnsupportedExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), "zipkin", ""); err == nil {
+		t.Errorf("Setup() error = nil, want error")
+	}
+}
@@ -0,0 +1,341 @@
+// Package webhook provides an HTTP listener that turns webhook requests into
+// windows, for window sources that cannot act as gRPC clients.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/protovalidate-go"
+	types "github.com/orca-telemetry/core/datalayer"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/logging"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultSignatureHeader carries the HMAC signature of a request body when a
+// route does not name its own header
+const DefaultSignatureHeader = "X-Orca-Signature"
+
+// ErrNoRoutes is returned when the webhook listener has nothing to serve
+var ErrNoRoutes = errors.New("webhook config has no routes")
+
+// maxBodyBytes bounds the size of a webhook request body
+const maxBodyBytes = 1 << 20
+
+// time formats of the time fields in a payload
+const (
+	TimeFormatRFC3339 = "rfc3339"
+	TimeFormatUnix    = "unix"
+	TimeFormatUnixMs  = "unix_ms"
+)
+
+type (
+	// Config holds the webhook routes
+	Config struct {
+		Routes []Route `json:"routes"`
+	}
+
+	// Route maps requests on a path onto windows of a window type
+	Route struct {
+		// URL path of the route, e.g. "/webhooks/billing"
+		Path              string `json:"path"`
+		WindowTypeName    string `json:"window_type_name"`
+		WindowTypeVersion string `json:"window_type_version"`
+
+		// Key of the HMAC-SHA256 signature of request bodies, given directly
+		// or as the name of an environment variable holding it
+		Secret    string `json:"secret"`
+		SecretEnv string `json:"secret_env"`
+
+		// Header carrying the hex encoded signature, optionally prefixed
+		// with "sha256=". Defaults to DefaultSignatureHeader
+		SignatureHeader string `json:"signature_header"`
+
+		// Where the window is found in the payload
+		Fields FieldMapping `json:"fields"`
+
+		// Format of the time fields: rfc3339 (default), unix or unix_ms
+		TimeFormat string `json:"time_format"`
+
+		// Origin of windows when the payload does not carry one
+		Origin string `json:"origin"`
+
+		// Namespace whose window type and algorithms the windows are of,
+		// resolved as for windows emitted without a namespace when empty
+		Namespace string `json:"namespace"`
+	}
+
+	// FieldMapping gives the dot separated paths of window fields in a
+	// payload, e.g. "data.period.start"
+	FieldMapping struct {
+		TimeFrom string `json:"time_from"`
+		TimeTo   string `json:"time_to"`
+		Origin   string `json:"origin"`
+		// metadata field names to their paths
+		Metadata map[string]string `json:"metadata"`
+	}
+
+	// Emitter validates and emits windows
+	Emitter interface {
+		EmitWindow(ctx context.Context, window *pb.Window) (*pb.WindowEmitStatus, error)
+	}
+)
+
+// LoadConfig reads the webhook routes from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read webhook config: %w", err)
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("could not parse webhook config: %w", err)
+	}
+	return config, nil
+}
+
+// secret returns the HMAC key of the route
+func (r Route) secret() string {
+	if r.SecretEnv != "" {
+		return os.Getenv(r.SecretEnv)
+	}
+	return r.Secret
+}
+
+// validate checks that a route is complete
+func (r Route) validate() error {
+	if !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("route path %q must start with '/'", r.Path)
+	}
+	if r.WindowTypeName == "" || r.WindowTypeVersion == "" {
+		return fmt.Errorf("route %v must name a window type and version", r.Path)
+	}
+	if r.secret() == "" {
+		return fmt.Errorf("route %v must have a signature secret", r.Path)
+	}
+	if r.Fields.TimeFrom == "" || r.Fields.TimeTo == "" {
+		return fmt.Errorf("route %v must map time_from and time_to", r.Path)
+	}
+	if r.Fields.Origin == "" && r.Origin == "" {
+		return fmt.Errorf("route %v must map or set an origin", r.Path)
+	}
+	switch r.TimeFormat {
+	case "", TimeFormatRFC3339, TimeFormatUnix, TimeFormatUnixMs:
+	default:
+		return fmt.Errorf("route %v has unsupported time format %q", r.Path, r.TimeFormat)
+	}
+	return nil
+}
+
+// NewHandler builds the HTTP handler serving the configured routes
+func NewHandler(config *Config, emitter Emitter) (http.Handler, error) {
+	if len(config.Routes) == 0 {
+		return nil, ErrNoRoutes
+	}
+	mux := http.NewServeMux()
+	paths := make(map[string]bool, len(config.Routes))
+	for _, route := range config.Routes {
+		if err := route.validate(); err != nil {
+			return nil, err
+		}
+		if paths[route.Path] {
+			return nil, fmt.Errorf("route %v is configured more than once", route.Path)
+		}
+		paths[route.Path] = true
+		mux.Handle("POST "+route.Path, &routeHandler{route: route, emitter: emitter})
+	}
+	return mux, nil
+}
+
+type routeHandler struct {
+	route   Route
+	emitter Emitter
+}
+
+func (h *routeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	if !h.verifySignature(r.Header, body) {
+		slog.Warn("rejected webhook with invalid signature", "path", h.route.Path)
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	window, err := h.route.windowFromPayload(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := logging.WithWindow(r.Context(), window)
+	slog.DebugContext(ctx, "received webhook window", "path", h.route.Path)
+	// the signature authenticates the caller as the route, which can emit
+	// windows of its window type in its namespace
+	scope := auth.WindowTypeScope(auth.NamespaceScope(h.route.Namespace), h.route.WindowTypeName)
+	ctx = auth.WithIdentity(ctx, auth.Identity{
+		Name:   h.route.Path,
+		Method: auth.MethodWebhook,
+		Grants: []types.Grant{{Role: auth.RoleEmitter, Scope: scope}},
+	})
+	status, err := h.emitter.EmitWindow(ctx, window)
+	if err != nil {
+		var validationErr *protovalidate.ValidationError
+		if errors.As(err, &validationErr) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if retryAfter, ok := ratelimit.RetryAfter(err); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "too many windows, retry later")
+			return
+		}
+		slog.Error("could not emit webhook window", "path", h.route.Path, "error", err)
+		writeError(w, http.StatusInternalServerError, "could not emit window")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": status.GetStatus().String()})
+}
+
+// verifySignature checks the HMAC-SHA256 signature of the body
+func (h *routeHandler) verifySignature(header http.Header, body []byte) bool {
+	headerName := h.route.SignatureHeader
+	if headerName == "" {
+		headerName = DefaultSignatureHeader
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header.Get(headerName), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(h.route.secret()))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// windowFromPayload maps a JSON payload onto a window of the route
+func (r Route) windowFromPayload(body []byte) (*pb.Window, error) {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+
+	timeFrom, err := r.timeField(payload, r.Fields.TimeFrom)
+	if err != nil {
+		return nil, err
+	}
+	timeTo, err := r.timeField(payload, r.Fields.TimeTo)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := r.Origin
+	if r.Fields.Origin != "" {
+		value, err := lookup(payload, r.Fields.Origin)
+		if err != nil {
+			return nil, err
+		}
+		originStr, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %q must be a string", r.Fields.Origin)
+		}
+		origin = originStr
+	}
+
+	metadata := make(map[string]any, len(r.Fields.Metadata))
+	for name, path := range r.Fields.Metadata {
+		value, err := lookup(payload, path)
+		if err != nil {
+			return nil, err
+		}
+		metadata[name] = value
+	}
+	metadataPb, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert metadata: %w", err)
+	}
+
+	return &pb.Window{
+		TimeFrom:          timestamppb.New(timeFrom),
+		TimeTo:            timestamppb.New(timeTo),
+		WindowTypeName:    r.WindowTypeName,
+		WindowTypeVersion: r.WindowTypeVersion,
+		Origin:            origin,
+		Metadata:          metadataPb,
+		Namespace:         r.Namespace,
+	}, nil
+}
+
+// timeField parses a time in the route's time format from the payload
+func (r Route) timeField(payload any, path string) (time.Time, error) {
+	value, err := lookup(payload, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch r.TimeFormat {
+	case TimeFormatUnix, TimeFormatUnixMs:
+		number, ok := value.(float64)
+		if !ok {
+			return time.Time{}, fmt.Errorf("field %q must be a number", path)
+		}
+		if r.TimeFormat == TimeFormatUnixMs {
+			return time.UnixMilli(int64(number)).UTC(), nil
+		}
+		seconds, fraction := math.Modf(number)
+		return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), nil
+	default:
+		str, ok := value.(string)
+		if !ok {
+			return time.Time{}, fmt.Errorf("field %q must be an RFC 3339 time", path)
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("field %q must be an RFC 3339 time: %w", path, err)
+		}
+		return parsed.UTC(), nil
+	}
+}
+
+// lookup finds the value at a dot separated path in a JSON payload
+func lookup(payload any, path string) (any, error) {
+	value := payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in payload", path)
+		}
+		value, ok = object[key]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in payload", path)
+		}
+	}
+	return value, nil
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
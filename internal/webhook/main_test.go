@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	pb "github.com/orca-telemetry/core/protobufs/go"
+)
+
+const testSecret = "shh"
+
+type mockEmitter struct {
+	windows []*pb.Window
+	err     error
+}
+
+func (m *mockEmitter) EmitWindow(ctx context.Context, window *pb.Window) (*pb.WindowEmitStatus, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.windows = append(m.windows, window)
+	return &pb.WindowEmitStatus{Status: pb.WindowEmitStatus_PROCESSING_TRIGGERED}, nil
+}
+
+func testRoute() Route {
+	return Route{
+		Path:              "/webhooks/billing",
+		WindowTypeName:    "BillingPeriod",
+		WindowTypeVersion: "1.0.0",
+		Secret:            testSecret,
+		Fields: FieldMapping{
+			TimeFrom: "period.start",
+			TimeTo:   "period.end",
+			Origin:   "source",
+			Metadata: map[string]string{"account": "account.id"},
+		},
+	}
+}
+
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewHandler(t *testing.T) {
+	noSecret := testRoute()
+	noSecret.Secret = ""
+
+	noTimes := testRoute()
+	noTimes.Fields.TimeTo = ""
+
+	noOrigin := testRoute()
+	noOrigin.Fields.Origin = ""
+
+	staticOrigin := noOrigin
+	staticOrigin.Origin = "billing"
+
+	secretEnv := testRoute()
+	secretEnv.Secret = ""
+	secretEnv.SecretEnv = "ORCA_TEST_WEBHOOK_SECRET"
+	t.Setenv("ORCA_TEST_WEBHOOK_SECRET", testSecret)
+
+	badFormat := testRoute()
+	badFormat.TimeFormat = "iso"
+
+	tests := []struct {
+		name    string
+		routes  []Route
+		wantErr bool
+	}{
+		{name: "valid", routes: []Route{testRoute()}},
+		{name: "static origin", routes: []Route{staticOrigin}},
+		{name: "secret from env", routes: []Route{secretEnv}},
+		{name: "no routes", wantErr: true},
+		{name: "no secret", routes: []Route{noSecret}, wantErr: true},
+		{name: "no time mapping", routes: []Route{noTimes}, wantErr: true},
+		{name: "no origin", routes: []Route{noOrigin}, wantErr: true},
+		{name: "unsupported time format", routes: []Route{badFormat}, wantErr: true},
+		{name: "duplicate path", routes: []Route{testRoute(), testRoute()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHandler(&Config{Routes: tt.routes}, &mockEmitter{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// overLimit returns the error of a window over the rate limit of its origin
+func overLimit(t *testing.T) error {
+	t.Helper()
+	limiter := ratelimit.NewLimiter(ratelimit.Limits{Rates: map[string]ratelimit.Rate{
+		ratelimit.KeyOrigin: {Count: 1, Per: time.Second, Burst: 1},
+	}})
+	if _, err := limiter.Acquire(map[string]string{ratelimit.KeyOrigin: "stripe"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := limiter.Acquire(map[string]string{ratelimit.KeyOrigin: "stripe"})
+	return err
+}
+
+func TestWebhook(t *testing.T) {
+	validBody := `{
+		"source": "stripe",
+		"period": {"start": "2025-03-01T00:00:00Z", "end": "2025-03-02T00:00:00Z"},
+		"account": {"id": "acct_123"}
+	}`
+
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		signature string
+		emitErr   error
+		wantCode  int
+	}{
+		{name: "valid", path: "/webhooks/billing", body: validBody, signature: sign(testSecret, validBody), wantCode: http.StatusAccepted},
+		{name: "missing signature", path: "/webhooks/billing", body: validBody, wantCode: http.StatusUnauthorized},
+		{name: "wrong secret", path: "/webhooks/billing", body: validBody, signature: sign("guess", validBody), wantCode: http.StatusUnauthorized},
+		{name: "unknown route", path: "/webhooks/other", body: validBody, signature: sign(testSecret, validBody), wantCode: http.StatusNotFound},
+		{name: "invalid json", path: "/webhooks/billing", body: "{", signature: sign(testSecret, "{"), wantCode: http.StatusBadRequest},
+		{
+			name:      "missing field",
+			path:      "/webhooks/billing",
+			body:      `{"source": "stripe"}`,
+			signature: sign(testSecret, `{"source": "stripe"}`),
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "emit failure",
+			path:      "/webhooks/billing",
+			body:      validBody,
+			signature: sign(testSecret, validBody),
+			emitErr:   errors.New("datalayer unavailable"),
+			wantCode:  http.StatusInternalServerError,
+		},
+		{
+			name:      "rate limited",
+			path:      "/webhooks/billing",
+			body:      validBody,
+			signature: sign(testSecret, validBody),
+			emitErr:   overLimit(t),
+			wantCode:  http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emitter := &mockEmitter{err: tt.emitErr}
+			handler, err := NewHandler(&Config{Routes: []Route{testRoute()}}, emitter)
+			if err != nil {
+				t.Fatalf("NewHandler() error = %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(DefaultSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+				t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+			}
+			if tt.wantCode != http.StatusAccepted {
+				if len(emitter.windows) != 0 {
+					t.Errorf("emitted %d windows, want none", len(emitter.windows))
+				}
+				return
+			}
+
+			if len(emitter.windows) != 1 {
+				t.Fatalf("emitted %d windows, want 1", len(emitter.windows))
+			}
+			window := emitter.windows[0]
+			if window.GetWindowTypeName() != "BillingPeriod" || window.GetWindowTypeVersion() != "1.0.0" {
+				t.Errorf("window type = %v@%v", window.GetWindowTypeName(), window.GetWindowTypeVersion())
+			}
+			if window.GetOrigin() != "stripe" {
+				t.Errorf("origin = %v, want stripe", window.GetOrigin())
+			}
+			if got := window.GetTimeFrom().AsTime(); !got.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("time_from = %v", got)
+			}
+			if got := window.GetTimeTo().AsTime(); !got.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("time_to = %v", got)
+			}
+			if got := window.GetMetadata().GetFields()["account"].GetStringValue(); got != "acct_123" {
+				t.Errorf("metadata account = %v, want acct_123", got)
+			}
+		})
+	}
+}
+
+func TestTimeFormats(t *testing.T) {
+	want := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		format string
+		body   string
+	}{
+		{format: "", body: `{"from": "2025-03-01T13:30:00+01:00", "to": "2025-03-01T13:30:00+01:00"}`},
+		{format: TimeFormatUnix, body: `{"from": 1740832200, "to": 1740832200}`},
+		{format: TimeFormatUnixMs, body: `{"from": 1740832200000, "to": 1740832200000}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			route := Route{
+				TimeFormat: tt.format,
+				Origin:     "static",
+				Fields:     FieldMapping{TimeFrom: "from", TimeTo: "to"},
+			}
+			window, err := route.windowFromPayload([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("windowFromPayload() error = %v", err)
+			}
+			if got := window.GetTimeFrom().AsTime(); !got.Equal(want) {
+				t.Errorf("time_from = %v, want %v", got, want)
+			}
+			if window.GetOrigin() != "static" {
+				t.Errorf("origin = %v, want static", window.GetOrigin())
+			}
+		})
+	}
+}
@@ -2,49 +2,215 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	orca "github.com/orca-telemetry/core/internal"
+	"github.com/orca-telemetry/core/internal/auth"
+	"github.com/orca-telemetry/core/internal/certs"
 	dlyr "github.com/orca-telemetry/core/internal/datalayers"
+	"github.com/orca-telemetry/core/internal/envs"
+	"github.com/orca-telemetry/core/internal/gateway"
+	"github.com/orca-telemetry/core/internal/health"
+	"github.com/orca-telemetry/core/internal/metrics"
+	"github.com/orca-telemetry/core/internal/ratelimit"
+	"github.com/orca-telemetry/core/internal/webhook"
 	pb "github.com/orca-telemetry/core/protobufs/go"
 )
 
-func startGRPCServer(
-	platform string,
-	dbConnString string,
-	port int,
-	_ string,
-) {
-	orcaServer, err := orca.NewServer(context.Background(), dlyr.Platform(platform), dbConnString)
+// startGRPCServer serves OrcaCore in the background. The returned
+// interceptor authenticates calls, and is nil when authentication is
+// disabled. The returned TLS config serves the certificates of the server,
+// and is nil when TLS is not configured
+func startGRPCServer(config *envs.Config) (*orca.OrcaCoreServer, grpc.UnaryServerInterceptor, *tls.Config, func(...*http.Server)) {
+	orcaServer, err := orca.NewServer(context.Background(), dlyr.Platform(config.Platform), config.ConnectionString)
 	if err != nil {
 		slog.Error("issue launching Orca Server", "error", err)
 		os.Exit(1)
 	}
-	go func(server *orca.OrcaCoreServer) {
-		slog.Info("starting server", "port", port)
-		lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+	// the schema must match the queries of this release
+	if err := orcaServer.CheckSchema(context.Background()); err != nil {
+		slog.Error("refusing to start", "error", err)
+		os.Exit(1)
+	}
+	limits, err := emitLimits(config)
+	if err != nil {
+		slog.Error("invalid emit limits", "error", err)
+		os.Exit(1)
+	}
+	orcaServer.SetEmitLimits(limits)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	go orcaServer.RunScheduler(workerCtx)
+
+	var opts []grpc.ServerOption
+	var httpTLSConfig *tls.Config
+	if config.TLSCertFile != "" {
+		reloader, err := certs.NewReloader(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile)
 		if err != nil {
-			slog.Error("failed to listen", "message", err)
+			slog.Error("could not load server certificates", "error", err)
+			os.Exit(1)
 		}
-		var opts []grpc.ServerOption
-		grpcServer := grpc.NewServer(opts...)
-
-		pb.RegisterOrcaCoreServer(
-			grpcServer,
-			server,
+		go reloader.Run(workerCtx, certs.ReloadInterval)
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerTLSConfig(config.TLSClientAuth))))
+		httpTLSConfig = reloader.HTTPServerTLSConfig(config.TLSClientAuth)
+	}
+	var interceptor grpc.UnaryServerInterceptor
+	if config.AuthEnabled {
+		staticKeys, err := auth.ParseStaticKeys(config.APIKeys)
+		if err != nil {
+			slog.Error("invalid API keys", "error", err)
+			os.Exit(1)
+		}
+		authenticator := auth.NewAuthenticator(staticKeys, orcaServer)
+		interceptor = authenticator.UnaryServerInterceptor()
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(interceptor),
+			grpc.ChainStreamInterceptor(authenticator.StreamServerInterceptor()),
 		)
-		reflection.Register(grpcServer)
+	}
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterOrcaCoreServer(grpcServer, orcaServer)
+
+	// readiness of core, for orchestrator probes
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	datalayerCheck := health.Check{Name: "datalayer", Fn: orcaServer.CheckDatalayer}
+	schemaCheck := health.Check{Name: "schema", Fn: orcaServer.CheckSchema}
+	workersCheck := health.Check{Name: "workers", Fn: orcaServer.CheckWorkers}
+	monitor := health.NewMonitor(healthServer)
+	monitor.SetChecks(pb.OrcaCore_ServiceDesc.ServiceName, datalayerCheck, schemaCheck)
+	monitor.SetChecks(health.Overall, datalayerCheck, schemaCheck, workersCheck)
+	go monitor.Run(workerCtx, health.CheckInterval)
+
+	reflection.Register(grpcServer)
+
+	go func() {
+		slog.Info("starting server", "port", config.Port, "tls", config.TLSCertFile != "", "auth", config.AuthEnabled)
+		lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", config.Port))
+		if err != nil {
+			slog.Error("failed to listen", "message", err)
+		}
 		err = grpcServer.Serve(lis)
 		if err != nil {
 			slog.Error("failed to serve", "error", err)
 		}
-	}(orcaServer)
+	}()
+
+	shutdown := func(httpServers ...*http.Server) {
+		slog.Info("shutting down server")
+		monitor.Shutdown()
+		stopWorkers()
+
+		// the HTTP listeners drain their requests along with the gRPC server,
+		// for at most the grace period
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+		defer cancel()
+		var wg sync.WaitGroup
+		for _, server := range httpServers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := server.Shutdown(ctx); err != nil {
+					slog.Error("could not drain HTTP listener", "addr", server.Addr, "error", err)
+				}
+			}()
+		}
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			slog.Warn("stopping server with requests still in flight after the grace period")
+			grpcServer.Stop()
+		}
+		wg.Wait()
+	}
+	return orcaServer, interceptor, httpTLSConfig, shutdown
+}
+
+// shutdownGracePeriod is how long requests in flight have to finish on
+// shutdown before they are cut off
+const shutdownGracePeriod = 30 * time.Second
+
+// emitLimits parses the rate and concurrency limits on emitting windows
+func emitLimits(config *envs.Config) (ratelimit.Limits, error) {
+	rates, err := ratelimit.ParseRates(config.RateLimits)
+	if err != nil {
+		return ratelimit.Limits{}, fmt.Errorf("invalid ORCA_RATE_LIMITS: %w", err)
+	}
+	concurrency, err := ratelimit.ParseConcurrency(config.ConcurrencyLimits)
+	if err != nil {
+		return ratelimit.Limits{}, fmt.Errorf("invalid ORCA_CONCURRENCY_LIMITS: %w", err)
+	}
+	return ratelimit.Limits{Rates: rates, Concurrency: concurrency}, nil
+}
+
+func startWebhookServer(orcaServer *orca.OrcaCoreServer, port int, configPath string) *http.Server {
+	config, err := webhook.LoadConfig(configPath)
+	if err != nil {
+		slog.Error("could not load webhook config", "error", err)
+		os.Exit(1)
+	}
+	handler, err := webhook.NewHandler(config, orcaServer)
+	if err != nil {
+		slog.Error("invalid webhook config", "error", err)
+		os.Exit(1)
+	}
+
+	return serveHTTP("webhook listener", port, handler, nil)
+}
+
+// startGatewayServer serves the gateway with the certificates of the gRPC
+// server, so that API keys are not sent in the clear when TLS is configured
+func startGatewayServer(orcaServer *orca.OrcaCoreServer, interceptor grpc.UnaryServerInterceptor, tlsConfig *tls.Config, port int) *http.Server {
+	return serveHTTP("gateway", port, gateway.NewHandler(orcaServer, interceptor), tlsConfig)
+}
+
+func startMetricsServer(port int) *http.Server {
+	return serveHTTP("metrics", port, metrics.Handler(), nil)
+}
+
+// serveHTTP serves an auxiliary HTTP listener in the background, over TLS
+// when a config is given. The server is returned to be shut down with the
+// gRPC server
+func serveHTTP(name string, port int, handler http.Handler, tlsConfig *tls.Config) *http.Server {
+	server := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", port),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	go func() {
+		slog.Info("starting "+name, "port", port, "tls", tlsConfig != nil)
+		var err error
+		if tlsConfig != nil {
+			// the certificate is presented by the config, and reloaded with it
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("failed to serve "+name, "error", err)
+		}
+	}()
+	return server
 }
 
 func main() {
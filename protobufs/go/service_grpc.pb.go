@@ -22,6 +22,7 @@ const (
 	OrcaCore_RegisterProcessor_FullMethodName = "/OrcaCore/RegisterProcessor"
 	OrcaCore_EmitWindow_FullMethodName        = "/OrcaCore/EmitWindow"
 	OrcaCore_Expose_FullMethodName            = "/OrcaCore/Expose"
+	OrcaCore_Usage_FullMethodName             = "/OrcaCore/Usage"
 )
 
 // OrcaCoreClient is the client API for OrcaCore service.
@@ -40,6 +41,8 @@ type OrcaCoreClient interface {
 	EmitWindow(ctx context.Context, in *Window, opts ...grpc.CallOption) (*WindowEmitStatus, error)
 	// Expose the internal Orca state
 	Expose(ctx context.Context, in *ExposeSettings, opts ...grpc.CallOption) (*InternalState, error)
+	// Report the use of the rate and concurrency limits on emitting windows
+	Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageReport, error)
 }
 
 type orcaCoreClient struct {
@@ -80,6 +83,16 @@ func (c *orcaCoreClient) Expose(ctx context.Context, in *ExposeSettings, opts ..
 	return out, nil
 }
 
+func (c *orcaCoreClient) Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsageReport)
+	err := c.cc.Invoke(ctx, OrcaCore_Usage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrcaCoreServer is the server API for OrcaCore service.
 // All implementations must embed UnimplementedOrcaCoreServer
 // for forward compatibility.
@@ -96,6 +109,8 @@ type OrcaCoreServer interface {
 	EmitWindow(context.Context, *Window) (*WindowEmitStatus, error)
 	// Expose the internal Orca state
 	Expose(context.Context, *ExposeSettings) (*InternalState, error)
+	// Report the use of the rate and concurrency limits on emitting windows
+	Usage(context.Context, *UsageRequest) (*UsageReport, error)
 	mustEmbedUnimplementedOrcaCoreServer()
 }
 
@@ -115,6 +130,9 @@ func (UnimplementedOrcaCoreServer) EmitWindow(context.Context, *Window) (*Window
 func (UnimplementedOrcaCoreServer) Expose(context.Context, *ExposeSettings) (*InternalState, error) {
 	return nil, status.Error(codes.Unimplemented, "method Expose not implemented")
 }
+func (UnimplementedOrcaCoreServer) Usage(context.Context, *UsageRequest) (*UsageReport, error) {
+	return nil, status.Error(codes.Unimplemented, "method Usage not implemented")
+}
 func (UnimplementedOrcaCoreServer) mustEmbedUnimplementedOrcaCoreServer() {}
 func (UnimplementedOrcaCoreServer) testEmbeddedByValue()                  {}
 
@@ -190,6 +208,24 @@ func _OrcaCore_Expose_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrcaCore_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrcaCoreServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrcaCore_Usage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrcaCoreServer).Usage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrcaCore_ServiceDesc is the grpc.ServiceDesc for OrcaCore service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -209,6 +245,10 @@ var OrcaCore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Expose",
 			Handler:    _OrcaCore_Expose_Handler,
 		},
+		{
+			MethodName: "Usage",
+			Handler:    _OrcaCore_Usage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
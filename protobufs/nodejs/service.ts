@@ -135,7 +135,14 @@ export function resultStatusToJSON(object: ResultStatus): string {
 
 /** ExposeSettings provides optional settings to the `Expose` procedure */
 export interface ExposeSettings {
-  excludeProject?: string | undefined;
+  excludeProject?:
+    | string
+    | undefined;
+  /**
+   * Only expose the processors of this namespace, or of every namespace
+   * when empty
+   */
+  namespace?: string | undefined;
 }
 
 /** Window represents a time-bounded processing context that triggers algorithm execution. Windows are the primary input that start DAG processing flows. */
@@ -173,7 +180,16 @@ export interface Window {
    * defines its own required and optional metadata fields, as defined
    * at the time of registration.
    */
-  metadata?: { [key: string]: any } | undefined;
+  metadata?:
+    | { [key: string]: any }
+    | undefined;
+  /**
+   * Namespace whose window type the window is of, and whose algorithms it
+   * triggers, along with the algorithms of other namespaces they depend on.
+   * When empty, the default namespace, or else the only namespace with the
+   * window type. Rejected when several namespaces, but not the default, have it
+   */
+  namespace?: string | undefined;
 }
 
 /** MetadataField describes the metadata that can be carried along with windows */
@@ -219,7 +235,154 @@ export interface WindowType {
     | string
     | undefined;
   /** Metadata fields that are carried along with this window type */
-  metadataFields?: MetadataField[] | undefined;
+  metadataFields?:
+    | MetadataField[]
+    | undefined;
+  /**
+   * Optional schedule on which Orca core emits windows of this type itself.
+   * Registrations that do not set a schedule leave the schedule of the
+   * window type in place, and a schedule replaces any rollup
+   */
+  schedule?:
+    | WindowSchedule
+    | undefined;
+  /**
+   * Optional rollup, through which Orca core emits windows of this type
+   * once the windows of a finer window type that they cover have completed.
+   * Registrations that do not set a rollup leave the rollup of the window
+   * type in place, and a rollup replaces any schedule
+   */
+  rollup?:
+    | WindowRollup
+    | undefined;
+  /** Removes the schedule of the window type */
+  removeSchedule?:
+    | boolean
+    | undefined;
+  /** Removes the rollup of the window type */
+  removeRollup?: boolean | undefined;
+}
+
+/**
+ * WindowSchedule describes when Orca core emits windows of a window type.
+ * Each emitted window spans from one scheduled time to the next.
+ */
+export interface WindowSchedule {
+  trigger?:
+    | //
+    /**
+     * Standard 5 field cron expression, evaluated in `time_zone`
+     * Examples: "0 * * * *", "@daily"
+     */
+    { $case: "cron"; value: string }
+    | //
+    /** Fixed length of each window (in nanoseconds) */
+    { $case: "interval"; value: string }
+    | undefined;
+  /**
+   * Offset of interval windows (in nanoseconds). Without an offset,
+   * intervals are aligned to midnight of 1 January 1970 in `time_zone`
+   */
+  alignment?:
+    | string
+    | undefined;
+  /**
+   * IANA time zone the schedule is evaluated in. Defaults to UTC
+   * Examples: "Europe/London", "America/New_York"
+   */
+  timeZone?:
+    | string
+    | undefined;
+  /** The origin of emitted windows */
+  origin?:
+    | string
+    | undefined;
+  /**
+   * Whether windows missed while Orca core was unavailable are emitted
+   * when it recovers. Otherwise only the most recent missed window is
+   */
+  catchUp?: boolean | undefined;
+}
+
+/**
+ * WindowRollup describes how windows of a window type (the parent) are
+ * emitted from the completion of windows of a finer window type (the child).
+ * Child windows are grouped into parent windows by interval, origin and
+ * metadata, and the parent window carries the origin and metadata of its
+ * children. A child window completes once all of its algorithms have run.
+ */
+export interface WindowRollup {
+  /** Name of the child window type */
+  childWindowTypeName?:
+    | string
+    | undefined;
+  /** Version of the child window type */
+  childWindowTypeVersion?:
+    | string
+    | undefined;
+  /** Length of each parent window (in nanoseconds) */
+  interval?:
+    | string
+    | undefined;
+  /**
+   * Offset of parent windows (in nanoseconds). Without an offset, parent
+   * windows are aligned to midnight of 1 January 1970 in `time_zone`
+   */
+  alignment?:
+    | string
+    | undefined;
+  /** IANA time zone parent windows are aligned in. Defaults to UTC */
+  timeZone?:
+    | string
+    | undefined;
+  /**
+   * Number of completed child windows that complete a parent window
+   * Example: 60 for hourly windows rolled up from minute windows
+   */
+  childCount?:
+    | number
+    | undefined;
+  /** How child windows that complete after their parent are handled */
+  lateWindows?: WindowRollup_LateWindowPolicy | undefined;
+}
+
+/**
+ * LateWindowPolicy determines what happens when a child window completes
+ * after its parent window has been emitted
+ */
+export enum WindowRollup_LateWindowPolicy {
+  /** LATE_WINDOW_POLICY_IGNORE - The late child window is recorded, but the parent is not emitted again */
+  LATE_WINDOW_POLICY_IGNORE = 0,
+  /** LATE_WINDOW_POLICY_REEMIT - The parent window is emitted again, so its algorithms are rerun */
+  LATE_WINDOW_POLICY_REEMIT = 1,
+  UNRECOGNIZED = -1,
+}
+
+export function windowRollup_LateWindowPolicyFromJSON(object: any): WindowRollup_LateWindowPolicy {
+  switch (object) {
+    case 0:
+    case "LATE_WINDOW_POLICY_IGNORE":
+      return WindowRollup_LateWindowPolicy.LATE_WINDOW_POLICY_IGNORE;
+    case 1:
+    case "LATE_WINDOW_POLICY_REEMIT":
+      return WindowRollup_LateWindowPolicy.LATE_WINDOW_POLICY_REEMIT;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return WindowRollup_LateWindowPolicy.UNRECOGNIZED;
+  }
+}
+
+export function windowRollup_LateWindowPolicyToJSON(object: WindowRollup_LateWindowPolicy): string {
+  switch (object) {
+    case WindowRollup_LateWindowPolicy.LATE_WINDOW_POLICY_IGNORE:
+      return "LATE_WINDOW_POLICY_IGNORE";
+    case WindowRollup_LateWindowPolicy.LATE_WINDOW_POLICY_REEMIT:
+      return "LATE_WINDOW_POLICY_REEMIT";
+    case WindowRollup_LateWindowPolicy.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
 }
 
 /** WindowEmitStatus status message returned after emitting a window */
@@ -297,6 +460,14 @@ export interface AlgorithmDependency {
   processorRuntime?:
     | string
     | undefined;
+  /**
+   * Namespace of the processor that the algorithm is associated with. Empty
+   * for the namespace of the dependant algorithm. Algorithms of other
+   * namespaces can only be depended on by naming their namespace
+   */
+  processorNamespace?:
+    | string
+    | undefined;
   /**
    * A lookback field that specifies whether this dependency
    * depends on past results of this algorithm
@@ -309,6 +480,196 @@ export interface AlgorithmDependency {
     /** Timeframe of past results to depend on (in nanoseconds) */
     { $case: "lookbackTimeDelta"; value: string }
     | undefined;
+  /** How past results of the lookback are partitioned */
+  lookbackPartition?:
+    | AlgorithmDependency_LookbackPartition
+    | undefined;
+  /**
+   * The metadata field to partition the lookback on. Required when
+   * partitioning by metadata field, and must be one of the metadata
+   * fields of the dependant algorithm's window type
+   */
+  lookbackPartitionField?:
+    | string
+    | undefined;
+  /**
+   * Summarise the lookback into aggregates computed by Orca core, rather
+   * than sending every past result to the processor
+   */
+  lookbackAggregate?: LookbackAggregate | undefined;
+}
+
+/**
+ * LookbackPartition restricts the past results returned in a lookback
+ * to those produced for windows similar to the window being processed
+ */
+export enum AlgorithmDependency_LookbackPartition {
+  /** LOOKBACK_PARTITION_NONE - Past results from all windows are included */
+  LOOKBACK_PARTITION_NONE = 0,
+  /** LOOKBACK_PARTITION_ORIGIN - Only past results from windows with the same origin are included */
+  LOOKBACK_PARTITION_ORIGIN = 1,
+  /**
+   * LOOKBACK_PARTITION_METADATA_FIELD - Only past results from windows with the same value of the
+   * metadata field `lookback_partition_field` are included
+   */
+  LOOKBACK_PARTITION_METADATA_FIELD = 2,
+  UNRECOGNIZED = -1,
+}
+
+export function algorithmDependency_LookbackPartitionFromJSON(object: any): AlgorithmDependency_LookbackPartition {
+  switch (object) {
+    case 0:
+    case "LOOKBACK_PARTITION_NONE":
+      return AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_NONE;
+    case 1:
+    case "LOOKBACK_PARTITION_ORIGIN":
+      return AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_ORIGIN;
+    case 2:
+    case "LOOKBACK_PARTITION_METADATA_FIELD":
+      return AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_METADATA_FIELD;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return AlgorithmDependency_LookbackPartition.UNRECOGNIZED;
+  }
+}
+
+export function algorithmDependency_LookbackPartitionToJSON(object: AlgorithmDependency_LookbackPartition): string {
+  switch (object) {
+    case AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_NONE:
+      return "LOOKBACK_PARTITION_NONE";
+    case AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_ORIGIN:
+      return "LOOKBACK_PARTITION_ORIGIN";
+    case AlgorithmDependency_LookbackPartition.LOOKBACK_PARTITION_METADATA_FIELD:
+      return "LOOKBACK_PARTITION_METADATA_FIELD";
+    case AlgorithmDependency_LookbackPartition.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
+/**
+ * LookbackAggregate describes how the past results of a dependency are
+ * summarised before being sent to the processor. Aggregates other than
+ * count can only be taken over algorithms with a VALUE result type.
+ */
+export interface LookbackAggregate {
+  /** The aggregate function to apply */
+  function?:
+    | LookbackAggregate_Function
+    | undefined;
+  /**
+   * The percentile to compute, as a fraction between 0 and 1
+   * Only used by FUNCTION_PERCENTILE
+   */
+  percentile?:
+    | number
+    | undefined;
+  /**
+   * Width of the time buckets to aggregate over (in nanoseconds).
+   * Buckets are aligned to the unix epoch and assigned by window end
+   * time. When unset, the whole lookback is aggregated as one bucket.
+   */
+  bucketTimeDelta?: string | undefined;
+}
+
+/** The aggregate function applied to the past results */
+export enum LookbackAggregate_Function {
+  /** FUNCTION_UNSPECIFIED - Placeholder sentinel to make explicit that nothing was provided */
+  FUNCTION_UNSPECIFIED = 0,
+  /** FUNCTION_COUNT - Number of past results */
+  FUNCTION_COUNT = 1,
+  /** FUNCTION_SUM - Sum of past results */
+  FUNCTION_SUM = 2,
+  /** FUNCTION_MEAN - Arithmetic mean of past results */
+  FUNCTION_MEAN = 3,
+  /** FUNCTION_MIN - Smallest past result */
+  FUNCTION_MIN = 4,
+  /** FUNCTION_MAX - Largest past result */
+  FUNCTION_MAX = 5,
+  /** FUNCTION_LAST - The most recent past result */
+  FUNCTION_LAST = 6,
+  /** FUNCTION_PERCENTILE - A continuous percentile of past results, given by `percentile` */
+  FUNCTION_PERCENTILE = 7,
+  UNRECOGNIZED = -1,
+}
+
+export function lookbackAggregate_FunctionFromJSON(object: any): LookbackAggregate_Function {
+  switch (object) {
+    case 0:
+    case "FUNCTION_UNSPECIFIED":
+      return LookbackAggregate_Function.FUNCTION_UNSPECIFIED;
+    case 1:
+    case "FUNCTION_COUNT":
+      return LookbackAggregate_Function.FUNCTION_COUNT;
+    case 2:
+    case "FUNCTION_SUM":
+      return LookbackAggregate_Function.FUNCTION_SUM;
+    case 3:
+    case "FUNCTION_MEAN":
+      return LookbackAggregate_Function.FUNCTION_MEAN;
+    case 4:
+    case "FUNCTION_MIN":
+      return LookbackAggregate_Function.FUNCTION_MIN;
+    case 5:
+    case "FUNCTION_MAX":
+      return LookbackAggregate_Function.FUNCTION_MAX;
+    case 6:
+    case "FUNCTION_LAST":
+      return LookbackAggregate_Function.FUNCTION_LAST;
+    case 7:
+    case "FUNCTION_PERCENTILE":
+      return LookbackAggregate_Function.FUNCTION_PERCENTILE;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return LookbackAggregate_Function.UNRECOGNIZED;
+  }
+}
+
+export function lookbackAggregate_FunctionToJSON(object: LookbackAggregate_Function): string {
+  switch (object) {
+    case LookbackAggregate_Function.FUNCTION_UNSPECIFIED:
+      return "FUNCTION_UNSPECIFIED";
+    case LookbackAggregate_Function.FUNCTION_COUNT:
+      return "FUNCTION_COUNT";
+    case LookbackAggregate_Function.FUNCTION_SUM:
+      return "FUNCTION_SUM";
+    case LookbackAggregate_Function.FUNCTION_MEAN:
+      return "FUNCTION_MEAN";
+    case LookbackAggregate_Function.FUNCTION_MIN:
+      return "FUNCTION_MIN";
+    case LookbackAggregate_Function.FUNCTION_MAX:
+      return "FUNCTION_MAX";
+    case LookbackAggregate_Function.FUNCTION_LAST:
+      return "FUNCTION_LAST";
+    case LookbackAggregate_Function.FUNCTION_PERCENTILE:
+      return "FUNCTION_PERCENTILE";
+    case LookbackAggregate_Function.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
+/**
+ * LookbackAggregateBucket is the aggregate of the past results of a
+ * dependency over a span of windows
+ */
+export interface LookbackAggregateBucket {
+  /** Start of the earliest window in the bucket */
+  timeFrom?:
+    | Date
+    | undefined;
+  /** End of the latest window in the bucket */
+  timeTo?:
+    | Date
+    | undefined;
+  /** Number of past results in the bucket */
+  count?:
+    | string
+    | undefined;
+  /** The aggregated value */
+  value?: number | undefined;
 }
 
 /**
@@ -418,9 +779,31 @@ export interface ProcessorRegistration {
     | undefined;
   /**
    * A name that can be attached to a group of processors. Describes the project in which
-   * they are defined (typically a single git repository)
+   * they are defined (typically a single git repository). The project is the namespace of
+   * the processor and its algorithms, so processors of different projects can share names.
+   * Processors without a project are registered in the `default` namespace
+   */
+  projectName?:
+    | string
+    | undefined;
+  /** Overrides of the TLS settings core connects to the processor with */
+  tls?: ProcessorTLS | undefined;
+}
+
+/** TLS settings of the connection from core to a processor */
+export interface ProcessorTLS {
+  /**
+   * Name the certificate of the processor is verified against, when it differs
+   * from the host of the connection string
+   */
+  serverName?:
+    | string
+    | undefined;
+  /**
+   * Skip verifying the certificate of the processor. For development only,
+   * registrations setting it are refused in production
    */
-  projectName?: string | undefined;
+  insecureSkipVerify?: boolean | undefined;
 }
 
 /** The result of a dependency */
@@ -440,7 +823,15 @@ export interface AlgorithmDependencyResult {
     | Algorithm
     | undefined;
   /** The result */
-  result?: AlgorithmDependencyResultRow[] | undefined;
+  result?:
+    | AlgorithmDependencyResultRow[]
+    | undefined;
+  /**
+   * The aggregated lookback, ordered by time, when the dependency
+   * requests a lookback aggregate. Past results are then not included
+   * in `result`
+   */
+  lookbackAggregate?: LookbackAggregateBucket[] | undefined;
 }
 
 /** The algorithm execution packet */
@@ -604,8 +995,51 @@ export interface InternalState {
   processors?: ProcessorRegistration[] | undefined;
 }
 
+/** UsageRequest optionally narrows down the usage reported by `Usage` */
+export interface UsageRequest {
+  /**
+   * Only report the usage of this key (origin, caller or window_type), or of
+   * every key when empty
+   */
+  key?: string | undefined;
+}
+
+/** UsageReport is the use of the limits on emitting windows, by key value */
+export interface UsageReport {
+  usage?: KeyUsage[] | undefined;
+}
+
+/**
+ * KeyUsage is the use of the limits by one value of a key, e.g. the window
+ * type Hourly. Values idle for a while are no longer reported
+ */
+export interface KeyUsage {
+  /** The key windows are limited by: origin, caller or window_type */
+  key?:
+    | string
+    | undefined;
+  /** The value of the key, e.g. the name of the window type */
+  value?:
+    | string
+    | undefined;
+  /** Windows admitted */
+  allowed?:
+    | string
+    | undefined;
+  /** Windows rejected for being over a limit */
+  rejected?:
+    | string
+    | undefined;
+  /** Windows admitted that are still being processed */
+  inFlight?:
+    | number
+    | undefined;
+  /** Windows that can be emitted before the rate limit of the key is reached */
+  tokens?: number | undefined;
+}
+
 function createBaseExposeSettings(): ExposeSettings {
-  return { excludeProject: "" };
+  return { excludeProject: "", namespace: "" };
 }
 
 export const ExposeSettings: MessageFns<ExposeSettings> = {
@@ -613,6 +1047,9 @@ export const ExposeSettings: MessageFns<ExposeSettings> = {
     if (message.excludeProject !== undefined && message.excludeProject !== "") {
       writer.uint32(10).string(message.excludeProject);
     }
+    if (message.namespace !== undefined && message.namespace !== "") {
+      writer.uint32(18).string(message.namespace);
+    }
     return writer;
   },
 
@@ -631,6 +1068,14 @@ export const ExposeSettings: MessageFns<ExposeSettings> = {
           message.excludeProject = reader.string();
           continue;
         }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.namespace = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -641,7 +1086,10 @@ export const ExposeSettings: MessageFns<ExposeSettings> = {
   },
 
   fromJSON(object: any): ExposeSettings {
-    return { excludeProject: isSet(object.excludeProject) ? globalThis.String(object.excludeProject) : "" };
+    return {
+      excludeProject: isSet(object.excludeProject) ? globalThis.String(object.excludeProject) : "",
+      namespace: isSet(object.namespace) ? globalThis.String(object.namespace) : "",
+    };
   },
 
   toJSON(message: ExposeSettings): unknown {
@@ -649,6 +1097,9 @@ export const ExposeSettings: MessageFns<ExposeSettings> = {
     if (message.excludeProject !== undefined && message.excludeProject !== "") {
       obj.excludeProject = message.excludeProject;
     }
+    if (message.namespace !== undefined && message.namespace !== "") {
+      obj.namespace = message.namespace;
+    }
     return obj;
   },
 
@@ -658,6 +1109,7 @@ export const ExposeSettings: MessageFns<ExposeSettings> = {
   fromPartial<I extends Exact<DeepPartial<ExposeSettings>, I>>(object: I): ExposeSettings {
     const message = createBaseExposeSettings();
     message.excludeProject = object.excludeProject ?? "";
+    message.namespace = object.namespace ?? "";
     return message;
   },
 };
@@ -670,6 +1122,7 @@ function createBaseWindow(): Window {
     windowTypeVersion: "",
     origin: "",
     metadata: undefined,
+    namespace: "",
   };
 }
 
@@ -693,6 +1146,9 @@ export const Window: MessageFns<Window> = {
     if (message.metadata !== undefined) {
       Struct.encode(Struct.wrap(message.metadata), writer.uint32(50).fork()).join();
     }
+    if (message.namespace !== undefined && message.namespace !== "") {
+      writer.uint32(58).string(message.namespace);
+    }
     return writer;
   },
 
@@ -751,6 +1207,14 @@ export const Window: MessageFns<Window> = {
           message.metadata = Struct.unwrap(Struct.decode(reader, reader.uint32()));
           continue;
         }
+        case 7: {
+          if (tag !== 58) {
+            break;
+          }
+
+          message.namespace = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -768,6 +1232,7 @@ export const Window: MessageFns<Window> = {
       windowTypeVersion: isSet(object.windowTypeVersion) ? globalThis.String(object.windowTypeVersion) : "",
       origin: isSet(object.origin) ? globalThis.String(object.origin) : "",
       metadata: isObject(object.metadata) ? object.metadata : undefined,
+      namespace: isSet(object.namespace) ? globalThis.String(object.namespace) : "",
     };
   },
 
@@ -791,6 +1256,9 @@ export const Window: MessageFns<Window> = {
     if (message.metadata !== undefined) {
       obj.metadata = message.metadata;
     }
+    if (message.namespace !== undefined && message.namespace !== "") {
+      obj.namespace = message.namespace;
+    }
     return obj;
   },
 
@@ -805,6 +1273,7 @@ export const Window: MessageFns<Window> = {
     message.windowTypeVersion = object.windowTypeVersion ?? "";
     message.origin = object.origin ?? "";
     message.metadata = object.metadata ?? undefined;
+    message.namespace = object.namespace ?? "";
     return message;
   },
 };
@@ -886,7 +1355,16 @@ export const MetadataField: MessageFns<MetadataField> = {
 };
 
 function createBaseWindowType(): WindowType {
-  return { name: "", version: "", description: "", metadataFields: [] };
+  return {
+    name: "",
+    version: "",
+    description: "",
+    metadataFields: [],
+    schedule: undefined,
+    rollup: undefined,
+    removeSchedule: false,
+    removeRollup: false,
+  };
 }
 
 export const WindowType: MessageFns<WindowType> = {
@@ -905,6 +1383,18 @@ export const WindowType: MessageFns<WindowType> = {
         MetadataField.encode(v!, writer.uint32(34).fork()).join();
       }
     }
+    if (message.schedule !== undefined) {
+      WindowSchedule.encode(message.schedule, writer.uint32(42).fork()).join();
+    }
+    if (message.rollup !== undefined) {
+      WindowRollup.encode(message.rollup, writer.uint32(50).fork()).join();
+    }
+    if (message.removeSchedule !== undefined && message.removeSchedule !== false) {
+      writer.uint32(56).bool(message.removeSchedule);
+    }
+    if (message.removeRollup !== undefined && message.removeRollup !== false) {
+      writer.uint32(64).bool(message.removeRollup);
+    }
     return writer;
   },
 
@@ -950,6 +1440,38 @@ export const WindowType: MessageFns<WindowType> = {
           }
           continue;
         }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.schedule = WindowSchedule.decode(reader, reader.uint32());
+          continue;
+        }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.rollup = WindowRollup.decode(reader, reader.uint32());
+          continue;
+        }
+        case 7: {
+          if (tag !== 56) {
+            break;
+          }
+
+          message.removeSchedule = reader.bool();
+          continue;
+        }
+        case 8: {
+          if (tag !== 64) {
+            break;
+          }
+
+          message.removeRollup = reader.bool();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -967,6 +1489,10 @@ export const WindowType: MessageFns<WindowType> = {
       metadataFields: globalThis.Array.isArray(object?.metadataFields)
         ? object.metadataFields.map((e: any) => MetadataField.fromJSON(e))
         : [],
+      schedule: isSet(object.schedule) ? WindowSchedule.fromJSON(object.schedule) : undefined,
+      rollup: isSet(object.rollup) ? WindowRollup.fromJSON(object.rollup) : undefined,
+      removeSchedule: isSet(object.removeSchedule) ? globalThis.Boolean(object.removeSchedule) : false,
+      removeRollup: isSet(object.removeRollup) ? globalThis.Boolean(object.removeRollup) : false,
     };
   },
 
@@ -984,6 +1510,18 @@ export const WindowType: MessageFns<WindowType> = {
     if (message.metadataFields?.length) {
       obj.metadataFields = message.metadataFields.map((e) => MetadataField.toJSON(e));
     }
+    if (message.schedule !== undefined) {
+      obj.schedule = WindowSchedule.toJSON(message.schedule);
+    }
+    if (message.rollup !== undefined) {
+      obj.rollup = WindowRollup.toJSON(message.rollup);
+    }
+    if (message.removeSchedule !== undefined && message.removeSchedule !== false) {
+      obj.removeSchedule = message.removeSchedule;
+    }
+    if (message.removeRollup !== undefined && message.removeRollup !== false) {
+      obj.removeRollup = message.removeRollup;
+    }
     return obj;
   },
 
@@ -996,24 +1534,354 @@ export const WindowType: MessageFns<WindowType> = {
     message.version = object.version ?? "";
     message.description = object.description ?? "";
     message.metadataFields = object.metadataFields?.map((e) => MetadataField.fromPartial(e)) || [];
+    message.schedule = (object.schedule !== undefined && object.schedule !== null)
+      ? WindowSchedule.fromPartial(object.schedule)
+      : undefined;
+    message.rollup = (object.rollup !== undefined && object.rollup !== null)
+      ? WindowRollup.fromPartial(object.rollup)
+      : undefined;
+    message.removeSchedule = object.removeSchedule ?? false;
+    message.removeRollup = object.removeRollup ?? false;
     return message;
   },
 };
 
-function createBaseWindowEmitStatus(): WindowEmitStatus {
-  return { status: 0 };
+function createBaseWindowSchedule(): WindowSchedule {
+  return { trigger: undefined, alignment: "0", timeZone: "", origin: "", catchUp: false };
 }
 
-export const WindowEmitStatus: MessageFns<WindowEmitStatus> = {
-  encode(message: WindowEmitStatus, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
-    if (message.status !== undefined && message.status !== 0) {
-      writer.uint32(8).int32(message.status);
+export const WindowSchedule: MessageFns<WindowSchedule> = {
+  encode(message: WindowSchedule, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    switch (message.trigger?.$case) {
+      case "cron":
+        writer.uint32(10).string(message.trigger.value);
+        break;
+      case "interval":
+        writer.uint32(16).uint64(message.trigger.value);
+        break;
     }
-    return writer;
-  },
-
-  decode(input: BinaryReader | Uint8Array, length?: number): WindowEmitStatus {
-    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    if (message.alignment !== undefined && message.alignment !== "0") {
+      writer.uint32(24).uint64(message.alignment);
+    }
+    if (message.timeZone !== undefined && message.timeZone !== "") {
+      writer.uint32(34).string(message.timeZone);
+    }
+    if (message.origin !== undefined && message.origin !== "") {
+      writer.uint32(42).string(message.origin);
+    }
+    if (message.catchUp !== undefined && message.catchUp !== false) {
+      writer.uint32(48).bool(message.catchUp);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): WindowSchedule {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseWindowSchedule();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.trigger = { $case: "cron", value: reader.string() };
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.trigger = { $case: "interval", value: reader.uint64().toString() };
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.alignment = reader.uint64().toString();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.timeZone = reader.string();
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.origin = reader.string();
+          continue;
+        }
+        case 6: {
+          if (tag !== 48) {
+            break;
+          }
+
+          message.catchUp = reader.bool();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): WindowSchedule {
+    return {
+      trigger: isSet(object.cron)
+        ? { $case: "cron", value: globalThis.String(object.cron) }
+        : isSet(object.interval)
+        ? { $case: "interval", value: globalThis.String(object.interval) }
+        : undefined,
+      alignment: isSet(object.alignment) ? globalThis.String(object.alignment) : "0",
+      timeZone: isSet(object.timeZone) ? globalThis.String(object.timeZone) : "",
+      origin: isSet(object.origin) ? globalThis.String(object.origin) : "",
+      catchUp: isSet(object.catchUp) ? globalThis.Boolean(object.catchUp) : false,
+    };
+  },
+
+  toJSON(message: WindowSchedule): unknown {
+    const obj: any = {};
+    if (message.trigger?.$case === "cron") {
+      obj.cron = message.trigger.value;
+    } else if (message.trigger?.$case === "interval") {
+      obj.interval = message.trigger.value;
+    }
+    if (message.alignment !== undefined && message.alignment !== "0") {
+      obj.alignment = message.alignment;
+    }
+    if (message.timeZone !== undefined && message.timeZone !== "") {
+      obj.timeZone = message.timeZone;
+    }
+    if (message.origin !== undefined && message.origin !== "") {
+      obj.origin = message.origin;
+    }
+    if (message.catchUp !== undefined && message.catchUp !== false) {
+      obj.catchUp = message.catchUp;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<WindowSchedule>, I>>(base?: I): WindowSchedule {
+    return WindowSchedule.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<WindowSchedule>, I>>(object: I): WindowSchedule {
+    const message = createBaseWindowSchedule();
+    switch (object.trigger?.$case) {
+      case "cron": {
+        if (object.trigger?.value !== undefined && object.trigger?.value !== null) {
+          message.trigger = { $case: "cron", value: object.trigger.value };
+        }
+        break;
+      }
+      case "interval": {
+        if (object.trigger?.value !== undefined && object.trigger?.value !== null) {
+          message.trigger = { $case: "interval", value: object.trigger.value };
+        }
+        break;
+      }
+    }
+    message.alignment = object.alignment ?? "0";
+    message.timeZone = object.timeZone ?? "";
+    message.origin = object.origin ?? "";
+    message.catchUp = object.catchUp ?? false;
+    return message;
+  },
+};
+
+function createBaseWindowRollup(): WindowRollup {
+  return {
+    childWindowTypeName: "",
+    childWindowTypeVersion: "",
+    interval: "0",
+    alignment: "0",
+    timeZone: "",
+    childCount: 0,
+    lateWindows: 0,
+  };
+}
+
+export const WindowRollup: MessageFns<WindowRollup> = {
+  encode(message: WindowRollup, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.childWindowTypeName !== undefined && message.childWindowTypeName !== "") {
+      writer.uint32(10).string(message.childWindowTypeName);
+    }
+    if (message.childWindowTypeVersion !== undefined && message.childWindowTypeVersion !== "") {
+      writer.uint32(18).string(message.childWindowTypeVersion);
+    }
+    if (message.interval !== undefined && message.interval !== "0") {
+      writer.uint32(24).uint64(message.interval);
+    }
+    if (message.alignment !== undefined && message.alignment !== "0") {
+      writer.uint32(32).uint64(message.alignment);
+    }
+    if (message.timeZone !== undefined && message.timeZone !== "") {
+      writer.uint32(42).string(message.timeZone);
+    }
+    if (message.childCount !== undefined && message.childCount !== 0) {
+      writer.uint32(48).uint32(message.childCount);
+    }
+    if (message.lateWindows !== undefined && message.lateWindows !== 0) {
+      writer.uint32(56).int32(message.lateWindows);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): WindowRollup {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseWindowRollup();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.childWindowTypeName = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.childWindowTypeVersion = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.interval = reader.uint64().toString();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.alignment = reader.uint64().toString();
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.timeZone = reader.string();
+          continue;
+        }
+        case 6: {
+          if (tag !== 48) {
+            break;
+          }
+
+          message.childCount = reader.uint32();
+          continue;
+        }
+        case 7: {
+          if (tag !== 56) {
+            break;
+          }
+
+          message.lateWindows = reader.int32() as any;
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): WindowRollup {
+    return {
+      childWindowTypeName: isSet(object.childWindowTypeName) ? globalThis.String(object.childWindowTypeName) : "",
+      childWindowTypeVersion: isSet(object.childWindowTypeVersion)
+        ? globalThis.String(object.childWindowTypeVersion)
+        : "",
+      interval: isSet(object.interval) ? globalThis.String(object.interval) : "0",
+      alignment: isSet(object.alignment) ? globalThis.String(object.alignment) : "0",
+      timeZone: isSet(object.timeZone) ? globalThis.String(object.timeZone) : "",
+      childCount: isSet(object.childCount) ? globalThis.Number(object.childCount) : 0,
+      lateWindows: isSet(object.lateWindows) ? windowRollup_LateWindowPolicyFromJSON(object.lateWindows) : 0,
+    };
+  },
+
+  toJSON(message: WindowRollup): unknown {
+    const obj: any = {};
+    if (message.childWindowTypeName !== undefined && message.childWindowTypeName !== "") {
+      obj.childWindowTypeName = message.childWindowTypeName;
+    }
+    if (message.childWindowTypeVersion !== undefined && message.childWindowTypeVersion !== "") {
+      obj.childWindowTypeVersion = message.childWindowTypeVersion;
+    }
+    if (message.interval !== undefined && message.interval !== "0") {
+      obj.interval = message.interval;
+    }
+    if (message.alignment !== undefined && message.alignment !== "0") {
+      obj.alignment = message.alignment;
+    }
+    if (message.timeZone !== undefined && message.timeZone !== "") {
+      obj.timeZone = message.timeZone;
+    }
+    if (message.childCount !== undefined && message.childCount !== 0) {
+      obj.childCount = Math.round(message.childCount);
+    }
+    if (message.lateWindows !== undefined && message.lateWindows !== 0) {
+      obj.lateWindows = windowRollup_LateWindowPolicyToJSON(message.lateWindows);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<WindowRollup>, I>>(base?: I): WindowRollup {
+    return WindowRollup.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<WindowRollup>, I>>(object: I): WindowRollup {
+    const message = createBaseWindowRollup();
+    message.childWindowTypeName = object.childWindowTypeName ?? "";
+    message.childWindowTypeVersion = object.childWindowTypeVersion ?? "";
+    message.interval = object.interval ?? "0";
+    message.alignment = object.alignment ?? "0";
+    message.timeZone = object.timeZone ?? "";
+    message.childCount = object.childCount ?? 0;
+    message.lateWindows = object.lateWindows ?? 0;
+    return message;
+  },
+};
+
+function createBaseWindowEmitStatus(): WindowEmitStatus {
+  return { status: 0 };
+}
+
+export const WindowEmitStatus: MessageFns<WindowEmitStatus> = {
+  encode(message: WindowEmitStatus, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.status !== undefined && message.status !== 0) {
+      writer.uint32(8).int32(message.status);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): WindowEmitStatus {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
     const message = createBaseWindowEmitStatus();
     while (reader.pos < end) {
@@ -1059,7 +1927,17 @@ export const WindowEmitStatus: MessageFns<WindowEmitStatus> = {
 };
 
 function createBaseAlgorithmDependency(): AlgorithmDependency {
-  return { name: "", version: "", processorName: "", processorRuntime: "", lookback: undefined };
+  return {
+    name: "",
+    version: "",
+    processorName: "",
+    processorRuntime: "",
+    processorNamespace: "",
+    lookback: undefined,
+    lookbackPartition: 0,
+    lookbackPartitionField: "",
+    lookbackAggregate: undefined,
+  };
 }
 
 export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
@@ -1076,6 +1954,9 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
     if (message.processorRuntime !== undefined && message.processorRuntime !== "") {
       writer.uint32(34).string(message.processorRuntime);
     }
+    if (message.processorNamespace !== undefined && message.processorNamespace !== "") {
+      writer.uint32(82).string(message.processorNamespace);
+    }
     switch (message.lookback?.$case) {
       case "lookbackNum":
         writer.uint32(40).uint32(message.lookback.value);
@@ -1084,6 +1965,15 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
         writer.uint32(48).uint64(message.lookback.value);
         break;
     }
+    if (message.lookbackPartition !== undefined && message.lookbackPartition !== 0) {
+      writer.uint32(56).int32(message.lookbackPartition);
+    }
+    if (message.lookbackPartitionField !== undefined && message.lookbackPartitionField !== "") {
+      writer.uint32(66).string(message.lookbackPartitionField);
+    }
+    if (message.lookbackAggregate !== undefined) {
+      LookbackAggregate.encode(message.lookbackAggregate, writer.uint32(74).fork()).join();
+    }
     return writer;
   },
 
@@ -1126,6 +2016,14 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
           message.processorRuntime = reader.string();
           continue;
         }
+        case 10: {
+          if (tag !== 82) {
+            break;
+          }
+
+          message.processorNamespace = reader.string();
+          continue;
+        }
         case 5: {
           if (tag !== 40) {
             break;
@@ -1142,6 +2040,30 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
           message.lookback = { $case: "lookbackTimeDelta", value: reader.uint64().toString() };
           continue;
         }
+        case 7: {
+          if (tag !== 56) {
+            break;
+          }
+
+          message.lookbackPartition = reader.int32() as any;
+          continue;
+        }
+        case 8: {
+          if (tag !== 66) {
+            break;
+          }
+
+          message.lookbackPartitionField = reader.string();
+          continue;
+        }
+        case 9: {
+          if (tag !== 74) {
+            break;
+          }
+
+          message.lookbackAggregate = LookbackAggregate.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1157,11 +2079,21 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
       version: isSet(object.version) ? globalThis.String(object.version) : "",
       processorName: isSet(object.processorName) ? globalThis.String(object.processorName) : "",
       processorRuntime: isSet(object.processorRuntime) ? globalThis.String(object.processorRuntime) : "",
+      processorNamespace: isSet(object.processorNamespace) ? globalThis.String(object.processorNamespace) : "",
       lookback: isSet(object.lookbackNum)
         ? { $case: "lookbackNum", value: globalThis.Number(object.lookbackNum) }
         : isSet(object.lookbackTimeDelta)
         ? { $case: "lookbackTimeDelta", value: globalThis.String(object.lookbackTimeDelta) }
         : undefined,
+      lookbackPartition: isSet(object.lookbackPartition)
+        ? algorithmDependency_LookbackPartitionFromJSON(object.lookbackPartition)
+        : 0,
+      lookbackPartitionField: isSet(object.lookbackPartitionField)
+        ? globalThis.String(object.lookbackPartitionField)
+        : "",
+      lookbackAggregate: isSet(object.lookbackAggregate)
+        ? LookbackAggregate.fromJSON(object.lookbackAggregate)
+        : undefined,
     };
   },
 
@@ -1179,11 +2111,23 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
     if (message.processorRuntime !== undefined && message.processorRuntime !== "") {
       obj.processorRuntime = message.processorRuntime;
     }
+    if (message.processorNamespace !== undefined && message.processorNamespace !== "") {
+      obj.processorNamespace = message.processorNamespace;
+    }
     if (message.lookback?.$case === "lookbackNum") {
       obj.lookbackNum = Math.round(message.lookback.value);
     } else if (message.lookback?.$case === "lookbackTimeDelta") {
       obj.lookbackTimeDelta = message.lookback.value;
     }
+    if (message.lookbackPartition !== undefined && message.lookbackPartition !== 0) {
+      obj.lookbackPartition = algorithmDependency_LookbackPartitionToJSON(message.lookbackPartition);
+    }
+    if (message.lookbackPartitionField !== undefined && message.lookbackPartitionField !== "") {
+      obj.lookbackPartitionField = message.lookbackPartitionField;
+    }
+    if (message.lookbackAggregate !== undefined) {
+      obj.lookbackAggregate = LookbackAggregate.toJSON(message.lookbackAggregate);
+    }
     return obj;
   },
 
@@ -1196,6 +2140,7 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
     message.version = object.version ?? "";
     message.processorName = object.processorName ?? "";
     message.processorRuntime = object.processorRuntime ?? "";
+    message.processorNamespace = object.processorNamespace ?? "";
     switch (object.lookback?.$case) {
       case "lookbackNum": {
         if (object.lookback?.value !== undefined && object.lookback?.value !== null) {
@@ -1210,6 +2155,211 @@ export const AlgorithmDependency: MessageFns<AlgorithmDependency> = {
         break;
       }
     }
+    message.lookbackPartition = object.lookbackPartition ?? 0;
+    message.lookbackPartitionField = object.lookbackPartitionField ?? "";
+    message.lookbackAggregate = (object.lookbackAggregate !== undefined && object.lookbackAggregate !== null)
+      ? LookbackAggregate.fromPartial(object.lookbackAggregate)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseLookbackAggregate(): LookbackAggregate {
+  return { function: 0, percentile: 0, bucketTimeDelta: "0" };
+}
+
+export const LookbackAggregate: MessageFns<LookbackAggregate> = {
+  encode(message: LookbackAggregate, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.function !== undefined && message.function !== 0) {
+      writer.uint32(8).int32(message.function);
+    }
+    if (message.percentile !== undefined && message.percentile !== 0) {
+      writer.uint32(17).double(message.percentile);
+    }
+    if (message.bucketTimeDelta !== undefined && message.bucketTimeDelta !== "0") {
+      writer.uint32(24).uint64(message.bucketTimeDelta);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): LookbackAggregate {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseLookbackAggregate();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.function = reader.int32() as any;
+          continue;
+        }
+        case 2: {
+          if (tag !== 17) {
+            break;
+          }
+
+          message.percentile = reader.double();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.bucketTimeDelta = reader.uint64().toString();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): LookbackAggregate {
+    return {
+      function: isSet(object.function) ? lookbackAggregate_FunctionFromJSON(object.function) : 0,
+      percentile: isSet(object.percentile) ? globalThis.Number(object.percentile) : 0,
+      bucketTimeDelta: isSet(object.bucketTimeDelta) ? globalThis.String(object.bucketTimeDelta) : "0",
+    };
+  },
+
+  toJSON(message: LookbackAggregate): unknown {
+    const obj: any = {};
+    if (message.function !== undefined && message.function !== 0) {
+      obj.function = lookbackAggregate_FunctionToJSON(message.function);
+    }
+    if (message.percentile !== undefined && message.percentile !== 0) {
+      obj.percentile = message.percentile;
+    }
+    if (message.bucketTimeDelta !== undefined && message.bucketTimeDelta !== "0") {
+      obj.bucketTimeDelta = message.bucketTimeDelta;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<LookbackAggregate>, I>>(base?: I): LookbackAggregate {
+    return LookbackAggregate.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<LookbackAggregate>, I>>(object: I): LookbackAggregate {
+    const message = createBaseLookbackAggregate();
+    message.function = object.function ?? 0;
+    message.percentile = object.percentile ?? 0;
+    message.bucketTimeDelta = object.bucketTimeDelta ?? "0";
+    return message;
+  },
+};
+
+function createBaseLookbackAggregateBucket(): LookbackAggregateBucket {
+  return { timeFrom: undefined, timeTo: undefined, count: "0", value: 0 };
+}
+
+export const LookbackAggregateBucket: MessageFns<LookbackAggregateBucket> = {
+  encode(message: LookbackAggregateBucket, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.timeFrom !== undefined) {
+      Timestamp.encode(toTimestamp(message.timeFrom), writer.uint32(10).fork()).join();
+    }
+    if (message.timeTo !== undefined) {
+      Timestamp.encode(toTimestamp(message.timeTo), writer.uint32(18).fork()).join();
+    }
+    if (message.count !== undefined && message.count !== "0") {
+      writer.uint32(24).uint64(message.count);
+    }
+    if (message.value !== undefined && message.value !== 0) {
+      writer.uint32(33).double(message.value);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): LookbackAggregateBucket {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseLookbackAggregateBucket();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.timeFrom = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.timeTo = fromTimestamp(Timestamp.decode(reader, reader.uint32()));
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.count = reader.uint64().toString();
+          continue;
+        }
+        case 4: {
+          if (tag !== 33) {
+            break;
+          }
+
+          message.value = reader.double();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): LookbackAggregateBucket {
+    return {
+      timeFrom: isSet(object.timeFrom) ? fromJsonTimestamp(object.timeFrom) : undefined,
+      timeTo: isSet(object.timeTo) ? fromJsonTimestamp(object.timeTo) : undefined,
+      count: isSet(object.count) ? globalThis.String(object.count) : "0",
+      value: isSet(object.value) ? globalThis.Number(object.value) : 0,
+    };
+  },
+
+  toJSON(message: LookbackAggregateBucket): unknown {
+    const obj: any = {};
+    if (message.timeFrom !== undefined) {
+      obj.timeFrom = message.timeFrom.toISOString();
+    }
+    if (message.timeTo !== undefined) {
+      obj.timeTo = message.timeTo.toISOString();
+    }
+    if (message.count !== undefined && message.count !== "0") {
+      obj.count = message.count;
+    }
+    if (message.value !== undefined && message.value !== 0) {
+      obj.value = message.value;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<LookbackAggregateBucket>, I>>(base?: I): LookbackAggregateBucket {
+    return LookbackAggregateBucket.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<LookbackAggregateBucket>, I>>(object: I): LookbackAggregateBucket {
+    const message = createBaseLookbackAggregateBucket();
+    message.timeFrom = object.timeFrom ?? undefined;
+    message.timeTo = object.timeTo ?? undefined;
+    message.count = object.count ?? "0";
+    message.value = object.value ?? 0;
     return message;
   },
 };
@@ -1583,7 +2733,7 @@ export const Result: MessageFns<Result> = {
 };
 
 function createBaseProcessorRegistration(): ProcessorRegistration {
-  return { name: "", runtime: "", connectionStr: "", supportedAlgorithms: [], projectName: "" };
+  return { name: "", runtime: "", connectionStr: "", supportedAlgorithms: [], projectName: "", tls: undefined };
 }
 
 export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
@@ -1605,6 +2755,9 @@ export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
     if (message.projectName !== undefined && message.projectName !== "") {
       writer.uint32(42).string(message.projectName);
     }
+    if (message.tls !== undefined) {
+      ProcessorTLS.encode(message.tls, writer.uint32(50).fork()).join();
+    }
     return writer;
   },
 
@@ -1658,6 +2811,14 @@ export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
           message.projectName = reader.string();
           continue;
         }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.tls = ProcessorTLS.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1676,6 +2837,7 @@ export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
         ? object.supportedAlgorithms.map((e: any) => Algorithm.fromJSON(e))
         : [],
       projectName: isSet(object.projectName) ? globalThis.String(object.projectName) : "",
+      tls: isSet(object.tls) ? ProcessorTLS.fromJSON(object.tls) : undefined,
     };
   },
 
@@ -1696,6 +2858,9 @@ export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
     if (message.projectName !== undefined && message.projectName !== "") {
       obj.projectName = message.projectName;
     }
+    if (message.tls !== undefined) {
+      obj.tls = ProcessorTLS.toJSON(message.tls);
+    }
     return obj;
   },
 
@@ -1709,6 +2874,85 @@ export const ProcessorRegistration: MessageFns<ProcessorRegistration> = {
     message.connectionStr = object.connectionStr ?? "";
     message.supportedAlgorithms = object.supportedAlgorithms?.map((e) => Algorithm.fromPartial(e)) || [];
     message.projectName = object.projectName ?? "";
+    message.tls = (object.tls !== undefined && object.tls !== null)
+      ? ProcessorTLS.fromPartial(object.tls)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseProcessorTLS(): ProcessorTLS {
+  return { serverName: "", insecureSkipVerify: false };
+}
+
+export const ProcessorTLS: MessageFns<ProcessorTLS> = {
+  encode(message: ProcessorTLS, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.serverName !== undefined && message.serverName !== "") {
+      writer.uint32(10).string(message.serverName);
+    }
+    if (message.insecureSkipVerify !== undefined && message.insecureSkipVerify !== false) {
+      writer.uint32(16).bool(message.insecureSkipVerify);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): ProcessorTLS {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseProcessorTLS();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.serverName = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.insecureSkipVerify = reader.bool();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ProcessorTLS {
+    return {
+      serverName: isSet(object.serverName) ? globalThis.String(object.serverName) : "",
+      insecureSkipVerify: isSet(object.insecureSkipVerify) ? globalThis.Boolean(object.insecureSkipVerify) : false,
+    };
+  },
+
+  toJSON(message: ProcessorTLS): unknown {
+    const obj: any = {};
+    if (message.serverName !== undefined && message.serverName !== "") {
+      obj.serverName = message.serverName;
+    }
+    if (message.insecureSkipVerify !== undefined && message.insecureSkipVerify !== false) {
+      obj.insecureSkipVerify = message.insecureSkipVerify;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<ProcessorTLS>, I>>(base?: I): ProcessorTLS {
+    return ProcessorTLS.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ProcessorTLS>, I>>(object: I): ProcessorTLS {
+    const message = createBaseProcessorTLS();
+    message.serverName = object.serverName ?? "";
+    message.insecureSkipVerify = object.insecureSkipVerify ?? false;
     return message;
   },
 };
@@ -1794,7 +3038,7 @@ export const AlgorithmDependencyResultRow: MessageFns<AlgorithmDependencyResultR
 };
 
 function createBaseAlgorithmDependencyResult(): AlgorithmDependencyResult {
-  return { algorithm: undefined, result: [] };
+  return { algorithm: undefined, result: [], lookbackAggregate: [] };
 }
 
 export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> = {
@@ -1807,6 +3051,11 @@ export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> =
         AlgorithmDependencyResultRow.encode(v!, writer.uint32(18).fork()).join();
       }
     }
+    if (message.lookbackAggregate !== undefined && message.lookbackAggregate.length !== 0) {
+      for (const v of message.lookbackAggregate) {
+        LookbackAggregateBucket.encode(v!, writer.uint32(26).fork()).join();
+      }
+    }
     return writer;
   },
 
@@ -1836,6 +3085,17 @@ export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> =
           }
           continue;
         }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          const el = LookbackAggregateBucket.decode(reader, reader.uint32());
+          if (el !== undefined) {
+            message.lookbackAggregate!.push(el);
+          }
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1851,6 +3111,9 @@ export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> =
       result: globalThis.Array.isArray(object?.result)
         ? object.result.map((e: any) => AlgorithmDependencyResultRow.fromJSON(e))
         : [],
+      lookbackAggregate: globalThis.Array.isArray(object?.lookbackAggregate)
+        ? object.lookbackAggregate.map((e: any) => LookbackAggregateBucket.fromJSON(e))
+        : [],
     };
   },
 
@@ -1862,6 +3125,9 @@ export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> =
     if (message.result?.length) {
       obj.result = message.result.map((e) => AlgorithmDependencyResultRow.toJSON(e));
     }
+    if (message.lookbackAggregate?.length) {
+      obj.lookbackAggregate = message.lookbackAggregate.map((e) => LookbackAggregateBucket.toJSON(e));
+    }
     return obj;
   },
 
@@ -1874,6 +3140,7 @@ export const AlgorithmDependencyResult: MessageFns<AlgorithmDependencyResult> =
       ? Algorithm.fromPartial(object.algorithm)
       : undefined;
     message.result = object.result?.map((e) => AlgorithmDependencyResultRow.fromPartial(e)) || [];
+    message.lookbackAggregate = object.lookbackAggregate?.map((e) => LookbackAggregateBucket.fromPartial(e)) || [];
     return message;
   },
 };
@@ -2689,6 +3956,267 @@ export const InternalState: MessageFns<InternalState> = {
   },
 };
 
+function createBaseUsageRequest(): UsageRequest {
+  return { key: "" };
+}
+
+export const UsageRequest: MessageFns<UsageRequest> = {
+  encode(message: UsageRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.key !== undefined && message.key !== "") {
+      writer.uint32(10).string(message.key);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): UsageRequest {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseUsageRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.key = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): UsageRequest {
+    return { key: isSet(object.key) ? globalThis.String(object.key) : "" };
+  },
+
+  toJSON(message: UsageRequest): unknown {
+    const obj: any = {};
+    if (message.key !== undefined && message.key !== "") {
+      obj.key = message.key;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<UsageRequest>, I>>(base?: I): UsageRequest {
+    return UsageRequest.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<UsageRequest>, I>>(object: I): UsageRequest {
+    const message = createBaseUsageRequest();
+    message.key = object.key ?? "";
+    return message;
+  },
+};
+
+function createBaseUsageReport(): UsageReport {
+  return { usage: [] };
+}
+
+export const UsageReport: MessageFns<UsageReport> = {
+  encode(message: UsageReport, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.usage !== undefined && message.usage.length !== 0) {
+      for (const v of message.usage) {
+        KeyUsage.encode(v!, writer.uint32(10).fork()).join();
+      }
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): UsageReport {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseUsageReport();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          const el = KeyUsage.decode(reader, reader.uint32());
+          if (el !== undefined) {
+            message.usage!.push(el);
+          }
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): UsageReport {
+    return { usage: globalThis.Array.isArray(object?.usage) ? object.usage.map((e: any) => KeyUsage.fromJSON(e)) : [] };
+  },
+
+  toJSON(message: UsageReport): unknown {
+    const obj: any = {};
+    if (message.usage?.length) {
+      obj.usage = message.usage.map((e) => KeyUsage.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<UsageReport>, I>>(base?: I): UsageReport {
+    return UsageReport.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<UsageReport>, I>>(object: I): UsageReport {
+    const message = createBaseUsageReport();
+    message.usage = object.usage?.map((e) => KeyUsage.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseKeyUsage(): KeyUsage {
+  return { key: "", value: "", allowed: "0", rejected: "0", inFlight: 0, tokens: 0 };
+}
+
+export const KeyUsage: MessageFns<KeyUsage> = {
+  encode(message: KeyUsage, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.key !== undefined && message.key !== "") {
+      writer.uint32(10).string(message.key);
+    }
+    if (message.value !== undefined && message.value !== "") {
+      writer.uint32(18).string(message.value);
+    }
+    if (message.allowed !== undefined && message.allowed !== "0") {
+      writer.uint32(24).uint64(message.allowed);
+    }
+    if (message.rejected !== undefined && message.rejected !== "0") {
+      writer.uint32(32).uint64(message.rejected);
+    }
+    if (message.inFlight !== undefined && message.inFlight !== 0) {
+      writer.uint32(40).uint32(message.inFlight);
+    }
+    if (message.tokens !== undefined && message.tokens !== 0) {
+      writer.uint32(49).double(message.tokens);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): KeyUsage {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseKeyUsage();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.key = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.value = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.allowed = reader.uint64().toString();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.rejected = reader.uint64().toString();
+          continue;
+        }
+        case 5: {
+          if (tag !== 40) {
+            break;
+          }
+
+          message.inFlight = reader.uint32();
+          continue;
+        }
+        case 6: {
+          if (tag !== 49) {
+            break;
+          }
+
+          message.tokens = reader.double();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): KeyUsage {
+    return {
+      key: isSet(object.key) ? globalThis.String(object.key) : "",
+      value: isSet(object.value) ? globalThis.String(object.value) : "",
+      allowed: isSet(object.allowed) ? globalThis.String(object.allowed) : "0",
+      rejected: isSet(object.rejected) ? globalThis.String(object.rejected) : "0",
+      inFlight: isSet(object.inFlight) ? globalThis.Number(object.inFlight) : 0,
+      tokens: isSet(object.tokens) ? globalThis.Number(object.tokens) : 0,
+    };
+  },
+
+  toJSON(message: KeyUsage): unknown {
+    const obj: any = {};
+    if (message.key !== undefined && message.key !== "") {
+      obj.key = message.key;
+    }
+    if (message.value !== undefined && message.value !== "") {
+      obj.value = message.value;
+    }
+    if (message.allowed !== undefined && message.allowed !== "0") {
+      obj.allowed = message.allowed;
+    }
+    if (message.rejected !== undefined && message.rejected !== "0") {
+      obj.rejected = message.rejected;
+    }
+    if (message.inFlight !== undefined && message.inFlight !== 0) {
+      obj.inFlight = Math.round(message.inFlight);
+    }
+    if (message.tokens !== undefined && message.tokens !== 0) {
+      obj.tokens = message.tokens;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<KeyUsage>, I>>(base?: I): KeyUsage {
+    return KeyUsage.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<KeyUsage>, I>>(object: I): KeyUsage {
+    const message = createBaseKeyUsage();
+    message.key = object.key ?? "";
+    message.value = object.value ?? "";
+    message.allowed = object.allowed ?? "0";
+    message.rejected = object.rejected ?? "0";
+    message.inFlight = object.inFlight ?? 0;
+    message.tokens = object.tokens ?? 0;
+    return message;
+  },
+};
+
 /**
  * OrcaCore is the central orchestration service that:
  * - Manages the lifecycle of processing windows
@@ -2729,6 +4257,16 @@ export const OrcaCoreService = {
     responseSerialize: (value: InternalState): Buffer => Buffer.from(InternalState.encode(value).finish()),
     responseDeserialize: (value: Buffer): InternalState => InternalState.decode(value),
   },
+  /** Report the use of the rate and concurrency limits on emitting windows */
+  usage: {
+    path: "/OrcaCore/Usage",
+    requestStream: false,
+    responseStream: false,
+    requestSerialize: (value: UsageRequest): Buffer => Buffer.from(UsageRequest.encode(value).finish()),
+    requestDeserialize: (value: Buffer): UsageRequest => UsageRequest.decode(value),
+    responseSerialize: (value: UsageReport): Buffer => Buffer.from(UsageReport.encode(value).finish()),
+    responseDeserialize: (value: Buffer): UsageReport => UsageReport.decode(value),
+  },
 } as const;
 
 export interface OrcaCoreServer extends UntypedServiceImplementation {
@@ -2738,6 +4276,8 @@ export interface OrcaCoreServer extends UntypedServiceImplementation {
   emitWindow: handleUnaryCall<Window, WindowEmitStatus>;
   /** Expose the internal Orca state */
   expose: handleUnaryCall<ExposeSettings, InternalState>;
+  /** Report the use of the rate and concurrency limits on emitting windows */
+  usage: handleUnaryCall<UsageRequest, UsageReport>;
 }
 
 export interface OrcaCoreClient extends Client {
@@ -2789,6 +4329,19 @@ export interface OrcaCoreClient extends Client {
     options: Partial<CallOptions>,
     callback: (error: ServiceError | null, response: InternalState) => void,
   ): ClientUnaryCall;
+  /** Report the use of the rate and concurrency limits on emitting windows */
+  usage(request: UsageRequest, callback: (error: ServiceError | null, response: UsageReport) => void): ClientUnaryCall;
+  usage(
+    request: UsageRequest,
+    metadata: Metadata,
+    callback: (error: ServiceError | null, response: UsageReport) => void,
+  ): ClientUnaryCall;
+  usage(
+    request: UsageRequest,
+    metadata: Metadata,
+    options: Partial<CallOptions>,
+    callback: (error: ServiceError | null, response: UsageReport) => void,
+  ): ClientUnaryCall;
 }
 
 export const OrcaCoreClient = makeGenericClientConstructor(OrcaCoreService, "OrcaCore") as unknown as {
@@ -27,14 +27,14 @@ from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__
 from vendor import validate_pb2 as vendor_dot_validate__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\rservice.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x15vendor/validate.proto\")\n\x0e\x45xposeSettings\x12\x17\n\x0f\x65xclude_project\x18\x01 \x01(\t\"\xf8\x02\n\x06Window\x12:\n\ttime_from\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampB\x0b\xbaH\x08\xb2\x01\x02*\x00\xc8\x01\x01\x12\x38\n\x07time_to\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampB\x0b\xbaH\x08\xb2\x01\x02*\x00\xc8\x01\x01\x12$\n\x10window_type_name\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12\'\n\x13window_type_version\x18\x04 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12\x1a\n\x06origin\x18\x05 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12)\n\x08metadata\x18\x06 \x01(\x0b\x32\x17.google.protobuf.Struct:b\xbaH_\x1a]\n\x14window.time_ordering\x12&time_to must be greater than time_from\x1a\x1dthis.time_to > this.time_from\"B\n\rMetadataField\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1b\n\x0b\x64\x65scription\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\"\x80\x01\n\nWindowType\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1b\n\x0b\x64\x65scription\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12&\n\x0emetadataFields\x18\x04 \x03(\x0b\x32\x0e.MetadataField\"\xa4\x01\n\x10WindowEmitStatus\x12\x34\n\x06status\x18\x01 \x01(\x0e\x32\x1c.WindowEmitStatus.StatusEnumB\x06\xbaH\x03\xc8\x01\x01\"Z\n\nStatusEnum\x12\x15\n\x11TRIGGERING_FAILED\x10\x00\x12\x1b\n\x17NO_TRIGGERED_ALGORITHMS\x10\x01\x12\x18\n\x14PROCESSING_TRIGGERED\x10\x02\"\xd1\x01\n\x13\x41lgorithmDependency\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1e\n\x0eprocessor_name\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12!\n\x11processor_runtime\x18\x04 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x16\n\x0clookback_num\x18\x05 \x01(\rH\x00\x12\x1d\n\x13lookback_time_delta\x18\x06 \x01(\x04H\x00\x42\x11\n\x08lookback\x12\x05\xbaH\x02\x08\x00\"\xdc\x01\n\tAlgorithm\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12(\n\x0bwindow_type\x18\x03 \x01(\x0b\x32\x0b.WindowTypeB\x06\xbaH\x03\xc8\x01\x01\x12*\n\x0c\x64\x65pendencies\x18\x04 \x03(\x0b\x32\x14.AlgorithmDependency\x12(\n\x0bresult_type\x18\x05 \x01(\x0e\x32\x0b.ResultTypeB\x06\xbaH\x03\xc8\x01\x01\x12 \n\x0b\x64\x65scription\x18\x06 \x01(\tB\x0b\xbaH\x08r\x03\x18\xe8\x07\xc8\x01\x01\"\x1c\n\nFloatArray\x12\x0e\n\x06values\x18\x01 \x03(\x02\"\xc7\x01\n\x06Result\x12%\n\x06status\x18\x01 \x01(\x0e\x32\r.ResultStatusB\x06\xbaH\x03\xc8\x01\x01\x12\x16\n\x0csingle_value\x18\x02 \x01(\x02H\x00\x12#\n\x0c\x66loat_values\x18\x03 \x01(\x0b\x32\x0b.FloatArrayH\x00\x12/\n\x0cstruct_value\x18\x04 \x01(\x0b\x32\x17.google.protobuf.StructH\x00\x12\x19\n\ttimestamp\x18\x05 \x01(\x03\x42\x06\xbaH\x03\xc8\x01\x01\x42\r\n\x0bresult_data\"\xae\x01\n\x15ProcessorRegistration\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07runtime\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1e\n\x0e\x63onnection_str\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x30\n\x14supported_algorithms\x18\x04 \x03(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x14\n\x0cproject_name\x18\x05 \x01(\t\"`\n\x1c\x41lgorithmDependencyResultRow\x12\x1f\n\x06result\x18\x01 \x01(\x0b\x32\x07.ResultB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x02 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\"y\n\x19\x41lgorithmDependencyResult\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x35\n\x06result\x18\x02 \x03(\x0b\x32\x1d.AlgorithmDependencyResultRowB\x06\xbaH\x03\xc8\x01\x01\"k\n\x10\x45xecuteAlgorithm\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x30\n\x0c\x64\x65pendencies\x18\x02 \x03(\x0b\x32\x1a.AlgorithmDependencyResult\"\xda\x01\n\x10\x45xecutionRequest\x12\x17\n\x07\x65xec_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x02 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\x12/\n\x11\x61lgorithm_results\x18\x03 \x03(\x0b\x32\x10.AlgorithmResultB\x02\x18\x01\x12\"\n\nalgorithms\x18\x04 \x03(\x0b\x32\n.AlgorithmB\x02\x18\x01\x12\x37\n\x14\x61lgorithm_executions\x18\x05 \x03(\x0b\x32\x11.ExecuteAlgorithmB\x06\xbaH\x03\xc8\x01\x01\"^\n\x0f\x45xecutionResult\x12\x17\n\x07\x65xec_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x32\n\x10\x61lgorithm_result\x18\x03 \x01(\x0b\x32\x10.AlgorithmResultB\x06\xbaH\x03\xc8\x01\x01\"z\n\x0f\x41lgorithmResult\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06result\x18\x02 \x01(\x0b\x32\x07.ResultB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x03 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\"+\n\x06Status\x12\x10\n\x08received\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\"/\n\x12HealthCheckRequest\x12\x19\n\ttimestamp\x18\x01 \x01(\x03\x42\x06\xbaH\x03\xc8\x01\x01\"\xe3\x01\n\x13HealthCheckResponse\x12\x33\n\x06status\x18\x01 \x01(\x0e\x32\x1b.HealthCheckResponse.StatusB\x06\xbaH\x03\xc8\x01\x01\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\"\n\x07metrics\x18\x03 \x01(\x0b\x32\x11.ProcessorMetrics\"b\n\x06Status\x12\x12\n\x0eSTATUS_UNKNOWN\x10\x00\x12\x12\n\x0eSTATUS_SERVING\x10\x01\x12\x18\n\x14STATUS_TRANSITIONING\x10\x02\x12\x16\n\x12STATUS_NOT_SERVING\x10\x03\"k\n\x10ProcessorMetrics\x12\x14\n\x0c\x61\x63tive_tasks\x18\x01 \x01(\x05\x12\x14\n\x0cmemory_bytes\x18\x02 \x01(\x03\x12\x13\n\x0b\x63pu_percent\x18\x03 \x01(\x02\x12\x16\n\x0euptime_seconds\x18\x04 \x01(\x03\";\n\rInternalState\x12*\n\nprocessors\x18\x01 \x03(\x0b\x32\x16.ProcessorRegistration*K\n\nResultType\x12\x11\n\rNOT_SPECIFIED\x10\x00\x12\n\n\x06STRUCT\x10\x01\x12\t\n\x05VALUE\x10\x02\x12\t\n\x05\x41RRAY\x10\x03\x12\x08\n\x04NONE\x10\x04*p\n\x0cResultStatus\x12 \n\x1cRESULT_STATUS_HANDLED_FAILED\x10\x00\x12\"\n\x1eRESULT_STATUS_UNHANDLED_FAILED\x10\x01\x12\x1a\n\x16RESULT_STATUS_SUCEEDED\x10\x02\x32\x95\x01\n\x08OrcaCore\x12\x34\n\x11RegisterProcessor\x12\x16.ProcessorRegistration\x1a\x07.Status\x12(\n\nEmitWindow\x12\x07.Window\x1a\x11.WindowEmitStatus\x12)\n\x06\x45xpose\x12\x0f.ExposeSettings\x1a\x0e.InternalState2\x82\x01\n\rOrcaProcessor\x12\x37\n\x0e\x45xecuteDagPart\x12\x11.ExecutionRequest\x1a\x10.ExecutionResult0\x01\x12\x38\n\x0bHealthCheck\x12\x13.HealthCheckRequest\x1a\x14.HealthCheckResponseB,Z*github.com/orc-analytics/core/protobufs/gob\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\rservice.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x15vendor/validate.proto\"<\n\x0e\x45xposeSettings\x12\x17\n\x0f\x65xclude_project\x18\x01 \x01(\t\x12\x11\n\tnamespace\x18\x02 \x01(\t\"\x8b\x03\n\x06Window\x12:\n\ttime_from\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampB\x0b\xbaH\x08\xb2\x01\x02*\x00\xc8\x01\x01\x12\x38\n\x07time_to\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampB\x0b\xbaH\x08\xb2\x01\x02*\x00\xc8\x01\x01\x12$\n\x10window_type_name\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12\'\n\x13window_type_version\x18\x04 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12\x1a\n\x06origin\x18\x05 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01\x12)\n\x08metadata\x18\x06 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x11\n\tnamespace\x18\x07 \x01(\t:b\xbaH_\x1a]\n\x14window.time_ordering\x12&time_to must be greater than time_from\x1a\x1dthis.time_to > this.time_from\"B\n\rMetadataField\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1b\n\x0b\x64\x65scription\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\"\x81\x06\n\nWindowType\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1b\n\x0b\x64\x65scription\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12&\n\x0emetadataFields\x18\x04 \x03(\x0b\x32\x0e.MetadataField\x12!\n\x08schedule\x18\x05 \x01(\x0b\x32\x0f.WindowSchedule\x12\x1d\n\x06rollup\x18\x06 \x01(\x0b\x32\r.WindowRollup\x12\x17\n\x0fremove_schedule\x18\x07 \x01(\x08\x12\x15\n\rremove_rollup\x18\x08 \x01(\x08:\x8c\x04\xbaH\x88\x04\x1a\x8b\x01\n\x1dwindow_type.schedule_metadata\x12\x33scheduled window types cannot carry metadata fields\x1a\x35!has(this.schedule) || size(this.metadataFields) == 0\x1a~\n\x1bwindow_type.schedule_rollup\x12\x35window types cannot have both a schedule and a rollup\x1a(!has(this.schedule) || !has(this.rollup)\x1a\x7f\n\x1bwindow_type.remove_schedule\x12\x32window types cannot both set and remove a schedule\x1a,!has(this.schedule) || !this.remove_schedule\x1aw\n\x19window_type.remove_rollup\x12\x30window types cannot both set and remove a rollup\x1a(!has(this.rollup) || !this.remove_rollup\"\x9f\x01\n\x0eWindowSchedule\x12\x0e\n\x04\x63ron\x18\x01 \x01(\tH\x00\x12\x1b\n\x08interval\x18\x02 \x01(\x04\x42\x07\xbaH\x04\x32\x02 \x00H\x00\x12\x11\n\talignment\x18\x03 \x01(\x04\x12\x11\n\ttime_zone\x18\x04 \x01(\t\x12\x16\n\x06origin\x18\x05 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x10\n\x08\x63\x61tch_up\x18\x06 \x01(\x08\x42\x10\n\x07trigger\x12\x05\xbaH\x02\x08\x01\"\xc8\x02\n\x0cWindowRollup\x12&\n\x16\x63hild_window_type_name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12)\n\x19\x63hild_window_type_version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x19\n\x08interval\x18\x03 \x01(\x04\x42\x07\xbaH\x04\x32\x02 \x00\x12\x11\n\talignment\x18\x04 \x01(\x04\x12\x11\n\ttime_zone\x18\x05 \x01(\t\x12\x1c\n\x0b\x63hild_count\x18\x06 \x01(\rB\x07\xbaH\x04*\x02 \x00\x12\x34\n\x0clate_windows\x18\x07 \x01(\x0e\x32\x1e.WindowRollup.LateWindowPolicy\"P\n\x10LateWindowPolicy\x12\x1d\n\x19LATE_WINDOW_POLICY_IGNORE\x10\x00\x12\x1d\n\x19LATE_WINDOW_POLICY_REEMIT\x10\x01\"\xa4\x01\n\x10WindowEmitStatus\x12\x34\n\x06status\x18\x01 \x01(\x0e\x32\x1c.WindowEmitStatus.StatusEnumB\x06\xbaH\x03\xc8\x01\x01\"Z\n\nStatusEnum\x12\x15\n\x11TRIGGERING_FAILED\x10\x00\x12\x1b\n\x17NO_TRIGGERED_ALGORITHMS\x10\x01\x12\x18\n\x14PROCESSING_TRIGGERED\x10\x02\"\x95\x07\n\x13\x41lgorithmDependency\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1e\n\x0eprocessor_name\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12!\n\x11processor_runtime\x18\x04 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1b\n\x13processor_namespace\x18\n \x01(\t\x12\x16\n\x0clookback_num\x18\x05 \x01(\rH\x00\x12\x1d\n\x13lookback_time_delta\x18\x06 \x01(\x04H\x00\x12\x42\n\x12lookback_partition\x18\x07 \x01(\x0e\x32&.AlgorithmDependency.LookbackPartition\x12 \n\x18lookback_partition_field\x18\x08 \x01(\t\x12.\n\x12lookback_aggregate\x18\t \x01(\x0b\x32\x12.LookbackAggregate\"v\n\x11LookbackPartition\x12\x1b\n\x17LOOKBACK_PARTITION_NONE\x10\x00\x12\x1d\n\x19LOOKBACK_PARTITION_ORIGIN\x10\x01\x12%\n!LOOKBACK_PARTITION_METADATA_FIELD\x10\x02:\x96\x03\xbaH\x92\x03\x1a\xbe\x01\n-algorithm_dependency.lookback_partition_field\x12Hlookback_partition_field must be set when partitioning by metadata field\x1a\x43this.lookback_partition != 2 || this.lookback_partition_field != \'\'\x1a\xce\x01\n\'algorithm_dependency.lookback_aggregate\x12Ilookback_aggregate requires lookback_num or lookback_time_delta to be set\x1aX!has(this.lookback_aggregate) || has(this.lookback_num) || has(this.lookback_time_delta)B\x11\n\x08lookback\x12\x05\xbaH\x02\x08\x00\"\xc6\x02\n\x11LookbackAggregate\x12\x39\n\x08\x66unction\x18\x01 \x01(\x0e\x32\x1b.LookbackAggregate.FunctionB\n\xbaH\x07\x82\x01\x04\x10\x01 \x00\x12+\n\npercentile\x18\x02 \x01(\x01\x42\x17\xbaH\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00\xf0?)\x00\x00\x00\x00\x00\x00\x00\x00\x12\x19\n\x11\x62ucket_time_delta\x18\x03 \x01(\x04\"\xad\x01\n\x08\x46unction\x12\x18\n\x14\x46UNCTION_UNSPECIFIED\x10\x00\x12\x12\n\x0e\x46UNCTION_COUNT\x10\x01\x12\x10\n\x0c\x46UNCTION_SUM\x10\x02\x12\x11\n\rFUNCTION_MEAN\x10\x03\x12\x10\n\x0c\x46UNCTION_MIN\x10\x04\x12\x10\n\x0c\x46UNCTION_MAX\x10\x05\x12\x11\n\rFUNCTION_LAST\x10\x06\x12\x17\n\x13\x46UNCTION_PERCENTILE\x10\x07\"\x93\x01\n\x17LookbackAggregateBucket\x12-\n\ttime_from\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12+\n\x07time_to\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\r\n\x05\x63ount\x18\x03 \x01(\x04\x12\r\n\x05value\x18\x04 \x01(\x01\"\xdc\x01\n\tAlgorithm\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07version\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12(\n\x0bwindow_type\x18\x03 \x01(\x0b\x32\x0b.WindowTypeB\x06\xbaH\x03\xc8\x01\x01\x12*\n\x0c\x64\x65pendencies\x18\x04 \x03(\x0b\x32\x14.AlgorithmDependency\x12(\n\x0bresult_type\x18\x05 \x01(\x0e\x32\x0b.ResultTypeB\x06\xbaH\x03\xc8\x01\x01\x12 \n\x0b\x64\x65scription\x18\x06 \x01(\tB\x0b\xbaH\x08r\x03\x18\xe8\x07\xc8\x01\x01\"\x1c\n\nFloatArray\x12\x0e\n\x06values\x18\x01 \x03(\x02\"\xc7\x01\n\x06Result\x12%\n\x06status\x18\x01 \x01(\x0e\x32\r.ResultStatusB\x06\xbaH\x03\xc8\x01\x01\x12\x16\n\x0csingle_value\x18\x02 \x01(\x02H\x00\x12#\n\x0c\x66loat_values\x18\x03 \x01(\x0b\x32\x0b.FloatArrayH\x00\x12/\n\x0cstruct_value\x18\x04 \x01(\x0b\x32\x17.google.protobuf.StructH\x00\x12\x19\n\ttimestamp\x18\x05 \x01(\x03\x42\x06\xbaH\x03\xc8\x01\x01\x42\r\n\x0bresult_data\"\xca\x01\n\x15ProcessorRegistration\x12\x14\n\x04name\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x17\n\x07runtime\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1e\n\x0e\x63onnection_str\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x30\n\x14supported_algorithms\x18\x04 \x03(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x14\n\x0cproject_name\x18\x05 \x01(\t\x12\x1a\n\x03tls\x18\x06 \x01(\x0b\x32\r.ProcessorTLS\"A\n\x0cProcessorTLS\x12\x13\n\x0bserver_name\x18\x01 \x01(\t\x12\x1c\n\x14insecure_skip_verify\x18\x02 \x01(\x08\"`\n\x1c\x41lgorithmDependencyResultRow\x12\x1f\n\x06result\x18\x01 \x01(\x0b\x32\x07.ResultB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x02 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\"\xaf\x01\n\x19\x41lgorithmDependencyResult\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x35\n\x06result\x18\x02 \x03(\x0b\x32\x1d.AlgorithmDependencyResultRowB\x06\xbaH\x03\xc8\x01\x01\x12\x34\n\x12lookback_aggregate\x18\x03 \x03(\x0b\x32\x18.LookbackAggregateBucket\"k\n\x10\x45xecuteAlgorithm\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x30\n\x0c\x64\x65pendencies\x18\x02 \x03(\x0b\x32\x1a.AlgorithmDependencyResult\"\xda\x01\n\x10\x45xecutionRequest\x12\x17\n\x07\x65xec_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x02 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\x12/\n\x11\x61lgorithm_results\x18\x03 \x03(\x0b\x32\x10.AlgorithmResultB\x02\x18\x01\x12\"\n\nalgorithms\x18\x04 \x03(\x0b\x32\n.AlgorithmB\x02\x18\x01\x12\x37\n\x14\x61lgorithm_executions\x18\x05 \x03(\x0b\x32\x11.ExecuteAlgorithmB\x06\xbaH\x03\xc8\x01\x01\"^\n\x0f\x45xecutionResult\x12\x17\n\x07\x65xec_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01\x12\x32\n\x10\x61lgorithm_result\x18\x03 \x01(\x0b\x32\x10.AlgorithmResultB\x06\xbaH\x03\xc8\x01\x01\"z\n\x0f\x41lgorithmResult\x12%\n\talgorithm\x18\x01 \x01(\x0b\x32\n.AlgorithmB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06result\x18\x02 \x01(\x0b\x32\x07.ResultB\x06\xbaH\x03\xc8\x01\x01\x12\x1f\n\x06window\x18\x03 \x01(\x0b\x32\x07.WindowB\x06\xbaH\x03\xc8\x01\x01\"+\n\x06Status\x12\x10\n\x08received\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\"/\n\x12HealthCheckRequest\x12\x19\n\ttimestamp\x18\x01 \x01(\x03\x42\x06\xbaH\x03\xc8\x01\x01\"\xe3\x01\n\x13HealthCheckResponse\x12\x33\n\x06status\x18\x01 \x01(\x0e\x32\x1b.HealthCheckResponse.StatusB\x06\xbaH\x03\xc8\x01\x01\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\"\n\x07metrics\x18\x03 \x01(\x0b\x32\x11.ProcessorMetrics\"b\n\x06Status\x12\x12\n\x0eSTATUS_UNKNOWN\x10\x00\x12\x12\n\x0eSTATUS_SERVING\x10\x01\x12\x18\n\x14STATUS_TRANSITIONING\x10\x02\x12\x16\n\x12STATUS_NOT_SERVING\x10\x03\"k\n\x10ProcessorMetrics\x12\x14\n\x0c\x61\x63tive_tasks\x18\x01 \x01(\x05\x12\x14\n\x0cmemory_bytes\x18\x02 \x01(\x03\x12\x13\n\x0b\x63pu_percent\x18\x03 \x01(\x02\x12\x16\n\x0euptime_seconds\x18\x04 \x01(\x03\";\n\rInternalState\x12*\n\nprocessors\x18\x01 \x03(\x0b\x32\x16.ProcessorRegistration\"\x1b\n\x0cUsageRequest\x12\x0b\n\x03key\x18\x01 \x01(\t\"\'\n\x0bUsageReport\x12\x18\n\x05usage\x18\x01 \x03(\x0b\x32\t.KeyUsage\"l\n\x08KeyUsage\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t\x12\x0f\n\x07\x61llowed\x18\x03 \x01(\x04\x12\x10\n\x08rejected\x18\x04 \x01(\x04\x12\x11\n\tin_flight\x18\x05 \x01(\r\x12\x0e\n\x06tokens\x18\x06 \x01(\x01*K\n\nResultType\x12\x11\n\rNOT_SPECIFIED\x10\x00\x12\n\n\x06STRUCT\x10\x01\x12\t\n\x05VALUE\x10\x02\x12\t\n\x05\x41RRAY\x10\x03\x12\x08\n\x04NONE\x10\x04*p\n\x0cResultStatus\x12 \n\x1cRESULT_STATUS_HANDLED_FAILED\x10\x00\x12\"\n\x1eRESULT_STATUS_UNHANDLED_FAILED\x10\x01\x12\x1a\n\x16RESULT_STATUS_SUCEEDED\x10\x02\x32\xbb\x01\n\x08OrcaCore\x12\x34\n\x11RegisterProcessor\x12\x16.ProcessorRegistration\x1a\x07.Status\x12(\n\nEmitWindow\x12\x07.Window\x1a\x11.WindowEmitStatus\x12)\n\x06\x45xpose\x12\x0f.ExposeSettings\x1a\x0e.InternalState\x12$\n\x05Usage\x12\r.UsageRequest\x1a\x0c.UsageReport2\x82\x01\n\rOrcaProcessor\x12\x37\n\x0e\x45xecuteDagPart\x12\x11.ExecutionRequest\x1a\x10.ExecutionResult0\x01\x12\x38\n\x0bHealthCheck\x12\x13.HealthCheckRequest\x1a\x14.HealthCheckResponseB-Z+github.com/orca-telemetry/core/protobufs/gob\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
 _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'service_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
-  _globals['DESCRIPTOR']._serialized_options = b'Z*github.com/orc-analytics/core/protobufs/go'
+  _globals['DESCRIPTOR']._serialized_options = b'Z+github.com/orca-telemetry/core/protobufs/go'
   _globals['_WINDOW'].fields_by_name['time_from']._loaded_options = None
   _globals['_WINDOW'].fields_by_name['time_from']._serialized_options = b'\272H\010\262\001\002*\000\310\001\001'
   _globals['_WINDOW'].fields_by_name['time_to']._loaded_options = None
@@ -57,6 +57,22 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_WINDOWTYPE'].fields_by_name['version']._serialized_options = b'\272H\003\310\001\001'
   _globals['_WINDOWTYPE'].fields_by_name['description']._loaded_options = None
   _globals['_WINDOWTYPE'].fields_by_name['description']._serialized_options = b'\272H\003\310\001\001'
+  _globals['_WINDOWTYPE']._loaded_options = None
+  _globals['_WINDOWTYPE']._serialized_options = b'\272H\210\004\032\213\001\n\035window_type.schedule_metadata\0223scheduled window types cannot carry metadata fields\0325!has(this.schedule) || size(this.metadataFields) == 0\032~\n\033window_type.schedule_rollup\0225window types cannot have both a schedule and a rollup\032(!has(this.schedule) || !has(this.rollup)\032\177\n\033window_type.remove_schedule\0222window types cannot both set and remove a schedule\032,!has(this.schedule) || !this.remove_schedule\032w\n\031window_type.remove_rollup\0220window types cannot both set and remove a rollup\032(!has(this.rollup) || !this.remove_rollup'
+  _globals['_WINDOWSCHEDULE'].oneofs_by_name['trigger']._loaded_options = None
+  _globals['_WINDOWSCHEDULE'].oneofs_by_name['trigger']._serialized_options = b'\272H\002\010\001'
+  _globals['_WINDOWSCHEDULE'].fields_by_name['interval']._loaded_options = None
+  _globals['_WINDOWSCHEDULE'].fields_by_name['interval']._serialized_options = b'\272H\0042\002 \000'
+  _globals['_WINDOWSCHEDULE'].fields_by_name['origin']._loaded_options = None
+  _globals['_WINDOWSCHEDULE'].fields_by_name['origin']._serialized_options = b'\272H\003\310\001\001'
+  _globals['_WINDOWROLLUP'].fields_by_name['child_window_type_name']._loaded_options = None
+  _globals['_WINDOWROLLUP'].fields_by_name['child_window_type_name']._serialized_options = b'\272H\003\310\001\001'
+  _globals['_WINDOWROLLUP'].fields_by_name['child_window_type_version']._loaded_options = None
+  _globals['_WINDOWROLLUP'].fields_by_name['child_window_type_version']._serialized_options = b'\272H\003\310\001\001'
+  _globals['_WINDOWROLLUP'].fields_by_name['interval']._loaded_options = None
+  _globals['_WINDOWROLLUP'].fields_by_name['interval']._serialized_options = b'\272H\0042\002 \000'
+  _globals['_WINDOWROLLUP'].fields_by_name['child_count']._loaded_options = None
+  _globals['_WINDOWROLLUP'].fields_by_name['child_count']._serialized_options = b'\272H\004*\002 \000'
   _globals['_WINDOWEMITSTATUS'].fields_by_name['status']._loaded_options = None
   _globals['_WINDOWEMITSTATUS'].fields_by_name['status']._serialized_options = b'\272H\003\310\001\001'
   _globals['_ALGORITHMDEPENDENCY'].oneofs_by_name['lookback']._loaded_options = None
@@ -69,6 +85,12 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_ALGORITHMDEPENDENCY'].fields_by_name['processor_name']._serialized_options = b'\272H\003\310\001\001'
   _globals['_ALGORITHMDEPENDENCY'].fields_by_name['processor_runtime']._loaded_options = None
   _globals['_ALGORITHMDEPENDENCY'].fields_by_name['processor_runtime']._serialized_options = b'\272H\003\310\001\001'
+  _globals['_ALGORITHMDEPENDENCY']._loaded_options = None
+  _globals['_ALGORITHMDEPENDENCY']._serialized_options = b'\272H\222\003\032\276\001\n-algorithm_dependency.lookback_partition_field\022Hlookback_partition_field must be set when partitioning by metadata field\032Cthis.lookback_partition != 2 || this.lookback_partition_field != \'\'\032\316\001\n\'algorithm_dependency.lookback_aggregate\022Ilookback_aggregate requires lookback_num or lookback_time_delta to be set\032X!has(this.lookback_aggregate) || has(this.lookback_num) || has(this.lookback_time_delta)'
+  _globals['_LOOKBACKAGGREGATE'].fields_by_name['function']._loaded_options = None
+  _globals['_LOOKBACKAGGREGATE'].fields_by_name['function']._serialized_options = b'\272H\007\202\001\004\020\001 \000'
+  _globals['_LOOKBACKAGGREGATE'].fields_by_name['percentile']._loaded_options = None
+  _globals['_LOOKBACKAGGREGATE'].fields_by_name['percentile']._serialized_options = b'\272H\024\022\022\031\000\000\000\000\000\000\360?)\000\000\000\000\000\000\000\000'
   _globals['_ALGORITHM'].fields_by_name['name']._loaded_options = None
   _globals['_ALGORITHM'].fields_by_name['name']._serialized_options = b'\272H\003\310\001\001'
   _globals['_ALGORITHM'].fields_by_name['version']._loaded_options = None
@@ -125,58 +147,80 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_HEALTHCHECKREQUEST'].fields_by_name['timestamp']._serialized_options = b'\272H\003\310\001\001'
   _globals['_HEALTHCHECKRESPONSE'].fields_by_name['status']._loaded_options = None
   _globals['_HEALTHCHECKRESPONSE'].fields_by_name['status']._serialized_options = b'\272H\003\310\001\001'
-  _globals['_RESULTTYPE']._serialized_start=3000
-  _globals['_RESULTTYPE']._serialized_end=3075
-  _globals['_RESULTSTATUS']._serialized_start=3077
-  _globals['_RESULTSTATUS']._serialized_end=3189
+  _globals['_RESULTTYPE']._serialized_start=5689
+  _globals['_RESULTTYPE']._serialized_end=5764
+  _globals['_RESULTSTATUS']._serialized_start=5766
+  _globals['_RESULTSTATUS']._serialized_end=5878
   _globals['_EXPOSESETTINGS']._serialized_start=103
-  _globals['_EXPOSESETTINGS']._serialized_end=144
-  _globals['_WINDOW']._serialized_start=147
-  _globals['_WINDOW']._serialized_end=523
-  _globals['_METADATAFIELD']._serialized_start=525
-  _globals['_METADATAFIELD']._serialized_end=591
-  _globals['_WINDOWTYPE']._serialized_start=594
-  _globals['_WINDOWTYPE']._serialized_end=722
-  _globals['_WINDOWEMITSTATUS']._serialized_start=725
-  _globals['_WINDOWEMITSTATUS']._serialized_end=889
-  _globals['_WINDOWEMITSTATUS_STATUSENUM']._serialized_start=799
-  _globals['_WINDOWEMITSTATUS_STATUSENUM']._serialized_end=889
-  _globals['_ALGORITHMDEPENDENCY']._serialized_start=892
-  _globals['_ALGORITHMDEPENDENCY']._serialized_end=1101
-  _globals['_ALGORITHM']._serialized_start=1104
-  _globals['_ALGORITHM']._serialized_end=1324
-  _globals['_FLOATARRAY']._serialized_start=1326
-  _globals['_FLOATARRAY']._serialized_end=1354
-  _globals['_RESULT']._serialized_start=1357
-  _globals['_RESULT']._serialized_end=1556
-  _globals['_PROCESSORREGISTRATION']._serialized_start=1559
-  _globals['_PROCESSORREGISTRATION']._serialized_end=1733
-  _globals['_ALGORITHMDEPENDENCYRESULTROW']._serialized_start=1735
-  _globals['_ALGORITHMDEPENDENCYRESULTROW']._serialized_end=1831
-  _globals['_ALGORITHMDEPENDENCYRESULT']._serialized_start=1833
-  _globals['_ALGORITHMDEPENDENCYRESULT']._serialized_end=1954
-  _globals['_EXECUTEALGORITHM']._serialized_start=1956
-  _globals['_EXECUTEALGORITHM']._serialized_end=2063
-  _globals['_EXECUTIONREQUEST']._serialized_start=2066
-  _globals['_EXECUTIONREQUEST']._serialized_end=2284
-  _globals['_EXECUTIONRESULT']._serialized_start=2286
-  _globals['_EXECUTIONRESULT']._serialized_end=2380
-  _globals['_ALGORITHMRESULT']._serialized_start=2382
-  _globals['_ALGORITHMRESULT']._serialized_end=2504
-  _globals['_STATUS']._serialized_start=2506
-  _globals['_STATUS']._serialized_end=2549
-  _globals['_HEALTHCHECKREQUEST']._serialized_start=2551
-  _globals['_HEALTHCHECKREQUEST']._serialized_end=2598
-  _globals['_HEALTHCHECKRESPONSE']._serialized_start=2601
-  _globals['_HEALTHCHECKRESPONSE']._serialized_end=2828
-  _globals['_HEALTHCHECKRESPONSE_STATUS']._serialized_start=2730
-  _globals['_HEALTHCHECKRESPONSE_STATUS']._serialized_end=2828
-  _globals['_PROCESSORMETRICS']._serialized_start=2830
-  _globals['_PROCESSORMETRICS']._serialized_end=2937
-  _globals['_INTERNALSTATE']._serialized_start=2939
-  _globals['_INTERNALSTATE']._serialized_end=2998
-  _globals['_ORCACORE']._serialized_start=3192
-  _globals['_ORCACORE']._serialized_end=3341
-  _globals['_ORCAPROCESSOR']._serialized_start=3344
-  _globals['_ORCAPROCESSOR']._serialized_end=3474
+  _globals['_EXPOSESETTINGS']._serialized_end=163
+  _globals['_WINDOW']._serialized_start=166
+  _globals['_WINDOW']._serialized_end=561
+  _globals['_METADATAFIELD']._serialized_start=563
+  _globals['_METADATAFIELD']._serialized_end=629
+  _globals['_WINDOWTYPE']._serialized_start=632
+  _globals['_WINDOWTYPE']._serialized_end=1401
+  _globals['_WINDOWSCHEDULE']._serialized_start=1404
+  _globals['_WINDOWSCHEDULE']._serialized_end=1563
+  _globals['_WINDOWROLLUP']._serialized_start=1566
+  _globals['_WINDOWROLLUP']._serialized_end=1894
+  _globals['_WINDOWROLLUP_LATEWINDOWPOLICY']._serialized_start=1814
+  _globals['_WINDOWROLLUP_LATEWINDOWPOLICY']._serialized_end=1894
+  _globals['_WINDOWEMITSTATUS']._serialized_start=1897
+  _globals['_WINDOWEMITSTATUS']._serialized_end=2061
+  _globals['_WINDOWEMITSTATUS_STATUSENUM']._serialized_start=1971
+  _globals['_WINDOWEMITSTATUS_STATUSENUM']._serialized_end=2061
+  _globals['_ALGORITHMDEPENDENCY']._serialized_start=2064
+  _globals['_ALGORITHMDEPENDENCY']._serialized_end=2981
+  _globals['_ALGORITHMDEPENDENCY_LOOKBACKPARTITION']._serialized_start=2435
+  _globals['_ALGORITHMDEPENDENCY_LOOKBACKPARTITION']._serialized_end=2553
+  _globals['_LOOKBACKAGGREGATE']._serialized_start=2984
+  _globals['_LOOKBACKAGGREGATE']._serialized_end=3310
+  _globals['_LOOKBACKAGGREGATE_FUNCTION']._serialized_start=3137
+  _globals['_LOOKBACKAGGREGATE_FUNCTION']._serialized_end=3310
+  _globals['_LOOKBACKAGGREGATEBUCKET']._serialized_start=3313
+  _globals['_LOOKBACKAGGREGATEBUCKET']._serialized_end=3460
+  _globals['_ALGORITHM']._serialized_start=3463
+  _globals['_ALGORITHM']._serialized_end=3683
+  _globals['_FLOATARRAY']._serialized_start=3685
+  _globals['_FLOATARRAY']._serialized_end=3713
+  _globals['_RESULT']._serialized_start=3716
+  _globals['_RESULT']._serialized_end=3915
+  _globals['_PROCESSORREGISTRATION']._serialized_start=3918
+  _globals['_PROCESSORREGISTRATION']._serialized_end=4120
+  _globals['_PROCESSORTLS']._serialized_start=4122
+  _globals['_PROCESSORTLS']._serialized_end=4187
+  _globals['_ALGORITHMDEPENDENCYRESULTROW']._serialized_start=4189
+  _globals['_ALGORITHMDEPENDENCYRESULTROW']._serialized_end=4285
+  _globals['_ALGORITHMDEPENDENCYRESULT']._serialized_start=4288
+  _globals['_ALGORITHMDEPENDENCYRESULT']._serialized_end=4463
+  _globals['_EXECUTEALGORITHM']._serialized_start=4465
+  _globals['_EXECUTEALGORITHM']._serialized_end=4572
+  _globals['_EXECUTIONREQUEST']._serialized_start=4575
+  _globals['_EXECUTIONREQUEST']._serialized_end=4793
+  _globals['_EXECUTIONRESULT']._serialized_start=4795
+  _globals['_EXECUTIONRESULT']._serialized_end=4889
+  _globals['_ALGORITHMRESULT']._serialized_start=4891
+  _globals['_ALGORITHMRESULT']._serialized_end=5013
+  _globals['_STATUS']._serialized_start=5015
+  _globals['_STATUS']._serialized_end=5058
+  _globals['_HEALTHCHECKREQUEST']._serialized_start=5060
+  _globals['_HEALTHCHECKREQUEST']._serialized_end=5107
+  _globals['_HEALTHCHECKRESPONSE']._serialized_start=5110
+  _globals['_HEALTHCHECKRESPONSE']._serialized_end=5337
+  _globals['_HEALTHCHECKRESPONSE_STATUS']._serialized_start=5239
+  _globals['_HEALTHCHECKRESPONSE_STATUS']._serialized_end=5337
+  _globals['_PROCESSORMETRICS']._serialized_start=5339
+  _globals['_PROCESSORMETRICS']._serialized_end=5446
+  _globals['_INTERNALSTATE']._serialized_start=5448
+  _globals['_INTERNALSTATE']._serialized_end=5507
+  _globals['_USAGEREQUEST']._serialized_start=5509
+  _globals['_USAGEREQUEST']._serialized_end=5536
+  _globals['_USAGEREPORT']._serialized_start=5538
+  _globals['_USAGEREPORT']._serialized_end=5577
+  _globals['_KEYUSAGE']._serialized_start=5579
+  _globals['_KEYUSAGE']._serialized_end=5687
+  _globals['_ORCACORE']._serialized_start=5881
+  _globals['_ORCACORE']._serialized_end=6068
+  _globals['_ORCAPROCESSOR']._serialized_start=6071
+  _globals['_ORCAPROCESSOR']._serialized_end=6201
 # @@protoc_insertion_point(module_scope)
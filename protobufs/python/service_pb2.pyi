@@ -32,26 +32,30 @@ RESULT_STATUS_UNHANDLED_FAILED: ResultStatus
 RESULT_STATUS_SUCEEDED: ResultStatus
 
 class ExposeSettings(_message.Message):
-    __slots__ = ("exclude_project",)
+    __slots__ = ("exclude_project", "namespace")
     EXCLUDE_PROJECT_FIELD_NUMBER: _ClassVar[int]
+    NAMESPACE_FIELD_NUMBER: _ClassVar[int]
     exclude_project: str
-    def __init__(self, exclude_project: _Optional[str] = ...) -> None: ...
+    namespace: str
+    def __init__(self, exclude_project: _Optional[str] = ..., namespace: _Optional[str] = ...) -> None: ...
 
 class Window(_message.Message):
-    __slots__ = ("time_from", "time_to", "window_type_name", "window_type_version", "origin", "metadata")
+    __slots__ = ("time_from", "time_to", "window_type_name", "window_type_version", "origin", "metadata", "namespace")
     TIME_FROM_FIELD_NUMBER: _ClassVar[int]
     TIME_TO_FIELD_NUMBER: _ClassVar[int]
     WINDOW_TYPE_NAME_FIELD_NUMBER: _ClassVar[int]
     WINDOW_TYPE_VERSION_FIELD_NUMBER: _ClassVar[int]
     ORIGIN_FIELD_NUMBER: _ClassVar[int]
     METADATA_FIELD_NUMBER: _ClassVar[int]
+    NAMESPACE_FIELD_NUMBER: _ClassVar[int]
     time_from: _timestamp_pb2.Timestamp
     time_to: _timestamp_pb2.Timestamp
     window_type_name: str
     window_type_version: str
     origin: str
     metadata: _struct_pb2.Struct
-    def __init__(self, time_from: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., time_to: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., window_type_name: _Optional[str] = ..., window_type_version: _Optional[str] = ..., origin: _Optional[str] = ..., metadata: _Optional[_Union[_struct_pb2.Struct, _Mapping]] = ...) -> None: ...
+    namespace: str
+    def __init__(self, time_from: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., time_to: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., window_type_name: _Optional[str] = ..., window_type_version: _Optional[str] = ..., origin: _Optional[str] = ..., metadata: _Optional[_Union[_struct_pb2.Struct, _Mapping]] = ..., namespace: _Optional[str] = ...) -> None: ...
 
 class MetadataField(_message.Message):
     __slots__ = ("name", "description")
@@ -62,16 +66,64 @@ class MetadataField(_message.Message):
     def __init__(self, name: _Optional[str] = ..., description: _Optional[str] = ...) -> None: ...
 
 class WindowType(_message.Message):
-    __slots__ = ("name", "version", "description", "metadataFields")
+    __slots__ = ("name", "version", "description", "metadataFields", "schedule", "rollup", "remove_schedule", "remove_rollup")
     NAME_FIELD_NUMBER: _ClassVar[int]
     VERSION_FIELD_NUMBER: _ClassVar[int]
     DESCRIPTION_FIELD_NUMBER: _ClassVar[int]
     METADATAFIELDS_FIELD_NUMBER: _ClassVar[int]
+    SCHEDULE_FIELD_NUMBER: _ClassVar[int]
+    ROLLUP_FIELD_NUMBER: _ClassVar[int]
+    REMOVE_SCHEDULE_FIELD_NUMBER: _ClassVar[int]
+    REMOVE_ROLLUP_FIELD_NUMBER: _ClassVar[int]
     name: str
     version: str
     description: str
     metadataFields: _containers.RepeatedCompositeFieldContainer[MetadataField]
-    def __init__(self, name: _Optional[str] = ..., version: _Optional[str] = ..., description: _Optional[str] = ..., metadataFields: _Optional[_Iterable[_Union[MetadataField, _Mapping]]] = ...) -> None: ...
+    schedule: WindowSchedule
+    rollup: WindowRollup
+    remove_schedule: bool
+    remove_rollup: bool
+    def __init__(self, name: _Optional[str] = ..., version: _Optional[str] = ..., description: _Optional[str] = ..., metadataFields: _Optional[_Iterable[_Union[MetadataField, _Mapping]]] = ..., schedule: _Optional[_Union[WindowSchedule, _Mapping]] = ..., rollup: _Optional[_Union[WindowRollup, _Mapping]] = ..., remove_schedule: bool = ..., remove_rollup: bool = ...) -> None: ...
+
+class WindowSchedule(_message.Message):
+    __slots__ = ("cron", "interval", "alignment", "time_zone", "origin", "catch_up")
+    CRON_FIELD_NUMBER: _ClassVar[int]
+    INTERVAL_FIELD_NUMBER: _ClassVar[int]
+    ALIGNMENT_FIELD_NUMBER: _ClassVar[int]
+    TIME_ZONE_FIELD_NUMBER: _ClassVar[int]
+    ORIGIN_FIELD_NUMBER: _ClassVar[int]
+    CATCH_UP_FIELD_NUMBER: _ClassVar[int]
+    cron: str
+    interval: int
+    alignment: int
+    time_zone: str
+    origin: str
+    catch_up: bool
+    def __init__(self, cron: _Optional[str] = ..., interval: _Optional[int] = ..., alignment: _Optional[int] = ..., time_zone: _Optional[str] = ..., origin: _Optional[str] = ..., catch_up: bool = ...) -> None: ...
+
+class WindowRollup(_message.Message):
+    __slots__ = ("child_window_type_name", "child_window_type_version", "interval", "alignment", "time_zone", "child_count", "late_windows")
+    class LateWindowPolicy(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+        __slots__ = ()
+        LATE_WINDOW_POLICY_IGNORE: _ClassVar[WindowRollup.LateWindowPolicy]
+        LATE_WINDOW_POLICY_REEMIT: _ClassVar[WindowRollup.LateWindowPolicy]
+    LATE_WINDOW_POLICY_IGNORE: WindowRollup.LateWindowPolicy
+    LATE_WINDOW_POLICY_REEMIT: WindowRollup.LateWindowPolicy
+    CHILD_WINDOW_TYPE_NAME_FIELD_NUMBER: _ClassVar[int]
+    CHILD_WINDOW_TYPE_VERSION_FIELD_NUMBER: _ClassVar[int]
+    INTERVAL_FIELD_NUMBER: _ClassVar[int]
+    ALIGNMENT_FIELD_NUMBER: _ClassVar[int]
+    TIME_ZONE_FIELD_NUMBER: _ClassVar[int]
+    CHILD_COUNT_FIELD_NUMBER: _ClassVar[int]
+    LATE_WINDOWS_FIELD_NUMBER: _ClassVar[int]
+    child_window_type_name: str
+    child_window_type_version: str
+    interval: int
+    alignment: int
+    time_zone: str
+    child_count: int
+    late_windows: WindowRollup.LateWindowPolicy
+    def __init__(self, child_window_type_name: _Optional[str] = ..., child_window_type_version: _Optional[str] = ..., interval: _Optional[int] = ..., alignment: _Optional[int] = ..., time_zone: _Optional[str] = ..., child_count: _Optional[int] = ..., late_windows: _Optional[_Union[WindowRollup.LateWindowPolicy, str]] = ...) -> None: ...
 
 class WindowEmitStatus(_message.Message):
     __slots__ = ("status",)
@@ -88,20 +140,76 @@ class WindowEmitStatus(_message.Message):
     def __init__(self, status: _Optional[_Union[WindowEmitStatus.StatusEnum, str]] = ...) -> None: ...
 
 class AlgorithmDependency(_message.Message):
-    __slots__ = ("name", "version", "processor_name", "processor_runtime", "lookback_num", "lookback_time_delta")
+    __slots__ = ("name", "version", "processor_name", "processor_runtime", "processor_namespace", "lookback_num", "lookback_time_delta", "lookback_partition", "lookback_partition_field", "lookback_aggregate")
+    class LookbackPartition(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+        __slots__ = ()
+        LOOKBACK_PARTITION_NONE: _ClassVar[AlgorithmDependency.LookbackPartition]
+        LOOKBACK_PARTITION_ORIGIN: _ClassVar[AlgorithmDependency.LookbackPartition]
+        LOOKBACK_PARTITION_METADATA_FIELD: _ClassVar[AlgorithmDependency.LookbackPartition]
+    LOOKBACK_PARTITION_NONE: AlgorithmDependency.LookbackPartition
+    LOOKBACK_PARTITION_ORIGIN: AlgorithmDependency.LookbackPartition
+    LOOKBACK_PARTITION_METADATA_FIELD: AlgorithmDependency.LookbackPartition
     NAME_FIELD_NUMBER: _ClassVar[int]
     VERSION_FIELD_NUMBER: _ClassVar[int]
     PROCESSOR_NAME_FIELD_NUMBER: _ClassVar[int]
     PROCESSOR_RUNTIME_FIELD_NUMBER: _ClassVar[int]
+    PROCESSOR_NAMESPACE_FIELD_NUMBER: _ClassVar[int]
     LOOKBACK_NUM_FIELD_NUMBER: _ClassVar[int]
     LOOKBACK_TIME_DELTA_FIELD_NUMBER: _ClassVar[int]
+    LOOKBACK_PARTITION_FIELD_NUMBER: _ClassVar[int]
+    LOOKBACK_PARTITION_FIELD_FIELD_NUMBER: _ClassVar[int]
+    LOOKBACK_AGGREGATE_FIELD_NUMBER: _ClassVar[int]
     name: str
     version: str
     processor_name: str
     processor_runtime: str
+    processor_namespace: str
     lookback_num: int
     lookback_time_delta: int
-    def __init__(self, name: _Optional[str] = ..., version: _Optional[str] = ..., processor_name: _Optional[str] = ..., processor_runtime: _Optional[str] = ..., lookback_num: _Optional[int] = ..., lookback_time_delta: _Optional[int] = ...) -> None: ...
+    lookback_partition: AlgorithmDependency.LookbackPartition
+    lookback_partition_field: str
+    lookback_aggregate: LookbackAggregate
+    def __init__(self, name: _Optional[str] = ..., version: _Optional[str] = ..., processor_name: _Optional[str] = ..., processor_runtime: _Optional[str] = ..., processor_namespace: _Optional[str] = ..., lookback_num: _Optional[int] = ..., lookback_time_delta: _Optional[int] = ..., lookback_partition: _Optional[_Union[AlgorithmDependency.LookbackPartition, str]] = ..., lookback_partition_field: _Optional[str] = ..., lookback_aggregate: _Optional[_Union[LookbackAggregate, _Mapping]] = ...) -> None: ...
+
+class LookbackAggregate(_message.Message):
+    __slots__ = ("function", "percentile", "bucket_time_delta")
+    class Function(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+        __slots__ = ()
+        FUNCTION_UNSPECIFIED: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_COUNT: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_SUM: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_MEAN: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_MIN: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_MAX: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_LAST: _ClassVar[LookbackAggregate.Function]
+        FUNCTION_PERCENTILE: _ClassVar[LookbackAggregate.Function]
+    FUNCTION_UNSPECIFIED: LookbackAggregate.Function
+    FUNCTION_COUNT: LookbackAggregate.Function
+    FUNCTION_SUM: LookbackAggregate.Function
+    FUNCTION_MEAN: LookbackAggregate.Function
+    FUNCTION_MIN: LookbackAggregate.Function
+    FUNCTION_MAX: LookbackAggregate.Function
+    FUNCTION_LAST: LookbackAggregate.Function
+    FUNCTION_PERCENTILE: LookbackAggregate.Function
+    FUNCTION_FIELD_NUMBER: _ClassVar[int]
+    PERCENTILE_FIELD_NUMBER: _ClassVar[int]
+    BUCKET_TIME_DELTA_FIELD_NUMBER: _ClassVar[int]
+    function: LookbackAggregate.Function
+    percentile: float
+    bucket_time_delta: int
+    def __init__(self, function: _Optional[_Union[LookbackAggregate.Function, str]] = ..., percentile: _Optional[float] = ..., bucket_time_delta: _Optional[int] = ...) -> None: ...
+
+class LookbackAggregateBucket(_message.Message):
+    __slots__ = ("time_from", "time_to", "count", "value")
+    TIME_FROM_FIELD_NUMBER: _ClassVar[int]
+    TIME_TO_FIELD_NUMBER: _ClassVar[int]
+    COUNT_FIELD_NUMBER: _ClassVar[int]
+    VALUE_FIELD_NUMBER: _ClassVar[int]
+    time_from: _timestamp_pb2.Timestamp
+    time_to: _timestamp_pb2.Timestamp
+    count: int
+    value: float
+    def __init__(self, time_from: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., time_to: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., count: _Optional[int] = ..., value: _Optional[float] = ...) -> None: ...
 
 class Algorithm(_message.Message):
     __slots__ = ("name", "version", "window_type", "dependencies", "result_type", "description")
@@ -140,18 +248,28 @@ class Result(_message.Message):
     def __init__(self, status: _Optional[_Union[ResultStatus, str]] = ..., single_value: _Optional[float] = ..., float_values: _Optional[_Union[FloatArray, _Mapping]] = ..., struct_value: _Optional[_Union[_struct_pb2.Struct, _Mapping]] = ..., timestamp: _Optional[int] = ...) -> None: ...
 
 class ProcessorRegistration(_message.Message):
-    __slots__ = ("name", "runtime", "connection_str", "supported_algorithms", "project_name")
+    __slots__ = ("name", "runtime", "connection_str", "supported_algorithms", "project_name", "tls")
     NAME_FIELD_NUMBER: _ClassVar[int]
     RUNTIME_FIELD_NUMBER: _ClassVar[int]
     CONNECTION_STR_FIELD_NUMBER: _ClassVar[int]
     SUPPORTED_ALGORITHMS_FIELD_NUMBER: _ClassVar[int]
     PROJECT_NAME_FIELD_NUMBER: _ClassVar[int]
+    TLS_FIELD_NUMBER: _ClassVar[int]
     name: str
     runtime: str
     connection_str: str
     supported_algorithms: _containers.RepeatedCompositeFieldContainer[Algorithm]
     project_name: str
-    def __init__(self, name: _Optional[str] = ..., runtime: _Optional[str] = ..., connection_str: _Optional[str] = ..., supported_algorithms: _Optional[_Iterable[_Union[Algorithm, _Mapping]]] = ..., project_name: _Optional[str] = ...) -> None: ...
+    tls: ProcessorTLS
+    def __init__(self, name: _Optional[str] = ..., runtime: _Optional[str] = ..., connection_str: _Optional[str] = ..., supported_algorithms: _Optional[_Iterable[_Union[Algorithm, _Mapping]]] = ..., project_name: _Optional[str] = ..., tls: _Optional[_Union[ProcessorTLS, _Mapping]] = ...) -> None: ...
+
+class ProcessorTLS(_message.Message):
+    __slots__ = ("server_name", "insecure_skip_verify")
+    SERVER_NAME_FIELD_NUMBER: _ClassVar[int]
+    INSECURE_SKIP_VERIFY_FIELD_NUMBER: _ClassVar[int]
+    server_name: str
+    insecure_skip_verify: bool
+    def __init__(self, server_name: _Optional[str] = ..., insecure_skip_verify: bool = ...) -> None: ...
 
 class AlgorithmDependencyResultRow(_message.Message):
     __slots__ = ("result", "window")
@@ -162,12 +280,14 @@ class AlgorithmDependencyResultRow(_message.Message):
     def __init__(self, result: _Optional[_Union[Result, _Mapping]] = ..., window: _Optional[_Union[Window, _Mapping]] = ...) -> None: ...
 
 class AlgorithmDependencyResult(_message.Message):
-    __slots__ = ("algorithm", "result")
+    __slots__ = ("algorithm", "result", "lookback_aggregate")
     ALGORITHM_FIELD_NUMBER: _ClassVar[int]
     RESULT_FIELD_NUMBER: _ClassVar[int]
+    LOOKBACK_AGGREGATE_FIELD_NUMBER: _ClassVar[int]
     algorithm: Algorithm
     result: _containers.RepeatedCompositeFieldContainer[AlgorithmDependencyResultRow]
-    def __init__(self, algorithm: _Optional[_Union[Algorithm, _Mapping]] = ..., result: _Optional[_Iterable[_Union[AlgorithmDependencyResultRow, _Mapping]]] = ...) -> None: ...
+    lookback_aggregate: _containers.RepeatedCompositeFieldContainer[LookbackAggregateBucket]
+    def __init__(self, algorithm: _Optional[_Union[Algorithm, _Mapping]] = ..., result: _Optional[_Iterable[_Union[AlgorithmDependencyResultRow, _Mapping]]] = ..., lookback_aggregate: _Optional[_Iterable[_Union[LookbackAggregateBucket, _Mapping]]] = ...) -> None: ...
 
 class ExecuteAlgorithm(_message.Message):
     __slots__ = ("algorithm", "dependencies")
@@ -260,3 +380,31 @@ class InternalState(_message.Message):
     PROCESSORS_FIELD_NUMBER: _ClassVar[int]
     processors: _containers.RepeatedCompositeFieldContainer[ProcessorRegistration]
     def __init__(self, processors: _Optional[_Iterable[_Union[ProcessorRegistration, _Mapping]]] = ...) -> None: ...
+
+class UsageRequest(_message.Message):
+    __slots__ = ("key",)
+    KEY_FIELD_NUMBER: _ClassVar[int]
+    key: str
+    def __init__(self, key: _Optional[str] = ...) -> None: ...
+
+class UsageReport(_message.Message):
+    __slots__ = ("usage",)
+    USAGE_FIELD_NUMBER: _ClassVar[int]
+    usage: _containers.RepeatedCompositeFieldContainer[KeyUsage]
+    def __init__(self, usage: _Optional[_Iterable[_Union[KeyUsage, _Mapping]]] = ...) -> None: ...
+
+class KeyUsage(_message.Message):
+    __slots__ = ("key", "value", "allowed", "rejected", "in_flight", "tokens")
+    KEY_FIELD_NUMBER: _ClassVar[int]
+    VALUE_FIELD_NUMBER: _ClassVar[int]
+    ALLOWED_FIELD_NUMBER: _ClassVar[int]
+    REJECTED_FIELD_NUMBER: _ClassVar[int]
+    IN_FLIGHT_FIELD_NUMBER: _ClassVar[int]
+    TOKENS_FIELD_NUMBER: _ClassVar[int]
+    key: str
+    value: str
+    allowed: int
+    rejected: int
+    in_flight: int
+    tokens: float
+    def __init__(self, key: _Optional[str] = ..., value: _Optional[str] = ..., allowed: _Optional[int] = ..., rejected: _Optional[int] = ..., in_flight: _Optional[int] = ..., tokens: _Optional[float] = ...) -> None: ...
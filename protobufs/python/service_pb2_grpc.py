@@ -55,6 +55,11 @@ class OrcaCoreStub(object):
                 request_serializer=service__pb2.ExposeSettings.SerializeToString,
                 response_deserializer=service__pb2.InternalState.FromString,
                 _registered_method=True)
+        self.Usage = channel.unary_unary(
+                '/OrcaCore/Usage',
+                request_serializer=service__pb2.UsageRequest.SerializeToString,
+                response_deserializer=service__pb2.UsageReport.FromString,
+                _registered_method=True)
 
 
 class OrcaCoreServicer(object):
@@ -87,6 +92,14 @@ class OrcaCoreServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def Usage(self, request, context):
+        """------------------- Admin operations ------------------- 
+        Report the use of the rate and concurrency limits on emitting windows
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_OrcaCoreServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -105,6 +118,11 @@ def add_OrcaCoreServicer_to_server(servicer, server):
                     request_deserializer=service__pb2.ExposeSettings.FromString,
                     response_serializer=service__pb2.InternalState.SerializeToString,
             ),
+            'Usage': grpc.unary_unary_rpc_method_handler(
+                    servicer.Usage,
+                    request_deserializer=service__pb2.UsageRequest.FromString,
+                    response_serializer=service__pb2.UsageReport.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'OrcaCore', rpc_method_handlers)
@@ -203,6 +221,33 @@ class OrcaCore(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def Usage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/OrcaCore/Usage',
+            service__pb2.UsageRequest.SerializeToString,
+            service__pb2.UsageReport.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
 
 class OrcaProcessorStub(object):
     """OrcaProcessor defines the interface that each processing node must implement.
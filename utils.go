@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	types "github.com/orca-telemetry/core/datalayer"
 )
 
 // style for the placeholder text
@@ -23,8 +25,27 @@ var (
 				Foreground(lipgloss.Color("11")). // Yellow text
 				Italic(true).
 				MarginLeft(2)
+	checkPassStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")). // Green text
+			Bold(true)
+	checkFailStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")). // Red text
+			Bold(true)
 )
 
+// renderCheck renders a check of orca doctor, with its fix when it failed
+func renderCheck(check types.Check) string {
+	if check.Err == nil {
+		return checkPassStyle.Render("PASS") + "  " + check.Name
+	}
+	var sb strings.Builder
+	sb.WriteString(checkFailStyle.Render("FAIL") + "  " + check.Name + ": " + check.Err.Error())
+	if check.Fix != "" {
+		sb.WriteString("\n" + errorDetailStyle.Render("  fix: "+check.Fix))
+	}
+	return sb.String()
+}
+
 // ParsePostgresURL parses a PostgreSQL connection string and returns a map of named capture groups
 func ParsePostgresURL(s string, example string) (map[string]string, error) {
 	// define the regex pattern with named capture groups
@@ -95,3 +116,23 @@ func ParsePostgresURL(s string, example string) (map[string]string, error) {
 
 	return result, nil
 }
+
+// ParseSqliteURL parses a SQLite connection string and returns the path of its
+// database file
+func ParseSqliteURL(s string, example string) (map[string]string, error) {
+	if !strings.HasPrefix(s, "sqlite://") {
+		return nil, fmt.Errorf("Connection string must start with 'sqlite://'")
+	}
+	path, settings, _ := strings.Cut(strings.TrimPrefix(s, "sqlite://"), "?")
+	if path == "" {
+		var sb strings.Builder
+		sb.WriteString(errorHeaderStyle.Render("Invalid Connection String Format"))
+		sb.WriteString("\n")
+		sb.WriteString(errorDetailStyle.Render("Expected format: " + example))
+		sb.WriteString("\n")
+		sb.WriteString(errorDetailStyle.Render("Issues found:"))
+		sb.WriteString("\n" + errorDetailStyle.Render("• Missing database file (e.g., 'orca.db')"))
+		return nil, errors.New(sb.String())
+	}
+	return map[string]string{"path": path, "settings": settings}, nil
+}